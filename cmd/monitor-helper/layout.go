@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"os"
+	"strings"
+
+	"github.com/kbinani/screenshot"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/ui"
+)
+
+// layoutWidth is how many columns the ASCII diagram spans. Terminal cells
+// are about twice as tall as wide, so rows are scaled by half as much.
+const layoutWidth = 72
+
+// svgWidth is the width of the SVG diagram in pixels
+const svgWidth = 800
+
+// layoutMonitor is one monitor in a layout diagram
+type layoutMonitor struct {
+	num    int
+	bounds image.Rectangle
+	name   string
+}
+
+// detectLayout reads every monitor's position and name
+func detectLayout() ([]layoutMonitor, image.Rectangle) {
+	n := screenshot.NumActiveDisplays()
+	displays, _ := capture.Screen{}.Displays()
+
+	monitors := make([]layoutMonitor, n)
+	var desktop image.Rectangle
+	for i := range monitors {
+		monitors[i] = layoutMonitor{num: i + 1, bounds: screenshot.GetDisplayBounds(i)}
+		if i < len(displays) {
+			monitors[i].name = displays[i].String()
+		}
+		desktop = desktop.Union(monitors[i].bounds)
+	}
+	return monitors, desktop
+}
+
+// renderLayout draws the monitors as boxes at their relative positions,
+// each labelled with its number, resolution and name where it fits
+func renderLayout(monitors []layoutMonitor, desktop image.Rectangle) string {
+	scale := float64(layoutWidth) / float64(desktop.Dx())
+	rows := int(float64(desktop.Dy())*scale/2) + 1
+	grid := make([][]rune, rows)
+	for y := range grid {
+		grid[y] = []rune(strings.Repeat(" ", layoutWidth+1))
+	}
+	put := func(x, y int, r rune) {
+		if y >= 0 && y < rows && x >= 0 && x <= layoutWidth {
+			grid[y][x] = r
+		}
+	}
+	// Borders of monitors that touch meet in a corner rather than
+	// overwriting each other
+	border := func(x, y int, r rune) {
+		if y >= 0 && y < rows && x >= 0 && x <= layoutWidth {
+			if old := grid[y][x]; old != ' ' && old != r {
+				r = '+'
+			}
+			grid[y][x] = r
+		}
+	}
+
+	boxes := make([]image.Rectangle, len(monitors))
+	for i, m := range monitors {
+		b := m.bounds.Sub(desktop.Min)
+		x0, x1 := int(float64(b.Min.X)*scale), int(float64(b.Max.X)*scale)
+		y0, y1 := int(float64(b.Min.Y)*scale/2), int(float64(b.Max.Y)*scale/2)
+		// Keep tiny monitors big enough to show their number
+		x1, y1 = max(x1, x0+4), max(y1, y0+2)
+		boxes[i] = image.Rect(x0, y0, x1, y1)
+
+		for x := x0 + 1; x < x1; x++ {
+			border(x, y0, '-')
+			border(x, y1, '-')
+		}
+		for y := y0 + 1; y < y1; y++ {
+			border(x0, y, '|')
+			border(x1, y, '|')
+		}
+		for _, c := range [][2]int{{x0, y0}, {x1, y0}, {x0, y1}, {x1, y1}} {
+			put(c[0], c[1], '+')
+		}
+	}
+
+	// Labels go on top once every border is drawn
+	for i, m := range monitors {
+		x0, y0, x1, y1 := boxes[i].Min.X, boxes[i].Min.Y, boxes[i].Max.X, boxes[i].Max.Y
+
+		labels := []string{
+			fmt.Sprintf("#%d", m.num),
+			fmt.Sprintf("%dx%d", m.bounds.Dx(), m.bounds.Dy()),
+			m.name,
+		}
+		inner := x1 - x0 - 1
+		for line, label := range labels {
+			y := y0 + 1 + line
+			if label == "" || y >= y1 {
+				continue
+			}
+			text := []rune(label)
+			if len(text) > inner {
+				if line > 0 {
+					continue
+				}
+				text = text[:inner]
+			}
+			start := x0 + 1 + (inner-len(text))/2
+			for j, r := range text {
+				put(start+j, y, r)
+			}
+		}
+	}
+
+	var out strings.Builder
+	for _, row := range grid {
+		out.WriteString(strings.TrimRight(string(row), " "))
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// layoutSVG draws the monitors as an SVG image
+func layoutSVG(monitors []layoutMonitor, desktop image.Rectangle) string {
+	const pad = 10
+	scale := float64(svgWidth-2*pad) / float64(desktop.Dx())
+	height := int(float64(desktop.Dy())*scale) + 2*pad
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif">`+"\n", svgWidth, height)
+	for _, m := range monitors {
+		b := m.bounds.Sub(desktop.Min)
+		x, y := pad+float64(b.Min.X)*scale, pad+float64(b.Min.Y)*scale
+		w, h := float64(b.Dx())*scale, float64(b.Dy())*scale
+		cx, cy := x+w/2, y+h/2
+
+		fmt.Fprintf(&svg, `  <rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#e8f0fe" stroke="#1a73e8" stroke-width="2"/>`+"\n", x, y, w, h)
+		fmt.Fprintf(&svg, `  <text x="%.1f" y="%.1f" text-anchor="middle" font-size="28" font-weight="bold">%d</text>`+"\n", cx, cy-8, m.num)
+		fmt.Fprintf(&svg, `  <text x="%.1f" y="%.1f" text-anchor="middle" font-size="13">%dx%d at (%d, %d)</text>`+"\n",
+			cx, cy+14, m.bounds.Dx(), m.bounds.Dy(), m.bounds.Min.X, m.bounds.Min.Y)
+		if m.name != "" {
+			fmt.Fprintf(&svg, `  <text x="%.1f" y="%.1f" text-anchor="middle" font-size="13" fill="#555">%s</text>`+"\n",
+				cx, cy+32, html.EscapeString(m.name))
+		}
+	}
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
+
+// showLayout prints the monitor layout, or writes it as SVG to svgPath
+func showLayout(svgPath string) error {
+	monitors, desktop := detectLayout()
+	if len(monitors) == 0 || desktop.Empty() {
+		return fmt.Errorf("no active displays found")
+	}
+
+	if svgPath != "" {
+		if err := os.WriteFile(svgPath, []byte(layoutSVG(monitors, desktop)), 0644); err != nil {
+			return fmt.Errorf("failed to save layout: %w", err)
+		}
+		ui.Printf("✅ Saved layout of %d monitor(s) to: %s\n", len(monitors), svgPath)
+		return nil
+	}
+
+	ui.Printf("\n🗺️  Monitor layout (%dx%d desktop):\n\n", desktop.Dx(), desktop.Dy())
+	ui.Print(renderLayout(monitors, desktop))
+	ui.Println("\n💡 Numbers are what --monitors uses; save an image with --svg layout.svg")
+	return nil
+}
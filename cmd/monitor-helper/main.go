@@ -7,73 +7,111 @@ import (
 	"encoding/json"
 	"fmt"
 	"image"
-	"image/color"
 	"image/draw"
 	"image/png"
+	"math"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/kbinani/screenshot"
 	"github.com/spf13/cobra"
-	"golang.org/x/image/font"
-	"golang.org/x/image/font/basicfont"
-	"golang.org/x/image/math/fixed"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/imaging"
+	"task-tracker/internal/ui"
 )
 
-// MonitorPreset stores saved monitor configurations
-type MonitorPreset struct {
-	Monitors    string `json:"monitors"`
-	Description string `json:"description"`
-	Created     string `json:"created"`
+// MonitorInfo describes a detected monitor for --json output
+type MonitorInfo struct {
+	Number int `json:"number"` // what --monitors takes, from 1
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	capture.Display
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Print all monitors as JSON
+func detectMonitorsJSON() error {
+	displays, _ := capture.Screen{}.Displays()
+	monitors := []MonitorInfo{}
+	for i := 0; i < screenshot.NumActiveDisplays(); i++ {
+		bounds := screenshot.GetDisplayBounds(i)
+		info := MonitorInfo{
+			Number: i + 1,
+			X:      bounds.Min.X,
+			Y:      bounds.Min.Y,
+			Width:  bounds.Dx(),
+			Height: bounds.Dy(),
+		}
+		if i < len(displays) {
+			info.Display = displays[i]
+		}
+		monitors = append(monitors, info)
+	}
+	return printJSON(monitors)
 }
 
 // Detect and display all monitors
 func detectMonitors() {
 	n := screenshot.NumActiveDisplays()
-	fmt.Printf("\n🖥️  Detected %d monitor(s):\n\n", n)
-	fmt.Printf("%-5s %-15s %-20s %-15s\n", "#", "Resolution", "Position", "Size (approx)")
-	fmt.Println("---------------------------------------------------------------")
+	ui.Printf("\n🖥️  Detected %d monitor(s):\n\n", n)
+	table := ui.NewTable("#", "Resolution", "Position", "Size", "PPI", "Scale", "Name", "Model", "EDID ID", "Serial")
+	displays, _ := capture.Screen{}.Displays()
 
 	for i := 0; i < n; i++ {
 		bounds := screenshot.GetDisplayBounds(i)
 		width := bounds.Dx()
 		height := bounds.Dy()
 
-		// Estimate physical size (assuming 96 DPI)
-		widthInches := float64(width) / 96.0
-		heightInches := float64(height) / 96.0
-		diagonal := (widthInches*widthInches + heightInches*heightInches)
-
-		fmt.Printf("%-5d %dx%-10d (%d, %d)%-10s ~%.1f\"\n",
-			i+1, width, height, bounds.Min.X, bounds.Min.Y, "",
-			(widthInches*widthInches + heightInches*heightInches))
-		fmt.Printf("Diagonal width is : %v \n", diagonal)
-	}
+		var d capture.Display
+		if i < len(displays) {
+			d = displays[i]
+		}
 
-	fmt.Println("\n💡 Tips:")
-	fmt.Println("   - Monitor #1 is typically your primary monitor")
-	fmt.Println("   - Position shows where the monitor is in your layout")
-	fmt.Println("   - Use 'monitor-helper test-all' to identify each monitor visually")
-}
+		// Without a reported physical size, estimate it assuming 96 DPI
+		size, ppi, scale := "", "", ""
+		if d.Diagonal > 0 {
+			size = fmt.Sprintf("%.1f\"", d.Diagonal)
+			ppi = fmt.Sprintf("%.0f", d.PPI)
+		} else {
+			size = fmt.Sprintf("~%.1f\"", math.Hypot(float64(width), float64(height))/96)
+		}
+		if d.Scale > 0 {
+			scale = fmt.Sprintf("%.3gx", d.Scale)
+		}
 
-// Add text to image
-func addLabel(img *image.RGBA, text string) {
-	col := color.RGBA{255, 255, 255, 255}
-	point := fixed.Point26_6{X: fixed.I(20), Y: fixed.I(40)}
-
-	// Draw background
-	bgColor := color.RGBA{0, 0, 0, 200}
-	bgRect := image.Rect(10, 10, 600, 80)
-	draw.Draw(img, bgRect, &image.Uniform{bgColor}, image.Point{}, draw.Over)
-
-	// Draw text
-	d := &font.Drawer{
-		Dst:  img,
-		Src:  image.NewUniform(col),
-		Face: basicfont.Face7x13,
-		Dot:  point,
-	}
-	d.DrawString(text)
+		table.AddRow(
+			ui.Bold(fmt.Sprintf("%d", i+1)),
+			fmt.Sprintf("%dx%d", width, height),
+			fmt.Sprintf("(%d, %d)", bounds.Min.X, bounds.Min.Y),
+			size,
+			ppi,
+			scale,
+			ui.Cyan(d.Name),
+			d.Model,
+			ui.Dim(d.ID),
+			ui.Dim(d.Serial),
+		)
+	}
+	table.Render()
+
+	ui.Println("\n💡 Tips:")
+	ui.Println("   - Monitor #1 is typically your primary monitor")
+	ui.Println("   - Position shows where the monitor is in your layout")
+	ui.Println("   - Sizes marked ~ are estimates; the monitor didn't report its size")
+	ui.Println("   - Use 'monitor-helper layout' to see how the monitors are arranged")
+	ui.Println("   - Use 'monitor-helper test-all' to identify each monitor visually")
+	ui.Println("   - --monitors and presets accept a name, model, EDID ID or serial instead of a")
+	ui.Println("     number, which keeps them pointing at the same monitor after docking")
 }
 
 // Capture test screenshot from a specific monitor
@@ -85,7 +123,7 @@ func testCapture(monitorNum int) error {
 	}
 
 	idx := monitorNum - 1
-	fmt.Printf("\n📸 Capturing test screenshot from Monitor %d...\n", monitorNum)
+	ui.Printf("\n📸 Capturing test screenshot from Monitor %d...\n", monitorNum)
 
 	img, err := screenshot.CaptureDisplay(idx)
 	if err != nil {
@@ -98,7 +136,7 @@ func testCapture(monitorNum int) error {
 
 	// Add label
 	text := fmt.Sprintf("Monitor %d Test - %dx%d", monitorNum, bounds.Dx(), bounds.Dy())
-	addLabel(rgba, text)
+	imaging.Label(rgba, text)
 
 	// Save
 	filename := fmt.Sprintf("test_monitor_%d.png", monitorNum)
@@ -112,8 +150,8 @@ func testCapture(monitorNum int) error {
 		return fmt.Errorf("failed to encode PNG: %w", err)
 	}
 
-	fmt.Printf("✅ Saved to: %s\n", filename)
-	fmt.Println("   Open this file to verify you're capturing the correct monitor")
+	ui.Printf("✅ Saved to: %s\n", filename)
+	ui.Println("   Open this file to verify you're capturing the correct monitor")
 
 	return nil
 }
@@ -121,164 +159,83 @@ func testCapture(monitorNum int) error {
 // Test all monitors
 func testAllMonitors() error {
 	n := screenshot.NumActiveDisplays()
-	fmt.Printf("\n📸 Capturing test screenshots from all %d monitors...\n\n", n)
+	ui.Printf("\n📸 Capturing test screenshots from all %d monitors...\n\n", n)
 
 	for i := 1; i <= n; i++ {
 		if err := testCapture(i); err != nil {
-			fmt.Printf("⚠️  Failed to capture monitor %d: %v\n", i, err)
+			ui.Printf("⚠️  Failed to capture monitor %d: %v\n", i, err)
 			continue
 		}
 		time.Sleep(500 * time.Millisecond)
 	}
 
-	fmt.Printf("\n✅ Created %d test screenshots\n", n)
-	fmt.Println("   Review them to identify which monitor is which")
+	ui.Printf("\n✅ Created %d test screenshots\n", n)
+	ui.Println("   Review them to identify which monitor is which")
 
 	return nil
 }
 
-// Save a preset
-func savePreset(name, monitors, description string) error {
-	presetsFile := "monitor_presets.json"
-
-	// Load existing presets
-	presets := make(map[string]MonitorPreset)
-	if data, err := os.ReadFile(presetsFile); err == nil {
-		json.Unmarshal(data, &presets)
-	}
-
-	// Add new preset
-	presets[name] = MonitorPreset{
-		Monitors:    monitors,
-		Description: description,
-		Created:     time.Now().Format("2006-01-02 15:04:05"),
-	}
-
-	// Save
-	data, err := json.MarshalIndent(presets, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal presets: %w", err)
-	}
-
-	if err := os.WriteFile(presetsFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to save presets: %w", err)
-	}
-
-	fmt.Printf("✅ Saved preset '%s': monitors=%s\n", name, monitors)
-	if description != "" {
-		fmt.Printf("   Description: %s\n", description)
-	}
-
-	return nil
-}
-
-// List all presets
-func listPresets() error {
-	presetsFile := "monitor_presets.json"
-
-	data, err := os.ReadFile(presetsFile)
-	if err != nil {
-		fmt.Println("\n📋 No presets saved yet")
-		fmt.Println("\nCreate a preset with:")
-		fmt.Println("  monitor-helper preset <name> <monitors> [description]")
-		return nil
-	}
-
-	var presets map[string]MonitorPreset
-	if err := json.Unmarshal(data, &presets); err != nil {
-		return fmt.Errorf("failed to parse presets: %w", err)
-	}
-
-	if len(presets) == 0 {
-		fmt.Println("\n📋 No presets saved yet")
-		return nil
-	}
-
-	fmt.Println("\n📋 Saved Monitor Presets:")
-	for name, preset := range presets {
-		fmt.Printf("  • %s\n", name)
-		fmt.Printf("    Monitors: %s\n", preset.Monitors)
-		if preset.Description != "" {
-			fmt.Printf("    Description: %s\n", preset.Description)
+// readLine reads a line from stdin a byte at a time, so the fmt.Scanln
+// calls around it still see the rest of the input
+func readLine() string {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n == 0 || err != nil || buf[0] == '\n' {
+			break
 		}
-		fmt.Printf("    Created: %s\n\n", preset.Created)
-	}
-
-	fmt.Println("💡 Use a preset with:")
-	fmt.Println("  task-tracker start 'Task name' --monitors <monitors>")
-
-	return nil
-}
-
-// Get preset monitors config
-func getPreset(name string) {
-	presetsFile := "monitor_presets.json"
-
-	data, err := os.ReadFile(presetsFile)
-	if err != nil {
-		fmt.Println("all") // Default fallback
-		return
-	}
-
-	var presets map[string]MonitorPreset
-	if err := json.Unmarshal(data, &presets); err != nil {
-		fmt.Println("all")
-		return
-	}
-
-	if preset, ok := presets[name]; ok {
-		fmt.Println(preset.Monitors)
-	} else {
-		fmt.Println("all")
+		line = append(line, buf[0])
 	}
+	return strings.TrimSpace(string(line))
 }
 
 // Interactive setup wizard
 func interactiveSetup() error {
-	fmt.Println("\n" + "================================================================")
-	fmt.Println("  🎯 Task Tracker - Monitor Setup Wizard")
-	fmt.Println("================================================================")
+	ui.Println("\n" + "================================================================")
+	ui.Println("  🎯 Task Tracker - Monitor Setup Wizard")
+	ui.Println("================================================================")
 
 	// Step 1: Detect monitors
 	detectMonitors()
 
 	n := screenshot.NumActiveDisplays()
 	if n == 1 {
-		fmt.Println("\n✅ You have 1 monitor. No configuration needed!")
-		fmt.Println("   Just use: task-tracker start 'Task name'")
+		ui.Println("\n✅ You have 1 monitor. No configuration needed!")
+		ui.Println("   Just use: task-tracker start 'Task name'")
 		return nil
 	}
 
 	// Step 2: Test captures
-	fmt.Println("\n" + "----------------------------------------------------------------")
-	fmt.Println("Step 1: Let's test each monitor to identify them")
-	fmt.Println("----------------------------------------------------------------")
+	ui.Println("\n" + "----------------------------------------------------------------")
+	ui.Println("Step 1: Let's test each monitor to identify them")
+	ui.Println("----------------------------------------------------------------")
 
-	fmt.Print("\nPress Enter to capture test screenshots from all monitors...")
+	ui.Print("\nPress Enter to capture test screenshots from all monitors...")
 	fmt.Scanln()
 
 	if err := testAllMonitors(); err != nil {
 		return err
 	}
 
-	fmt.Println("\n✅ Please review the test_monitor_*.png files to identify each monitor")
-	fmt.Print("\nPress Enter when ready to continue...")
+	ui.Println("\n✅ Please review the test_monitor_*.png files to identify each monitor")
+	ui.Print("\nPress Enter when ready to continue...")
 	fmt.Scanln()
 
 	// Step 3: Create presets
-	fmt.Println("\n" + "----------------------------------------------------------------")
-	fmt.Println("Step 2: Let's create some useful presets")
-	fmt.Println("----------------------------------------------------------------")
+	ui.Println("\n" + "----------------------------------------------------------------")
+	ui.Println("Step 2: Let's create some useful presets")
+	ui.Println("----------------------------------------------------------------")
 
-	fmt.Println("\n💡 Common multi-monitor workflows:")
-	fmt.Println("   • Coding: Code editor + browser/docs")
-	fmt.Println("   • Design: Design tool + references")
-	fmt.Println("   • Meeting: Video call + notes")
-	fmt.Println("   • Testing: Code + browser + terminal")
+	ui.Println("\n💡 Common multi-monitor workflows:")
+	ui.Println("   • Coding: Code editor + browser/docs")
+	ui.Println("   • Design: Design tool + references")
+	ui.Println("   • Meeting: Video call + notes")
+	ui.Println("   • Testing: Code + browser + terminal")
 
 	for {
-		fmt.Println("\n" + "----------------------------------------------------------------")
-		fmt.Print("\nWould you like to create a preset? (y/n): ")
+		ui.Println("\n" + "----------------------------------------------------------------")
+		ui.Print("\nWould you like to create a preset? (y/n): ")
 
 		var create string
 		fmt.Scanln(&create)
@@ -287,53 +244,48 @@ func interactiveSetup() error {
 			break
 		}
 
-		fmt.Print("Preset name (e.g., 'coding', 'design', 'meeting'): ")
+		ui.Print("Preset name (e.g., 'coding', 'design', 'meeting'): ")
 		var name string
 		fmt.Scanln(&name)
 
 		if name == "" {
-			fmt.Println("❌ Preset name cannot be empty")
+			ui.Println("❌ Preset name cannot be empty")
 			continue
 		}
 
-		fmt.Println("\nWhich monitors for '" + name + "'?")
-		fmt.Println("  Examples: all, primary, 1, 1,2, 2,3")
-		fmt.Print("Monitors: ")
-		var monitors string
-		fmt.Scanln(&monitors)
+		ui.Println("\nWhich monitors for '" + name + "'?")
+		ui.Println("  Examples: all, primary, 1, 1,2, 2,3, DP-1, DELL U2720Q")
+		ui.Print("Monitors: ")
+		// Names can have spaces, like "DELL U2720Q"
+		monitors := readLine()
 		if monitors == "" {
 			monitors = "all"
 		}
 
-		fmt.Print("Description (optional): ")
-		var description string
-		fmt.Scanln(&description)
+		ui.Print("Description (optional): ")
+		description := readLine()
 
 		if err := savePreset(name, monitors, description); err != nil {
-			fmt.Printf("❌ Failed to save preset: %v\n", err)
+			ui.Printf("❌ Failed to save preset: %v\n", err)
 		}
 	}
 
 	// Step 4: Summary
-	fmt.Println("\n" + "================================================================")
-	fmt.Println("  ✅ Setup Complete!")
-	fmt.Println("================================================================")
+	ui.Println("\n" + "================================================================")
+	ui.Println("  ✅ Setup Complete!")
+	ui.Println("================================================================")
 
-	listPresets()
+	listPresets(false)
 
-	fmt.Println("\n🎉 You're all set! Try it out:")
-	fmt.Println("  task-tracker start 'My task' --monitors all")
+	ui.Println("\n🎉 You're all set! Try it out:")
+	ui.Println("  task-tracker start 'My task' --monitors all")
 
 	// Show preset example if any exist
-	presetsFile := "monitor_presets.json"
-	if data, err := os.ReadFile(presetsFile); err == nil {
-		var presets map[string]MonitorPreset
-		if json.Unmarshal(data, &presets) == nil && len(presets) > 0 {
-			for name, preset := range presets {
-				fmt.Printf("  task-tracker start 'My task' --monitors %s  # Using '%s' preset\n",
-					preset.Monitors, name)
-				break
-			}
+	if presets, err := loadPresets(); err == nil {
+		for name, preset := range presets {
+			ui.Printf("  task-tracker start 'My task' --monitors %s  # Using '%s' preset\n",
+				preset.Monitors, name)
+			break
 		}
 	}
 
@@ -352,9 +304,31 @@ func main() {
 		Use:   "detect",
 		Short: "Detect and show all monitors",
 		Run: func(cmd *cobra.Command, args []string) {
+			if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+				if err := detectMonitorsJSON(); err != nil {
+					ui.Printf("❌ Error: %v\n", err)
+					os.Exit(1)
+				}
+				return
+			}
 			detectMonitors()
 		},
 	}
+	detectCmd.Flags().Bool("json", false, "Print the monitors as JSON")
+
+	// Layout command
+	var layoutCmd = &cobra.Command{
+		Use:   "layout",
+		Short: "Draw where monitors are relative to each other",
+		Run: func(cmd *cobra.Command, args []string) {
+			svg, _ := cmd.Flags().GetString("svg")
+			if err := showLayout(svg); err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+	layoutCmd.Flags().String("svg", "", "Write the layout as an SVG image to this file")
 
 	// Test command
 	var testCmd = &cobra.Command{
@@ -363,8 +337,8 @@ func main() {
 		Args:  cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) == 0 {
-				fmt.Println("Usage: monitor-helper test <monitor_num>")
-				fmt.Println("   or: monitor-helper test-all")
+				ui.Println("Usage: monitor-helper test <monitor_num>")
+				ui.Println("   or: monitor-helper test-all")
 				return
 			}
 
@@ -372,7 +346,7 @@ func main() {
 			fmt.Sscanf(args[0], "%d", &monitorNum)
 
 			if err := testCapture(monitorNum); err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
+				ui.Printf("❌ Error: %v\n", err)
 				os.Exit(1)
 			}
 		},
@@ -384,7 +358,7 @@ func main() {
 		Short: "Capture test screenshots from all monitors",
 		Run: func(cmd *cobra.Command, args []string) {
 			if err := testAllMonitors(); err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
+				ui.Printf("❌ Error: %v\n", err)
 				os.Exit(1)
 			}
 		},
@@ -404,7 +378,7 @@ func main() {
 			}
 
 			if err := savePreset(name, monitors, description); err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
+				ui.Printf("❌ Error: %v\n", err)
 				os.Exit(1)
 			}
 		},
@@ -415,22 +389,111 @@ func main() {
 		Use:   "list",
 		Short: "List all saved presets",
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := listPresets(); err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if err := listPresets(asJSON); err != nil {
+				ui.Printf("❌ Error: %v\n", err)
 				os.Exit(1)
 			}
 		},
 	}
+	listCmd.Flags().Bool("json", false, "Print the presets as JSON")
 
 	// Get command
 	var getCmd = &cobra.Command{
-		Use:   "get <preset_name>",
-		Short: "Get monitors config from preset",
+		Use:   "get [preset_name]",
+		Short: "Get monitors config from preset (the default preset without a name)",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			asJSON, _ := cmd.Flags().GetBool("json")
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			if err := getPreset(name, asJSON); err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+	getCmd.Flags().Bool("json", false, "Print the preset as JSON, with found=false when falling back to all")
+
+	// Edit command
+	var editCmd = &cobra.Command{
+		Use:   "edit <name>",
+		Short: "Change a preset's monitors or description",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			getPreset(args[0])
+			var monitors, description *string
+			if cmd.Flags().Changed("monitors") {
+				v, _ := cmd.Flags().GetString("monitors")
+				monitors = &v
+			}
+			if cmd.Flags().Changed("description") {
+				v, _ := cmd.Flags().GetString("description")
+				description = &v
+			}
+			if monitors == nil && description == nil {
+				ui.Println("❌ Error: give --monitors and/or --description")
+				os.Exit(1)
+			}
+			if err := editPreset(args[0], monitors, description); err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
 		},
 	}
+	editCmd.Flags().StringP("monitors", "m", "", "New monitors (all, primary, 1,2, DP-1, etc.)")
+	editCmd.Flags().StringP("description", "d", "", "New description")
+
+	// Delete command
+	var deleteCmd = &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a preset",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := deletePreset(args[0]); err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	// Rename command
+	var renameCmd = &cobra.Command{
+		Use:   "rename <name> <new_name>",
+		Short: "Rename a preset",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := renamePreset(args[0], args[1]); err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	// Default command
+	var defaultCmd = &cobra.Command{
+		Use:   "default [name]",
+		Short: "Show or set the preset get returns without a name",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			clear, _ := cmd.Flags().GetBool("clear")
+			switch {
+			case clear:
+				err = setDefaultPreset("")
+			case len(args) == 1:
+				err = setDefaultPreset(args[0])
+			default:
+				err = showDefaultPreset()
+			}
+			if err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+	defaultCmd.Flags().Bool("clear", false, "Clear the default preset")
 
 	// Setup command
 	var setupCmd = &cobra.Command{
@@ -438,22 +501,29 @@ func main() {
 		Short: "Interactive setup wizard",
 		Run: func(cmd *cobra.Command, args []string) {
 			if err := interactiveSetup(); err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
+				ui.Printf("❌ Error: %v\n", err)
 				os.Exit(1)
 			}
 		},
 	}
 
+	ui.BindFlags(rootCmd)
+
 	rootCmd.AddCommand(detectCmd)
+	rootCmd.AddCommand(layoutCmd)
 	rootCmd.AddCommand(testCmd)
 	rootCmd.AddCommand(testAllCmd)
 	rootCmd.AddCommand(presetCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(getCmd)
+	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(deleteCmd)
+	rootCmd.AddCommand(renameCmd)
+	rootCmd.AddCommand(defaultCmd)
 	rootCmd.AddCommand(setupCmd)
 
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		ui.Println(err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kbinani/screenshot"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/paths"
+	"task-tracker/internal/ui"
+)
+
+// legacyPresetsFile is where presets were saved before they moved next to
+// task-tracker's config, relative to the directory monitor-helper ran in
+const legacyPresetsFile = "monitor_presets.json"
+
+// MonitorPreset stores saved monitor configurations
+type MonitorPreset struct {
+	Monitors    string `json:"monitors"`
+	Description string `json:"description"`
+	Created     string `json:"created"`
+	Default     bool   `json:"default,omitempty"` // returned by get without a name
+}
+
+// namedPreset is a preset with its name, for --json output
+type namedPreset struct {
+	Name string `json:"name"`
+	MonitorPreset
+}
+
+// notice prints to stderr, where it can't end up in a captured preset
+func notice(format string, a ...any) {
+	s := fmt.Sprintf(format, a...)
+	if ui.Plain() {
+		s = ui.Strip(s)
+	}
+	fmt.Fprint(os.Stderr, s)
+}
+
+// presetsPath returns where presets are saved, moving a preset file from
+// the current directory there the first time it's used
+func presetsPath() (string, error) {
+	path, err := paths.Presets()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(legacyPresetsFile); err != nil {
+		return path, nil
+	}
+	if abs, err := filepath.Abs(legacyPresetsFile); err == nil && abs == path {
+		return path, nil
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		notice("⚠️  Ignoring ./%s; presets are now kept in %s\n", legacyPresetsFile, path)
+		return path, nil
+	}
+	data, err := os.ReadFile(legacyPresetsFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read presets: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to move presets: %w", err)
+	}
+	os.Remove(legacyPresetsFile)
+	notice("📦 Moved presets from ./%s to %s\n", legacyPresetsFile, path)
+	return path, nil
+}
+
+// loadPresets reads the saved presets; a missing file means none
+func loadPresets() (map[string]MonitorPreset, error) {
+	path, err := presetsPath()
+	if err != nil {
+		return nil, err
+	}
+	presets := make(map[string]MonitorPreset)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return presets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presets: %w", err)
+	}
+	if err := json.Unmarshal(data, &presets); err != nil {
+		return nil, fmt.Errorf("failed to parse presets: %w", err)
+	}
+	return presets, nil
+}
+
+// writePresets replaces the saved presets
+func writePresets(presets map[string]MonitorPreset) error {
+	path, err := presetsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(presets, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal presets: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save presets: %w", err)
+	}
+	return nil
+}
+
+// findPreset loads the presets and checks that name is one of them
+func findPreset(name string) (map[string]MonitorPreset, error) {
+	presets, err := loadPresets()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := presets[name]; !ok {
+		return nil, fmt.Errorf("no preset named '%s' (see 'monitor-helper list')", name)
+	}
+	return presets, nil
+}
+
+// defaultPreset returns the name of the default preset, if one is set
+func defaultPreset(presets map[string]MonitorPreset) (string, bool) {
+	for name, preset := range presets {
+		if preset.Default {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// checkMonitors checks a monitors spec against the attached displays and
+// returns what wouldn't resolve right now. A spec task-tracker would reject
+// is an error; anything else is only a warning, since presets are often
+// made for monitors that are only attached when docked.
+func checkMonitors(spec string) ([]string, error) {
+	items, err := capture.ParseMonitors(spec)
+	if err != nil {
+		return nil, err
+	}
+	if items[0] == "all" || items[0] == "primary" {
+		return nil, nil
+	}
+
+	n := screenshot.NumActiveDisplays()
+	displays, _ := capture.Screen{}.Displays()
+	var problems []string
+	for _, part := range items {
+		if num, err := strconv.Atoi(part); err == nil {
+			if num > n {
+				problems = append(problems, fmt.Sprintf("monitor %d isn't attached (%d are)", num, n))
+			}
+			continue
+		}
+		found := false
+		for _, d := range displays {
+			if d.Matches(part) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("no attached monitor named '%s'", part))
+		}
+	}
+	return problems, nil
+}
+
+// validateMonitors checks a spec before it's saved, printing warnings for
+// monitors that aren't attached
+func validateMonitors(spec string) error {
+	problems, err := checkMonitors(spec)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		ui.Printf("⚠️  %s; task-tracker start will fail with this preset until it's attached\n", p)
+	}
+	return nil
+}
+
+// Save a preset
+func savePreset(name, monitors, description string) error {
+	if err := validateMonitors(monitors); err != nil {
+		return err
+	}
+
+	// Load existing presets
+	presets, err := loadPresets()
+	if err != nil {
+		return err
+	}
+
+	// Add new preset, staying the default if it was
+	presets[name] = MonitorPreset{
+		Monitors:    monitors,
+		Description: description,
+		Created:     time.Now().Format("2006-01-02 15:04:05"),
+		Default:     presets[name].Default,
+	}
+
+	if err := writePresets(presets); err != nil {
+		return err
+	}
+
+	ui.Printf("✅ Saved preset '%s': monitors=%s\n", name, monitors)
+	if description != "" {
+		ui.Printf("   Description: %s\n", description)
+	}
+
+	return nil
+}
+
+// Edit a preset's monitors or description; nil leaves a field as it is
+func editPreset(name string, monitors, description *string) error {
+	presets, err := findPreset(name)
+	if err != nil {
+		return err
+	}
+	preset := presets[name]
+	if monitors != nil {
+		if err := validateMonitors(*monitors); err != nil {
+			return err
+		}
+		preset.Monitors = *monitors
+	}
+	if description != nil {
+		preset.Description = *description
+	}
+	presets[name] = preset
+
+	if err := writePresets(presets); err != nil {
+		return err
+	}
+	ui.Printf("✅ Updated preset '%s': monitors=%s\n", name, preset.Monitors)
+	if preset.Description != "" {
+		ui.Printf("   Description: %s\n", preset.Description)
+	}
+	return nil
+}
+
+// Delete a preset
+func deletePreset(name string) error {
+	presets, err := findPreset(name)
+	if err != nil {
+		return err
+	}
+	wasDefault := presets[name].Default
+	delete(presets, name)
+
+	if err := writePresets(presets); err != nil {
+		return err
+	}
+	ui.Printf("🗑️  Deleted preset '%s'\n", name)
+	if wasDefault {
+		ui.Println("   It was the default; get without a name now returns 'all'")
+	}
+	return nil
+}
+
+// Rename a preset, keeping its settings
+func renamePreset(oldName, newName string) error {
+	presets, err := findPreset(oldName)
+	if err != nil {
+		return err
+	}
+	if _, exists := presets[newName]; exists {
+		return fmt.Errorf("a preset named '%s' already exists", newName)
+	}
+	presets[newName] = presets[oldName]
+	delete(presets, oldName)
+
+	if err := writePresets(presets); err != nil {
+		return err
+	}
+	ui.Printf("✅ Renamed preset '%s' to '%s'\n", oldName, newName)
+	return nil
+}
+
+// Set the default preset, or clear it when name is empty
+func setDefaultPreset(name string) error {
+	var presets map[string]MonitorPreset
+	var err error
+	if name == "" {
+		presets, err = loadPresets()
+	} else {
+		presets, err = findPreset(name)
+	}
+	if err != nil {
+		return err
+	}
+
+	for n, preset := range presets {
+		preset.Default = n == name
+		presets[n] = preset
+	}
+	if err := writePresets(presets); err != nil {
+		return err
+	}
+
+	if name == "" {
+		ui.Println("✅ Cleared the default preset")
+	} else {
+		ui.Printf("✅ '%s' is now the default preset: monitors=%s\n", name, presets[name].Monitors)
+	}
+	return nil
+}
+
+// Show the default preset
+func showDefaultPreset() error {
+	presets, err := loadPresets()
+	if err != nil {
+		return err
+	}
+	name, ok := defaultPreset(presets)
+	if !ok {
+		ui.Println("📋 No default preset; set one with: monitor-helper default <name>")
+		return nil
+	}
+	ui.Printf("📋 Default preset: %s (monitors=%s)\n", ui.Bold(name), presets[name].Monitors)
+	return nil
+}
+
+// List all presets
+func listPresets(asJSON bool) error {
+	presets, err := loadPresets()
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		list := []namedPreset{}
+		for name, preset := range presets {
+			list = append(list, namedPreset{Name: name, MonitorPreset: preset})
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		return printJSON(list)
+	}
+
+	if len(presets) == 0 {
+		ui.Println("\n📋 No presets saved yet")
+		ui.Println("\nCreate a preset with:")
+		ui.Println("  monitor-helper preset <name> <monitors> [description]")
+		return nil
+	}
+
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ui.Print("\n📋 Saved Monitor Presets:\n\n")
+	table := ui.NewTable("Name", "Monitors", "Description", "Created", "Attached")
+	for _, name := range names {
+		preset := presets[name]
+		label := ui.Bold(name)
+		if preset.Default {
+			label += ui.Dim(" (default)")
+		}
+		status := ui.Green("yes")
+		if problems, err := checkMonitors(preset.Monitors); err != nil {
+			status = ui.Red(err.Error())
+		} else if len(problems) > 0 {
+			status = ui.Yellow(strings.Join(problems, "; "))
+		}
+		table.AddRow(label, ui.Cyan(preset.Monitors), preset.Description, ui.Dim(preset.Created), status)
+	}
+	table.Render()
+	ui.Println()
+
+	ui.Println("💡 Use a preset with:")
+	ui.Println("  task-tracker start 'Task name' --monitors <monitors>")
+	ui.Println("  task-tracker start 'Task name' --monitors \"$(monitor-helper get)\"  # the default preset")
+
+	return nil
+}
+
+// Get preset monitors config, falling back to all monitors. An empty name
+// gets the default preset.
+func getPreset(name string, asJSON bool) error {
+	presets, err := loadPresets()
+	if err != nil && asJSON {
+		return err
+	}
+	if name == "" {
+		name, _ = defaultPreset(presets)
+	}
+	preset, ok := presets[name]
+	if !ok {
+		preset.Monitors = "all" // Default fallback
+	}
+
+	if asJSON {
+		return printJSON(struct {
+			namedPreset
+			Found bool `json:"found"`
+		}{namedPreset{Name: name, MonitorPreset: preset}, ok})
+	}
+	ui.Println(preset.Monitors)
+	return nil
+}
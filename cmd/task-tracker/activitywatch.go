@@ -0,0 +1,361 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+const (
+	defaultAWServer = "http://localhost:5600"
+
+	// awMinEvent drops window events shorter than this on import
+	awMinEvent = 5 * time.Second
+
+	// awTimelineMin is the shortest activity shown in the review timeline
+	awTimelineMin = time.Minute
+)
+
+// ActivityEvent is a window or away-from-keyboard period imported from
+// ActivityWatch
+type ActivityEvent struct {
+	Start           string  `json:"start"`
+	RelativeTime    float64 `json:"relative_time"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	App             string  `json:"app,omitempty"`
+	Title           string  `json:"title,omitempty"`
+	AFK             bool    `json:"afk,omitempty"`
+}
+
+// awClient talks to the ActivityWatch server REST API
+type awClient struct {
+	base string
+	http *http.Client
+}
+
+type awBucket struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Client   string `json:"client"`
+	Hostname string `json:"hostname"`
+}
+
+type awEvent struct {
+	ID        int64          `json:"id,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+	Duration  float64        `json:"duration"`
+	Data      map[string]any `json:"data"`
+}
+
+func newAWClient(server string) *awClient {
+	return &awClient{
+		base: strings.TrimSuffix(server, "/") + "/api/0",
+		http: &http.Client{Timeout: 10 * time.Second, Transport: httpTransport()},
+	}
+}
+
+// do sends a request and decodes a JSON response into out, if non-nil
+func (c *awClient) do(method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.base+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent())
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: ActivityWatch not reachable at %s: %v", errIntegration, c.base, err)
+	}
+	defer resp.Body.Close()
+
+	// 304 is returned when creating a bucket that already exists
+	if resp.StatusCode == http.StatusNotModified {
+		return nil
+	}
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: ActivityWatch %s %s: %s %s", errIntegration, method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: invalid ActivityWatch response: %v", errIntegration, err)
+	}
+	return nil
+}
+
+func (c *awClient) buckets() (map[string]awBucket, error) {
+	buckets := map[string]awBucket{}
+	err := c.do("GET", "/buckets/", nil, &buckets)
+	return buckets, err
+}
+
+func (c *awClient) events(bucket string, start, end time.Time) ([]awEvent, error) {
+	q := url.Values{}
+	q.Set("start", start.Format(time.RFC3339))
+	q.Set("end", end.Format(time.RFC3339))
+	q.Set("limit", "-1")
+
+	var events []awEvent
+	err := c.do("GET", "/buckets/"+url.PathEscape(bucket)+"/events?"+q.Encode(), nil, &events)
+	return events, err
+}
+
+// findBucket picks the bucket of a type, preferring this machine's
+func findBucket(buckets map[string]awBucket, kind string) (string, bool) {
+	host, _ := os.Hostname()
+	found := ""
+	for id, b := range buckets {
+		if b.Type != kind {
+			continue
+		}
+		if b.Hostname == host {
+			return id, true
+		}
+		found = id
+	}
+	return found, found != ""
+}
+
+// importActivity replaces the session's activity with ActivityWatch's
+// window and AFK events during the session
+func (t *TaskTracker) importActivity(c *awClient) error {
+	buckets, err := c.buckets()
+	if err != nil {
+		return err
+	}
+
+	start, end := t.StartTime, t.EndTime
+	activity := []ActivityEvent{}
+
+	if id, ok := findBucket(buckets, "currentwindow"); ok {
+		events, err := c.events(id, start, end)
+		if err != nil {
+			return err
+		}
+		for _, e := range mergeWindowEvents(events) {
+			if time.Duration(e.Duration*float64(time.Second)) < awMinEvent {
+				continue
+			}
+			activity = append(activity, t.activityEvent(e, false))
+		}
+	}
+
+	if id, ok := findBucket(buckets, "afkstatus"); ok {
+		events, err := c.events(id, start, end)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			if status, _ := e.Data["status"].(string); status == "afk" {
+				activity = append(activity, t.activityEvent(e, true))
+			}
+		}
+	}
+
+	if len(activity) == 0 {
+		return fmt.Errorf("%w: no window or AFK events found in ActivityWatch for this session", errIntegration)
+	}
+
+	sort.SliceStable(activity, func(i, j int) bool {
+		return activity[i].RelativeTime < activity[j].RelativeTime
+	})
+	t.Activity = activity
+	return nil
+}
+
+func (t *TaskTracker) activityEvent(e awEvent, afk bool) ActivityEvent {
+	app, _ := e.Data["app"].(string)
+	title, _ := e.Data["title"].(string)
+	return ActivityEvent{
+		Start:           e.Timestamp.Format(time.RFC3339),
+		RelativeTime:    e.Timestamp.Sub(t.StartTime).Seconds(),
+		DurationSeconds: e.Duration,
+		App:             app,
+		Title:           title,
+		AFK:             afk,
+	}
+}
+
+// mergeWindowEvents joins back-to-back events of the same app, since AW
+// splits them on every title change
+func mergeWindowEvents(events []awEvent) []awEvent {
+	sort.Slice(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+
+	merged := []awEvent{}
+	for _, e := range events {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			lastEnd := last.Timestamp.Add(time.Duration(last.Duration * float64(time.Second)))
+			if last.Data["app"] == e.Data["app"] && e.Timestamp.Sub(lastEnd) < awMinEvent {
+				end := e.Timestamp.Add(time.Duration(e.Duration * float64(time.Second)))
+				last.Duration = end.Sub(last.Timestamp).Seconds()
+				continue
+			}
+		}
+		merged = append(merged, e)
+	}
+	return merged
+}
+
+// awBucketID is the bucket task-tracker exports to
+func awBucketID() string {
+	host, _ := os.Hostname()
+	return "task-tracker_" + host
+}
+
+// exportActivity writes the session to ActivityWatch as one event per
+// stretch of capture between gaps, so AW doesn't show the task during
+// sleep or lock. Events from an earlier export of the session are replaced.
+func (t *TaskTracker) exportActivity(c *awClient) (int, error) {
+	host, _ := os.Hostname()
+	bucket := awBucketID()
+	err := c.do("POST", "/buckets/"+url.PathEscape(bucket), awBucket{
+		ID: bucket, Client: "task-tracker", Type: "app.task-tracker.session", Hostname: host,
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	existing, err := c.events(bucket, t.StartTime.Add(-time.Minute), t.EndTime.Add(time.Minute))
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range existing {
+		if e.Data["session_id"] == t.SessionID {
+			if err := c.do("DELETE", fmt.Sprintf("/buckets/%s/events/%d", url.PathEscape(bucket), e.ID), nil, nil); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	data := map[string]any{
+		"session_id": t.SessionID,
+		"task":       t.TaskName,
+	}
+	if t.Ticket != nil {
+		data["ticket"] = t.Ticket.String()
+	}
+	if len(t.Tags) > 0 {
+		data["tags"] = t.Tags
+	}
+
+	events := []awEvent{}
+	segStart := t.StartTime
+	for _, gap := range t.Gaps {
+		gapStart, _ := time.Parse(time.RFC3339, gap.Start)
+		gapEnd, _ := time.Parse(time.RFC3339, gap.End)
+		if gapStart.After(segStart) {
+			events = append(events, awEvent{Timestamp: segStart, Duration: gapStart.Sub(segStart).Seconds(), Data: data})
+		}
+		segStart = gapEnd
+	}
+	if t.EndTime.After(segStart) {
+		events = append(events, awEvent{Timestamp: segStart, Duration: t.EndTime.Sub(segStart).Seconds(), Data: data})
+	}
+
+	if err := c.do("POST", "/buckets/"+url.PathEscape(bucket)+"/events", events, nil); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+// newActivityWatchCmd builds the activitywatch command group
+func newActivityWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "activitywatch",
+		Aliases: []string{"aw"},
+		Short:   "Import from and export to ActivityWatch",
+		Long: `Exchange data with a running ActivityWatch server (https://activitywatch.net).
+
+import adds AW's window and AFK events during a session to its timeline, so the
+review file shows which apps were in use. export writes the session to the
+task-tracker_<hostname> bucket, split at capture gaps so it lines up with AW's
+own AFK data. Re-running either replaces what it wrote before.`,
+	}
+
+	server := os.Getenv("AW_SERVER")
+	if server == "" {
+		server = defaultAWServer
+	}
+	cmd.PersistentFlags().String("server", server, "ActivityWatch server URL (or set AW_SERVER)")
+
+	importCmd := &cobra.Command{
+		Use:   "import [session_id]",
+		Short: "Add ActivityWatch window and AFK events to a session",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			serverURL, _ := cmd.Flags().GetString("server")
+			if err := tracker.importActivity(newAWClient(serverURL)); err != nil {
+				ui.Printf("❌ Import failed: %v\n", err)
+				exit(exitCode(err))
+			}
+			if err := tracker.saveMetadata(); err != nil {
+				ui.Printf("❌ Failed to save metadata: %v\n", err)
+				exit(exitCode(err))
+			}
+			if fileExists(filepath.Join(tracker.SessionDir, "review.md")) {
+				if err := tracker.GenerateReviewFile(5); err != nil {
+					ui.Printf("⚠️  Failed to regenerate review file: %v\n", err)
+				}
+			}
+
+			ui.Printf("✅ Imported %d activity event(s) into %s\n", len(tracker.Activity), tracker.SessionID)
+		},
+	}
+
+	exportCmd := &cobra.Command{
+		Use:   "export [session_id]",
+		Short: "Write a session to ActivityWatch",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			serverURL, _ := cmd.Flags().GetString("server")
+			n, err := tracker.exportActivity(newAWClient(serverURL))
+			if err != nil {
+				ui.Printf("❌ Export failed: %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("✅ Exported %s as %d event(s) to bucket %s\n", tracker.SessionID, n, awBucketID())
+		},
+	}
+
+	cmd.AddCommand(importCmd, exportCmd)
+	return cmd
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/i18n"
+)
+
+// aggregatesFile caches per-session totals for reports over many sessions,
+// kept in capturesDir
+const aggregatesFile = "aggregates.json"
+
+// aggregatesVersion is bumped whenever what's cached changes, so old caches
+// are rebuilt instead of read with missing fields
+const aggregatesVersion = 2
+
+// SessionAggregates are the totals of one session that summaries need,
+// computed once and reused until its metadata.json changes
+type SessionAggregates struct {
+	SessionID     string             `json:"session_id"`
+	TaskName      string             `json:"task_name"`
+	Ticket        string             `json:"ticket,omitempty"`
+	Tags          []string           `json:"tags,omitempty"`
+	StartTime     time.Time          `json:"start_time"`
+	ActiveMinutes float64            `json:"active_minutes"`
+	WallMinutes   float64            `json:"wall_minutes"`
+	Screenshots   int                `json:"screenshots"`
+	Apps          map[string]float64 `json:"apps,omitempty"`       // minutes per ActivityWatch app
+	Categories    map[string]float64 `json:"categories,omitempty"` // minutes per RescueTime category
+	Metrics       *WorkMetrics       `json:"metrics,omitempty"`
+
+	// metadata.json as it was when these were computed
+	ModTime int64 `json:"mod_time"`
+	Size    int64 `json:"size"`
+}
+
+// aggregateCache is the on-disk cache, keyed by session ID
+type aggregateCache struct {
+	Version  int                           `json:"version"`
+	Sessions map[string]*SessionAggregates `json:"sessions"`
+}
+
+// aggregate computes a session's totals
+func aggregate(t *TaskTracker) *SessionAggregates {
+	a := &SessionAggregates{
+		SessionID:     t.SessionID,
+		TaskName:      t.TaskName,
+		Tags:          t.Tags,
+		StartTime:     t.StartTime,
+		ActiveMinutes: t.activeDuration().Minutes(),
+		WallMinutes:   t.wallDuration().Minutes(),
+		Screenshots:   len(t.Screenshots),
+		Apps:          appUsage(t),
+		Categories:    categoryUsage(t),
+		Metrics:       t.Metrics,
+	}
+	if t.Ticket != nil {
+		a.Ticket = t.Ticket.String()
+	}
+	return a
+}
+
+// loadAggregates returns the totals of every saved session, oldest first.
+// Sessions whose metadata.json changed since they were cached (or that
+// aren't cached yet) are loaded and recomputed, and the cache is updated.
+func loadAggregates() ([]*SessionAggregates, error) {
+	entries, err := os.ReadDir(capturesDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", capturesDir, err)
+	}
+
+	cachePath := filepath.Join(capturesDir, aggregatesFile)
+	cache := aggregateCache{}
+	if data, err := os.ReadFile(cachePath); err == nil {
+		json.Unmarshal(data, &cache)
+	}
+	if cache.Version != aggregatesVersion || cache.Sessions == nil {
+		cache = aggregateCache{Version: aggregatesVersion, Sessions: map[string]*SessionAggregates{}}
+	}
+
+	changed := false
+	current := map[string]bool{}
+	var result []*SessionAggregates
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(capturesDir, entry.Name(), "metadata.json"))
+		if err != nil {
+			continue // still running or not a session
+		}
+		id := entry.Name()
+		current[id] = true
+
+		a := cache.Sessions[id]
+		if a == nil || a.ModTime != info.ModTime().UnixNano() || a.Size != info.Size() {
+			t, err := loadSession(id)
+			if err != nil {
+				continue
+			}
+			a = aggregate(t)
+			// Loading converts old sessions, which rewrites metadata.json
+			if info, err = os.Stat(filepath.Join(t.SessionDir, "metadata.json")); err != nil {
+				continue
+			}
+			a.ModTime, a.Size = info.ModTime().UnixNano(), info.Size()
+			cache.Sessions[id] = a
+			changed = true
+		}
+		result = append(result, a)
+	}
+
+	for id := range cache.Sessions {
+		if !current[id] {
+			delete(cache.Sessions, id)
+			changed = true
+		}
+	}
+	if changed {
+		// A cache that can't be written only costs speed next time
+		if data, err := json.Marshal(cache); err == nil {
+			tmp := cachePath + ".tmp"
+			if os.WriteFile(tmp, data, 0644) == nil {
+				os.Rename(tmp, cachePath)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].SessionID < result[j].SessionID
+	})
+	return result, nil
+}
+
+// selectAggregates picks the totals of the sessions named in args, or with
+// --all or --since of every matching session
+func selectAggregates(cmd *cobra.Command, args []string) ([]*SessionAggregates, error) {
+	all, _ := cmd.Flags().GetBool("all")
+	since, _ := cmd.Flags().GetDuration("since")
+	if len(args) > 0 && (all || since > 0) {
+		return nil, fmt.Errorf("%w: give session IDs or --all/--since, not both", errUsage)
+	}
+	if len(args) == 0 && !all && since <= 0 {
+		return nil, fmt.Errorf("%w: give a session ID, --all or --since", errUsage)
+	}
+
+	wanted := map[string]bool{}
+	for _, ref := range args {
+		id, err := resolveSession(ref)
+		if err != nil {
+			return nil, err
+		}
+		wanted[id] = true
+	}
+
+	aggregates, err := loadAggregates()
+	if err != nil {
+		return nil, err
+	}
+	selected := []*SessionAggregates{}
+	for _, a := range aggregates {
+		if len(wanted) > 0 && !wanted[a.SessionID] {
+			continue
+		}
+		if since > 0 && time.Since(a.StartTime) > since {
+			continue
+		}
+		selected = append(selected, a)
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("%w: no sessions match", errSessionNotFound)
+	}
+	return selected, nil
+}
+
+// totalRow is one line of a totals table
+type totalRow struct {
+	name     string
+	sessions int
+	minutes  float64
+}
+
+// writeTotalsTable renders rows as a markdown table, most time first
+func writeTotalsTable(md *strings.Builder, title, first string, rows map[string]*totalRow, limit int) {
+	if len(rows) == 0 {
+		return
+	}
+	sorted := make([]*totalRow, 0, len(rows))
+	for _, r := range rows {
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].minutes != sorted[j].minutes {
+			return sorted[i].minutes > sorted[j].minutes
+		}
+		return sorted[i].name < sorted[j].name
+	})
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+
+	md.WriteString(fmt.Sprintf("## %s\n\n| %s | %s | %s |\n|---|---|---|\n", title, first, i18n.T("totals.sessions"), i18n.T("totals.time")))
+	for _, r := range sorted {
+		md.WriteString(fmt.Sprintf("| %s | %d | %.1f h |\n", r.name, r.sessions, r.minutes/60))
+	}
+	md.WriteString("\n")
+}
+
+// writeRateTable renders the rate-of-work metrics of the sessions that have
+// them, a row per week, so trends show without decoding any frames
+func writeRateTable(md *strings.Builder, aggregates []*SessionAggregates) {
+	type week struct {
+		name          string
+		sessions      int
+		minutes       float64
+		changes       int
+		builds, tests int
+	}
+	var weeks []*week
+	byName := map[string]*week{}
+	for _, a := range aggregates {
+		if a.Metrics == nil {
+			continue
+		}
+		year, n := a.StartTime.Local().ISOWeek()
+		name := fmt.Sprintf("%d-W%02d", year, n)
+		w := byName[name]
+		if w == nil {
+			w = &week{name: name}
+			byName[name] = w
+			weeks = append(weeks, w)
+		}
+		w.sessions++
+		w.minutes += a.ActiveMinutes
+		w.changes += a.Metrics.ScreenChanges
+		w.builds += a.Metrics.BuildRuns
+		w.tests += a.Metrics.TestRuns
+	}
+	if len(weeks) == 0 {
+		return
+	}
+	sort.Slice(weeks, func(i, j int) bool { return weeks[i].name < weeks[j].name })
+
+	md.WriteString(fmt.Sprintf("## %s\n\n| %s | %s | %s | %s | %s | %s |\n|---|---|---|---|---|---|\n", i18n.T("rate.title"),
+		i18n.T("rate.week"), i18n.T("totals.sessions"), i18n.T("rate.active"), i18n.T("rate.changes"), i18n.T("rate.builds"), i18n.T("rate.tests")))
+	for _, w := range weeks {
+		rate := "–"
+		if w.minutes > 0 {
+			rate = fmt.Sprintf("%.0f", float64(w.changes)/(w.minutes/60))
+		}
+		md.WriteString(fmt.Sprintf("| %s | %d | %.1f h | %s | %d | %d |\n", w.name, w.sessions, w.minutes/60, rate, w.builds, w.tests))
+	}
+	md.WriteString("\n")
+}
+
+// GenerateTotals renders time totals over many sessions: overall, per
+// ticket, per tag, app and activity class usage where imported, and the
+// rate of work per week
+func GenerateTotals(aggregates []*SessionAggregates) string {
+	var md strings.Builder
+	first, last := aggregates[0].StartTime, aggregates[len(aggregates)-1].StartTime
+	md.WriteString(fmt.Sprintf("# %s\n\n", i18n.T("totals.title", first.Local().Format("2006-01-02"), last.Local().Format("2006-01-02"))))
+
+	var active, wall float64
+	var screenshots int
+	tickets := map[string]*totalRow{}
+	tags := map[string]*totalRow{}
+	apps := map[string]*totalRow{}
+	categories := map[string]*totalRow{}
+	add := func(rows map[string]*totalRow, name string, minutes float64) {
+		r := rows[name]
+		if r == nil {
+			r = &totalRow{name: name}
+			rows[name] = r
+		}
+		r.sessions++
+		r.minutes += minutes
+	}
+
+	for _, a := range aggregates {
+		active += a.ActiveMinutes
+		wall += a.WallMinutes
+		screenshots += a.Screenshots
+		ticket := a.Ticket
+		if ticket == "" {
+			ticket = i18n.T("digest.no_ticket")
+		}
+		add(tickets, ticket, a.ActiveMinutes)
+		for _, tag := range a.Tags {
+			add(tags, tag, a.ActiveMinutes)
+		}
+		for app, minutes := range a.Apps {
+			add(apps, app, minutes)
+		}
+		for category, minutes := range a.Categories {
+			add(categories, category, minutes)
+		}
+	}
+
+	md.WriteString(fmt.Sprintf("- **%s:** %d\n", i18n.T("digest.sessions"), len(aggregates)))
+	md.WriteString(fmt.Sprintf("- **%s:** %.1f h\n", i18n.T("digest.active"), active/60))
+	md.WriteString(fmt.Sprintf("- **%s:** %.1f h\n", i18n.T("totals.wall"), wall/60))
+	md.WriteString(fmt.Sprintf("- **%s:** %d\n\n", i18n.T("report.screenshots"), screenshots))
+
+	writeTotalsTable(&md, i18n.T("totals.by_ticket"), i18n.T("report.ticket"), tickets, 0)
+	writeTotalsTable(&md, i18n.T("totals.by_tag"), i18n.T("totals.tag"), tags, 0)
+	writeTotalsTable(&md, i18n.T("compare.apps"), i18n.T("compare.app"), apps, compareTopN)
+	writeTotalsTable(&md, i18n.T("compare.categories"), i18n.T("compare.category"), categories, compareTopN)
+	writeRateTable(&md, aggregates)
+	return md.String()
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/telemetry"
+	"task-tracker/internal/ui"
+)
+
+// auditFile is the append-only log of destructive and outbound actions,
+// kept in capturesDir. Nothing in task-tracker rewrites or truncates it.
+const auditFile = "audit.log"
+
+// Audited actions
+const (
+	auditDelete = "delete" // files removed from disk
+	auditUpload = "upload" // data sent to a web service
+	auditExport = "export" // a session written to an archive
+	auditSend   = "send"   // session data handed to an external command
+)
+
+var auditActions = []string{auditDelete, auditUpload, auditExport, auditSend}
+
+// AuditEntry is one line of the audit log
+type AuditEntry struct {
+	Time    string `json:"time"`
+	Action  string `json:"action"`
+	Target  string `json:"target"`
+	Detail  string `json:"detail,omitempty"`
+	Session string `json:"session,omitempty"`
+	User    string `json:"user,omitempty"`
+}
+
+// audit appends an entry to the audit log. A log that can't be written is
+// reported but doesn't stop the action.
+func audit(action, target, detail, session string) {
+	entry := AuditEntry{
+		Time:    time.Now().Format(time.RFC3339),
+		Action:  action,
+		Target:  target,
+		Detail:  detail,
+		Session: session,
+	}
+	if u, err := user.Current(); err == nil {
+		entry.User = u.Username
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(capturesDir, 0755); err != nil {
+		ui.Printf("⚠️  Failed to write audit log: %v\n", err)
+		return
+	}
+	f, err := os.OpenFile(filepath.Join(capturesDir, auditFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		ui.Printf("⚠️  Failed to write audit log: %v\n", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		ui.Printf("⚠️  Failed to write audit log: %v\n", err)
+	}
+}
+
+// auditTransport records requests that send data (anything but GET and
+// HEAD) in the audit log
+type auditTransport struct {
+	base http.RoundTripper
+}
+
+func (t auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return resp, err
+	}
+
+	// The query is left out; some services put credentials there
+	target := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+	detail := req.Method
+	switch {
+	case err != nil:
+		detail += " failed: " + err.Error()
+	default:
+		detail += " " + resp.Status
+	}
+	audit(auditUpload, target, detail, "")
+	return resp, err
+}
+
+// httpTransport is the transport of every client that talks to a web
+// service: traced, and audited when it sends data
+func httpTransport() http.RoundTripper {
+	return auditTransport{base: telemetry.Transport(nil)}
+}
+
+// readAudit reads the audit log, oldest first
+func readAudit() ([]AuditEntry, error) {
+	f, err := os.Open(filepath.Join(capturesDir, auditFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		var e AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("audit log line %d is corrupt: %v", n, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// newAuditCmd builds the audit command
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show the log of deletions, uploads, exports and data sent to other tools",
+		Long: `Show the append-only audit log in task_captures/audit.log, for compliance
+reviews of what data left the machine and what was deleted.
+
+It records deleted screenshots and blobs, every request that sends data to Jira,
+GitHub, GitLab, Linear, Azure Boards, ActivityWatch, WakaTime or RescueTime,
+export archives, and prompts handed to a summarizer command.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			entries, err := readAudit()
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			action, _ := cmd.Flags().GetString("action")
+			if action != "" && !slices.Contains(auditActions, action) {
+				ui.Printf("❌ Invalid --action '%s' (use %s)\n", action, strings.Join(auditActions, ", "))
+				exit(exitUsage)
+			}
+			since, _ := cmd.Flags().GetDuration("since")
+			session, _ := cmd.Flags().GetString("session")
+			if session != "" {
+				if session, err = resolveSession(session); err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+			}
+
+			shown := []AuditEntry{}
+			for _, e := range entries {
+				if action != "" && e.Action != action {
+					continue
+				}
+				if session != "" && e.Session != session {
+					continue
+				}
+				if since > 0 {
+					at, err := time.Parse(time.RFC3339, e.Time)
+					if err == nil && time.Since(at) > since {
+						continue
+					}
+				}
+				shown = append(shown, e)
+			}
+
+			if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				for _, e := range shown {
+					enc.Encode(e)
+				}
+				return
+			}
+
+			if len(shown) == 0 {
+				ui.Println("\n📋 Nothing in the audit log")
+				return
+			}
+			table := ui.NewTable("Time", "Action", "Target", "Detail", "Session", "User")
+			for _, e := range shown {
+				when := e.Time
+				if at, err := time.Parse(time.RFC3339, e.Time); err == nil {
+					when = at.Local().Format("2006-01-02 15:04:05")
+				}
+				table.AddRow(when, ui.Bold(e.Action), e.Target, ui.Dim(e.Detail), e.Session, e.User)
+			}
+			ui.Println()
+			table.Render()
+		},
+	}
+
+	cmd.Flags().String("action", "", "Only show one action ("+strings.Join(auditActions, ", ")+")")
+	cmd.Flags().Duration("since", 0, "Only show entries from the last duration, e.g. 720h")
+	cmd.Flags().String("session", "", "Only show entries for one session")
+	cmd.Flags().Bool("json", false, "Print the entries as JSON lines")
+	return cmd
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"task-tracker/internal/i18n"
+)
+
+// Reason codes for placeholder frames
+const (
+	awayPrivacy      = "privacy"
+	awayBlackout     = "blackout"
+	awayDisconnected = "disconnected"
+	awayLowDisk      = "low_disk"
+	awayBattery      = "battery"
+	awayRule         = "rule"
+)
+
+// AwayFrame is a placeholder for a tick on which capture was paused. It has
+// no image, only a reason code, so viewers can show the pause in sequence
+// instead of jumping from the frame before it to the one after.
+type AwayFrame struct {
+	Timestamp    string  `json:"timestamp"`
+	RelativeTime float64 `json:"relative_time"`
+	Reason       string  `json:"reason"`
+}
+
+// recordAway adds a placeholder frame for a paused tick when placeholders
+// are on
+func (t *TaskTracker) recordAway(at time.Time, reason string) {
+	t.log(logInfo, "skip", "Tick skipped: "+reason)
+	if !t.Placeholders {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Away = append(t.Away, AwayFrame{
+		Timestamp:    at.Format(time.RFC3339),
+		RelativeTime: at.Sub(t.StartTime).Seconds(),
+		Reason:       reason,
+	})
+}
+
+// awayRuns collapses consecutive placeholders with the same reason into one
+// timeline entry each
+func (t *TaskTracker) awayRuns() []timelineEntry {
+	var entries []timelineEntry
+	for i := 0; i < len(t.Away); {
+		first := t.Away[i]
+		last := first
+		j := i + 1
+		for j < len(t.Away) && t.Away[j].Reason == first.Reason &&
+			t.Away[j].RelativeTime-last.RelativeTime <= t.gapThreshold().Seconds() {
+			last = t.Away[j]
+			j++
+		}
+
+		entries = append(entries, timelineEntry{
+			RelativeTime: first.RelativeTime,
+			Text: fmt.Sprintf("> 💤 **%s:** %s",
+				i18n.T("timeline.paused", first.RelativeTime/60, last.RelativeTime/60),
+				i18n.T("timeline.paused_why", i18n.T("away."+first.Reason), j-i)),
+		})
+		i = j
+	}
+	return entries
+}
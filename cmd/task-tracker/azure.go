@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// azureClient talks to the Azure DevOps (Azure Boards) REST API.
+// AZURE_DEVOPS_ORG_URL (e.g. https://dev.azure.com/myorg) and
+// AZURE_DEVOPS_TOKEN, a personal access token, configure it. Worklogs add
+// to a work item's Completed Work and take from its Remaining Work.
+type azureClient struct {
+	restClient
+}
+
+// newAzureClient returns nil if Azure DevOps isn't configured
+func newAzureClient() *azureClient {
+	base, token := os.Getenv("AZURE_DEVOPS_ORG_URL"), os.Getenv("AZURE_DEVOPS_TOKEN")
+	if base == "" || token == "" {
+		return nil
+	}
+	return &azureClient{restClient: newRESTClient("Azure DevOps", base, func(req *http.Request) {
+		req.SetBasicAuth("", token)
+	})}
+}
+
+// Scheduling fields, in hours
+const (
+	azureOriginal  = "Microsoft.VSTS.Scheduling.OriginalEstimate"
+	azureRemaining = "Microsoft.VSTS.Scheduling.RemainingWork"
+	azureCompleted = "Microsoft.VSTS.Scheduling.CompletedWork"
+)
+
+// itemPath is the API path of Project#1234 under the given area
+func (c *azureClient) itemPath(key, area string) string {
+	project, id := splitRepoIssue(key)
+	return "/" + url.PathEscape(project) + "/_apis/wit/" + area + "/" + id
+}
+
+// workItem fetches a work item's fields
+func (c *azureClient) workItem(key string) (map[string]any, error) {
+	var item struct {
+		Fields map[string]any `json:"fields"`
+	}
+	if err := c.do("GET", c.itemPath(key, "workitems")+"?api-version=7.0", nil, &item); err != nil {
+		return nil, err
+	}
+	return item.Fields, nil
+}
+
+// hours reads a numeric scheduling field
+func hours(fields map[string]any, name string) float64 {
+	h, _ := fields[name].(float64)
+	return h
+}
+
+// GetIssue fetches a work item's title, state and scheduling
+func (c *azureClient) GetIssue(key string) (*Issue, error) {
+	fields, err := c.workItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	title, _ := fields["System.Title"].(string)
+	state, _ := fields["System.State"].(string)
+	issue := &Issue{Key: key, Title: title, State: state, URL: c.IssueURL(key)}
+	if _, ok := fields[azureOriginal]; ok {
+		issue.Estimate = &Estimate{
+			OriginalSeconds:  int64(hours(fields, azureOriginal) * 3600),
+			RemainingSeconds: int64(hours(fields, azureRemaining) * 3600),
+			LoggedSeconds:    int64(hours(fields, azureCompleted) * 3600),
+			FetchedAt:        time.Now().Format(time.RFC3339),
+		}
+	}
+	return issue, nil
+}
+
+// PostComment comments on a work item. Comments are HTML, so the text is
+// escaped and its line breaks kept.
+func (c *azureClient) PostComment(key, text string) error {
+	body := strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+	return c.do("POST", c.itemPath(key, "workItems")+"/comments?api-version=7.0-preview.3", map[string]string{"text": body}, nil)
+}
+
+// PostWorklog moves the time from Remaining Work to Completed Work and
+// comments with the details, since work items keep no log of entries
+func (c *azureClient) PostWorklog(key string, spent time.Duration, started time.Time, comment string) error {
+	fields, err := c.workItem(key)
+	if err != nil {
+		return err
+	}
+
+	h := spent.Hours()
+	patch := []map[string]any{
+		{"op": "add", "path": "/fields/" + azureCompleted, "value": hours(fields, azureCompleted) + h},
+	}
+	if _, ok := fields[azureRemaining]; ok {
+		patch = append(patch, map[string]any{"op": "add", "path": "/fields/" + azureRemaining, "value": max(0, hours(fields, azureRemaining)-h)})
+	}
+	if err := c.send("PATCH", c.itemPath(key, "workitems")+"?api-version=7.0", "application/json-patch+json", patch, nil); err != nil {
+		return err
+	}
+	return c.PostComment(key, worklogComment(spent, started, comment))
+}
+
+// IssueURL is the work item's page
+func (c *azureClient) IssueURL(key string) string {
+	project, id := splitRepoIssue(key)
+	return fmt.Sprintf("%s/%s/_workitems/edit/%s", c.base, url.PathEscape(project), id)
+}
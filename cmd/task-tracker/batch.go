@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// addBatchFlags adds the flags for picking several sessions to work on
+func addBatchFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("all", false, "Process every saved session")
+	cmd.Flags().Duration("since", 0, "Process sessions started within the last duration, e.g. 720h")
+	cmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "Number of sessions to process at once")
+}
+
+// batchSessions resolves the sessions named in args, or with --all or
+// --since every matching saved session, oldest first
+func batchSessions(cmd *cobra.Command, args []string) ([]string, error) {
+	all, _ := cmd.Flags().GetBool("all")
+	since, _ := cmd.Flags().GetDuration("since")
+
+	if len(args) > 0 {
+		if all || since > 0 {
+			return nil, fmt.Errorf("%w: give session IDs or --all/--since, not both", errUsage)
+		}
+		ids := make([]string, len(args))
+		for i, ref := range args {
+			id, err := resolveSession(ref)
+			if err != nil {
+				return nil, err
+			}
+			ids[i] = id
+		}
+		return ids, nil
+	}
+	if !all && since <= 0 {
+		return nil, fmt.Errorf("%w: give a session ID, --all or --since", errUsage)
+	}
+
+	sessions, err := listSessions()
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, s := range sessions {
+		if since > 0 {
+			start, err := time.Parse(time.RFC3339, s.StartTime)
+			if err != nil || time.Since(start) > since {
+				continue
+			}
+		}
+		ids = append(ids, s.SessionID)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: no sessions match", errSessionNotFound)
+	}
+	return ids, nil
+}
+
+// runBatch loads each session and runs fn on it, jobs at a time, with a
+// progress bar. Errors are printed as they happen and returned by session ID.
+func runBatch(label string, ids []string, jobs int, fn func(*TaskTracker) error) map[string]error {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	progress := ui.NewProgress(label, len(ids))
+	var mu sync.Mutex
+	failed := map[string]error{}
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for range min(jobs, len(ids)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				tracker, err := loadSession(id)
+				if err == nil {
+					err = fn(tracker)
+				}
+				if err != nil {
+					ui.Printf("❌ %s: %v\n", id, err)
+					mu.Lock()
+					failed[id] = err
+					mu.Unlock()
+				}
+				progress.Step(err)
+			}
+		}()
+	}
+	for _, id := range ids {
+		queue <- id
+	}
+	close(queue)
+	wg.Wait()
+	progress.Finish()
+	return failed
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"task-tracker/internal/power"
+	"task-tracker/internal/ui"
+)
+
+const (
+	defaultBatterySlow  = 50
+	defaultBatteryJPEG  = 20
+	defaultBatteryCheck = time.Minute
+)
+
+// BatteryConfig is the policy for capturing on battery power. Each stage
+// applies while on battery with less charge than its level, in percent; 0
+// turns it off and 100 applies it whenever on battery.
+type BatteryConfig struct {
+	SlowBelow  int      `json:"slow_below"`  // double the interval
+	JPEGBelow  int      `json:"jpeg_below"`  // also save frames as JPEG
+	PauseBelow int      `json:"pause_below"` // pause capture
+	CheckEvery Duration `json:"check_every"`
+}
+
+// Validate checks the levels are percentages
+func (c BatteryConfig) Validate() error {
+	for _, level := range []int{c.SlowBelow, c.JPEGBelow, c.PauseBelow} {
+		if level < 0 || level > 100 {
+			return fmt.Errorf("battery: levels must be between 0 and 100, got %d", level)
+		}
+	}
+	if c.CheckEvery.Duration < time.Second {
+		return fmt.Errorf("battery.check_every must be at least 1s")
+	}
+	return nil
+}
+
+// stageFor maps the power status to a degradation stage
+func (c BatteryConfig) stageFor(st power.Status) throttleStage {
+	if !st.OnBattery {
+		return stageOK
+	}
+	below := func(level int) bool {
+		return level == 100 || (level > 0 && st.Percent >= 0 && st.Percent < level)
+	}
+	switch {
+	case below(c.PauseBelow):
+		return stagePaused
+	case below(c.JPEGBelow):
+		return stageJPEG
+	case below(c.SlowBelow):
+		return stageSlow
+	}
+	return stageOK
+}
+
+// batteryMonitor tracks the battery for the capture loop
+type batteryMonitor struct {
+	cfg       BatteryConfig
+	lastCheck time.Time
+	stage     throttleStage
+	none      bool // no battery; stop checking
+}
+
+// check re-reads the battery if CheckEvery has passed and reports whether
+// the stage changed
+func (m *batteryMonitor) check(t *TaskTracker, now time.Time) bool {
+	if m.none || now.Sub(m.lastCheck) < m.cfg.CheckEvery.Duration {
+		return false
+	}
+	m.lastCheck = now
+
+	st, err := power.Battery()
+	if errors.Is(err, power.ErrNoBattery) {
+		m.none = true
+		return false
+	}
+	if err != nil {
+		return false
+	}
+
+	stage := m.cfg.stageFor(st)
+	if stage == m.stage {
+		return false
+	}
+
+	msg := fmt.Sprintf("On battery: %s", stage)
+	if st.Percent >= 0 {
+		msg = fmt.Sprintf("On battery (%d%%): %s", st.Percent, stage)
+	}
+	if !st.OnBattery {
+		msg = fmt.Sprintf("On AC power: %s", stage)
+	}
+	ui.Printf("🔋 %s\n", msg)
+	t.addEvent(msg)
+	t.notify(eventBattery, "Battery", msg)
+
+	m.stage = stage
+	return true
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/archive"
+	"task-tracker/internal/capture"
+	"task-tracker/internal/delta"
+	"task-tracker/internal/imaging"
+	"task-tracker/internal/ui"
+)
+
+// benchEncoder is one storage setting measured by bench
+type benchEncoder struct {
+	name   string
+	flags  string // start flags that select it
+	encode func(prev, cur *image.RGBA) ([]byte, error)
+}
+
+var benchEncoders = []benchEncoder{
+	{
+		name: "png",
+		encode: func(_, cur *image.RGBA) ([]byte, error) {
+			return encodePNGLevel(cur, png.DefaultCompression)
+		},
+	},
+	{
+		name:  "png + optimize",
+		flags: "--optimize",
+		encode: func(_, cur *image.RGBA) ([]byte, error) {
+			data, err := encodePNGLevel(cur, png.DefaultCompression)
+			if err != nil {
+				return nil, err
+			}
+			return imaging.OptimizePNG(data)
+		},
+	},
+	{
+		name:  "delta",
+		flags: "--delta",
+		encode: func(prev, cur *image.RGBA) ([]byte, error) {
+			if prev == nil {
+				return encodePNGLevel(cur, png.DefaultCompression)
+			}
+			changed, total, ok := delta.Diff(prev, cur, delta.DefaultTileSize)
+			if !ok || float64(len(changed)) > float64(total)*keyframeChangeRatio {
+				return encodePNGLevel(cur, png.DefaultCompression)
+			}
+			var buf bytes.Buffer
+			err := delta.Encode(&buf, "keyframe.png", cur, delta.DefaultTileSize, changed)
+			return buf.Bytes(), err
+		},
+	},
+}
+
+func encodePNGLevel(img image.Image, level png.CompressionLevel) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := png.Encoder{CompressionLevel: level}
+	err := enc.Encode(&buf, img)
+	return buf.Bytes(), err
+}
+
+// benchResult holds averages for one encoder
+type benchResult struct {
+	encoder    benchEncoder
+	encodeTime time.Duration
+	bytes      int64
+}
+
+// parseSize parses sizes like "500MB" or "2GB"
+func parseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	units := []struct {
+		suffix string
+		mult   int64
+	}{{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+
+	for _, u := range units {
+		if num, ok := strings.CutSuffix(s, u.suffix); ok {
+			n, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid size '%s'", s)
+			}
+			return int64(n * float64(u.mult)), nil
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size '%s' (use e.g. 500MB or 2GB)", s)
+	}
+	return n, nil
+}
+
+// newBenchCmd builds the bench command
+func newBenchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure capture and encode cost on this machine",
+		Long: `Capture a few frames and measure capture latency, encode time and bytes per
+frame for each storage setting, then recommend the smallest setting that keeps
+up with the target interval and fits the disk budget for a working day.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			frames, _ := cmd.Flags().GetInt("frames")
+			interval, _ := cmd.Flags().GetInt("interval")
+			hours, _ := cmd.Flags().GetFloat64("hours")
+			budgetStr, _ := cmd.Flags().GetString("budget")
+			monitors, _ := cmd.Flags().GetString("monitors")
+			backend, _ := cmd.Flags().GetString("backend")
+			display, _ := cmd.Flags().GetString("display")
+
+			budget, err := parseSize(budgetStr)
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitUsage)
+			}
+			if frames < 2 || interval < 1 || hours <= 0 {
+				ui.Println("❌ --frames must be at least 2, --interval and --hours positive")
+				exit(exitUsage)
+			}
+
+			capturer, err := capture.New(backend, display)
+			if err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				exit(exitCode(fmt.Errorf("%w: %v", errCaptureUnavailable, err)))
+			}
+			tracker := &TaskTracker{MonitorsConfig: monitors, Capturer: capturer}
+			if err := tracker.setupMonitors(); err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				exit(exitCode(err))
+			}
+
+			ui.Printf("\n⏱️  Capturing %d frame(s) per monitor...\n", frames)
+
+			var captureTime time.Duration
+			captured := map[int][]*image.RGBA{}
+			for i := 0; i < frames; i++ {
+				for _, m := range tracker.MonitorsToCapture {
+					start := time.Now()
+					img, err := capturer.Capture(m)
+					if err != nil {
+						ui.Printf("❌ Failed to capture monitor %d: %v\n", m+1, err)
+						exit(exitCode(fmt.Errorf("%w: %v", errCaptureUnavailable, err)))
+					}
+					captureTime += time.Since(start)
+					captured[m] = append(captured[m], img)
+				}
+				time.Sleep(500 * time.Millisecond)
+			}
+			perTick := len(tracker.MonitorsToCapture)
+			captureTime /= time.Duration(frames)
+
+			results := []benchResult{}
+			for _, enc := range benchEncoders {
+				r := benchResult{encoder: enc}
+				for _, seq := range captured {
+					var prev *image.RGBA
+					for _, img := range seq {
+						start := time.Now()
+						data, err := enc.encode(prev, img)
+						if err != nil {
+							ui.Printf("❌ %s failed: %v\n", enc.name, err)
+							exit(exitError)
+						}
+						r.encodeTime += time.Since(start)
+						r.bytes += int64(len(data))
+						if prev == nil {
+							prev = img // first frame is the keyframe
+						}
+					}
+				}
+				// per tick, across all monitors
+				r.encodeTime /= time.Duration(frames)
+				r.bytes /= int64(frames)
+				results = append(results, r)
+			}
+
+			ticks := hours * 3600 / float64(interval)
+			table := ui.NewTable("Setting", "Encode/tick", "Bytes/tick", fmt.Sprintf("Per %gh", hours), "Fits")
+			var fitting []benchResult
+			for _, r := range results {
+				day := int64(float64(r.bytes) * ticks)
+				fast := captureTime+r.encodeTime < time.Duration(interval)*time.Second
+				fits := fast && day <= budget
+
+				verdict := ui.Green("yes")
+				switch {
+				case !fast:
+					verdict = ui.Red("too slow")
+				case !fits:
+					verdict = ui.Yellow("over budget")
+				default:
+					fitting = append(fitting, r)
+				}
+
+				table.AddRow(r.encoder.name, r.encodeTime.Round(time.Millisecond).String(),
+					formatBytes(r.bytes), formatBytes(day), verdict)
+			}
+
+			ui.Printf("\n📊 Capture latency: %s per tick (%d monitor(s))\n", captureTime.Round(time.Millisecond), perTick)
+			ui.Printf("🎯 Target: every %ds, %s per %gh\n\n", interval, formatBytes(budget), hours)
+			table.Render()
+
+			sort.Slice(results, func(i, j int) bool { return results[i].bytes < results[j].bytes })
+			if len(fitting) > 0 {
+				sort.Slice(fitting, func(i, j int) bool { return fitting[i].bytes < fitting[j].bytes })
+				best := fitting[0]
+				ui.Printf("\n✅ Recommended: %s\n", best.encoder.name)
+				ui.Printf("   task-tracker start \"Task\" --interval %d %s\n", interval, best.encoder.flags)
+				return
+			}
+
+			// Nothing fits: suggest the interval the smallest setting needs
+			best := results[0]
+			needed := int(float64(best.bytes)*hours*3600/float64(budget)) + 1
+			ui.Printf("\n⚠️  No setting fits %s per %gh at a %ds interval\n", formatBytes(budget), hours, interval)
+			ui.Printf("💡 %s fits with --interval %d or a larger --budget\n", best.encoder.name, needed)
+		},
+	}
+
+	cmd.Flags().Int("frames", 5, "Frames to capture per monitor")
+	cmd.Flags().IntP("interval", "i", 30, "Target capture interval in seconds")
+	cmd.Flags().Float64("hours", 8, "Hours of capture the budget must cover")
+	cmd.Flags().String("budget", "2GB", "Disk budget for --hours of capture (e.g. 500MB, 2GB)")
+	cmd.Flags().StringP("monitors", "m", "all", "Monitors to capture (all, primary, 1, 1,2, or names like DP-1, etc.)")
+	cmd.Flags().String("backend", capture.BackendScreen, "Capture backend (screen, virtual or fake)")
+	cmd.Flags().String("display", "", "X11 display to capture, e.g. :99 (defaults to $DISPLAY)")
+	cmd.AddCommand(newBenchArchiveCmd())
+	return cmd
+}
+
+// archiveResult is one codec and level measured by bench archive
+type archiveResult struct {
+	codec string
+	level int
+	bytes int64
+	time  time.Duration
+}
+
+// countingWriter counts bytes written to it and throws them away
+type countingWriter struct{ n int64 }
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// sessionFiles reads every file an export of the session would contain,
+// shared blobs included
+func sessionFiles(t *TaskTracker) ([][]byte, error) {
+	paths := map[string]bool{}
+	entries, err := os.ReadDir(t.SessionDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			paths[filepath.Join(t.SessionDir, entry.Name())] = true
+		}
+	}
+	for _, shot := range t.Screenshots {
+		paths[shot.Path] = true
+	}
+
+	var files [][]byte
+	for path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue // dropped frame or missing blob; export would fail too
+		}
+		files = append(files, data)
+	}
+	return files, nil
+}
+
+// benchArchive compresses files into a zip with codec at level
+func benchArchive(files [][]byte, codec string, level int) (archiveResult, error) {
+	r := archiveResult{codec: codec, level: level}
+	out := &countingWriter{}
+	start := time.Now()
+
+	zw := zip.NewWriter(out)
+	method, err := archive.Writer(zw, codec, level)
+	if err != nil {
+		return r, err
+	}
+	for i, content := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: fmt.Sprintf("f%d", i), Method: method})
+		if err != nil {
+			return r, err
+		}
+		if _, err := w.Write(content); err != nil {
+			return r, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return r, err
+	}
+
+	r.time = time.Since(start)
+	r.bytes = out.n
+	return r, nil
+}
+
+// newBenchArchiveCmd builds the bench archive command
+func newBenchArchiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "archive [session_id]",
+		Short: "Measure export compression codecs and levels on a session",
+		Long: `Compress a session as 'task-tracker export' would with every codec at several
+levels and show size and speed, then recommend an archive setting for config.
+Nothing is written to disk.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			files, err := sessionFiles(tracker)
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			var total int64
+			for _, f := range files {
+				total += int64(len(f))
+			}
+			if total == 0 {
+				ui.Println("❌ The session has no files to compress")
+				exit(exitUsage)
+			}
+
+			ui.Printf("\n⏱️  Compressing %d file(s), %s...\n\n", len(files), formatBytes(total))
+			var results []archiveResult
+			for _, codec := range archive.Codecs {
+				for _, level := range archive.Levels(codec) {
+					r, err := benchArchive(files, codec, level)
+					if err != nil {
+						ui.Printf("❌ %s %d failed: %v\n", codec, level, err)
+						exit(exitError)
+					}
+					results = append(results, r)
+				}
+			}
+
+			smallest := results[0].bytes
+			for _, r := range results {
+				smallest = min(smallest, r.bytes)
+			}
+
+			table := ui.NewTable("Codec", "Level", "Size", "Ratio", "Speed", "Time")
+			var best *archiveResult
+			for i, r := range results {
+				seconds := max(r.time.Seconds(), 1e-6)
+				table.AddRow(r.codec, fmt.Sprint(r.level), formatBytes(r.bytes),
+					fmt.Sprintf("%.1f%%", float64(r.bytes)/float64(total)*100),
+					fmt.Sprintf("%.0f MB/s", float64(total)/seconds/1e6),
+					r.time.Round(time.Millisecond).String())
+
+				// The fastest setting within 5% of the smallest archive
+				if float64(r.bytes) <= float64(smallest)*1.05 && (best == nil || r.time < best.time) {
+					best = &results[i]
+				}
+			}
+			table.Render()
+
+			ui.Printf("\n✅ Recommended: %s level %d\n", best.codec, best.level)
+			ui.Printf("   \"archive\": {\"compression\": \"%s\", \"level\": %d}\n", best.codec, best.level)
+		},
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"task-tracker/internal/ui"
+)
+
+// BlackoutWindow is a recurring period during which capture pauses, such
+// as a lunch break or Friday afternoons
+type BlackoutWindow struct {
+	Days  []string `json:"days,omitempty"` // mon-sun; empty means every day
+	Start string   `json:"start"`          // "12:00"
+	End   string   `json:"end,omitempty"`  // "13:00"; empty means midnight
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseWeekday accepts "fri", "Friday" and so on
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) < 3 {
+		return 0, false
+	}
+	d, ok := weekdays[s[:3]]
+	return d, ok
+}
+
+// parseClock parses "15:04" into minutes since midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s' (use HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Validate checks days and times, and that the window isn't empty
+func (b BlackoutWindow) Validate() error {
+	for _, d := range b.Days {
+		if _, ok := parseWeekday(d); !ok {
+			return fmt.Errorf("blackout: unknown day '%s' (use mon, tue, ...)", d)
+		}
+	}
+	start, err := parseClock(b.Start)
+	if err != nil {
+		return fmt.Errorf("blackout: start: %w", err)
+	}
+	if b.End != "" {
+		end, err := parseClock(b.End)
+		if err != nil {
+			return fmt.Errorf("blackout: end: %w", err)
+		}
+		// Contains would take this as a whole day, which is rarely meant
+		if end == start {
+			return fmt.Errorf("blackout: %s starts and ends at the same time (for a whole day use start 00:00 without an end)", b)
+		}
+	}
+	return nil
+}
+
+// onDay reports whether the window starts on weekday d
+func (b BlackoutWindow) onDay(d time.Weekday) bool {
+	if len(b.Days) == 0 {
+		return true
+	}
+	for _, name := range b.Days {
+		if wd, _ := parseWeekday(name); wd == d {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains reports whether at falls in the window. A window whose end is
+// before its start runs past midnight into the next day.
+func (b BlackoutWindow) Contains(at time.Time) bool {
+	start, _ := parseClock(b.Start)
+	end := 24 * 60
+	if b.End != "" {
+		end, _ = parseClock(b.End)
+	}
+	now := at.Hour()*60 + at.Minute()
+
+	if start < end {
+		return b.onDay(at.Weekday()) && now >= start && now < end
+	}
+	yesterday := at.AddDate(0, 0, -1).Weekday()
+	return (b.onDay(at.Weekday()) && now >= start) || (b.onDay(yesterday) && now < end)
+}
+
+func (b BlackoutWindow) String() string {
+	s := b.Start + "+"
+	if b.End != "" {
+		s = b.Start + "-" + b.End
+	}
+	if len(b.Days) > 0 {
+		s += " " + strings.Join(b.Days, ",")
+	}
+	return s
+}
+
+// blackout returns the configured window at covers, if any
+func (t *TaskTracker) blackout(at time.Time) (BlackoutWindow, bool) {
+	for _, b := range t.Blackout {
+		if b.Contains(at.Local()) {
+			return b, true
+		}
+	}
+	return BlackoutWindow{}, false
+}
+
+// inBlackout reports whether capture should pause at now, noting when a
+// blackout starts and ends. Blackout time isn't counted as active time.
+func (t *TaskTracker) inBlackout(now time.Time) bool {
+	b, in := t.blackout(now)
+	if in != t.blackedOut {
+		t.blackedOut = in
+		if in {
+			msg := fmt.Sprintf("Blackout window %s: capture paused", b)
+			ui.Printf("🌙 %s\n", msg)
+			t.addEvent(msg)
+		} else {
+			ui.Println("☀️  Blackout window over, capture resumed")
+			t.addEvent("Blackout window over: capture resumed")
+		}
+	}
+	return in
+}
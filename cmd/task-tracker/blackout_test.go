@@ -0,0 +1,100 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBlackoutContains(t *testing.T) {
+	// 2026-01-05 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 1, 4+day, hour, minute, 0, 0, time.Local)
+	}
+	lunch := BlackoutWindow{Start: "12:00", End: "13:00"}
+	fridays := BlackoutWindow{Days: []string{"Friday"}, Start: "15:00"}
+	nights := BlackoutWindow{Days: []string{"mon"}, Start: "22:00", End: "06:00"}
+
+	tests := []struct {
+		name   string
+		window BlackoutWindow
+		at     time.Time
+		want   bool
+	}{
+		{"lunch start", lunch, at(1, 12, 0), true},
+		{"lunch end is outside", lunch, at(1, 13, 0), false},
+		{"before lunch", lunch, at(3, 11, 59), false},
+		{"friday afternoon", fridays, at(5, 16, 30), true},
+		{"friday until midnight", fridays, at(5, 23, 59), true},
+		{"thursday afternoon", fridays, at(4, 16, 30), false},
+		{"overnight start day", nights, at(1, 23, 0), true},
+		{"overnight into next day", nights, at(2, 5, 59), true},
+		{"overnight ended", nights, at(2, 6, 0), false},
+		{"overnight not started the day before", nights, at(1, 5, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Contains(tt.at); got != tt.want {
+				t.Errorf("%s.Contains(%s) = %v, want %v", tt.window, tt.at.Format("Mon 15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlackoutValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  BlackoutWindow
+		wantErr bool
+	}{
+		{"every day", BlackoutWindow{Start: "12:00", End: "13:00"}, false},
+		{"until midnight", BlackoutWindow{Days: []string{"sat", "Sunday"}, Start: "00:00"}, false},
+		{"unknown day", BlackoutWindow{Days: []string{"someday"}, Start: "12:00"}, true},
+		{"bad start", BlackoutWindow{Start: "noon"}, true},
+		{"bad end", BlackoutWindow{Start: "12:00", End: "25:00"}, true},
+		{"empty", BlackoutWindow{Start: "12:00", End: "12:00"}, true},
+		{"empty written differently", BlackoutWindow{Start: "9:00", End: "09:00"}, true},
+		{"whole day", BlackoutWindow{Start: "00:00"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.window.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInBlackoutNotesChanges(t *testing.T) {
+	tracker := &TaskTracker{Blackout: []BlackoutWindow{{Start: "12:00", End: "13:00"}}}
+	if tracker.inBlackout(time.Date(2026, 1, 5, 11, 59, 0, 0, time.Local)) {
+		t.Error("in blackout before it starts")
+	}
+	if !tracker.inBlackout(time.Date(2026, 1, 5, 12, 30, 0, 0, time.Local)) {
+		t.Error("not in blackout during it")
+	}
+	if tracker.inBlackout(time.Date(2026, 1, 5, 13, 0, 0, 0, time.Local)) {
+		t.Error("still in blackout after it ends")
+	}
+	if len(tracker.Notes) != 2 {
+		t.Errorf("got %d notes, want one when the blackout starts and one when it ends", len(tracker.Notes))
+	}
+}
+
+func TestParseBlackouts(t *testing.T) {
+	windows, err := parseBlackouts("12:00-13:00, 18:00-, none")
+	if err != nil {
+		t.Fatalf("parseBlackouts: %v", err)
+	}
+	want := []BlackoutWindow{{Start: "12:00", End: "13:00"}, {Start: "18:00"}}
+	if len(windows) != len(want) {
+		t.Fatalf("got %d windows, want %d", len(windows), len(want))
+	}
+	for i := range want {
+		if windows[i].String() != want[i].String() {
+			t.Errorf("window %d = %s, want %s", i, windows[i], want[i])
+		}
+	}
+	if _, err := parseBlackouts("lunch"); err == nil {
+		t.Error("parseBlackouts(\"lunch\") succeeded, want an error")
+	}
+}
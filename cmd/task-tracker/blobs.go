@@ -0,0 +1,182 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// blobsDir holds deduplicated frames shared by all sessions, stored as
+// blobs/<first two hash chars>/<sha256>.png. Sessions refer to blobs from
+// their metadata.json, which serves as the session's manifest.
+const blobsDir = "blobs"
+
+// frameHash identifies a frame by its pixels, so the same screen hashes
+// the same in every session regardless of PNG metadata
+func frameHash(img *image.RGBA) string {
+	h := sha256.New()
+	b := img.Bounds()
+	fmt.Fprintf(h, "%dx%d\n", b.Dx(), b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		start := img.PixOffset(b.Min.X, y)
+		h.Write(img.Pix[start : start+b.Dx()*4])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// blobPath returns where a blob is stored under outputDir
+func blobPath(outputDir, hash string) string {
+	return filepath.Join(outputDir, blobsDir, hash[:2], hash+".png")
+}
+
+// saveBlob stores a frame in the blob directory unless an identical frame
+// is already there. Blobs carry no PNG text chunks since they're shared.
+func (t *TaskTracker) saveBlob(img *image.RGBA) (path, hash string, err error) {
+	hash = frameHash(img)
+	path = blobPath(t.OutputDir, hash)
+	if fileExists(path) {
+		return path, hash, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	// Another session may be writing the same blob
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := writePNG(tmp, img, nil); err != nil {
+		os.Remove(tmp)
+		return "", "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", "", err
+	}
+	return path, hash, nil
+}
+
+// sessionIDPattern matches session directory names, 20060102_150405 with
+// a suffix for sessions started in the same second
+var sessionIDPattern = regexp.MustCompile(`^\d{8}_\d{6}(_\d+)?$`)
+
+// referencedBlobs returns the hashes used by any session. Unlike
+// listSessions it fails on a session whose metadata is missing or can't
+// be parsed: a capture still running, one that crashed before saving, or
+// a damaged file may all use blobs nothing else refers to.
+func referencedBlobs() (map[string]bool, error) {
+	entries, err := os.ReadDir(capturesDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", capturesDir, err)
+	}
+
+	refs := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.IsDir() || !sessionIDPattern.MatchString(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(capturesDir, entry.Name(), "metadata.json")
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("session %s has no metadata.json: it's still capturing or stopped without saving, and may use blobs no other session does", entry.Name())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var metadata SessionMetadata
+		if err := json.Unmarshal(data, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		for _, shot := range metadata.Screenshots {
+			if shot.Blob != "" {
+				refs[shot.Blob] = true
+			}
+		}
+	}
+	return refs, nil
+}
+
+// newGCCmd builds the gc command
+func newGCCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Delete deduplicated frames no session refers to",
+		Long: `Delete blobs (frames stored once with 'start --dedupe') that are no longer
+referenced by any session, e.g. after deleting sessions or dropping screenshots.
+
+gc refuses to run while a capture is in progress, since a running session's
+frames aren't recorded in its metadata until it stops. It also refuses while
+any session directory lacks a readable metadata.json, whether its capture is
+still running or stopped without saving: delete or repair that session first.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			if len(runningSessions()) > 0 {
+				ui.Println("❌ A capture is running. Stop it before running gc")
+				exit(exitUsage)
+			}
+
+			refs, err := referencedBlobs()
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				ui.Println("   gc won't delete blobs until every session's metadata can be read")
+				exit(exitCode(err))
+			}
+
+			root := filepath.Join(capturesDir, blobsDir)
+			var removed, kept int
+			var freed int64
+			err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+
+				hash := strings.TrimSuffix(d.Name(), ".png")
+				if refs[hash] {
+					kept++
+					return nil
+				}
+
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				if !dryRun {
+					if err := os.Remove(path); err != nil {
+						return err
+					}
+					audit(auditDelete, path, "gc: unreferenced blob", "")
+				}
+				removed++
+				freed += info.Size()
+				return nil
+			})
+			if err != nil && !os.IsNotExist(err) {
+				ui.Printf("❌ gc failed: %v\n", err)
+				exit(exitCode(err))
+			}
+
+			verb := "Removed"
+			if dryRun {
+				verb = "Would remove"
+			}
+			ui.Printf("🧹 %s %d unreferenced blob(s), %s (%d still in use)\n", verb, removed, formatBytes(freed), kept)
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show what would be removed without deleting anything")
+	return cmd
+}
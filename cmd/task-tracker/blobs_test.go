@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReferencedBlobs(t *testing.T) {
+	saveSessions(t,
+		SessionMetadata{SessionID: "20260105_090000", Screenshots: []Screenshot{{Blob: "aa11"}, {Path: "screen_090000.png"}}},
+		SessionMetadata{SessionID: "20260105_093000_2", Screenshots: []Screenshot{{Blob: "bb22"}}},
+	)
+	// saveSessions leaves a session without metadata, as a running one is
+	if _, err := referencedBlobs(); err == nil {
+		t.Fatal("referencedBlobs succeeded with a session lacking metadata.json")
+	}
+	if err := os.Remove(filepath.Join(capturesDir, "20260105_120000")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(capturesDir, blobsDir, "aa"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := referencedBlobs()
+	if err != nil {
+		t.Fatalf("referencedBlobs: %v", err)
+	}
+	if len(refs) != 2 || !refs["aa11"] || !refs["bb22"] {
+		t.Errorf("refs = %v, want aa11 and bb22", refs)
+	}
+
+	broken := filepath.Join(capturesDir, "20260106_100000")
+	if err := os.Mkdir(broken, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(broken, "metadata.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := referencedBlobs(); err == nil {
+		t.Error("referencedBlobs succeeded with unparseable metadata")
+	}
+}
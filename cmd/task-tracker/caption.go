@@ -0,0 +1,53 @@
+package main
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// newCaptionCmd builds the caption command
+func newCaptionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "caption [session_id] [screenshot_num] [text]",
+		Short: "Attach a caption to a screenshot",
+		Long: `Attach a caption to a screenshot of a saved session (1-based number).
+Captions appear in the review file to give reviewers context the AI may miss.
+Pass an empty text to remove a caption.`,
+		Args: cobra.ExactArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			num, err := strconv.Atoi(args[1])
+			if err != nil || num < 1 || num > len(tracker.Screenshots) {
+				ui.Printf("❌ Invalid screenshot number '%s' (1-%d)\n", args[1], len(tracker.Screenshots))
+				exit(exitUsage)
+			}
+
+			tracker.Screenshots[num-1].Caption = args[2]
+			if err := tracker.saveMetadata(); err != nil {
+				ui.Printf("❌ Failed to save metadata: %v\n", err)
+				exit(exitCode(err))
+			}
+
+			if fileExists(filepath.Join(tracker.SessionDir, "review.md")) {
+				if err := tracker.GenerateReviewFile(5); err != nil {
+					ui.Printf("⚠️  Failed to regenerate review file: %v\n", err)
+				}
+			}
+
+			if args[2] == "" {
+				ui.Printf("✅ Removed caption from screenshot %d\n", num)
+			} else {
+				ui.Printf("✅ Captioned screenshot %d: %s\n", num, args[2])
+			}
+		},
+	}
+}
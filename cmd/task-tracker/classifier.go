@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/ui"
+)
+
+// ClassifierConfig is a local model that flags frames likely to show
+// sensitive content, such as password fields or banking pages. Flagged
+// frames are quarantined before anything else sees them.
+//
+// The command runs for the whole session. For each frame it reads a JSON
+// line like {"image": "/abs/frame.png", "window": "Online Banking"} on
+// stdin and answers with one line like {"score": 0.93, "label": "banking"}.
+type ClassifierConfig struct {
+	Command   []string `json:"command,omitempty"`
+	Threshold float64  `json:"threshold"` // score at which a frame is quarantined
+}
+
+// defaultClassifierThreshold quarantines frames the model is fairly sure of
+const defaultClassifierThreshold = 0.5
+
+// Validate checks the threshold
+func (c ClassifierConfig) Validate() error {
+	if c.Threshold <= 0 || c.Threshold > 1 {
+		return fmt.Errorf("classifier.threshold must be above 0 and at most 1")
+	}
+	return nil
+}
+
+// quarantineDir holds a session's flagged frames. They're never part of
+// the session's screenshots, so reviews, exports and uploads skip them.
+const quarantineDir = "quarantine"
+
+// QuarantinedFrame is a frame the classifier flagged
+type QuarantinedFrame struct {
+	Path         string  `json:"path"`
+	Monitor      int     `json:"monitor"`
+	Timestamp    string  `json:"timestamp"`
+	RelativeTime float64 `json:"relative_time"`
+	Resolution   string  `json:"resolution"`
+	Label        string  `json:"label,omitempty"`
+	Score        float64 `json:"score"`
+}
+
+// verdict is the classifier's answer for one frame
+type verdict struct {
+	Score float64 `json:"score"`
+	Label string  `json:"label"`
+}
+
+// classifier keeps the model process running between frames; it's only
+// used from the writer goroutine
+type classifier struct {
+	cfg    ClassifierConfig
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Scanner
+}
+
+// start launches the model process
+func (c *classifier) start() error {
+	cmd := exec.Command(c.cfg.Command[0], c.cfg.Command[1:]...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", c.cfg.Command[0], err)
+	}
+	c.cmd, c.stdin, c.stdout = cmd, stdin, bufio.NewScanner(stdout)
+	return nil
+}
+
+// classify asks the model about the frame at path. A model that fails is
+// restarted on the next frame.
+func (c *classifier) classify(path, window string) (verdict, error) {
+	if c.cmd == nil {
+		if err := c.start(); err != nil {
+			return verdict{}, err
+		}
+	}
+
+	line, err := json.Marshal(map[string]string{"image": path, "window": window})
+	if err != nil {
+		return verdict{}, err
+	}
+	var v verdict
+	if _, err = c.stdin.Write(append(line, '\n')); err == nil {
+		if !c.stdout.Scan() {
+			err = fmt.Errorf("%s exited", c.cfg.Command[0])
+		} else if jsonErr := json.Unmarshal(c.stdout.Bytes(), &v); jsonErr != nil {
+			err = fmt.Errorf("%s wrote an invalid verdict: %v", c.cfg.Command[0], jsonErr)
+		}
+	}
+	if err != nil {
+		c.close()
+		return verdict{}, err
+	}
+	return v, nil
+}
+
+// close stops the model process
+func (c *classifier) close() {
+	if c.cmd == nil {
+		return
+	}
+	c.stdin.Close()
+	done := make(chan struct{})
+	go func() {
+		c.cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		c.cmd.Process.Kill()
+		<-done
+	}
+	c.cmd = nil
+}
+
+// quarantine classifies a frame before it's saved. Flagged frames are
+// written to the quarantine directory and recorded instead of becoming a
+// screenshot; it reports whether that happened. If the model can't answer,
+// the frame is quarantined too: privacy wins over a complete session.
+func (t *TaskTracker) quarantine(f capturedFrame, at time.Time, window string) bool {
+	dir := filepath.Join(t.SessionDir, quarantineDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		ui.Printf("⚠️  Failed to create %s: %v\n", dir, err)
+		return false
+	}
+	path := filepath.Join(dir, f.filename)
+	if err := writePNG(path, f.img, nil); err != nil {
+		ui.Printf("⚠️  Failed to write %s for classification: %v\n", path, err)
+		return false
+	}
+
+	abs, _ := filepath.Abs(path)
+	v, err := t.Classifier.classify(abs, window)
+	if err != nil {
+		ui.Printf("⚠️  Classifier failed, quarantining the frame: %v\n", err)
+		t.log(logWarn, "error", fmt.Sprintf("Classifier failed, quarantining the frame: %v", err))
+		v = verdict{Score: 1, Label: "unclassified"}
+	} else if v.Score < t.Classifier.cfg.Threshold {
+		os.Remove(path)
+		return false
+	}
+
+	b := f.img.Bounds()
+	t.mu.Lock()
+	t.Quarantined = append(t.Quarantined, QuarantinedFrame{
+		Path:         path,
+		Monitor:      f.monitorIdx + 1,
+		Timestamp:    at.Format(time.RFC3339),
+		RelativeTime: at.Sub(t.StartTime).Seconds(),
+		Resolution:   fmt.Sprintf("%dx%d", b.Dx(), b.Dy()),
+		Label:        v.Label,
+		Score:        v.Score,
+	})
+	t.mu.Unlock()
+
+	ui.Printf("🔒 Quarantined a frame from monitor %d (%s, %.2f)\n", f.monitorIdx+1, strings.TrimSpace(v.Label), v.Score)
+	t.log(logInfo, "skip", fmt.Sprintf("Quarantined a frame from monitor %d (%s, %.2f)", f.monitorIdx+1, strings.TrimSpace(v.Label), v.Score))
+	return true
+}
+
+// quarantineEntries lists quarantined frames in the timeline so the review
+// shows that something was withheld, without showing it
+func (t *TaskTracker) quarantineEntries() []timelineEntry {
+	entries := make([]timelineEntry, 0, len(t.Quarantined))
+	for _, q := range t.Quarantined {
+		entries = append(entries, timelineEntry{
+			RelativeTime: q.RelativeTime,
+			Text: fmt.Sprintf("> 🔒 **%s:** %s",
+				i18n.T("timeline.quarantined", q.RelativeTime/60), i18n.T("timeline.quarantined_why")),
+		})
+	}
+	return entries
+}
+
+// activeWindow returns the focused window's title, if the backend knows it
+func (t *TaskTracker) activeWindow() string {
+	if in, ok := t.Capturer.(capture.Inspector); ok {
+		title, _ := in.ActiveWindow()
+		return title
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"time"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/imaging"
+	"task-tracker/internal/ui"
+)
+
+// clipboardPoll is how often the clipboard is checked for a new image
+const clipboardPoll = 2 * time.Second
+
+// clipboardMonitor is the monitor index of images copied to the clipboard;
+// they're saved as monitor -1
+const clipboardMonitor = -2
+
+// sourceClipboard marks screenshots that were copied to the clipboard
+// rather than captured
+const sourceClipboard = "clipboard"
+
+// clipboardReader returns the capturer's clipboard, or nil when the
+// backend can't read one
+func (t *TaskTracker) clipboardReader() capture.ClipboardReader {
+	r, _ := t.Capturer.(capture.ClipboardReader)
+	return r
+}
+
+// primeClipboard remembers what's on the clipboard when the session starts,
+// so only images copied during it are saved
+func (t *TaskTracker) primeClipboard() {
+	if r := t.clipboardReader(); r != nil {
+		if data, ok := r.ClipboardImage(); ok {
+			t.clipboardSum = sha256.Sum256(data)
+		}
+	}
+}
+
+// checkClipboard adds an image newly copied to the clipboard, such as a
+// snip taken by hand, to the session as a manual capture. Images copied
+// while capture is paused for privacy or a blackout are skipped.
+func (t *TaskTracker) checkClipboard(now time.Time) {
+	r := t.clipboardReader()
+	if r == nil {
+		return
+	}
+	data, ok := r.ClipboardImage()
+	if !ok {
+		return
+	}
+	sum := sha256.Sum256(data)
+	if sum == t.clipboardSum {
+		return
+	}
+	t.clipboardSum = sum
+	if t.inBlackout(now) || t.private(now) || !t.pausedSince().IsZero() {
+		return
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		ui.Printf("⚠️  Couldn't read the image on the clipboard: %v\n", err)
+		return
+	}
+	t.queueTick(capturedTick{
+		at:     now,
+		source: sourceClipboard,
+		frames: []capturedFrame{{
+			img:        imaging.ToRGBA(img),
+			monitorIdx: clipboardMonitor,
+			filename:   fmt.Sprintf("clip_%s.png", now.Format("150405")),
+		}},
+	})
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/ui"
+)
+
+// compareTopN is how many apps or categories a comparison lists
+const compareTopN = 10
+
+// comparisonRow is one line of a side-by-side comparison
+type comparisonRow struct {
+	Label string
+	A, B  string
+	Delta string
+}
+
+// minutesDelta formats the change from a to b minutes
+func minutesDelta(a, b float64) string {
+	d := b - a
+	if math.Abs(d) < 0.05 {
+		return "="
+	}
+	s := i18n.T("compare.min_delta", d)
+	if a > 0 {
+		s += fmt.Sprintf(" (%+.0f%%)", d/a*100)
+	}
+	return s
+}
+
+// countDelta formats the change from a to b
+func countDelta(a, b int) string {
+	if a == b {
+		return "="
+	}
+	return fmt.Sprintf("%+d", b-a)
+}
+
+// overviewRows compares the headline numbers of two sessions
+func overviewRows(a, b *TaskTracker) []comparisonRow {
+	activeA, activeB := a.activeDuration().Minutes(), b.activeDuration().Minutes()
+	wallA, wallB := a.wallDuration().Minutes(), b.wallDuration().Minutes()
+
+	rows := []comparisonRow{
+		{i18n.T("digest.task"), a.TaskName, b.TaskName, ""},
+		{i18n.T("report.ticket"), a.Ticket.String(), b.Ticket.String(), ""},
+		{i18n.T("digest.started"), a.StartTime.Local().Format("2006-01-02 15:04"), b.StartTime.Local().Format("2006-01-02 15:04"), ""},
+		{i18n.T("digest.active"), i18n.T("compare.min", activeA), i18n.T("compare.min", activeB), minutesDelta(activeA, activeB)},
+		{i18n.T("compare.wall"), i18n.T("compare.min", wallA), i18n.T("compare.min", wallB), minutesDelta(wallA, wallB)},
+		{i18n.T("report.screenshots"), fmt.Sprint(len(a.Screenshots)), fmt.Sprint(len(b.Screenshots)), countDelta(len(a.Screenshots), len(b.Screenshots))},
+		{i18n.T("compare.gaps"), fmt.Sprint(len(a.Gaps)), fmt.Sprint(len(b.Gaps)), countDelta(len(a.Gaps), len(b.Gaps))},
+		{i18n.T("report.notes"), fmt.Sprint(len(a.Notes)), fmt.Sprint(len(b.Notes)), countDelta(len(a.Notes), len(b.Notes))},
+		{i18n.T("review.markers"), fmt.Sprint(len(a.Markers)), fmt.Sprint(len(b.Markers)), countDelta(len(a.Markers), len(b.Markers))},
+	}
+	if len(a.Productivity) > 0 || len(b.Productivity) > 0 {
+		pa, pb := productivityPulse(a.Productivity), productivityPulse(b.Productivity)
+		rows = append(rows, comparisonRow{i18n.T("compare.pulse"), fmt.Sprintf("%.0f", pa), fmt.Sprintf("%.0f", pb), fmt.Sprintf("%+.0f", pb-pa)})
+	}
+	return rows
+}
+
+// appUsage totals minutes per app from imported ActivityWatch events
+func appUsage(t *TaskTracker) map[string]float64 {
+	usage := make(map[string]float64)
+	for _, e := range t.Activity {
+		if !e.AFK {
+			usage[e.App] += e.DurationSeconds / 60
+		}
+	}
+	return usage
+}
+
+// categoryUsage totals minutes per RescueTime category
+func categoryUsage(t *TaskTracker) map[string]float64 {
+	usage := make(map[string]float64)
+	for _, e := range t.Productivity {
+		usage[e.Category] += e.Seconds / 60
+	}
+	return usage
+}
+
+// usageRows compares two usage maps, biggest first
+func usageRows(a, b map[string]float64) []comparisonRow {
+	names := make([]string, 0, len(a)+len(b))
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		mi, mj := math.Max(a[names[i]], b[names[i]]), math.Max(a[names[j]], b[names[j]])
+		if mi != mj {
+			return mi > mj
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > compareTopN {
+		names = names[:compareTopN]
+	}
+
+	rows := make([]comparisonRow, len(names))
+	for i, name := range names {
+		rows[i] = comparisonRow{name, i18n.T("compare.min", a[name]), i18n.T("compare.min", b[name]), minutesDelta(a[name], b[name])}
+	}
+	return rows
+}
+
+// renderComparison prints rows as a terminal table
+func renderComparison(first string, a, b *TaskTracker, rows []comparisonRow) {
+	table := ui.NewTable(first, a.SessionID, b.SessionID, i18n.T("compare.change"))
+	for _, r := range rows {
+		table.AddRow(r.Label, r.A, r.B, r.Delta)
+	}
+	table.Render()
+}
+
+// writeComparisonTable renders rows as a markdown table
+func writeComparisonTable(md *strings.Builder, first string, a, b *TaskTracker, rows []comparisonRow) {
+	md.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n|---|---|---|---|\n", first, a.SessionID, b.SessionID, i18n.T("compare.change")))
+	for _, r := range rows {
+		md.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", r.Label, r.A, r.B, r.Delta))
+	}
+	md.WriteString("\n")
+}
+
+// writeComparison writes a markdown comparison with sampled frames side by
+// side. Image paths are relative to the output file.
+func writeComparison(a, b *TaskTracker, output string, samples int) error {
+	var md strings.Builder
+	md.WriteString(fmt.Sprintf("# %s\n\n", i18n.T("compare.title", a.SessionID, b.SessionID)))
+	writeComparisonTable(&md, "", a, b, overviewRows(a, b))
+
+	if apps := usageRows(appUsage(a), appUsage(b)); len(apps) > 0 {
+		md.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("compare.apps")))
+		writeComparisonTable(&md, i18n.T("compare.app"), a, b, apps)
+	}
+	if categories := usageRows(categoryUsage(a), categoryUsage(b)); len(categories) > 0 {
+		md.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("compare.categories")))
+		writeComparisonTable(&md, i18n.T("compare.category"), a, b, categories)
+	}
+
+	shotsA, shotsB := a.sampleScreenshots(samples), b.sampleScreenshots(samples)
+	if len(shotsA) > 0 || len(shotsB) > 0 {
+		outDir, _ := filepath.Abs(filepath.Dir(output))
+		frame := func(t *TaskTracker, shots []Screenshot, i int) (string, error) {
+			if i >= len(shots) {
+				return "", nil
+			}
+			path, err := t.viewablePath(shots[i])
+			if err != nil {
+				return "", err
+			}
+			if abs, err := filepath.Abs(path); err == nil {
+				if rel, err := filepath.Rel(outDir, abs); err == nil {
+					path = rel
+				}
+			}
+			return fmt.Sprintf("%s<br>![](%s)", i18n.T("compare.min", shots[i].RelativeTime/60), filepath.ToSlash(path)), nil
+		}
+
+		md.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("compare.frames")))
+		md.WriteString(fmt.Sprintf("| %s | %s |\n|---|---|\n", a.SessionID, b.SessionID))
+		for i := 0; i < max(len(shotsA), len(shotsB)); i++ {
+			left, err := frame(a, shotsA, i)
+			if err != nil {
+				return err
+			}
+			right, err := frame(b, shotsB, i)
+			if err != nil {
+				return err
+			}
+			md.WriteString(fmt.Sprintf("| %s | %s |\n", left, right))
+		}
+		md.WriteString("\n")
+	}
+
+	return os.WriteFile(output, []byte(md.String()), 0644)
+}
+
+// newCompareCmd builds the compare command
+func newCompareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare [session_id] [session_id]",
+		Short: "Compare two sessions side by side",
+		Long: `Compare durations, app usage (from 'activitywatch import') and activity
+classification (from 'rescuetime import') of two sessions, e.g. before and after
+a process change or two attempts at the same task.
+
+With -o the comparison is also written as markdown with sampled frames of both
+sessions side by side.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			a, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			b, err := loadSession(args[1])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			renderComparison("", a, b, overviewRows(a, b))
+			if apps := usageRows(appUsage(a), appUsage(b)); len(apps) > 0 {
+				ui.Println("\n🪟 " + i18n.T("compare.apps"))
+				renderComparison(i18n.T("compare.app"), a, b, apps)
+			}
+			if categories := usageRows(categoryUsage(a), categoryUsage(b)); len(categories) > 0 {
+				ui.Println("\n📊 " + i18n.T("compare.categories"))
+				renderComparison(i18n.T("compare.category"), a, b, categories)
+			}
+
+			output, _ := cmd.Flags().GetString("output")
+			if output == "" {
+				return
+			}
+			samples, _ := cmd.Flags().GetInt("samples")
+			if err := writeComparison(a, b, output, samples); err != nil {
+				ui.Printf("❌ Failed to write comparison: %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("\n✅ Comparison with sampled frames saved to %s\n", output)
+		},
+	}
+	cmd.Flags().StringP("output", "o", "", "Also write a markdown comparison with sampled frames")
+	cmd.Flags().Int("samples", 5, "Frames sampled from each session for -o")
+	return cmd
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/ui"
+)
+
+// compositeMonitor is the monitor index of a frame composited from all
+// captured monitors; it's saved as monitor 0
+const compositeMonitor = -1
+
+// captureComposite queues one frame holding every captured monitor in its
+// place on the virtual desktop
+func (t *TaskTracker) captureComposite(now time.Time, monitors []int) {
+	regions := t.maskedRegions()
+	img, err := capture.Composite(t.Capturer, monitors)
+	t.recordCaptures(monitors, err, now)
+	if img == nil {
+		return
+	}
+
+	desktop := image.Rectangle{}
+	for _, m := range monitors {
+		desktop = desktop.Union(t.Capturer.Bounds(m))
+	}
+	maskFrame(img, regions, desktop)
+	luma, skip := t.unchanged(compositeMonitor, img)
+	if skip {
+		ui.Printf("💤 Unchanged: %s (%d frames skipped so far)\n", now.Format("150405"), t.Duplicates)
+		return
+	}
+	pos, known := t.cursor()
+
+	filename := fmt.Sprintf("screen_all_%s.png", t.frameStamp(now))
+	t.queueTick(capturedTick{
+		at: now,
+		frames: []capturedFrame{{
+			img:        img,
+			monitorIdx: compositeMonitor,
+			filename:   filename,
+			cursor:     frameCursor(pos, known, desktop, img),
+			luma:       luma,
+		}},
+	})
+}
+
+// monitorLabel names a screenshot's monitor for the review file
+func monitorLabel(monitor int) string {
+	if monitor == compositeMonitor+1 {
+		return "all (composite)"
+	}
+	if monitor == clipboardMonitor+1 {
+		return "clipboard"
+	}
+	return fmt.Sprintf("%d", monitor)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"task-tracker/internal/archive"
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/paths"
+)
+
+// Config holds user settings loaded from config.json
+type Config struct {
+	Rounding   RoundingConfig   `json:"rounding"`
+	Watermark  WatermarkConfig  `json:"watermark"`
+	Disk       DiskConfig       `json:"disk"`
+	Battery    BatteryConfig    `json:"battery"`
+	Load       LoadConfig       `json:"load"`
+	Blackout   []BlackoutWindow `json:"blackout,omitempty"`
+	Review     ReviewConfig     `json:"review"`
+	Language   string           `json:"language,omitempty"`
+	OutputDir  string           `json:"output_dir,omitempty"` // capture sessions; default ./task_captures
+	Rules      string           `json:"rules,omitempty"`      // Starlark rules script
+	Capture    CaptureConfig    `json:"capture"`
+	Summarizer SummarizerConfig `json:"summarizer"`
+	Embeddings EmbeddingsConfig `json:"embeddings"`
+	Classifier ClassifierConfig `json:"classifier"`
+	Archive    ArchiveConfig    `json:"archive"`
+	Mask       MaskConfig       `json:"mask"`
+	Repo       RepoConfig       `json:"repo"`
+	Timesheet  TimesheetConfig  `json:"timesheet"`
+	Digest     DigestConfig     `json:"digest"`
+
+	Notifications NotificationsConfig `json:"notifications"`
+}
+
+// defaultConfig returns the settings used when the config file doesn't set
+// them
+func defaultConfig() *Config {
+	return &Config{
+		Rounding: RoundingConfig{Mode: RoundNone},
+		Disk:     DiskConfig{LowSpace: defaultLowSpace, CheckEvery: Duration{defaultDiskCheck}},
+		Battery: BatteryConfig{
+			SlowBelow:  defaultBatterySlow,
+			JPEGBelow:  defaultBatteryJPEG,
+			CheckEvery: Duration{defaultBatteryCheck},
+		},
+		Load: LoadConfig{
+			CPUAbove:    defaultLoadCPU,
+			MemoryBelow: defaultLoadMemory,
+			SelfAbove:   defaultLoadSelf,
+			MaxDefer:    Duration{defaultLoadMaxDefer},
+			CheckEvery:  Duration{defaultLoadCheck},
+		},
+		Review: ReviewConfig{
+			MaxTokens: defaultReviewTokens,
+			MaxBytes:  defaultReviewBytes,
+			MinDetail: defaultMinDetail,
+			MinChange: defaultMinChange,
+		},
+		Capture:    CaptureConfig{MaxFailures: defaultMaxFailures, RetryEvery: Duration{defaultRetryEvery}},
+		Summarizer: SummarizerConfig{Timeout: Duration{defaultSummarizerTimeout}},
+		Embeddings: EmbeddingsConfig{Timeout: Duration{defaultEmbeddingsTimeout}},
+		Classifier: ClassifierConfig{Threshold: defaultClassifierThreshold},
+		Archive:    ArchiveConfig{Compression: archive.Deflate},
+		Mask:       MaskConfig{Own: true},
+	}
+}
+
+// loadConfig reads the config file, returning defaults if it doesn't exist.
+// Unknown keys and invalid values are errors, with the line they're on.
+func loadConfig() (*Config, error) {
+	path, err := paths.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return defaultConfig(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg, problems := parseConfig(path, data)
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid config %w", problems[0])
+	}
+	return cfg, nil
+}
+
+// Validate checks every section's settings
+func (c *Config) Validate() error {
+	if err := c.Rounding.Validate(); err != nil {
+		return err
+	}
+	if err := c.Watermark.Validate(); err != nil {
+		return err
+	}
+	if c.Disk.CheckEvery.Duration < time.Second {
+		return fmt.Errorf("disk.check_every must be at least 1s")
+	}
+	if err := c.Battery.Validate(); err != nil {
+		return err
+	}
+	if err := c.Load.Validate(); err != nil {
+		return err
+	}
+	if err := c.Capture.Validate(); err != nil {
+		return err
+	}
+	if err := c.Summarizer.Validate(); err != nil {
+		return err
+	}
+	if err := c.Classifier.Validate(); err != nil {
+		return err
+	}
+	if err := c.Embeddings.Validate(); err != nil {
+		return err
+	}
+	if err := c.Review.Validate(); err != nil {
+		return err
+	}
+	if err := c.Archive.Validate(); err != nil {
+		return err
+	}
+	if err := c.Mask.Validate(); err != nil {
+		return err
+	}
+	if err := c.Repo.Validate(); err != nil {
+		return err
+	}
+	if err := c.Timesheet.Validate(); err != nil {
+		return err
+	}
+	if err := c.Digest.Validate(); err != nil {
+		return err
+	}
+	if err := c.Notifications.Validate(); err != nil {
+		return err
+	}
+	if c.Language != "" {
+		if !i18n.Supports(c.Language) {
+			return fmt.Errorf("language: unsupported language '%s' (use %s)",
+				c.Language, strings.Join(i18n.Supported(), ", "))
+		}
+	}
+	for _, b := range c.Blackout {
+		if err := b.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Duration is a time.Duration that reads and writes as a string like "15m"
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"15m\": %w", err)
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// ByteSize is a byte count that reads and writes as a string like "1GB"
+type ByteSize int64
+
+func (b ByteSize) MarshalJSON() ([]byte, error) {
+	return json.Marshal(formatBytes(int64(b)))
+}
+
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("size must be a string like \"1GB\": %w", err)
+	}
+
+	n, err := parseSize(s)
+	if err != nil {
+		return err
+	}
+	*b = ByteSize(n)
+	return nil
+}
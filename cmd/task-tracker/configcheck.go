@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/paths"
+	"task-tracker/internal/ui"
+)
+
+// configError is a problem in the config file, with the line and column it
+// starts at when known
+type configError struct {
+	path      string
+	line, col int
+	err       error
+}
+
+func (e *configError) Error() string {
+	if e.line == 0 {
+		return fmt.Sprintf("%s: %v", e.path, e.err)
+	}
+	return fmt.Sprintf("%s:%d:%d: %v", e.path, e.line, e.col, e.err)
+}
+
+func (e *configError) Unwrap() error {
+	return e.err
+}
+
+// configKeyPath matches the key a validation message starts with, e.g.
+// "battery.check_every" or "notifications.routes[0]"
+var configKeyPath = regexp.MustCompile(`^[a-z_]+(?:\.[a-z_]+|\[\d+\])*`)
+
+// parseConfig reads config JSON over the defaults; lines starting with //
+// are comments. Unlike plain json.Unmarshal it reports keys Config doesn't
+// have, and it checks every setting, so a typo fails here instead of
+// quietly meaning the default. Problems are in file order; only the first
+// validation failure is reported, after the file parses.
+func parseConfig(path string, data []byte) (*Config, []error) {
+	data = stripComments(data)
+	w := &configWalker{path: path, data: data, dec: json.NewDecoder(bytes.NewReader(data)), keys: map[string]int64{}}
+	// The decoder's token errors are vaguer about where JSON breaks
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, []error{w.syntaxError(err)}
+	}
+	if err := w.value(reflect.TypeOf(Config{}), ""); err != nil {
+		return nil, append(w.problems, w.syntaxError(err))
+	}
+	if len(w.problems) > 0 {
+		return nil, w.problems
+	}
+
+	cfg := defaultConfig()
+	if err := json.Unmarshal(data, cfg); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, []error{w.at(w.keyOffset(typeErr.Field, typeErr.Offset), fmt.Errorf("%s: expected %s, got a JSON %s", typeErr.Field, typeErr.Type, typeErr.Value))}
+		}
+		return nil, []error{w.syntaxError(err)}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		key := configKeyPath.FindString(err.Error())
+		for key != "" {
+			if off, ok := w.keys[key]; ok {
+				return nil, []error{w.at(off, err)}
+			}
+			key = key[:max(strings.LastIndexAny(key, ".["), 0)]
+		}
+		return nil, []error{&configError{path: path, err: err}}
+	}
+	return cfg, nil
+}
+
+// stripComments blanks lines starting with //, which JSON doesn't allow,
+// keeping line numbers as they are in the file
+func stripComments(data []byte) []byte {
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		if bytes.HasPrefix(bytes.TrimSpace(line), []byte("//")) {
+			lines[i] = nil
+		}
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
+
+// configWalker walks config JSON token by token alongside the Config type,
+// recording where each key is and collecting keys that don't exist and
+// values their type's UnmarshalJSON rejects (durations, sizes)
+type configWalker struct {
+	path     string
+	data     []byte
+	dec      *json.Decoder
+	keys     map[string]int64 // key path -> offset of the key
+	problems []error
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// value walks the next value as type t. It only returns syntax errors;
+// a value of the wrong kind is left for json.Unmarshal to report.
+func (w *configWalker) value(t reflect.Type, key string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	start := w.dec.InputOffset()
+
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		var raw json.RawMessage
+		if err := w.dec.Decode(&raw); err != nil {
+			return err
+		}
+		target := reflect.New(t).Interface().(json.Unmarshaler)
+		if err := target.UnmarshalJSON(raw); err != nil {
+			w.problems = append(w.problems, w.at(w.keyOffset(key, start), fmt.Errorf("%s: %v", key, err)))
+		}
+		return nil
+	}
+
+	tok, err := w.dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case delim == '{' && t.Kind() == reflect.Struct:
+		for w.dec.More() {
+			name, err := w.key(key)
+			if err != nil {
+				return err
+			}
+			field, ok := jsonField(t, name)
+			if !ok {
+				w.problems = append(w.problems, w.at(w.keys[joinKey(key, name)], fmt.Errorf("unknown key '%s'", joinKey(key, name))))
+				if err := w.skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := w.value(field.Type, joinKey(key, name)); err != nil {
+				return err
+			}
+		}
+	case delim == '{' && t.Kind() == reflect.Map:
+		for w.dec.More() {
+			name, err := w.key(key)
+			if err != nil {
+				return err
+			}
+			if err := w.value(t.Elem(), joinKey(key, name)); err != nil {
+				return err
+			}
+		}
+	case delim == '[' && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+		for i := 0; w.dec.More(); i++ {
+			elem := fmt.Sprintf("%s[%d]", key, i)
+			w.keys[elem] = w.dec.InputOffset()
+			if err := w.value(t.Elem(), elem); err != nil {
+				return err
+			}
+		}
+	default:
+		return w.skipRest()
+	}
+	_, err = w.dec.Token() // closing delimiter
+	return err
+}
+
+// key reads an object key and records where it is
+func (w *configWalker) key(parent string) (string, error) {
+	tok, err := w.dec.Token()
+	if err != nil {
+		return "", err
+	}
+	name, _ := tok.(string)
+	quoted, _ := json.Marshal(name)
+	w.keys[joinKey(parent, name)] = w.dec.InputOffset() - int64(len(quoted))
+	return name, nil
+}
+
+// keyOffset is where key is, or start for the top level
+func (w *configWalker) keyOffset(key string, start int64) int64 {
+	if off, ok := w.keys[key]; ok {
+		return off
+	}
+	return start
+}
+
+// skip consumes the next value
+func (w *configWalker) skip() error {
+	var raw json.RawMessage
+	return w.dec.Decode(&raw)
+}
+
+// skipRest consumes the rest of an object or array whose opening
+// delimiter was read
+func (w *configWalker) skipRest() error {
+	for depth := 1; depth > 0; {
+		tok, err := w.dec.Token()
+		if err != nil {
+			return err
+		}
+		if d, ok := tok.(json.Delim); ok {
+			if d == '{' || d == '[' {
+				depth++
+			} else {
+				depth--
+			}
+		}
+	}
+	return nil
+}
+
+// at places err at a byte offset in the file
+func (w *configWalker) at(offset int64, err error) *configError {
+	offset = min(max(offset, 0), int64(len(w.data)))
+	// Point at the key or value, not the whitespace before it
+	for offset < int64(len(w.data)) && strings.ContainsRune(" \t\r\n,:", rune(w.data[offset])) {
+		offset++
+	}
+	before := w.data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := int(offset) - bytes.LastIndexByte(before, '\n')
+	return &configError{path: w.path, line: line, col: col, err: err}
+}
+
+// syntaxError places a JSON syntax error
+func (w *configWalker) syntaxError(err error) *configError {
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) {
+		return w.at(syntax.Offset-1, errors.New(strings.TrimPrefix(syntax.Error(), "json: ")))
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return w.at(int64(len(w.data)), errors.New("unexpected end of file"))
+	}
+	return &configError{path: w.path, err: err}
+}
+
+// jsonField finds the struct field a JSON key sets, matching names the way
+// encoding/json does
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		if strings.EqualFold(tag, name) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func joinKey(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// newConfigCmd builds the config command
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Check the config file",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "validate [file]",
+		Short: "Report unknown keys and invalid settings in the config file",
+		Long: `Check the config file (default: the one task-tracker reads, see
+TASK_TRACKER_CONFIG) without starting anything. Unknown keys, malformed
+durations and sizes, and invalid settings are reported with the line and
+column they're on. start refuses an invalid config the same way, rather
+than quietly using defaults.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			path, err := paths.Config()
+			if len(args) > 0 {
+				path, err = args[0], nil
+			}
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitUsage)
+			}
+
+			if _, problems := parseConfig(path, data); len(problems) > 0 {
+				for _, p := range problems {
+					ui.Printf("❌ %v\n", p)
+				}
+				exit(exitUsage)
+			}
+			ui.Printf("✅ %s is valid\n", path)
+		},
+	})
+	return cmd
+}
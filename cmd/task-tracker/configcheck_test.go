@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfigPositions(t *testing.T) {
+	type problem struct {
+		line, col int
+		msg       string
+	}
+	tests := []struct {
+		name string
+		data string
+		want []problem
+	}{
+		{
+			name: "unknown keys",
+			data: `{
+  // Billing
+  "rounding": {"mode": "up", "increment": "15m"},
+  "colour": true,
+  "capture": {
+    "intervall": "30s"
+  }
+}`,
+			want: []problem{
+				{4, 3, "unknown key 'colour'"},
+				{6, 5, "unknown key 'capture.intervall'"},
+			},
+		},
+		{
+			name: "invalid duration",
+			data: `{
+  "capture": {"interval": "soon"}
+}`,
+			want: []problem{{2, 15, "capture.interval"}},
+		},
+		{
+			name: "invalid monitors",
+			data: `{
+  "capture": {
+    "monitors": "0,2"
+  }
+}`,
+			want: []problem{{3, 5, "capture.monitors: invalid monitor '0'"}},
+		},
+		{
+			name: "monitors mixed with all",
+			data: `{"capture": {"monitors": "all, DP-1"}}`,
+			want: []problem{{1, 14, "capture.monitors: 'all' can't be combined"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, errs := parseConfig("config.json", []byte(tt.data))
+			if cfg != nil {
+				t.Fatal("parseConfig accepted an invalid config")
+			}
+			if len(errs) != len(tt.want) {
+				t.Fatalf("got %d problems %v, want %d", len(errs), errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				var cerr *configError
+				if !errors.As(errs[i], &cerr) {
+					t.Fatalf("problem %d is %T, want *configError", i, errs[i])
+				}
+				if cerr.line != want.line || cerr.col != want.col || !strings.Contains(cerr.err.Error(), want.msg) {
+					t.Errorf("problem %d = %v, want config.json:%d:%d: %s...", i, errs[i], want.line, want.col, want.msg)
+				}
+			}
+		})
+	}
+}
+
+func TestParseConfigValid(t *testing.T) {
+	cfg, errs := parseConfig("config.json", []byte(`{
+  // Comments are allowed on their own line
+  "capture": {"interval": "1m", "monitors": "1, DP-2"}
+}`))
+	if len(errs) > 0 {
+		t.Fatalf("parseConfig: %v", errs)
+	}
+	if cfg.Capture.Interval.Duration != time.Minute || cfg.Capture.Monitors != "1, DP-2" {
+		t.Errorf("capture = %+v, want interval 1m and monitors '1, DP-2'", cfg.Capture)
+	}
+	if cfg.Capture.MaxFailures != defaultMaxFailures {
+		t.Errorf("max_failures = %d, want the default %d", cfg.Capture.MaxFailures, defaultMaxFailures)
+	}
+}
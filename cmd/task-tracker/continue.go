@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/ui"
+)
+
+// sessionChain returns the sessions t continues, most recent first. A
+// missing or looping link ends the chain.
+func sessionChain(t *TaskTracker) []*TaskTracker {
+	var chain []*TaskTracker
+	seen := map[string]bool{t.SessionID: true}
+	for id := t.Parent; id != "" && !seen[id]; {
+		seen[id] = true
+		parent, err := loadSession(id)
+		if err != nil {
+			break
+		}
+		chain = append(chain, parent)
+		id = parent.Parent
+	}
+	return chain
+}
+
+// writeContinues adds the parent link and the task's time across all
+// linked sessions to the review file header
+func writeContinues(md *strings.Builder, t *TaskTracker) {
+	if t.Parent == "" {
+		return
+	}
+
+	chain := sessionChain(t)
+	total := t.activeDuration().Minutes()
+	for _, s := range chain {
+		total += s.activeDuration().Minutes()
+	}
+	md.WriteString(fmt.Sprintf("**%s:** %s\n", i18n.T("review.continues"),
+		i18n.T("review.chain", t.Parent, total, len(chain)+1)))
+}
+
+// newContinueCmd builds the continue command
+func newContinueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "continue <session_id|ticket>",
+		Short: "Start a new session continuing an earlier task",
+		Long: `Start capturing a new session that continues an earlier one, e.g. the next
+day. The task name, ticket and tags are taken from the earlier session (the
+latest one for a ticket) unless overridden with flags, and the new session links
+back to it so review files show the total time spent on the task.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			parent, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			ui.Printf("🔗 Continuing %s (%s)\n", parent.TaskName, parent.SessionID)
+			runStart(cmd, "", parent)
+		},
+	}
+	addStartFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"image"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/imaging"
+)
+
+// CursorPos is the mouse position in a screenshot's pixels
+type CursorPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// cursor asks the capturer where the mouse is on the desktop
+func (t *TaskTracker) cursor() (image.Point, bool) {
+	p, ok := t.Capturer.(capture.Pointer)
+	if !ok {
+		return image.Point{}, false
+	}
+	return p.Cursor()
+}
+
+// frameCursor locates the mouse in a frame captured from bounds, or nil
+// when it's on another monitor or unknown
+func frameCursor(pos image.Point, known bool, bounds image.Rectangle, img *image.RGBA) *image.Point {
+	if !known {
+		return nil
+	}
+	p, ok := capture.FramePoint(pos, bounds, img.Bounds())
+	if !ok {
+		return nil
+	}
+	return &p
+}
+
+// drawCursor overlays the pointer on a frame when --cursor is on
+func (t *TaskTracker) drawCursor(f capturedFrame) {
+	if !t.DrawCursor || f.cursor == nil {
+		return
+	}
+	imaging.DrawCursor(f.img, *f.cursor, 1+f.img.Bounds().Dy()/1800)
+}
+
+// cursorPos converts a frame position to the form kept in metadata
+func cursorPos(f capturedFrame) *CursorPos {
+	if f.cursor == nil {
+		return nil
+	}
+	p := f.cursor.Sub(f.img.Bounds().Min)
+	return &CursorPos{X: p.X, Y: p.Y}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"task-tracker/internal/capture"
+	"task-tracker/internal/ui"
+)
+
+// desktopAway reports whether the captured desktop is disconnected, as when
+// the user closes an RDP window without logging off. Capture pauses instead
+// of failing on every tick, and the time isn't counted.
+func (t *TaskTracker) desktopAway() bool {
+	c, ok := t.Capturer.(capture.Connector)
+	if !ok {
+		return false
+	}
+
+	away := !c.Connected()
+	if away != t.disconnected {
+		t.disconnected = away
+		if away {
+			ui.Println("🔌 Remote desktop disconnected: capture paused until you reconnect")
+			t.addEvent("Remote desktop disconnected: capture paused")
+		} else {
+			ui.Println("🔌 Remote desktop reconnected, capture resumed")
+			t.addEvent("Remote desktop reconnected: capture resumed")
+		}
+	}
+	return away
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/ui"
+)
+
+// digestTopN is how many activities and notable sessions a digest lists
+const digestTopN = 5
+
+// digestCheckEvery is how often digest --daemon looks at the clock. Checking
+// rather than sleeping until the send time copes with the machine sleeping.
+const digestCheckEvery = time.Minute
+
+// DigestConfig is when digest --daemon sends the weekly digest
+type DigestConfig struct {
+	Day string `json:"day,omitempty"` // mon-sun; default fri
+	At  string `json:"at,omitempty"`  // "16:00"; default 16:00
+}
+
+// Validate checks the day and time
+func (c DigestConfig) Validate() error {
+	if c.Day != "" {
+		if _, ok := parseWeekday(c.Day); !ok {
+			return fmt.Errorf("digest.day: unknown day '%s' (use mon, tue, ...)", c.Day)
+		}
+	}
+	if c.At != "" {
+		if _, err := parseClock(c.At); err != nil {
+			return fmt.Errorf("digest.at: %w", err)
+		}
+	}
+	return nil
+}
+
+// next returns the first scheduled send time after after, in after's
+// location
+func (c DigestConfig) next(after time.Time) time.Time {
+	day := time.Friday
+	if c.Day != "" {
+		day, _ = parseWeekday(c.Day)
+	}
+	minutes := 16 * 60
+	if c.At != "" {
+		minutes, _ = parseClock(c.At)
+	}
+
+	y, m, d := after.Date()
+	at := time.Date(y, m, d, minutes/60, minutes%60, 0, 0, after.Location())
+	at = at.AddDate(0, 0, (int(day)-int(at.Weekday())+7)%7)
+	if !at.After(after) {
+		at = at.AddDate(0, 0, 7)
+	}
+	return at
+}
+
+func (c DigestConfig) String() string {
+	day, at := c.Day, c.At
+	if day == "" {
+		day = "fri"
+	}
+	if at == "" {
+		at = "16:00"
+	}
+	return day + " " + at
+}
+
+// GenerateDigest renders a weekly digest of the given sessions: hours per
+// ticket, the activities most time went to and the sessions that stand out
+func GenerateDigest(aggregates []*SessionAggregates, from, to time.Time) string {
+	var md strings.Builder
+	md.WriteString(fmt.Sprintf("# %s\n\n", i18n.T("digest.title", from.Local().Format("2006-01-02"), to.Local().Format("2006-01-02"))))
+
+	if len(aggregates) == 0 {
+		md.WriteString(i18n.T("digest.empty") + "\n")
+		return md.String()
+	}
+
+	var active float64
+	tickets := map[string]*totalRow{}
+	activities := map[string]*totalRow{}
+	add := func(rows map[string]*totalRow, name string, minutes float64) {
+		r := rows[name]
+		if r == nil {
+			r = &totalRow{name: name}
+			rows[name] = r
+		}
+		r.sessions++
+		r.minutes += minutes
+	}
+
+	for _, a := range aggregates {
+		active += a.ActiveMinutes
+		ticket := a.Ticket
+		if ticket == "" {
+			ticket = i18n.T("digest.no_ticket")
+		}
+		add(tickets, ticket, a.ActiveMinutes)
+
+		// Imported app and category usage says most about what the time
+		// went to; without it, fall back to the session's tags
+		switch {
+		case len(a.Apps) > 0:
+			for app, minutes := range a.Apps {
+				add(activities, app, minutes)
+			}
+		case len(a.Categories) > 0:
+			for category, minutes := range a.Categories {
+				add(activities, category, minutes)
+			}
+		default:
+			for _, tag := range a.Tags {
+				add(activities, "#"+tag, a.ActiveMinutes)
+			}
+		}
+	}
+
+	md.WriteString(fmt.Sprintf("- **%s:** %d\n", i18n.T("digest.sessions"), len(aggregates)))
+	md.WriteString(fmt.Sprintf("- **%s:** %.1f h\n", i18n.T("digest.active"), active/60))
+	md.WriteString(fmt.Sprintf("- **%s:** %d\n\n", i18n.T("digest.tickets"), len(tickets)))
+
+	writeTotalsTable(&md, i18n.T("digest.per_ticket"), i18n.T("report.ticket"), tickets, 0)
+	writeTotalsTable(&md, i18n.T("digest.top"), i18n.T("digest.activity"), activities, digestTopN)
+
+	notable := make([]*SessionAggregates, len(aggregates))
+	copy(notable, aggregates)
+	sort.SliceStable(notable, func(i, j int) bool { return notable[i].ActiveMinutes > notable[j].ActiveMinutes })
+	if len(notable) > digestTopN {
+		notable = notable[:digestTopN]
+	}
+	md.WriteString(fmt.Sprintf("## %s\n\n| %s | %s | %s | %s | %s |\n|---|---|---|---|---|\n", i18n.T("digest.notable"),
+		i18n.T("report.session"), i18n.T("digest.task"), i18n.T("report.ticket"), i18n.T("digest.started"), i18n.T("report.time")))
+	for _, a := range notable {
+		md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %.1f h |\n", a.SessionID, a.TaskName, a.Ticket,
+			a.StartTime.Local().Format("2006-01-02 15:04"), a.ActiveMinutes/60))
+	}
+	md.WriteString("\n")
+	return md.String()
+}
+
+// compileDigest loads the sessions started in the since before to and
+// renders their digest
+func compileDigest(since time.Duration, to time.Time) (string, time.Time, error) {
+	aggregates, err := loadAggregates()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	from := to.Add(-since)
+	selected := []*SessionAggregates{}
+	for _, a := range aggregates {
+		if !a.StartTime.Before(from) {
+			selected = append(selected, a)
+		}
+	}
+	return GenerateDigest(selected, from, to), from, nil
+}
+
+func newDigestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Compile a weekly digest of hours per ticket and notable sessions",
+		Long: `Compile a digest of the sessions started in the past week (or --since):
+hours per ticket, the top activities from imported app and category usage
+(tags when there is none) and the longest sessions.
+
+The digest is printed as markdown, or written to a file with -o. With
+--send it's delivered on the channels the "digest" event is routed to under
+"notifications" in the config file, e.g. by email or to Slack.
+
+With --daemon it keeps running and sends a digest every week at the time set
+under "digest" in the config file, Friday at 16:00 unless set otherwise:
+
+  "digest": {"day": "fri", "at": "16:00"}
+
+Run it in the background or as a login service; a send missed while the
+machine was asleep goes out when it wakes.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			since, _ := cmd.Flags().GetDuration("since")
+			if since <= 0 {
+				ui.Println("❌ --since must be positive")
+				exit(exitUsage)
+			}
+			output, _ := cmd.Flags().GetString("output")
+			send, _ := cmd.Flags().GetBool("send")
+			if daemon, _ := cmd.Flags().GetBool("daemon"); daemon {
+				if output != "" || send {
+					ui.Println("❌ --daemon always sends; it can't be combined with -o or --send")
+					exit(exitUsage)
+				}
+				runDigestDaemon(since)
+				return
+			}
+
+			digest, from, err := compileDigest(since, time.Now())
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			if output == "" && !send {
+				ui.Print(digest)
+				return
+			}
+			if output != "" {
+				if err := os.WriteFile(output, []byte(digest), 0644); err != nil {
+					ui.Printf("❌ Failed to save digest: %v\n", err)
+					exit(exitCode(err))
+				}
+				ui.Printf("✅ Digest saved to %s\n", output)
+			}
+			if send {
+				n, _ := digestNotifier()
+				sent, err := deliverDigest(n, digest, from, time.Now())
+				if err != nil {
+					ui.Printf("❌ Failed to send digest: %v\n", err)
+					exit(exitCode(err))
+				}
+				ui.Printf("✅ Digest sent to %s\n", strings.Join(sent, ", "))
+			}
+		},
+	}
+	cmd.Flags().Duration("since", 7*24*time.Hour, "How far back the digest goes")
+	cmd.Flags().StringP("output", "o", "", "Write the digest to a file")
+	cmd.Flags().Bool("send", false, "Send the digest on the channels the digest event is routed to")
+	cmd.Flags().Bool("daemon", false, "Keep running and send the digest every week as scheduled under digest in the config file")
+	return cmd
+}
+
+// digestNotifier returns the notifier and schedule from the config file,
+// exiting if the digest isn't routed to any channel
+func digestNotifier() (*notifier, DigestConfig) {
+	cfg, err := loadConfig()
+	if err != nil {
+		ui.Printf("❌ Error: %v\n", err)
+		exit(exitCode(err))
+	}
+	n := newNotifier(cfg.Notifications)
+	if n == nil || len(n.routed(eventDigest)) == 0 {
+		ui.Printf("❌ No channel to send the digest on: route the \"%s\" event to one under notifications in the config file\n", eventDigest)
+		exit(exitUsage)
+	}
+	return n, cfg.Digest
+}
+
+// deliverDigest sends a digest and waits for every channel, returning the
+// channels it went to
+func deliverDigest(n *notifier, digest string, from, to time.Time) ([]string, error) {
+	period := fmt.Sprintf("%s – %s", from.Local().Format("2006-01-02"), to.Local().Format("2006-01-02"))
+	return n.deliver(Notification{Event: eventDigest, Session: period, Title: i18n.T("digest.subject"), Body: digest})
+}
+
+// runDigestDaemon sends a digest of the past since at every scheduled time
+// until interrupted. A failed send is reported and retried at the next one.
+func runDigestDaemon(since time.Duration) {
+	n, schedule := digestNotifier()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	due := schedule.next(time.Now())
+	ui.Printf("📬 Sending the weekly digest every %s; the next one at %s. Press Ctrl+C to stop.\n",
+		schedule, due.Format("Mon 2006-01-02 15:04"))
+	ticker := time.NewTicker(digestCheckEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			ui.Println("\n👋 Digest daemon stopped")
+			return
+		case now := <-ticker.C:
+			if now.Before(due) {
+				continue
+			}
+			due = schedule.next(now)
+			digest, from, err := compileDigest(since, now)
+			if err == nil {
+				var sent []string
+				if sent, err = deliverDigest(n, digest, from, now); err == nil {
+					ui.Printf("✅ Digest sent to %s; the next one at %s\n", strings.Join(sent, ", "), due.Format("Mon 2006-01-02 15:04"))
+					continue
+				}
+			}
+			ui.Printf("⚠️  Failed to send digest: %v; trying again at %s\n", err, due.Format("Mon 2006-01-02 15:04"))
+		}
+	}
+}
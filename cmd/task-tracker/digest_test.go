@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/i18n"
+)
+
+func TestDigestConfigNext(t *testing.T) {
+	// 2026-01-05 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 1, 4+day, hour, minute, 0, 0, time.Local)
+	}
+	fridays := DigestConfig{}
+	mondays := DigestConfig{Day: "Monday", At: "09:30"}
+
+	tests := []struct {
+		name     string
+		schedule DigestConfig
+		after    time.Time
+		want     time.Time
+	}{
+		{"earlier in the week", fridays, at(1, 10, 0), at(5, 16, 0)},
+		{"same day before", fridays, at(5, 15, 59), at(5, 16, 0)},
+		{"exactly due is next week", fridays, at(5, 16, 0), at(12, 16, 0)},
+		{"same day after", fridays, at(5, 17, 0), at(12, 16, 0)},
+		{"weekend", fridays, at(6, 12, 0), at(12, 16, 0)},
+		{"configured day and time", mondays, at(5, 16, 0), at(8, 9, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("%s.next(%s) = %s, want %s", tt.schedule, tt.after.Format("Mon 15:04"),
+					got.Format("Mon 01-02 15:04"), tt.want.Format("Mon 01-02 15:04"))
+			}
+		})
+	}
+
+	for _, bad := range []DigestConfig{{Day: "someday"}, {At: "4pm"}} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("%+v: Validate() = nil, want an error", bad)
+		}
+	}
+}
+
+func TestGenerateDigestLocalized(t *testing.T) {
+	t.Cleanup(func() { i18n.SetLocale(i18n.Default) })
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)
+	aggregates := []*SessionAggregates{{SessionID: "20260105_120000", TaskName: "Fix login", ActiveMinutes: 90}}
+
+	if err := i18n.SetLocale("de"); err != nil {
+		t.Fatal(err)
+	}
+	digest := GenerateDigest(aggregates, from, from.AddDate(0, 0, 7))
+	for _, want := range []string{"# Wochenübersicht: 2026-01-05", "## Stunden pro Ticket", "| (keins) | 1 | 1.5 h |", "| Sitzung | Aufgabe |"} {
+		if !strings.Contains(digest, want) {
+			t.Errorf("German digest lacks %q:\n%s", want, digest)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"task-tracker/internal/disk"
+	"task-tracker/internal/ui"
+)
+
+// Typical stored bytes per screen pixel for desktop content (text, flat
+// UI). Photos and video compress far worse, so these are estimates, not
+// limits; 'task-tracker bench' measures the real numbers.
+const (
+	pngBytesPerPixel       = 0.5
+	optimizedBytesPerPixel = 0.35
+	deltaBytesPerPixel     = 0.08 // averaged over deltas and keyframes
+	lossyBytesPerPixel     = 0.15 // JPEG or WebP at the default quality
+)
+
+// bytesPerPixel returns the estimate for the session's storage settings
+func (t *TaskTracker) bytesPerPixel() float64 {
+	switch {
+	case t.Format == formatJPEG || t.Format == formatWebP:
+		return lossyBytesPerPixel
+	case t.Delta:
+		return deltaBytesPerPixel
+	case t.Optimize:
+		return optimizedBytesPerPixel
+	}
+	return pngBytesPerPixel
+}
+
+// estimateBytesPerHour estimates disk use from the captured monitors'
+// resolutions and intervals
+func (t *TaskTracker) estimateBytesPerHour() int64 {
+	pixels := 0.0
+	for _, m := range t.MonitorsToCapture {
+		b := t.Capturer.Bounds(m)
+		w, h := t.savedSize(b.Dx(), b.Dy())
+		ticks := time.Hour.Seconds() / t.monitorInterval(m).Seconds()
+		pixels += float64(w*h) * ticks
+	}
+	return int64(pixels * t.bytesPerPixel())
+}
+
+// checkDiskSpace compares the estimate for a planned session against the
+// free space in the session directory. It fails if the session would fill
+// the disk, unless force is set, and warns when it would use most of it.
+func (t *TaskTracker) checkDiskSpace(planned time.Duration, force bool) error {
+	perHour := t.estimateBytesPerHour()
+	need := int64(float64(perHour) * planned.Hours())
+
+	free, err := disk.Free(t.SessionDir)
+	if err != nil {
+		ui.Printf("⚠️  Couldn't check free disk space: %v\n", err)
+		return nil
+	}
+
+	ui.Printf("💾 Estimated %s/hour, %s for %s (%s free)\n",
+		formatBytes(perHour), formatBytes(need), formatPlanned(planned), formatBytes(int64(free)))
+
+	switch {
+	case need > int64(free) && !force:
+		return fmt.Errorf("session would need ~%s but only %s is free; use a longer --interval, --delta, a shorter --planned, or --force",
+			formatBytes(need), formatBytes(int64(free)))
+	case need > int64(free):
+		ui.Println("⚠️  Not enough free space for the planned session (continuing because of --force)")
+	case need > int64(free)*8/10:
+		ui.Println("⚠️  The planned session would use most of the free disk space")
+	}
+	return nil
+}
+
+// formatPlanned renders a planned duration like 8h or 1h30m
+func formatPlanned(d time.Duration) string {
+	s := strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"image"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/imaging"
+)
+
+// unchangedMax is the largest share of a frame that may differ from the
+// last one kept for its monitor for it to count as a duplicate. A ticking
+// clock or a blinking caret moves a cell or two of the comparison grid;
+// scrolling or typing a few words moves more.
+const unchangedMax = 0.005
+
+// unchanged reports whether a frame is nearly identical to the last one
+// kept for its monitor, releasing it and counting it as skipped if so.
+// Frames are compared with the last kept frame, not the last captured one,
+// so slow changes add up until a frame is saved. Otherwise it returns the
+// frame's luma for keep once the frame is written. Capture loop only.
+func (t *TaskTracker) unchanged(monitorIdx int, img *image.RGBA) (*imaging.Luma, bool) {
+	if t.KeepDuplicates || t.forceCapture {
+		return nil, false
+	}
+	luma := imaging.NewLuma(img)
+	t.mu.Lock()
+	prev := t.lastKept[monitorIdx]
+	t.mu.Unlock()
+	if prev == nil || imaging.Change(luma, prev) > unchangedMax {
+		return luma, false
+	}
+
+	capture.Release(img)
+	t.mu.Lock()
+	t.Duplicates++
+	t.mu.Unlock()
+	t.log(logInfo, "skip", fmt.Sprintf("Unchanged frame of monitor %s skipped", monitorLabel(monitorIdx+1)))
+	return nil, true
+}
+
+// keep makes a written frame the one later frames of its monitor are
+// compared with. Frames dropped, quarantined or not written never are, so
+// the next identical frame is still saved. Writer only.
+func (t *TaskTracker) keep(f capturedFrame) {
+	if f.luma == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.lastKept == nil {
+		t.lastKept = make(map[int]*imaging.Luma)
+	}
+	t.lastKept[f.monitorIdx] = f.luma
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// dropScreenshots removes screenshots by 1-based index from the session,
+// renumbering markers, and returns the removed ones by their old index.
+// An index given twice is dropped once. Their files stay until
+// deleteScreenshots.
+func (t *TaskTracker) dropScreenshots(indices []int) (map[int]Screenshot, error) {
+	dropped := make(map[int]Screenshot)
+	for _, idx := range indices {
+		if idx < 1 || idx > len(t.Screenshots) {
+			return nil, fmt.Errorf("screenshot %d out of range (1-%d)", idx, len(t.Screenshots))
+		}
+		dropped[idx] = t.Screenshots[idx-1]
+	}
+
+	kept := []Screenshot{}
+	renumber := make(map[int]int) // old 1-based index -> new 1-based index
+	for i, shot := range t.Screenshots {
+		if _, ok := dropped[i+1]; !ok {
+			kept = append(kept, shot)
+			renumber[i+1] = len(kept)
+		}
+	}
+	t.Screenshots = kept
+
+	// Markers refer to screenshots by index
+	for i := range t.Markers {
+		refs := []int{}
+		for _, n := range t.Markers[i].Screenshots {
+			if newIdx, ok := renumber[n]; ok {
+				refs = append(refs, newIdx)
+			}
+		}
+		t.Markers[i].Screenshots = refs
+	}
+	return dropped, nil
+}
+
+// deleteScreenshots deletes the files of dropped screenshots. Call it once
+// metadata without them is saved, so a failure never leaves metadata
+// pointing at deleted files. Keyframes still needed by kept delta frames
+// stay on disk, and shared blobs are left for gc.
+func (t *TaskTracker) deleteScreenshots(dropped map[int]Screenshot) error {
+	indices := make([]int, 0, len(dropped))
+	for idx := range dropped {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+
+	refs := deltaRefs(t.Screenshots)
+	for _, idx := range indices {
+		shot := dropped[idx]
+		if refs[filepath.Base(shot.Path)] || shot.Blob != "" {
+			continue
+		}
+		if err := os.Remove(shot.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete %s: %w", shot.Path, err)
+		}
+		audit(auditDelete, shot.Path, fmt.Sprintf("screenshot %d dropped", idx), t.SessionID)
+	}
+	return nil
+}
+
+// newEditCmd builds the edit command for fixing up a finished session
+func newEditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit [session_id]",
+		Short: "Edit task name, ticket, time, tags or screenshots of a session",
+		Long: `Edit a saved session and rewrite its metadata.
+
+Only the flags you pass are changed. Dropped screenshots are deleted from disk,
+and existing review and smart commit files are regenerated to match. A session
+moved to another ticket counts as not logged there, so 'ticket --post' logs it.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			cfg, err := loadConfig()
+			if err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				exit(exitCode(err))
+			}
+			tracker.Rounding = cfg.Rounding
+
+			flags := cmd.Flags()
+			changed := false
+
+			if flags.Changed("name") {
+				tracker.TaskName, _ = flags.GetString("name")
+				changed = true
+			}
+			if flags.Changed("ticket") {
+				arg, _ := flags.GetString("ticket")
+				ticket, err := parseTicket(arg)
+				if err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+				// Time logged on the old ticket stays there; the new one
+				// hasn't had it yet
+				if tracker.LoggedAt != "" && !ticket.Same(tracker.Ticket) {
+					ui.Printf("💡 The time was logged on %s at %s; remove that worklog by hand if it doesn't belong there\n", tracker.Ticket, tracker.LoggedAt)
+					tracker.LoggedAt = ""
+				}
+				if !ticket.Same(tracker.Ticket) {
+					// The cached estimate was the old ticket's
+					tracker.Estimate = nil
+				}
+				tracker.Ticket = ticket
+				changed = true
+			}
+			if flags.Changed("time") {
+				spent, _ := flags.GetString("time")
+				// Empty goes back to the tracked time; anything else must
+				// be something Jira accepts when the session is logged
+				if strings.TrimSpace(spent) != "" {
+					if _, err := parseTimeSpent(spent); err != nil {
+						ui.Printf("❌ %v\n", err)
+						exit(exitUsage)
+					}
+				}
+				tracker.TimeSpent = strings.TrimSpace(spent)
+				changed = true
+			}
+			if flags.Changed("tags") {
+				tracker.Tags, _ = flags.GetStringSlice("tags")
+				changed = true
+			}
+
+			var dropped map[int]Screenshot
+			if drop, _ := flags.GetIntSlice("drop"); len(drop) > 0 {
+				if dropped, err = tracker.dropScreenshots(drop); err != nil {
+					ui.Printf("❌ Failed to drop screenshots: %v\n", err)
+					exit(exitCode(err))
+				}
+				changed = true
+			}
+
+			if !changed {
+				ui.Println("💡 Nothing to change. See 'task-tracker edit --help' for available flags")
+				return
+			}
+
+			if err := tracker.saveMetadata(); err != nil {
+				ui.Printf("❌ Failed to save metadata: %v\n", err)
+				exit(exitCode(err))
+			}
+			if len(dropped) > 0 {
+				if err := tracker.deleteScreenshots(dropped); err != nil {
+					ui.Printf("❌ Failed to delete dropped screenshots: %v\n", err)
+					exit(exitCode(err))
+				}
+				ui.Printf("🗑️  Dropped %d screenshot(s)\n", len(dropped))
+			}
+
+			// Keep generated files consistent with the new metadata
+			if fileExists(filepath.Join(tracker.SessionDir, "review.md")) {
+				if err := tracker.GenerateReviewFile(5); err != nil {
+					ui.Printf("⚠️  Failed to regenerate review file: %v\n", err)
+				}
+			}
+			commitPath := filepath.Join(tracker.SessionDir, "smart_commit.txt")
+			if fileExists(commitPath) {
+				if tracker.GenerateSmartCommit() == "" {
+					os.Remove(commitPath)
+				} else if err := tracker.SaveSmartCommit(); err != nil {
+					ui.Printf("⚠️  Failed to regenerate smart commit: %v\n", err)
+				}
+			}
+
+			ui.Printf("✅ Updated session %s\n", tracker.SessionID)
+			ui.Printf("   Task: %s\n", tracker.TaskName)
+			if tracker.Ticket != nil {
+				ui.Printf("   Ticket: %s\n", tracker.Ticket)
+			}
+			ui.Printf("   Screenshots: %d\n", len(tracker.Screenshots))
+		},
+	}
+
+	cmd.Flags().String("name", "", "New task name")
+	cmd.Flags().StringP("ticket", "t", "", "New ticket, as for start --ticket (empty to clear)")
+	cmd.Flags().String("time", "", "Time spent override (e.g., 1h 20m, empty to auto-calculate)")
+	cmd.Flags().StringSlice("tags", nil, "Replace session tags (comma-separated)")
+	cmd.Flags().IntSlice("drop", nil, "Screenshot numbers to delete (1-based, comma-separated)")
+
+	return cmd
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDropScreenshots(t *testing.T) {
+	tracker := &TaskTracker{
+		Screenshots: []Screenshot{{Path: "a.png"}, {Path: "b.png"}, {Path: "c.png"}, {Path: "d.png"}},
+		Markers:     []Marker{{Label: "login", Screenshots: []int{2, 4}}},
+	}
+	dropped, err := tracker.dropScreenshots([]int{3, 2, 3})
+	if err != nil {
+		t.Fatalf("dropScreenshots: %v", err)
+	}
+	if len(dropped) != 2 || dropped[2].Path != "b.png" || dropped[3].Path != "c.png" {
+		t.Errorf("dropped %v, want b.png and c.png once each", dropped)
+	}
+	var kept []string
+	for _, s := range tracker.Screenshots {
+		kept = append(kept, s.Path)
+	}
+	if !slices.Equal(kept, []string{"a.png", "d.png"}) {
+		t.Errorf("kept %v, want [a.png d.png]", kept)
+	}
+	if got := tracker.Markers[0].Screenshots; !slices.Equal(got, []int{2}) {
+		t.Errorf("marker refers to %v, want [2]", got)
+	}
+
+	if _, err := tracker.dropScreenshots([]int{3}); err == nil {
+		t.Error("dropping screenshot 3 of 2 succeeded, want an error")
+	}
+}
+
+func TestReportIgnoresEstimateWithoutTicket(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.Local)
+	// As left by a session whose ticket was cleared before the fix
+	tracker := &TaskTracker{
+		SessionID: "20260105_090000",
+		TaskName:  "Untracked",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		Estimate:  &Estimate{OriginalSeconds: 4 * 3600, RemainingSeconds: 3600},
+	}
+	if delta := tracker.estimateDelta(); delta != "" {
+		t.Errorf("estimateDelta = %q without a ticket, want none", delta)
+	}
+	if report := tracker.GenerateReport(); strings.Contains(report, "**Estimate:**") {
+		t.Errorf("report shows an estimate without a ticket:\n%s", report)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// EmbeddingsConfig is the local model that embeds frames and search text in
+// the same space, e.g. a CLIP ONNX model behind a small script. The command
+// reads JSON lines like {"image": "/abs/frame.png"} or {"text": "..."} on
+// stdin and writes one JSON array of numbers per line to stdout, in order.
+type EmbeddingsConfig struct {
+	Command []string `json:"command,omitempty"`
+	Model   string   `json:"model,omitempty"` // recorded in the index; changing it re-embeds everything
+	Timeout Duration `json:"timeout"`
+}
+
+// defaultEmbeddingsTimeout bounds a single run of the embedding command
+const defaultEmbeddingsTimeout = 10 * time.Minute
+
+// Validate checks the timeout
+func (e EmbeddingsConfig) Validate() error {
+	if e.Timeout.Duration <= 0 {
+		return fmt.Errorf("embeddings.timeout must be positive")
+	}
+	return nil
+}
+
+// model names the model vectors came from, so vectors of different models
+// are never compared
+func (e EmbeddingsConfig) model() string {
+	if e.Model != "" {
+		return e.Model
+	}
+	return strings.Join(e.Command, " ")
+}
+
+// embedInput is one line sent to the embedding command
+type embedInput struct {
+	Image string `json:"image,omitempty"`
+	Text  string `json:"text,omitempty"`
+}
+
+// embed runs the command once for all inputs and returns their vectors,
+// normalized to unit length
+func (e EmbeddingsConfig) embed(inputs []embedInput) ([][]float32, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.Timeout.Duration)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, e.Command[0], e.Command[1:]...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%w: failed to run %s: %v", errIntegration, e.Command[0], err)
+	}
+
+	// Write in the background so a model that answers line by line can't
+	// deadlock on a full pipe
+	go func() {
+		enc := json.NewEncoder(stdin)
+		for _, in := range inputs {
+			if enc.Encode(in) != nil {
+				break
+			}
+		}
+		stdin.Close()
+	}()
+
+	vectors := make([][]float32, 0, len(inputs))
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 1<<20), 16<<20)
+	for len(vectors) < len(inputs) && scanner.Scan() {
+		var v []float32
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil || len(v) == 0 {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return nil, fmt.Errorf("%w: %s wrote an invalid vector for line %d", errIntegration, e.Command[0], len(vectors)+1)
+		}
+		vectors = append(vectors, normalize(v))
+	}
+	io.Copy(io.Discard, stdout)
+
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("%w: %s timed out after %s", errIntegration, e.Command[0], e.Timeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s failed: %v", errIntegration, e.Command[0], err)
+	}
+	if len(vectors) < len(inputs) {
+		return nil, fmt.Errorf("%w: %s returned %d vectors for %d inputs", errIntegration, e.Command[0], len(vectors), len(inputs))
+	}
+	return vectors, nil
+}
+
+// normalize scales v to unit length so a dot product is the cosine similarity
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	scale := float32(1 / math.Sqrt(sum))
+	for i := range v {
+		v[i] *= scale
+	}
+	return v
+}
+
+// similarity is the cosine similarity of two unit vectors, or -1 if they
+// can't be compared
+func similarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return -1
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
+
+// embeddingsFile is the index of frame vectors for all sessions, kept in
+// capturesDir
+const embeddingsFile = "embeddings.json"
+
+// frameEmbedding is one keyframe's vector. Frames are identified by their
+// stored path, which survives screenshots being dropped and renumbered.
+type frameEmbedding struct {
+	Session string    `json:"session"`
+	Path    string    `json:"path"`
+	Vector  []float32 `json:"vector"`
+}
+
+// embeddingIndex holds the vectors of one model
+type embeddingIndex struct {
+	Model  string           `json:"model"`
+	Frames []frameEmbedding `json:"frames"`
+}
+
+// loadEmbeddingIndex reads the index, returning an empty one if it doesn't
+// exist or was built with another model
+func loadEmbeddingIndex(model string) (*embeddingIndex, error) {
+	index := &embeddingIndex{Model: model}
+	data, err := os.ReadFile(filepath.Join(capturesDir, embeddingsFile))
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedding index: %w", err)
+	}
+
+	var stored embeddingIndex
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to parse embedding index: %w", err)
+	}
+	if stored.Model != model {
+		return index, nil
+	}
+	return &stored, nil
+}
+
+// save writes the index atomically
+func (idx *embeddingIndex) save() error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(capturesDir, embeddingsFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write embedding index: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// update embeds the keyframes of sessions that aren't indexed yet and drops
+// vectors of frames that no longer exist. Delta frames are skipped; they
+// look like their keyframe. It returns the number of frames embedded.
+func (idx *embeddingIndex) update(e EmbeddingsConfig, sessions []SessionMetadata) (int, error) {
+	current := make(map[string]bool)
+	var pending []frameEmbedding
+	var inputs []embedInput
+	indexed := make(map[string]bool)
+	for _, f := range idx.Frames {
+		indexed[f.Session+"/"+f.Path] = true
+	}
+
+	for _, s := range sessions {
+		sessionDir := filepath.Join(capturesDir, s.SessionID)
+		for _, shot := range s.Screenshots {
+			key := s.SessionID + "/" + shot.Path
+			current[key] = true
+			path, _ := resolvePath(sessionDir, s.SessionID, shot.Path)
+			if indexed[key] || isDelta(path) || !fileExists(path) {
+				continue
+			}
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return 0, err
+			}
+			pending = append(pending, frameEmbedding{Session: s.SessionID, Path: shot.Path})
+			inputs = append(inputs, embedInput{Image: abs})
+		}
+	}
+
+	kept := idx.Frames[:0]
+	for _, f := range idx.Frames {
+		if current[f.Session+"/"+f.Path] {
+			kept = append(kept, f)
+		}
+	}
+	idx.Frames = kept
+
+	if len(inputs) > 0 {
+		vectors, err := e.embed(inputs)
+		if err != nil {
+			return 0, err
+		}
+		for i := range pending {
+			pending[i].Vector = vectors[i]
+		}
+		idx.Frames = append(idx.Frames, pending...)
+	}
+	return len(inputs), idx.save()
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"runtime"
+	"syscall"
+
+	"task-tracker/internal/telemetry"
+	"task-tracker/internal/ui"
+)
+
+// Exit codes, so wrappers and scripts can react to specific failures
+const (
+	exitOK                 = 0
+	exitError              = 1 // anything not covered below
+	exitUsage              = 2 // bad arguments or flags
+	exitCaptureUnavailable = 3 // no displays or the capture backend failed
+	exitPermissionDenied   = 4
+	exitDiskFull           = 5
+	exitSessionNotFound    = 6 // unknown session ID or no running session
+	exitIntegration        = 7 // Jira or another external service failed
+)
+
+// Error categories; wrap these with %w to select an exit code
+var (
+	errCaptureUnavailable = errors.New("capture backend unavailable")
+	errSessionNotFound    = errors.New("session not found")
+	errIntegration        = errors.New("integration failed")
+	errUsage              = errors.New("invalid usage")
+)
+
+// exitCode maps an error to the process exit code
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case errors.Is(err, errCaptureUnavailable):
+		return exitCaptureUnavailable
+	case errors.Is(err, errSessionNotFound):
+		return exitSessionNotFound
+	case errors.Is(err, errIntegration):
+		return exitIntegration
+	case errors.Is(err, fs.ErrPermission):
+		return exitPermissionDenied
+	case isDiskFull(err):
+		return exitDiskFull
+	}
+	return exitError
+}
+
+// exit ends the process with code once buffered telemetry is flushed,
+// which os.Exit would drop. Commands exit through it on errors too.
+func exit(code int) {
+	if err := telemetry.Shutdown(); err != nil {
+		ui.Printf("⚠️  %v\n", err)
+	}
+	os.Exit(code)
+}
+
+// isDiskFull reports whether err was caused by running out of disk space
+func isDiskFull(err error) bool {
+	if errors.Is(err, syscall.ENOSPC) {
+		return true
+	}
+
+	// ERROR_HANDLE_DISK_FULL and ERROR_DISK_FULL
+	var errno syscall.Errno
+	if runtime.GOOS == "windows" && errors.As(err, &errno) {
+		return errno == 39 || errno == 112
+	}
+	return false
+}
+
+// exitCodesHelp documents the exit codes in the root command help
+const exitCodesHelp = `Exit codes:
+  0  success
+  1  general error
+  2  invalid usage
+  3  capture backend unavailable
+  4  permission denied
+  5  disk full
+  6  session not found / no running session
+  7  integration failure`
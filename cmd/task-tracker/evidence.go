@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/imaging"
+	"task-tracker/internal/pdf"
+	"task-tracker/internal/ui"
+)
+
+// QA evidence documents and their labeled step frames, in the session
+// directory
+const (
+	evidenceFile = "evidence.md"
+	evidencePDF  = "evidence.pdf"
+	evidenceDir  = "evidence"
+)
+
+// addStep records the next numbered QA step and asks the capture loop for
+// a frame of it right away. Presses that come faster than the capture
+// share its frame.
+func (t *TaskTracker) addStep(description string) Marker {
+	t.mu.Lock()
+	step := 1
+	for _, m := range t.Markers {
+		if m.Step > 0 {
+			step++
+		}
+	}
+	marker := Marker{
+		Timestamp:    time.Now().Format(time.RFC3339),
+		RelativeTime: time.Since(t.StartTime).Seconds(),
+		Label:        description,
+		Step:         step,
+	}
+	t.Markers = append(t.Markers, marker)
+	t.mu.Unlock()
+
+	select {
+	case t.stepNow <- struct{}{}:
+	default:
+	}
+
+	if description == "" {
+		ui.Printf("🧪 Step %d\n", step)
+	} else {
+		ui.Printf("🧪 Step %d: %s\n", step, description)
+	}
+	return marker
+}
+
+// captureStep captures every monitor for a QA step, including ones whose
+// own interval isn't due. A paused session captures nothing.
+func (t *TaskTracker) captureStep() {
+	if !t.pausedSince().IsZero() {
+		ui.Println("⚠️  The session is paused; the step gets the first frame captured after 'task-tracker resume'")
+		return
+	}
+	t.forceCapture = true
+	defer func() { t.forceCapture = false }()
+	t.captureScreenshot()
+}
+
+// readSteps turns each line typed into the start terminal into a QA step
+// until stdin closes
+func (t *TaskTracker) readSteps() {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		t.addStep(strings.TrimSpace(scanner.Text()))
+	}
+}
+
+// steps returns the session's QA steps in order
+func (t *TaskTracker) steps() []Marker {
+	var steps []Marker
+	for _, m := range t.Markers {
+		if m.Step > 0 {
+			steps = append(steps, m)
+		}
+	}
+	return steps
+}
+
+// stepFrames labels each frame of a step with its number, saves it under
+// evidence/ and adds it to doc, returning the saved paths relative to the
+// session directory. Redactions are applied first, so evidence shows what
+// the session's viewers would.
+func (t *TaskTracker) stepFrames(step Marker, doc *pdf.Document) ([]string, error) {
+	var frames []string
+	for _, n := range step.Screenshots {
+		if n < 1 || n > len(t.Screenshots) {
+			continue
+		}
+		shot := t.Screenshots[n-1]
+		path, err := t.viewablePath(shot)
+		if err != nil {
+			return nil, err
+		}
+		img, err := loadFrame(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		rgba := imaging.ToRGBA(img)
+		// The label font only has ASCII, so the label isn't translated
+		label := fmt.Sprintf("Step %d", step.Step)
+		name := fmt.Sprintf("step_%02d.png", step.Step)
+		if len(step.Screenshots) > 1 {
+			label += fmt.Sprintf(" - Monitor %d", shot.Monitor)
+			name = fmt.Sprintf("step_%02d_m%d.png", step.Step, shot.Monitor)
+		}
+		imaging.Label(rgba, label)
+
+		rel := filepath.Join(evidenceDir, name)
+		if err := writePNG(filepath.Join(t.SessionDir, rel), rgba, nil); err != nil {
+			return nil, err
+		}
+		if err := doc.Image(rgba, 0.5); err != nil {
+			return nil, err
+		}
+		frames = append(frames, filepath.ToSlash(rel))
+	}
+	return frames, nil
+}
+
+// writeEvidence writes the numbered test-evidence document of a QA session
+// as markdown and PDF. It does nothing for sessions without steps.
+func (t *TaskTracker) writeEvidence() error {
+	steps := t.steps()
+	if len(steps) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Join(t.SessionDir, evidenceDir), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", evidenceDir, err)
+	}
+
+	title := i18n.T("evidence.title", t.TaskName)
+	doc := pdf.New(title)
+	var md strings.Builder
+	md.WriteString("# " + title + "\n\n")
+	doc.Heading(title)
+
+	var details []string
+	detail := func(label, value string) {
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", label, value))
+		details = append(details, label+": "+value)
+	}
+	detail(i18n.T("review.session"), t.SessionID)
+	if t.Ticket != nil {
+		detail(i18n.T("report.ticket"), t.Ticket.String())
+	}
+	detail(i18n.T("summary.started"), t.StartTime.Local().Format("2006-01-02 15:04:05 MST"))
+	if !t.EndTime.IsZero() {
+		detail(i18n.T("summary.ended"), t.EndTime.Local().Format("2006-01-02 15:04:05 MST"))
+	}
+	detail(i18n.T("evidence.steps"), fmt.Sprintf("%d", len(steps)))
+	md.WriteString("\n")
+	doc.Text(strings.Join(details, "\n"))
+
+	for _, step := range steps {
+		heading := i18n.T("evidence.step", step.Step)
+		md.WriteString("## " + heading + "\n\n")
+		doc.Subheading(heading)
+
+		elapsed := time.Duration(step.RelativeTime * float64(time.Second)).Round(time.Second)
+		when := i18n.T("evidence.at", t.StartTime.Add(elapsed).Local().Format("15:04:05"), elapsed)
+		description := step.Label
+		if description == "" {
+			description = i18n.T("evidence.no_description")
+		}
+		md.WriteString(fmt.Sprintf("_%s_\n\n%s\n\n", when, description))
+		doc.Text(when)
+		doc.Space(4)
+		doc.Text(description)
+		doc.Space(6)
+
+		frames, err := t.stepFrames(step, doc)
+		if err != nil {
+			return err
+		}
+		if len(frames) == 0 {
+			md.WriteString("_" + i18n.T("evidence.no_frame") + "_\n\n")
+			doc.Text(i18n.T("evidence.no_frame"))
+		}
+		for _, f := range frames {
+			md.WriteString(fmt.Sprintf("![%s](%s)\n\n", heading, f))
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(t.SessionDir, evidenceFile), []byte(md.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", evidenceFile, err)
+	}
+	file, err := os.Create(filepath.Join(t.SessionDir, evidencePDF))
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", evidencePDF, err)
+	}
+	if err := doc.Write(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", evidencePDF, err)
+	}
+	return file.Close()
+}
+
+// newEvidenceCmd builds the evidence command
+func newEvidenceCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "evidence [session_id]",
+		Short: "Rebuild the QA evidence document of a session",
+		Long: `Rebuild evidence.md and evidence.pdf of a session recorded with --qa, e.g.
+after redacting frames.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			if len(tracker.steps()) == 0 {
+				ui.Printf("❌ %s has no QA steps (record them with 'start --qa')\n", tracker.SessionID)
+				exit(exitUsage)
+			}
+			if err := tracker.writeEvidence(); err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("🧪 Evidence: %s, %s\n", filepath.Join(tracker.SessionDir, evidenceFile), filepath.Join(tracker.SessionDir, evidencePDF))
+		},
+	}
+}
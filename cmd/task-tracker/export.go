@@ -0,0 +1,403 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/archive"
+	"task-tracker/internal/delta"
+	"task-tracker/internal/imaging"
+	"task-tracker/internal/ui"
+)
+
+// anonymizeBlock is the pixelation block size used by export --anonymize.
+// There's no OCR to find text regions, so the whole frame is pixelated.
+const anonymizeBlock = 16
+
+// absPathPattern matches absolute Unix and Windows paths
+var absPathPattern = regexp.MustCompile(`(?:[A-Za-z]:\\|/(?:home|Users|root|tmp|var|opt|mnt|media)\b)[^\s"'<>|:*?]*`)
+
+// urlPattern matches http and https URLs, which name internal hosts such
+// as the company's tracker
+var urlPattern = regexp.MustCompile(`https?://[^\s"'<>()\[\]]+`)
+
+// scrubber removes identifying details from free text
+type scrubber struct {
+	replacer *strings.Replacer
+}
+
+// newScrubber collects the local username, hostname and home directory
+func newScrubber() *scrubber {
+	var pairs []string
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		pairs = append(pairs, home, "~")
+	}
+	if u, err := user.Current(); err == nil {
+		name := u.Username
+		if i := strings.LastIndexAny(name, `\/`); i >= 0 {
+			name = name[i+1:] // DOMAIN\user on Windows
+		}
+		if len(name) > 2 {
+			pairs = append(pairs, name, "<user>")
+		}
+	}
+	if host, err := os.Hostname(); err == nil && len(host) > 2 {
+		pairs = append(pairs, host, "<host>")
+	}
+	return &scrubber{replacer: strings.NewReplacer(pairs...)}
+}
+
+func (s *scrubber) scrub(text string) string {
+	text = s.replacer.Replace(text)
+	text = urlPattern.ReplaceAllString(text, "<url>")
+	return absPathPattern.ReplaceAllString(text, "<path>")
+}
+
+// anonymize strips identifying details from metadata in place
+func (s *scrubber) anonymize(m *SessionMetadata) {
+	m.Name = ""   // chosen by the user, often after the customer or project
+	m.Parent = "" // links to sessions that aren't shared
+	m.TaskName = s.scrub(m.TaskName)
+	m.TicketComment = s.scrub(m.TicketComment)
+	if m.Summary != nil {
+		m.Summary.Text = s.scrub(m.Summary.Text)
+	}
+	m.Display = ""
+	if m.Desktop != nil {
+		m.Desktop.ID = 0
+		m.Desktop.Station = "" // a remote session's station names its connection
+	}
+	for i := range m.Displays {
+		m.Displays[i].Serial = "" // an EDID serial identifies the machine
+	}
+	if m.Ticket != nil {
+		m.Ticket.URL = "" // names the company's tracker
+	}
+	for i := range m.Tags {
+		m.Tags[i] = s.scrub(m.Tags[i])
+	}
+	m.Videos = nil      // videos can't be pixelated, so they're left out
+	m.Quarantined = nil // the frames aren't exported and labels say what they showed
+	for i := range m.Screenshots {
+		m.Screenshots[i].Path = anonymizedName(filepath.Base(m.Screenshots[i].Path))
+		m.Screenshots[i].Caption = s.scrub(m.Screenshots[i].Caption)
+		m.Screenshots[i].SHA256 = "" // pixelated frames no longer match
+	}
+	for i := range m.Notes {
+		m.Notes[i].Text = s.scrub(m.Notes[i].Text)
+	}
+	for i := range m.Markers {
+		m.Markers[i].Label = s.scrub(m.Markers[i].Label)
+	}
+	for i := range m.Activity {
+		m.Activity[i].Title = s.scrub(m.Activity[i].Title)
+	}
+	for i := range m.Productivity {
+		m.Productivity[i].Activity = s.scrub(m.Productivity[i].Activity)
+	}
+	if m.Repo != nil {
+		m.Repo.Path = filepath.Base(m.Repo.Path) // the checkout's location says whose it is
+		for i := range m.Repo.Components {
+			m.Repo.Components[i].Owners = nil
+		}
+	}
+}
+
+// exportSession writes a session directory to a zip archive, compressed
+// as compression says
+func exportSession(sessionID, output string, anonymize bool, compression ArchiveConfig) (int, error) {
+	sessionDir := filepath.Join(capturesDir, sessionID)
+	data, err := os.ReadFile(filepath.Join(sessionDir, "metadata.json"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read metadata: %w", err)
+	}
+
+	var metadata SessionMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return 0, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+
+	// Shared blobs are packed into the session so the archive is
+	// self-contained. Paths of sessions saved before they were stored
+	// relative to the session are converted on the way.
+	blobs := make(map[string]string)        // archive name -> blob path
+	redactions := make(map[string][]Region) // archive name -> regions to blur
+	for i, shot := range metadata.Screenshots {
+		path, _ := resolvePath(sessionDir, sessionID, shot.Path)
+		name := filepath.Base(path)
+		if shot.Blob != "" {
+			name = shot.Blob + ".png"
+			blobs[name] = path
+			path = filepath.Join(sessionDir, name)
+		}
+		if len(shot.Redact) > 0 && !anonymize {
+			// Redacted frames are exported as full PNGs, delta frames too
+			redactions[name] = append(redactions[name], shot.Redact...)
+			path = filepath.Join(sessionDir, anonymizedName(name))
+		}
+		metadata.Screenshots[i].Path = storedPath(sessionDir, path)
+	}
+
+	var s *scrubber
+	if anonymize {
+		s = newScrubber()
+		s.anonymize(&metadata)
+	}
+	if data, err = json.MarshalIndent(metadata, "", "  "); err != nil {
+		return 0, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	entries, err := os.ReadDir(sessionDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	method, err := archive.Writer(zw, compression.Compression, compression.level())
+	if err != nil {
+		return 0, err
+	}
+	root := sessionID + "/"
+
+	if err := addZipFile(zw, method, root+"metadata.json", data, time.Now()); err != nil {
+		return 0, err
+	}
+
+	count := 1
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == "metadata.json" || name == controlSocketName {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return 0, err
+		}
+		content, err := os.ReadFile(filepath.Join(sessionDir, name))
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		if anonymize {
+			if name == summaryTextFile || name == reportFile {
+				continue // rendered from the metadata before it was anonymized
+			}
+			switch strings.ToLower(filepath.Ext(name)) {
+			case ".png", ".jpg", ".webp", delta.Ext:
+				// Frames are re-encoded as PNGs, delta frames in full
+				if content, err = pixelateFrame(filepath.Join(sessionDir, name)); err != nil {
+					return 0, fmt.Errorf("failed to anonymize %s: %w", name, err)
+				}
+				name = anonymizedName(name)
+			case ".md", ".txt", ".json":
+				content = []byte(s.scrub(string(content)))
+			default:
+				continue // unknown content can't be checked, so leave it out
+			}
+		} else if regions := redactions[name]; regions != nil {
+			if content, err = redactedFrame(filepath.Join(sessionDir, name), regions); err != nil {
+				return 0, fmt.Errorf("failed to redact %s: %w", name, err)
+			}
+			name = anonymizedName(name)
+		}
+
+		if err := addZipFile(zw, method, root+name, content, info.ModTime()); err != nil {
+			return 0, err
+		}
+		count++
+	}
+
+	blobNames := make([]string, 0, len(blobs))
+	for name := range blobs {
+		blobNames = append(blobNames, name)
+	}
+	sort.Strings(blobNames)
+
+	for _, name := range blobNames {
+		path := blobs[name]
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, fmt.Errorf("missing blob %s: %w", path, err)
+		}
+
+		var content []byte
+		if anonymize {
+			content, err = pixelateFrame(path)
+		} else if regions := redactions[name]; regions != nil {
+			content, err = redactedFrame(path, regions)
+		} else {
+			content, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read blob %s: %w", path, err)
+		}
+
+		if err := addZipFile(zw, method, root+name, content, info.ModTime()); err != nil {
+			return 0, err
+		}
+		count++
+	}
+
+	if err := zw.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return count, f.Close()
+}
+
+// addZipFile writes one file to the archive
+func addZipFile(zw *zip.Writer, method uint16, name string, content []byte, modified time.Time) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+// anonymizedName is the archive name of an anonymized frame, which is
+// always a PNG
+func anonymizedName(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name)) + ".png"
+}
+
+// pixelateFrame pixelates a whole screenshot so no text in it is readable
+func pixelateFrame(path string) ([]byte, error) {
+	img, err := loadFrame(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rgba := imaging.ToRGBA(img)
+	imaging.Pixelate(rgba, rgba.Bounds(), anonymizeBlock)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, rgba); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ArchiveConfig picks the compression of export archives. Level 0 means
+// the codec's default.
+type ArchiveConfig struct {
+	Compression string `json:"compression"`
+	Level       int    `json:"level,omitempty"`
+}
+
+// level resolves the configured level
+func (a ArchiveConfig) level() int {
+	if a.Level == 0 {
+		return archive.DefaultLevel(a.Compression)
+	}
+	return a.Level
+}
+
+// Validate checks the codec and level
+func (a ArchiveConfig) Validate() error {
+	if err := archive.Validate(a.Compression, a.level()); err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	return nil
+}
+
+// newExportCmd builds the export command
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [session_id]",
+		Short: "Export a session as a zip archive",
+		Long: `Export a session directory (metadata, screenshots, review and commit files)
+as a zip archive for sharing.
+
+With --anonymize the archive is safe to attach to public bug reports:
+screenshots are pixelated so text can't be read, and usernames, hostnames,
+the home directory and absolute paths are stripped from metadata and text
+files. The session name, the session it continues, the remote desktop
+station and quarantined frames are left out of metadata, URLs are
+removed from text files, and SUMMARY.txt and a saved report.md are left
+out. The session on disk is left untouched.
+
+Entries are compressed with archive.compression from config (deflate unless
+set), or --compression: store, lz4, deflate or zstd. Screenshots are already
+compressed PNGs, so store or lz4 are much faster than deflate at nearly the
+same size; 'task-tracker bench archive' measures each on a real session.
+zstd archives open with 7-Zip and WinZip, lz4 ones only with task-tracker
+view and task-viewer.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			sessionID, err := resolveSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			anonymize, _ := cmd.Flags().GetBool("anonymize")
+			output, _ := cmd.Flags().GetString("output")
+
+			cfg, err := loadConfig()
+			if err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				exit(exitCode(err))
+			}
+			compression := cfg.Archive
+			if cmd.Flags().Changed("compression") {
+				compression.Compression, _ = cmd.Flags().GetString("compression")
+				compression.Level = 0
+			}
+			if cmd.Flags().Changed("level") {
+				compression.Level, _ = cmd.Flags().GetInt("level")
+			}
+			if err := compression.Validate(); err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitUsage)
+			}
+
+			if output == "" {
+				output = sessionID + ".zip"
+				if anonymize {
+					output = sessionID + "_anon.zip"
+				}
+			}
+
+			count, err := exportSession(sessionID, output, anonymize, compression)
+			if err != nil {
+				os.Remove(output)
+				ui.Printf("❌ Export failed: %v\n", err)
+				exit(exitCode(err))
+			}
+
+			detail := fmt.Sprintf("%d file(s), %s", count, compression.Compression)
+			if anonymize {
+				detail += ", anonymized"
+			}
+			audit(auditExport, output, detail, sessionID)
+			ui.Printf("📦 Exported %d file(s) to %s\n", count, output)
+			if anonymize {
+				ui.Println("🕶️  Screenshots pixelated and identifying details stripped")
+			}
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "Archive path (default <session_id>.zip)")
+	cmd.Flags().Bool("anonymize", false, "Pixelate screenshots and strip usernames, hostnames and paths")
+	cmd.Flags().String("compression", "", "Compression: store, lz4, deflate or zstd (default from config)")
+	cmd.Flags().Int("level", 0, "Compression level (default: the codec's default)")
+	return cmd
+}
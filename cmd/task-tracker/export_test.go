@@ -0,0 +1,169 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/archive"
+	"task-tracker/internal/capture"
+)
+
+func TestExportAnonymizedMetadata(t *testing.T) {
+	dir := t.TempDir()
+	prev := capturesDir
+	capturesDir = dir
+	t.Cleanup(func() { capturesDir = prev })
+
+	const sessionID = "20260105_090000"
+	sessionDir := filepath.Join(dir, sessionID)
+	if err := os.Mkdir(sessionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	frame, err := os.Create(filepath.Join(sessionDir, "screenshot_m1_090000.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(frame, image.NewRGBA(image.Rect(0, 0, 32, 32))); err != nil {
+		t.Fatal(err)
+	}
+	frame.Close()
+
+	const home = "/home/alice"
+	metadata := SessionMetadata{
+		SessionID:   sessionID,
+		Name:        "acme-outage",
+		Parent:      "20260104_170000",
+		TaskName:    "Fix the build in " + home + "/acme",
+		Tags:        []string{home},
+		Ticket:      &TicketRef{Provider: providerJira, Key: "ACME-1", URL: "https://acme.atlassian.net/browse/ACME-1"},
+		Desktop:     &capture.Desktop{Type: capture.DesktopRemote, ID: 3, Station: "RDP-Tcp#3"},
+		Screenshots: []Screenshot{{Path: "screenshot_m1_090000.png", Monitor: 1, SHA256: "abc"}},
+		Quarantined: []QuarantinedFrame{{Path: "quarantine/screenshot_m1_090030.png", Label: "acme payroll"}},
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sessionDir, "metadata.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(t.TempDir(), "export.zip")
+	if _, err := exportSession(sessionID, output, true, ArchiveConfig{Compression: archive.Deflate}); err != nil {
+		t.Fatalf("exportSession: %v", err)
+	}
+
+	exported, ok := readArchive(t, output)[sessionID+"/metadata.json"]
+	if !ok {
+		t.Fatal("archive has no metadata.json")
+	}
+
+	for _, leak := range []string{"acme-outage", "20260104_170000", "RDP-Tcp", "acme payroll", "atlassian", home} {
+		if strings.Contains(string(exported), leak) {
+			t.Errorf("exported metadata contains %q", leak)
+		}
+	}
+
+	var got SessionMetadata
+	if err := json.Unmarshal(exported, &got); err != nil {
+		t.Fatalf("exported metadata is invalid: %v", err)
+	}
+	if got.Ticket == nil || got.Ticket.Key != "ACME-1" {
+		t.Errorf("ticket = %+v, want the key kept", got.Ticket)
+	}
+	if got.Desktop == nil || got.Desktop.Type != capture.DesktopRemote {
+		t.Errorf("desktop = %+v, want the type kept", got.Desktop)
+	}
+	if len(got.Screenshots) != 1 || got.Screenshots[0].SHA256 != "" {
+		t.Fatalf("screenshots = %+v, want one without a checksum", got.Screenshots)
+	}
+	if want := anonymizedName("screenshot_m1_090000.png"); got.Screenshots[0].Path != want {
+		t.Errorf("screenshot path = %s, want %s", got.Screenshots[0].Path, want)
+	}
+}
+
+func TestExportAnonymizedDropsTicketURL(t *testing.T) {
+	dir := t.TempDir()
+	prev := capturesDir
+	capturesDir = dir
+	t.Cleanup(func() { capturesDir = prev })
+
+	const url = "https://acme.atlassian.net/browse/ACME-1"
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.Local)
+	tracker := &TaskTracker{
+		SessionID:  "20260105_090000",
+		SessionDir: filepath.Join(dir, "20260105_090000"),
+		TaskName:   "Ticketed task",
+		Ticket:     &TicketRef{Provider: providerJira, Key: "ACME-1", URL: url},
+		StartTime:  start,
+		EndTime:    start.Add(time.Hour),
+	}
+	if err := os.Mkdir(tracker.SessionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.writeSummaryText(); err != nil {
+		t.Fatalf("writeSummaryText: %v", err)
+	}
+	files := map[string]string{
+		reportFile:      tracker.GenerateReport(),
+		"review.md":     "Worked on [ACME-1](" + url + ") all morning.",
+		"metadata.json": `{"session_id": "20260105_090000", "ticket": {"provider": "jira", "key": "ACME-1", "url": "` + url + `"}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tracker.SessionDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{summaryTextFile, reportFile} {
+		data, err := os.ReadFile(filepath.Join(tracker.SessionDir, name))
+		if err != nil || !strings.Contains(string(data), url) {
+			t.Fatalf("%s doesn't link the ticket, so the test proves nothing", name)
+		}
+	}
+
+	output := filepath.Join(t.TempDir(), "export.zip")
+	if _, err := exportSession(tracker.SessionID, output, true, ArchiveConfig{Compression: archive.Deflate}); err != nil {
+		t.Fatalf("exportSession: %v", err)
+	}
+	exported := readArchive(t, output)
+	if _, ok := exported[tracker.SessionID+"/review.md"]; !ok {
+		t.Error("review.md is missing from the archive")
+	}
+	for name, content := range exported {
+		if strings.Contains(string(content), "atlassian") {
+			t.Errorf("%s contains the ticket URL", name)
+		}
+	}
+}
+
+// readArchive returns the contents of every file in a zip archive by name
+func readArchive(t *testing.T, path string) map[string][]byte {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("archive unreadable: %v", err)
+	}
+	defer zr.Close()
+
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name], err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return files
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/telemetry"
+	"task-tracker/internal/ui"
+)
+
+// Defaults for dropping monitors that keep failing
+const (
+	defaultMaxFailures = 3
+	defaultRetryEvery  = 5 * time.Minute
+)
+
+// CaptureConfig controls how capture failures are handled
+type CaptureConfig struct {
+	MaxFailures int      `json:"max_failures"` // consecutive failures before a monitor is dropped
+	RetryEvery  Duration `json:"retry_every"`  // how often dropped monitors are tried again
+
+	// MonitorIntervals captures some monitors less often, e.g. "2:5m"
+	MonitorIntervals string `json:"monitor_intervals,omitempty"`
+
+	// Defaults for start's --interval and --monitors
+	Interval Duration `json:"interval"`
+	Monitors string   `json:"monitors,omitempty"`
+}
+
+// Validate checks the failure settings
+func (c CaptureConfig) Validate() error {
+	if c.MaxFailures < 1 {
+		return fmt.Errorf("capture.max_failures must be at least 1")
+	}
+	if c.RetryEvery.Duration < time.Second {
+		return fmt.Errorf("capture.retry_every must be at least 1s")
+	}
+	if c.Interval.Duration != 0 && (c.Interval.Duration < time.Second || c.Interval.Duration%time.Second != 0) {
+		return fmt.Errorf("capture.interval must be whole seconds, at least 1s")
+	}
+	// Monitor numbers and names are checked against the displays at start
+	if c.Monitors != "" {
+		if _, err := capture.ParseMonitors(c.Monitors); err != nil {
+			return fmt.Errorf("capture.monitors: %w", err)
+		}
+	}
+	if _, err := parseMonitorIntervals(c.MonitorIntervals, 0); err != nil {
+		return fmt.Errorf("capture.monitor_intervals: %w", err)
+	}
+	return nil
+}
+
+// monitorHealth tracks a monitor's consecutive capture failures
+type monitorHealth struct {
+	failures  int
+	droppedAt time.Time // zero while the monitor is in the rotation
+}
+
+// liveMonitors filters out monitors dropped after repeated failures,
+// letting each back in for one attempt every RetryEvery
+func (t *TaskTracker) liveMonitors(monitors []int, now time.Time) []int {
+	live := make([]int, 0, len(monitors))
+	for _, m := range monitors {
+		h := t.health[m]
+		if h == nil || h.droppedAt.IsZero() || now.Sub(h.droppedAt) >= t.Capture.RetryEvery.Duration {
+			live = append(live, m)
+		}
+	}
+	return live
+}
+
+// recordCaptures updates each monitor's failure streak after a tick. The
+// first failure in a streak is printed; after MaxFailures in a row the
+// monitor is dropped with a notice instead of failing every tick, which
+// is common when a docked monitor goes to sleep.
+func (t *TaskTracker) recordCaptures(monitors []int, err error, now time.Time) {
+	if t.health == nil {
+		t.health = make(map[int]*monitorHealth)
+	}
+	failed := map[int]error{}
+	for _, de := range capture.DisplayErrors(err) {
+		failed[de.Display] = de.Err
+	}
+
+	for _, m := range monitors {
+		h := t.health[m]
+		if h == nil {
+			h = &monitorHealth{}
+			t.health[m] = h
+		}
+
+		ferr, bad := failed[m]
+		if !bad {
+			if !h.droppedAt.IsZero() {
+				ui.Printf("✅ Monitor %d is back, capturing it again\n", m+1)
+				t.addEvent(fmt.Sprintf("Monitor %d back in capture", m+1))
+			}
+			*h = monitorHealth{}
+			continue
+		}
+
+		telemetry.Add("task_tracker.capture.failures", "1", 1, telemetry.A("monitor", m+1))
+		h.failures++
+		switch {
+		case !h.droppedAt.IsZero():
+			h.droppedAt = now // retry failed; wait another round quietly
+		case h.failures >= t.Capture.MaxFailures:
+			h.droppedAt = now
+			retry := t.Capture.RetryEvery.Duration
+			every := formatPlanned(retry)
+			if retry < time.Minute {
+				every = retry.String()
+			}
+			ui.Printf("⚠️  Monitor %d failed %d times in a row (%v); dropped from capture, retrying every %s\n",
+				m+1, h.failures, ferr, every)
+			t.addEvent(fmt.Sprintf("Monitor %d dropped from capture after %d failures: %v", m+1, h.failures, ferr))
+			t.notify(eventError, fmt.Sprintf("Monitor %d dropped from capture", m+1),
+				fmt.Sprintf("It failed %d times in a row (%v); retrying every %s.", h.failures, ferr, every))
+		case h.failures == 1:
+			ui.Printf("❌ Failed to capture monitor %d: %v\n", m+1, ferr)
+			t.log(logError, "error", fmt.Sprintf("Failed to capture monitor %d: %v", m+1, ferr))
+			t.notify(eventError, fmt.Sprintf("Failed to capture monitor %d", m+1), ferr.Error())
+		}
+	}
+}
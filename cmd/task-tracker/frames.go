@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	_ "golang.org/x/image/webp"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/delta"
+	"task-tracker/internal/imaging"
+)
+
+const (
+	// keyframeEvery forces a full frame after this many deltas, so a long
+	// session doesn't hang off a single keyframe
+	keyframeEvery = 120
+
+	// keyframeChangeRatio is the share of changed tiles above which a full
+	// frame is stored instead of a delta
+	keyframeChangeRatio = 0.5
+
+	// framesDir holds PNGs reconstructed from delta frames for the review
+	framesDir = "frames"
+
+	// defaultQuality is the JPEG and WebP quality unless --quality is given
+	defaultQuality = 85
+)
+
+// Frame formats for --format
+const (
+	formatPNG  = "png"
+	formatJPEG = "jpeg"
+	formatWebP = "webp"
+)
+
+var frameFormats = []string{formatPNG, formatJPEG, formatWebP}
+
+// frameExt is the file extension frames of a format are saved with
+var frameExt = map[string]string{formatPNG: ".png", formatJPEG: ".jpg", formatWebP: ".webp"}
+
+// frameFormat returns the format of a saved frame from its extension, ""
+// for delta frames
+func frameFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return formatPNG
+	case ".jpg", ".jpeg":
+		return formatJPEG
+	case ".webp":
+		return formatWebP
+	}
+	return ""
+}
+
+// keyframe is the last full frame saved for a monitor
+type keyframe struct {
+	img    *image.RGBA
+	name   string
+	deltas int
+}
+
+// checkFormat validates --format and --quality. Delta frames, shared blobs
+// and optimizing all work on PNGs, so they only go with png.
+func (t *TaskTracker) checkFormat() error {
+	if !slices.Contains(frameFormats, t.Format) {
+		return fmt.Errorf("%w: unknown --format '%s' (use %s)", errUsage, t.Format, strings.Join(frameFormats, ", "))
+	}
+	if t.Quality < 1 || t.Quality > 100 {
+		return fmt.Errorf("%w: --quality must be between 1 and 100", errUsage)
+	}
+	if t.Format == formatPNG {
+		return nil
+	}
+	if t.Delta || t.Dedupe || t.Optimize {
+		return fmt.Errorf("%w: --format %s can't be combined with --delta, --dedupe, --optimize or --pngquant", errUsage, t.Format)
+	}
+	if t.Format == formatWebP {
+		if _, err := exec.LookPath("cwebp"); err != nil {
+			return fmt.Errorf("%w: --format webp needs cwebp in PATH", errUsage)
+		}
+	}
+	return nil
+}
+
+// savedSize is the size a w×h frame is saved at, after --scale and
+// --max-width. Frames are only ever made smaller.
+func (t *TaskTracker) savedSize(w, h int) (int, int) {
+	scale := t.Scale
+	if scale <= 0 || scale > 1 {
+		scale = 1
+	}
+	if t.MaxWidth > 0 && float64(w)*scale > float64(t.MaxWidth) {
+		scale = float64(t.MaxWidth) / float64(w)
+	}
+	if scale == 1 {
+		return w, h
+	}
+	return max(1, int(float64(w)*scale+0.5)), max(1, int(float64(h)*scale+0.5))
+}
+
+// downscale resizes a frame for --scale and --max-width before it's
+// encoded, moving its cursor position along
+func (t *TaskTracker) downscale(f capturedFrame) capturedFrame {
+	b := f.img.Bounds()
+	w, h := t.savedSize(b.Dx(), b.Dy())
+	if w == b.Dx() && h == b.Dy() {
+		return f
+	}
+	if f.cursor != nil {
+		p := f.cursor.Sub(b.Min)
+		f.cursor = &image.Point{X: p.X * w / b.Dx(), Y: p.Y * h / b.Dy()}
+	}
+	f.img = capture.Downscale(f.img, w, h)
+	return f
+}
+
+// saveFrame writes a captured frame and returns its path, plus its blob
+// hash when deduplicating and the quality it was encoded at when lossy.
+// With delta storage on, frames that mostly match the monitor's keyframe
+// are stored as changed tiles only.
+func (t *TaskTracker) saveFrame(monitorIdx int, filename string, img *image.RGBA, text []imaging.PNGText) (path, blob string, quality int, err error) {
+	base := strings.TrimSuffix(filename, filepath.Ext(filename))
+	switch {
+	case t.throttleJPEG.Load():
+		quality = throttleJPEGQuality
+		if t.Format != formatPNG {
+			quality = min(quality, t.Quality)
+		}
+		path = filepath.Join(t.SessionDir, base+frameExt[formatJPEG])
+		return path, "", quality, writeJPEG(path, img, quality)
+	case t.Format == formatJPEG:
+		path = filepath.Join(t.SessionDir, base+frameExt[formatJPEG])
+		return path, "", t.Quality, writeJPEG(path, img, t.Quality)
+	case t.Format == formatWebP:
+		path = filepath.Join(t.SessionDir, base+frameExt[formatWebP])
+		return path, "", t.Quality, writeWebP(path, img, t.Quality)
+	}
+	if t.Dedupe {
+		path, blob, err = t.saveBlob(img)
+		return path, blob, 0, err
+	}
+
+	path = filepath.Join(t.SessionDir, filename)
+	if !t.Delta {
+		return path, "", 0, writePNG(path, img, text)
+	}
+
+	if t.keyframes == nil {
+		t.keyframes = make(map[int]*keyframe)
+	}
+
+	key := t.keyframes[monitorIdx]
+	if key != nil && key.deltas < keyframeEvery {
+		changed, total, ok := delta.Diff(key.img, img, delta.DefaultTileSize)
+		if ok && float64(len(changed)) <= float64(total)*keyframeChangeRatio {
+			path = strings.TrimSuffix(path, filepath.Ext(path)) + delta.Ext
+			if err := writeDelta(path, key.name, img, changed); err != nil {
+				return "", "", 0, err
+			}
+			key.deltas++
+			return path, "", 0, nil
+		}
+	}
+
+	if err := writePNG(path, img, text); err != nil {
+		return "", "", 0, err
+	}
+	if key != nil {
+		capture.Release(key.img)
+	}
+	t.keyframes[monitorIdx] = &keyframe{img: img, name: filename}
+	return path, "", 0, nil
+}
+
+// releaseFrame hands a saved frame's buffer back to the capture pool,
+// unless it's kept as the monitor's keyframe
+func (t *TaskTracker) releaseFrame(monitorIdx int, img *image.RGBA) {
+	if key := t.keyframes[monitorIdx]; key != nil && key.img == img {
+		return
+	}
+	capture.Release(img)
+}
+
+func writePNG(path string, img image.Image, text []imaging.PNGText) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	if err := imaging.EncodePNG(file, img, text); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return file.Close()
+}
+
+func writeDelta(path, ref string, img *image.RGBA, changed []int) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	if err := delta.Encode(file, ref, img, delta.DefaultTileSize, changed); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to encode delta frame: %w", err)
+	}
+	return file.Close()
+}
+
+func writeJPEG(path string, img image.Image, quality int) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	if err := jpeg.Encode(file, img, &jpeg.Options{Quality: quality}); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to encode JPEG: %w", err)
+	}
+	return file.Close()
+}
+
+// writeWebP encodes a frame with the external cwebp tool, which reads it
+// as PNG on stdin. Quality 100 is lossless.
+func writeWebP(path string, img image.Image, quality int) error {
+	args := []string{"-quiet", "-q", strconv.Itoa(quality)}
+	if quality >= 100 {
+		args = []string{"-quiet", "-lossless"}
+	}
+	var in bytes.Buffer
+	if err := imaging.EncodePNG(&in, img, nil); err != nil {
+		return fmt.Errorf("failed to encode PNG for cwebp: %w", err)
+	}
+	cmd := exec.Command("cwebp", append(args, "-o", path, "--", "-")...)
+	cmd.Stdin = &in
+	if out, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(path)
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("failed to encode WebP: %v: %s", err, msg)
+		}
+		return fmt.Errorf("failed to encode WebP: %w", err)
+	}
+	return nil
+}
+
+// isDelta reports whether a screenshot is stored as a delta frame
+func isDelta(path string) bool {
+	return filepath.Ext(path) == delta.Ext
+}
+
+// loadFrame decodes a screenshot, reconstructing delta frames from their
+// keyframe
+func loadFrame(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if !isDelta(path) {
+		img, _, err := image.Decode(file)
+		return img, err
+	}
+
+	dir := filepath.Dir(path)
+	return delta.Decode(file, func(name string) (image.Image, error) {
+		if isDelta(name) {
+			return nil, fmt.Errorf("keyframe %s is itself a delta", name)
+		}
+		return loadFrame(filepath.Join(dir, filepath.Base(name)))
+	})
+}
+
+// deltaRefs returns the keyframe file names referenced by delta frames
+func deltaRefs(shots []Screenshot) map[string]bool {
+	refs := make(map[string]bool)
+	for _, shot := range shots {
+		if !isDelta(shot.Path) {
+			continue
+		}
+		file, err := os.Open(shot.Path)
+		if err != nil {
+			continue
+		}
+		if h, err := delta.ReadHeader(file); err == nil {
+			refs[h.Ref] = true
+		}
+		file.Close()
+	}
+	return refs
+}
+
+// viewablePath returns a PNG path for a screenshot, reconstructing delta
+// frames into the session's frames directory and applying redactions on
+// first use
+func (t *TaskTracker) viewablePath(shot Screenshot) (string, error) {
+	if len(shot.Redact) == 0 {
+		return t.reconstructedPath(shot)
+	}
+
+	path := t.redactedPath(shot)
+	if fileExists(path) {
+		return path, nil
+	}
+	data, err := redactedFrame(shot.Path, shot.Redact)
+	if err != nil {
+		return "", fmt.Errorf("failed to redact %s: %w", shot.Path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, data, 0644)
+}
+
+// reconstructedPath returns a PNG path for a screenshot as captured
+func (t *TaskTracker) reconstructedPath(shot Screenshot) (string, error) {
+	if !isDelta(shot.Path) {
+		return shot.Path, nil
+	}
+
+	name := strings.TrimSuffix(filepath.Base(shot.Path), delta.Ext) + ".png"
+	path := filepath.Join(t.SessionDir, framesDir, name)
+	if fileExists(path) {
+		return path, nil
+	}
+
+	img, err := loadFrame(shot.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to reconstruct %s: %w", shot.Path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, writePNG(path, img, nil)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/ui"
+)
+
+// Gap is a stretch of a session with no capture ticks (sleep, crash, lock)
+type Gap struct {
+	Start           string  `json:"start"`
+	End             string  `json:"end"`
+	RelativeStart   float64 `json:"relative_start"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// Minimum silence that counts as a gap
+const minGap = 2 * time.Minute
+
+// gapThreshold is three missed ticks, but never less than minGap
+func (t *TaskTracker) gapThreshold() time.Duration {
+	threshold := 3 * t.CaptureInterval
+	if threshold < minGap {
+		threshold = minGap
+	}
+	return threshold
+}
+
+// recordTick notes that the capture loop ran at now, records a gap if the
+// previous tick was too long ago and adds the elapsed time to the active
+// total. Gaps are detected on the wall clock because the monotonic clock
+// doesn't advance while some machines sleep; active time is measured on the
+// monotonic clock so NTP adjustments can't stretch or shrink it.
+func (t *TaskTracker) recordTick(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tick(now)
+}
+
+// tick is recordTick for callers that already hold t.mu
+func (t *TaskTracker) tick(now time.Time) {
+	last := t.lastTick
+	if last.IsZero() {
+		last = t.StartTime
+	}
+	wallNow := now.Round(0)
+	silence := wallNow.Sub(last.Round(0))
+	elapsed := now.Sub(last) // monotonic when both readings carry one
+
+	if silence > t.gapThreshold() && !t.lastTick.IsZero() {
+		gap := Gap{
+			Start:           last.Round(0).Format(time.RFC3339),
+			End:             wallNow.Format(time.RFC3339),
+			RelativeStart:   last.Round(0).Sub(t.StartTime.Round(0)).Seconds(),
+			DurationSeconds: silence.Seconds(),
+		}
+		t.Gaps = append(t.Gaps, gap)
+		ui.Printf("⚠️  Gap detected: no captures for %.1f minutes\n", silence.Minutes())
+		t.log(logWarn, "gap", fmt.Sprintf("No captures for %.1f minutes", silence.Minutes()))
+	}
+
+	// Time across a gap or a suspend isn't active time
+	if elapsed > 0 && elapsed <= t.gapThreshold() {
+		t.activeTime += elapsed
+	}
+	t.lastTick = now
+}
+
+// skipTick moves the last tick to now without counting the time since as
+// active, for periods that are deliberately not tracked
+func (t *TaskTracker) skipTick(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastTick = now
+}
+
+// lastTickTime returns when the capture loop last ran
+func (t *TaskTracker) lastTickTime() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lastTick
+}
+
+// activeDuration is the tracked time excluding gaps. Call it with t.mu
+// held or after capture has stopped.
+func (t *TaskTracker) activeDuration() time.Duration {
+	return t.activeTime
+}
+
+// wallDuration is the wall-clock time between start and end
+func (t *TaskTracker) wallDuration() time.Duration {
+	return t.EndTime.Round(0).Sub(t.StartTime.Round(0))
+}
+
+// gapSeconds totals the time lost to gaps
+func (t *TaskTracker) gapSeconds() float64 {
+	total := 0.0
+	for _, gap := range t.Gaps {
+		total += gap.DurationSeconds
+	}
+	return total
+}
+
+// timelineEntry is a note, gap or activity shown between screenshots in the
+// review file
+type timelineEntry struct {
+	RelativeTime float64
+	Text         string
+}
+
+// timeline merges notes, gaps, pauses, away placeholders and imported activity in chronological order
+func (t *TaskTracker) timeline() []timelineEntry {
+	entries := []timelineEntry{}
+	for _, note := range t.Notes {
+		entries = append(entries, timelineEntry{
+			RelativeTime: note.RelativeTime,
+			Text:         fmt.Sprintf("> 📝 **%s:** %s", i18n.T("timeline.note", note.RelativeTime/60), note.Text),
+		})
+	}
+	for _, gap := range t.Gaps {
+		entries = append(entries, timelineEntry{
+			RelativeTime: gap.RelativeStart,
+			Text: fmt.Sprintf("> ⏸️ **%s:** %s",
+				i18n.T("timeline.gap", gap.RelativeStart/60, (gap.RelativeStart+gap.DurationSeconds)/60),
+				i18n.T("timeline.gap_why", gap.DurationSeconds/60)),
+		})
+	}
+	for _, a := range t.Activity {
+		if a.DurationSeconds < awTimelineMin.Seconds() {
+			continue
+		}
+		text := fmt.Sprintf("> 🪟 **%s (%.1f min, for %.1f min):** %s", a.App, a.RelativeTime/60, a.DurationSeconds/60, a.Title)
+		if a.AFK {
+			text = fmt.Sprintf("> 💤 **%s:** %s",
+				i18n.T("timeline.away", a.RelativeTime/60, (a.RelativeTime+a.DurationSeconds)/60),
+				i18n.T("timeline.away_why"))
+		}
+		entries = append(entries, timelineEntry{RelativeTime: a.RelativeTime, Text: text})
+	}
+
+	entries = append(entries, t.awayRuns()...)
+	entries = append(entries, t.pauseEntries()...)
+	entries = append(entries, t.quarantineEntries()...)
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].RelativeTime < entries[j].RelativeTime
+	})
+	return entries
+}
+
+// writeTimeline renders timeline entries into the review file
+func writeTimeline(md *strings.Builder, entries []timelineEntry) {
+	for _, e := range entries {
+		md.WriteString(e.Text + "\n\n")
+	}
+}
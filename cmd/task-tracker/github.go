@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// githubClient talks to the GitHub REST API. GITHUB_TOKEN configures it;
+// GITHUB_API_URL points it at GitHub Enterprise Server. GitHub has no time
+// tracking, so worklogs are posted as comments.
+type githubClient struct {
+	restClient
+	web string // web UI base, for issue links
+}
+
+// newGitHubClient returns nil if GitHub isn't configured
+func newGitHubClient() *githubClient {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil
+	}
+
+	api, web := os.Getenv("GITHUB_API_URL"), "https://github.com"
+	if api == "" {
+		api = "https://api.github.com"
+	} else {
+		// Enterprise Server serves the API under /api/v3 of its web host
+		web = strings.TrimSuffix(strings.TrimSuffix(api, "/"), "/api/v3")
+	}
+	return &githubClient{
+		restClient: newRESTClient("GitHub", api, func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		}),
+		web: web,
+	}
+}
+
+// issuePath is the API path of owner/repo#12
+func (c *githubClient) issuePath(key string) string {
+	repo, number := splitRepoIssue(key)
+	return "/repos/" + repo + "/issues/" + number
+}
+
+// GetIssue fetches an issue's title and state
+func (c *githubClient) GetIssue(key string) (*Issue, error) {
+	var issue struct {
+		Title   string `json:"title"`
+		State   string `json:"state"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := c.do("GET", c.issuePath(key), nil, &issue); err != nil {
+		return nil, err
+	}
+	return &Issue{Key: key, Title: issue.Title, State: issue.State, URL: issue.HTMLURL}, nil
+}
+
+// PostComment comments on an issue. GitHub renders markdown as is.
+func (c *githubClient) PostComment(key, text string) error {
+	return c.do("POST", c.issuePath(key)+"/comments", map[string]string{"body": text}, nil)
+}
+
+// PostWorklog records the time as a comment
+func (c *githubClient) PostWorklog(key string, spent time.Duration, started time.Time, comment string) error {
+	return c.PostComment(key, worklogComment(spent, started, comment))
+}
+
+// IssueURL is the issue's page
+func (c *githubClient) IssueURL(key string) string {
+	repo, number := splitRepoIssue(key)
+	return c.web + "/" + repo + "/issues/" + number
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// gitlabClient talks to the GitLab REST API. GITLAB_TOKEN configures it;
+// GITLAB_URL points it at a self-managed instance. Worklogs use GitLab's
+// issue time tracking.
+type gitlabClient struct {
+	restClient
+}
+
+// newGitLabClient returns nil if GitLab isn't configured
+func newGitLabClient() *gitlabClient {
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		return nil
+	}
+	base := os.Getenv("GITLAB_URL")
+	if base == "" {
+		base = "https://gitlab.com"
+	}
+	return &gitlabClient{restClient: newRESTClient("GitLab", base, func(req *http.Request) {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	})}
+}
+
+// issuePath is the API path of group/project#12
+func (c *gitlabClient) issuePath(key string) string {
+	project, iid := splitRepoIssue(key)
+	return "/api/v4/projects/" + url.PathEscape(project) + "/issues/" + iid
+}
+
+// GetIssue fetches an issue's title, state and time tracking
+func (c *gitlabClient) GetIssue(key string) (*Issue, error) {
+	var issue struct {
+		Title     string `json:"title"`
+		State     string `json:"state"`
+		WebURL    string `json:"web_url"`
+		TimeStats struct {
+			TimeEstimate   int64 `json:"time_estimate"`
+			TotalTimeSpent int64 `json:"total_time_spent"`
+		} `json:"time_stats"`
+	}
+	if err := c.do("GET", c.issuePath(key), nil, &issue); err != nil {
+		return nil, err
+	}
+
+	stats := issue.TimeStats
+	return &Issue{
+		Key:   key,
+		Title: issue.Title,
+		State: issue.State,
+		URL:   issue.WebURL,
+		Estimate: &Estimate{
+			OriginalSeconds:  stats.TimeEstimate,
+			RemainingSeconds: max(0, stats.TimeEstimate-stats.TotalTimeSpent),
+			LoggedSeconds:    stats.TotalTimeSpent,
+			FetchedAt:        time.Now().Format(time.RFC3339),
+		},
+	}, nil
+}
+
+// PostComment adds a note to an issue. GitLab renders markdown as is.
+func (c *gitlabClient) PostComment(key, text string) error {
+	return c.do("POST", c.issuePath(key)+"/notes", map[string]string{"body": text}, nil)
+}
+
+// PostWorklog adds spent time to the issue
+func (c *gitlabClient) PostWorklog(key string, spent time.Duration, started time.Time, comment string) error {
+	// GitLab wants "1h20m" rather than Jira's "1h 20m"
+	body := map[string]string{"duration": strings.ReplaceAll(formatTimeSpent(spent), " ", "")}
+	if comment != "" {
+		body["summary"] = comment
+	}
+	return c.do("POST", c.issuePath(key)+"/add_spent_time", body, nil)
+}
+
+// IssueURL is the issue's page
+func (c *gitlabClient) IssueURL(key string) string {
+	project, iid := splitRepoIssue(key)
+	return c.base + "/" + project + "/-/issues/" + iid
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"task-tracker/internal/load"
+	"task-tracker/internal/telemetry"
+	"task-tracker/internal/ui"
+)
+
+const (
+	defaultLoadCPU      = 85
+	defaultLoadMemory   = 10
+	defaultLoadSelf     = 25
+	defaultLoadMaxDefer = 30 * time.Second
+	defaultLoadCheck    = 2 * time.Second
+
+	// loadHysteresis is how far, in percent, usage has to fall back past a
+	// limit before the machine counts as calm again
+	loadHysteresis = 10
+
+	// deferPoll is how often deferred work looks whether it may go ahead
+	deferPoll = 250 * time.Millisecond
+)
+
+// LoadConfig is the policy for staying out of the way while the machine is
+// busy, e.g. during a compile. Frames are still captured on time, but
+// encoding them and optimizing earlier ones waits until usage falls back.
+// A limit of 0 turns it off.
+type LoadConfig struct {
+	CPUAbove    int      `json:"cpu_above"`    // percent of all CPUs busy
+	MemoryBelow int      `json:"memory_below"` // percent of memory available
+	SelfAbove   int      `json:"self_above"`   // percent of all CPUs task-tracker itself uses
+	MaxDefer    Duration `json:"max_defer"`    // longest a tick's frames wait to be encoded
+	CheckEvery  Duration `json:"check_every"`
+}
+
+// Validate checks the limits are percentages
+func (c LoadConfig) Validate() error {
+	for _, limit := range []int{c.CPUAbove, c.MemoryBelow, c.SelfAbove} {
+		if limit < 0 || limit > 100 {
+			return fmt.Errorf("load: limits must be between 0 and 100, got %d", limit)
+		}
+	}
+	if c.CheckEvery.Duration < time.Second {
+		return fmt.Errorf("load.check_every must be at least 1s")
+	}
+	if c.MaxDefer.Duration < 0 {
+		return fmt.Errorf("load.max_defer can't be negative")
+	}
+	return nil
+}
+
+func (c LoadConfig) enabled() bool {
+	return c.MaxDefer.Duration > 0 && (c.CPUAbove > 0 || c.MemoryBelow > 0 || c.SelfAbove > 0)
+}
+
+// overLimits lists the limits usage is past. margin widens them, so a busy
+// machine has to calm down clearly before it counts as calm.
+func (c LoadConfig) overLimits(u load.Usage, margin int) []string {
+	var over []string
+	if c.CPUAbove > 0 && u.System > c.CPUAbove-margin {
+		over = append(over, fmt.Sprintf("CPU %d%%", u.System))
+	}
+	if c.MemoryBelow > 0 && u.MemFree >= 0 && u.MemFree < c.MemoryBelow+margin {
+		over = append(over, fmt.Sprintf("%d%% memory free", u.MemFree))
+	}
+	if c.SelfAbove > 0 && u.Self > c.SelfAbove-margin {
+		over = append(over, fmt.Sprintf("task-tracker at %d%% CPU", u.Self))
+	}
+	return over
+}
+
+// governor samples CPU and memory use in the background and flags the
+// machine busy for the writer and background optimizing
+type governor struct {
+	cfg   LoadConfig
+	meter load.Meter
+	busy  atomic.Bool
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// startGovernor starts sampling, if the load policy is on
+func (t *TaskTracker) startGovernor() {
+	if !t.Load.enabled() {
+		return
+	}
+	g := &governor{cfg: t.Load, stop: make(chan struct{}), done: make(chan struct{})}
+	if _, err := g.meter.Read(); err != nil {
+		if !errors.Is(err, load.ErrUnsupported) {
+			ui.Printf("⚠️  Can't read system load, encoding won't wait for a busy machine: %v\n", err)
+		}
+		return
+	}
+	t.governor = g
+
+	go func() {
+		defer close(g.done)
+		ticker := time.NewTicker(g.cfg.CheckEvery.Duration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-g.stop:
+				return
+			case <-ticker.C:
+				g.check(t)
+			}
+		}
+	}()
+}
+
+// stopGovernor stops sampling and lets deferred work go ahead
+func (t *TaskTracker) stopGovernor() {
+	if t.governor == nil {
+		return
+	}
+	close(t.governor.stop)
+	<-t.governor.done
+}
+
+// check samples usage and records the machine turning busy or calm
+func (g *governor) check(t *TaskTracker) {
+	u, err := g.meter.Read()
+	if err != nil {
+		return
+	}
+
+	busy := g.busy.Load()
+	var msg string
+	if !busy {
+		if over := g.cfg.overLimits(u, 0); len(over) > 0 {
+			msg = fmt.Sprintf("Machine busy (%s): deferring frame encoding", strings.Join(over, ", "))
+		}
+	} else if len(g.cfg.overLimits(u, loadHysteresis)) == 0 {
+		msg = "Machine calm again: encoding deferred frames"
+	}
+	if msg == "" {
+		return
+	}
+
+	g.busy.Store(!busy)
+	ui.Printf("🐢 %s\n", msg)
+	t.addEvent(msg)
+}
+
+// machineBusy reports whether background work should wait
+func (t *TaskTracker) machineBusy() bool {
+	return t.governor != nil && t.governor.busy.Load()
+}
+
+// deferWhileBusy holds the writer back while the machine is busy. It gives
+// up after MaxDefer, when the next tick is already waiting (so none is
+// dropped for it), or when capture stops.
+func (t *TaskTracker) deferWhileBusy() {
+	g := t.governor
+	if !t.machineBusy() {
+		return
+	}
+
+	start := time.Now()
+	poll := time.NewTicker(deferPoll)
+	defer poll.Stop()
+	for g.busy.Load() && len(t.writeQueue) == 0 && time.Since(start) < g.cfg.MaxDefer.Duration {
+		select {
+		case <-g.stop:
+			return
+		case <-poll.C:
+		}
+	}
+	telemetry.Observe("task_tracker.encode.deferred", time.Since(start))
+}
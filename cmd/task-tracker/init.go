@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/paths"
+	"task-tracker/internal/ui"
+)
+
+// ticketEnv lists the environment variables each ticket system reads.
+// Credentials stay out of the config file.
+var ticketEnv = map[string][]string{
+	"jira":   {"JIRA_URL", "JIRA_EMAIL (Jira Cloud)", "JIRA_API_TOKEN"},
+	"github": {"GITHUB_TOKEN"},
+	"gitlab": {"GITLAB_TOKEN", "GITLAB_URL (self-managed)"},
+	"linear": {"LINEAR_API_KEY"},
+	"azure":  {"AZURE_DEVOPS_ORG_URL", "AZURE_DEVOPS_TOKEN"},
+}
+
+// initAnswers is what the init wizard asked
+type initAnswers struct {
+	OutputDir string
+	Interval  int // seconds
+	Monitors  string
+	Mask      string // window title pattern
+	Blackout  []BlackoutWindow
+	Tickets   string
+	Slack     string // webhook URL
+}
+
+// wizard reads answers from the terminal
+type wizard struct {
+	in *bufio.Reader
+}
+
+// ask prints a question and returns the answer, or def for an empty one
+func (w *wizard) ask(question, def string) string {
+	if def != "" {
+		ui.Printf("%s [%s]: ", question, def)
+	} else {
+		ui.Printf("%s: ", question)
+	}
+	line, _ := w.in.ReadString('\n')
+	if line = strings.TrimSpace(line); line != "" {
+		return line
+	}
+	return def
+}
+
+// askUntil repeats a question until check accepts the answer. Defaults
+// must pass, so input ending can't loop forever.
+func (w *wizard) askUntil(question, def string, check func(string) error) string {
+	for {
+		answer := w.ask(question, def)
+		err := check(answer)
+		if err == nil {
+			return answer
+		}
+		ui.Printf("❌ %v\n", err)
+	}
+}
+
+// yes asks a yes/no question
+func (w *wizard) yes(question string, def bool) bool {
+	d := "y/N"
+	if def {
+		d = "Y/n"
+	}
+	answer := strings.ToLower(w.ask(question+" ("+d+")", ""))
+	if answer == "" {
+		return def
+	}
+	return answer == "y" || answer == "yes"
+}
+
+// monitorHelper finds the monitor-helper binary in PATH or next to this
+// one
+func monitorHelper() string {
+	if path, err := exec.LookPath("monitor-helper"); err == nil {
+		return path
+	}
+	if self, err := os.Executable(); err == nil {
+		path := filepath.Join(filepath.Dir(self), "monitor-helper")
+		if _, err := exec.LookPath(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// defaultPresetMonitors returns the monitors of monitor-helper's default
+// preset, if one is set
+func defaultPresetMonitors() string {
+	path, err := paths.Presets()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var presets map[string]struct {
+		Monitors string `json:"monitors"`
+		Default  bool   `json:"default"`
+	}
+	if json.Unmarshal(data, &presets) != nil {
+		return ""
+	}
+	for _, p := range presets {
+		if p.Default {
+			return p.Monitors
+		}
+	}
+	return ""
+}
+
+// parseBlackouts parses "12:00-13:00, 18:00-" into blackout windows
+// applying every day
+func parseBlackouts(s string) ([]BlackoutWindow, error) {
+	var windows []BlackoutWindow
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || part == "none" {
+			continue
+		}
+		start, end, _ := strings.Cut(part, "-")
+		b := BlackoutWindow{Start: strings.TrimSpace(start), End: strings.TrimSpace(end)}
+		if err := b.Validate(); err != nil {
+			return nil, err
+		}
+		windows = append(windows, b)
+	}
+	return windows, nil
+}
+
+// run asks the questions
+func (w *wizard) run() initAnswers {
+	var a initAnswers
+
+	ui.Println("\nStep 1/5: Sessions")
+	a.OutputDir = w.ask("Save sessions in (relative paths are from where you run task-tracker)", defaultCapturesDir)
+	interval := w.askUntil("Capture every how many seconds", "30", func(s string) error {
+		if n, err := strconv.Atoi(s); err != nil || n < 1 {
+			return fmt.Errorf("give a whole number of seconds")
+		}
+		return nil
+	})
+	a.Interval, _ = strconv.Atoi(interval)
+
+	ui.Println("\nStep 2/5: Monitors")
+	if helper := monitorHelper(); helper != "" {
+		if w.yes("Run the monitor-helper wizard to identify your monitors and save presets?", false) {
+			cmd := exec.Command(helper, "setup")
+			cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+			if err := cmd.Run(); err != nil {
+				ui.Printf("⚠️  monitor-helper: %v\n", err)
+			}
+			ui.Println()
+		}
+	} else {
+		ui.Println("💡 monitor-helper isn't installed; 'task-tracker start' lists your monitors when it starts")
+	}
+	def := defaultPresetMonitors()
+	if def == "" {
+		def = "all"
+	}
+	a.Monitors = w.ask("Monitors to capture (all, primary, 1,2 or names like DP-1)", def)
+
+	ui.Println("\nStep 3/5: Privacy")
+	a.Mask = w.askUntil("Blank out windows whose title matches (regular expression, e.g. 1Password|Bank; empty for none)", "", func(s string) error {
+		_, err := regexp.Compile(s)
+		return err
+	})
+	blackouts := w.askUntil("Never capture between (e.g. 12:00-13:00, comma-separated; empty for none)", "", func(s string) error {
+		_, err := parseBlackouts(s)
+		return err
+	})
+	a.Blackout, _ = parseBlackouts(blackouts)
+
+	ui.Println("\nStep 4/5: Ticket system")
+	a.Tickets = w.askUntil("Which do you log time to (jira, github, gitlab, linear, azure or none)", "none", func(s string) error {
+		if _, ok := ticketEnv[s]; !ok && s != "none" {
+			return fmt.Errorf("unknown ticket system '%s'", s)
+		}
+		return nil
+	})
+
+	ui.Println("\nStep 5/5: Notifications")
+	a.Slack = w.askUntil("Slack incoming webhook for summaries and capture errors (empty for none)", "", func(s string) error {
+		if s != "" && !strings.HasPrefix(s, "https://") {
+			return fmt.Errorf("webhook URLs start with https://")
+		}
+		return nil
+	})
+	return a
+}
+
+// initConfig writes the answers as a commented config file. Settings left
+// at their defaults are listed in comments, so they're easy to find.
+func initConfig(a initAnswers) string {
+	q := func(s string) string {
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+	var c strings.Builder
+	c.WriteString("// task-tracker config, written by 'task-tracker init'.\n")
+	c.WriteString("// Lines starting with // are comments. Check changes with 'task-tracker config validate';\n")
+	c.WriteString("// the README lists every setting.\n")
+	c.WriteString("{\n")
+	c.WriteString("  // Where sessions are saved\n")
+	fmt.Fprintf(&c, "  \"output_dir\": %s,\n\n", q(a.OutputDir))
+
+	c.WriteString("  \"capture\": {\n")
+	c.WriteString("    // Defaults for start's --interval and --monitors\n")
+	fmt.Fprintf(&c, "    \"interval\": %s,\n", q(fmt.Sprintf("%ds", a.Interval)))
+	fmt.Fprintf(&c, "    \"monitors\": %s\n", q(a.Monitors))
+	c.WriteString("    // \"monitor_intervals\": \"2:5m\"  capture some monitors less often\n")
+	c.WriteString("  },\n\n")
+
+	c.WriteString("  // Windows blanked out of every frame; own blanks the terminal task-tracker runs in\n")
+	c.WriteString("  \"mask\": {\n")
+	if a.Mask != "" {
+		c.WriteString("    \"own\": true,\n")
+		fmt.Fprintf(&c, "    \"windows\": [%s]\n", q(a.Mask))
+	} else {
+		c.WriteString("    \"own\": true\n")
+		c.WriteString("    // \"windows\": [\"1Password|Bank\"]\n")
+	}
+	c.WriteString("  },\n\n")
+
+	c.WriteString("  // Times nothing is captured, e.g. lunch; days are mon-sun, every day if left out\n")
+	c.WriteString("  \"blackout\": [")
+	for i, b := range a.Blackout {
+		if i > 0 {
+			c.WriteString(",")
+		}
+		fmt.Fprintf(&c, "\n    {\"start\": %s", q(b.Start))
+		if b.End != "" {
+			fmt.Fprintf(&c, ", \"end\": %s", q(b.End))
+		}
+		c.WriteString("}")
+	}
+	if len(a.Blackout) > 0 {
+		c.WriteString("\n  ")
+	}
+	c.WriteString("],\n\n")
+
+	c.WriteString("  // Where session events go; channels: desktop, slack, teams, email\n")
+	c.WriteString("  \"notifications\": {\n")
+	if a.Slack != "" {
+		fmt.Fprintf(&c, "    \"channels\": {\"slack\": {\"type\": \"slack\", \"url\": %s}},\n", q(a.Slack))
+		c.WriteString("    \"routes\": [{\"events\": [\"summary\", \"error\"], \"channels\": [\"slack\"]}]\n")
+	} else {
+		c.WriteString("    \"channels\": {\"desktop\": {\"type\": \"desktop\"}},\n")
+		c.WriteString("    \"routes\": [{\"events\": [\"error\"], \"channels\": [\"desktop\"]}]\n")
+	}
+	c.WriteString("  }\n")
+
+	if env := ticketEnv[a.Tickets]; len(env) > 0 {
+		fmt.Fprintf(&c, "\n  // %s credentials come from the environment, not this file:\n", a.Tickets)
+		fmt.Fprintf(&c, "  // %s\n", strings.Join(env, ", "))
+	}
+	c.WriteString("}\n")
+	return c.String()
+}
+
+// newInitCmd builds the init command
+func newInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "Set up task-tracker: sessions, monitors, privacy and integrations",
+		Long: `Walk through first-run setup and write a commented config file:
+where sessions are saved, the capture interval, which monitors to capture
+(running the monitor-helper wizard if it's installed), windows to blank
+out, times not to capture, the ticket system and Slack notifications.
+
+Every setting can be changed in the file afterwards; 'task-tracker config
+validate' checks it.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			path, err := paths.Config()
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			ui.Println("================================================================")
+			ui.Println("  🎯 Task Tracker - Setup")
+			ui.Println("================================================================")
+			ui.Printf("\n📝 Config file: %s\n", path)
+
+			w := &wizard{in: bufio.NewReader(os.Stdin)}
+			if fileExists(path) && !w.yes("⚠️  It already exists. Replace it?", false) {
+				ui.Println("Nothing changed")
+				return
+			}
+
+			a := w.run()
+			data := initConfig(a)
+			if _, problems := parseConfig(path, []byte(data)); len(problems) > 0 {
+				ui.Printf("❌ %v\n", problems[0])
+				exit(exitError)
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+				ui.Printf("❌ Failed to write config: %v\n", err)
+				exit(exitCode(err))
+			}
+
+			ui.Printf("\n✅ Wrote %s\n", path)
+			if env := ticketEnv[a.Tickets]; len(env) > 0 {
+				ui.Printf("🔑 Set %s in your shell profile to log time to %s\n", strings.Join(env, ", "), a.Tickets)
+			}
+			ui.Println("\n🎉 You're all set! Try it out:")
+			ui.Println("  task-tracker start 'My task'")
+		},
+	}
+}
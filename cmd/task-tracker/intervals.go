@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-tracker/internal/ui"
+)
+
+// parseMonitorIntervals parses "1:30s,2:5m" into intervals by 0-based
+// monitor index. A bare number is seconds. numMonitors 0 skips checking
+// that the monitors exist.
+func parseMonitorIntervals(s string, numMonitors int) (map[int]time.Duration, error) {
+	intervals := make(map[int]time.Duration)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		monitor, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid monitor interval '%s' (use monitor:interval, e.g. 2:5m)", part)
+		}
+		m, err := strconv.Atoi(strings.TrimSpace(monitor))
+		if err != nil || m < 1 {
+			return nil, fmt.Errorf("invalid monitor '%s' in '%s' (use a monitor number)", monitor, part)
+		}
+		if numMonitors > 0 && m > numMonitors {
+			return nil, fmt.Errorf("invalid monitor '%s' in '%s' (1-%d)", monitor, part, numMonitors)
+		}
+		value = strings.TrimSpace(value)
+		d, err := time.ParseDuration(value)
+		if secs, serr := strconv.Atoi(value); serr == nil {
+			d, err = time.Duration(secs)*time.Second, nil
+		}
+		if err != nil || d < time.Second {
+			return nil, fmt.Errorf("invalid interval '%s' for monitor %d (e.g. 30s or 5m)", value, m)
+		}
+		intervals[m-1] = d
+	}
+	return intervals, nil
+}
+
+// setMonitorIntervals applies per-monitor intervals from a flag or config.
+// Ticks still come every CaptureInterval; a monitor with a longer interval
+// just sits out ticks until it's due, so none may be shorter.
+func (t *TaskTracker) setMonitorIntervals(spec string) error {
+	intervals, err := parseMonitorIntervals(spec, t.Capturer.NumDisplays())
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if len(intervals) == 0 {
+		return nil
+	}
+	if t.Composite {
+		return fmt.Errorf("%w: per-monitor intervals don't work with --composite, which saves all monitors in one frame", errUsage)
+	}
+
+	var described []string
+	for _, m := range t.MonitorsToCapture {
+		d, ok := intervals[m]
+		if !ok {
+			continue
+		}
+		if d < t.CaptureInterval {
+			return fmt.Errorf("%w: monitor %d's interval %s is shorter than --interval %s; lower --interval instead",
+				errUsage, m+1, d, t.CaptureInterval)
+		}
+		described = append(described, fmt.Sprintf("monitor %d every %s", m+1, d))
+	}
+	t.MonitorIntervals = intervals
+	if len(described) < len(t.MonitorsToCapture) {
+		described = append(described, fmt.Sprintf("others every %s", t.CaptureInterval))
+	}
+	ui.Printf("⏲️  Capturing %s\n", strings.Join(described, ", "))
+	return nil
+}
+
+// monitorInterval is how often monitor m is captured
+func (t *TaskTracker) monitorInterval(m int) time.Duration {
+	if d, ok := t.MonitorIntervals[m]; ok {
+		return d
+	}
+	return t.CaptureInterval
+}
+
+// dueMonitors drops monitors whose own interval hasn't passed since they
+// were last captured, and marks the rest as captured now. Half a tick of
+// slack keeps a 5m monitor on a 30s tick from slipping to 5m30s.
+func (t *TaskTracker) dueMonitors(monitors []int, now time.Time) []int {
+	if len(t.MonitorIntervals) == 0 {
+		return monitors
+	}
+	if t.lastCaptured == nil {
+		t.lastCaptured = make(map[int]time.Time)
+	}
+
+	due := make([]int, 0, len(monitors))
+	for _, m := range monitors {
+		d, ok := t.MonitorIntervals[m]
+		last := t.lastCaptured[m]
+		if ok && !t.forceCapture && !last.IsZero() && now.Sub(last) < d-t.CaptureInterval/2 {
+			continue
+		}
+		t.lastCaptured[m] = now
+		due = append(due, m)
+	}
+	return due
+}
+
+// monitorSeconds converts intervals to the form kept in metadata
+func monitorSeconds(intervals map[int]time.Duration) map[string]float64 {
+	if len(intervals) == 0 {
+		return nil
+	}
+	seconds := make(map[string]float64, len(intervals))
+	for m, d := range intervals {
+		seconds[strconv.Itoa(m+1)] = d.Seconds()
+	}
+	return seconds
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseMonitorIntervals(t *testing.T) {
+	tests := []struct {
+		spec     string
+		monitors int
+		want     map[int]time.Duration
+		wantErr  bool
+	}{
+		{"", 2, map[int]time.Duration{}, false},
+		{"1:30s,2:5m", 2, map[int]time.Duration{0: 30 * time.Second, 1: 5 * time.Minute}, false},
+		{" 2 : 90 ", 2, map[int]time.Duration{1: 90 * time.Second}, false},
+		{"3:1m,", 0, map[int]time.Duration{2: time.Minute}, false},
+		{"3:1m", 2, nil, true},
+		{"0:1m", 2, nil, true},
+		{"x:1m", 2, nil, true},
+		{"1", 2, nil, true},
+		{"1:soon", 2, nil, true},
+		{"1:500ms", 2, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parseMonitorIntervals(tt.spec, tt.monitors)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMonitorIntervals(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("parseMonitorIntervals(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDueMonitors(t *testing.T) {
+	tracker := &TaskTracker{
+		CaptureInterval:  30 * time.Second,
+		MonitorIntervals: map[int]time.Duration{1: 2 * time.Minute},
+	}
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.Local)
+	var due [][]int
+	for i := 0; i < 5; i++ {
+		due = append(due, tracker.dueMonitors([]int{0, 1}, start.Add(time.Duration(i)*30*time.Second)))
+	}
+	want := [][]int{{0, 1}, {0}, {0}, {0}, {0, 1}}
+	for i := range want {
+		if !slices.Equal(due[i], want[i]) {
+			t.Errorf("tick %d: due %v, want %v", i, due[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Each running start process listens on a unix socket inside its session
+// directory. capturesDir/running/<name> holds the ID of the session with
+// that name so other invocations (note, stop, ...) can find it.
+const (
+	controlSocketName = "control.sock"
+	runningDir        = "running"
+)
+
+// sessionNamePattern limits session names to what's safe as a file name
+var sessionNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// controlRequest is sent by CLI commands to the running session
+type controlRequest struct {
+	Command string `json:"command"`
+	Text    string `json:"text,omitempty"`
+	For     string `json:"for,omitempty"`    // duration, for privacy
+	Lines   int    `json:"lines,omitempty"`  // recent entries, for logs
+	Follow  bool   `json:"follow,omitempty"` // keep streaming, for logs
+}
+
+// controlResponse is the running session's reply
+type controlResponse struct {
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Start listening for control requests from other task-tracker invocations
+func (t *TaskTracker) startControlServer() error {
+	socketPath := filepath.Join(t.SessionDir, controlSocketName)
+	os.Remove(socketPath) // stale socket from a crashed run
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to open control socket: %w", err)
+	}
+	t.listener = listener
+
+	runningPath := filepath.Join(t.OutputDir, runningDir, t.sessionName())
+	if err := os.MkdirAll(filepath.Dir(runningPath), 0755); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to register running session: %w", err)
+	}
+	if err := os.WriteFile(runningPath, []byte(t.SessionID), 0644); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to register running session: %w", err)
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return // listener closed
+			}
+			go t.handleControlConn(conn)
+		}
+	}()
+
+	return nil
+}
+
+// Stop the control server and unregister the active session
+func (t *TaskTracker) stopControlServer() {
+	if t.listener == nil {
+		return
+	}
+	t.listener.Close()
+	t.listener = nil
+
+	os.Remove(filepath.Join(t.SessionDir, controlSocketName))
+
+	runningPath := filepath.Join(t.OutputDir, runningDir, t.sessionName())
+	if data, err := os.ReadFile(runningPath); err == nil && string(data) == t.SessionID {
+		os.Remove(runningPath)
+	}
+}
+
+// sessionName is the name other commands use to address the session
+func (t *TaskTracker) sessionName() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.SessionID
+}
+
+func (t *TaskTracker) handleControlConn(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	var req controlRequest
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		json.NewEncoder(conn).Encode(controlResponse{Error: "invalid request"})
+		return
+	}
+	if req.Command == "logs" {
+		t.serveLogs(conn, req)
+		return
+	}
+
+	json.NewEncoder(conn).Encode(t.handleControl(req))
+}
+
+// Dispatch a control request
+func (t *TaskTracker) handleControl(req controlRequest) controlResponse {
+	switch req.Command {
+	case "note":
+		text := strings.TrimSpace(req.Text)
+		if text == "" {
+			return controlResponse{Error: "note text cannot be empty"}
+		}
+		note := t.addNote(text)
+		return controlResponse{OK: true, Message: fmt.Sprintf("Note added at %.1f min", note.RelativeTime/60)}
+
+	case "ping":
+		return controlResponse{OK: true, Message: t.SessionID}
+
+	case "stop":
+		if t.cancel == nil {
+			return controlResponse{Error: "session can't be stopped remotely"}
+		}
+		t.cancel()
+		return controlResponse{OK: true, Message: fmt.Sprintf("Stopping %s", t.sessionName())}
+
+	case "mark":
+		if t.QA {
+			marker := t.addStep(strings.TrimSpace(req.Text))
+			return controlResponse{OK: true, Message: fmt.Sprintf("Step %d captured", marker.Step)}
+		}
+		marker := t.addMarker(strings.TrimSpace(req.Text))
+		if len(marker.Screenshots) == 0 {
+			return controlResponse{OK: true, Message: "Marker set; it will flag the next capture"}
+		}
+		return controlResponse{OK: true, Message: fmt.Sprintf("Marker set at %.1f min", marker.RelativeTime/60)}
+
+	case "privacy":
+		switch req.Text {
+		case "on":
+			d, err := time.ParseDuration(req.For)
+			if err != nil || d < 0 {
+				return controlResponse{Error: fmt.Sprintf("invalid duration '%s'", req.For)}
+			}
+			return controlResponse{OK: true, Message: t.setPrivacy(true, d)}
+		case "off":
+			return controlResponse{OK: true, Message: t.setPrivacy(false, 0)}
+		}
+		return controlResponse{Error: "privacy must be 'on' or 'off'"}
+
+	case "pause", "resume":
+		msg, err := t.setPaused(req.Command == "pause")
+		if err != nil {
+			return controlResponse{Error: err.Error()}
+		}
+		return controlResponse{OK: true, Message: msg}
+
+	default:
+		return controlResponse{Error: fmt.Sprintf("unknown command '%s'", req.Command)}
+	}
+}
+
+// addSessionFlag adds --session to commands that talk to a running session
+func addSessionFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP("session", "s", "", "Running session to address, by name or ID (needed when several are running)")
+}
+
+// runningSession is a session registered in capturesDir/running
+type runningSession struct {
+	Name      string
+	SessionID string
+}
+
+// runningSessions lists registered sessions whose control socket answers,
+// removing registrations left behind by crashed runs
+func runningSessions() []runningSession {
+	dir := filepath.Join(capturesDir, runningDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var sessions []runningSession
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		s := runningSession{Name: entry.Name(), SessionID: strings.TrimSpace(string(data))}
+		conn, err := net.DialTimeout("unix", filepath.Join(capturesDir, s.SessionID, controlSocketName), 2*time.Second)
+		if err != nil {
+			os.Remove(filepath.Join(dir, entry.Name()))
+			continue
+		}
+		conn.Close()
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+// findRunning picks the running session named by target (a name or a
+// session ID). An empty target is fine when only one session is running.
+func findRunning(target string) (runningSession, error) {
+	sessions := runningSessions()
+	if len(sessions) == 0 {
+		return runningSession{}, fmt.Errorf("%w: no capture is running (start one with 'task-tracker start')", errSessionNotFound)
+	}
+
+	if target == "" {
+		if len(sessions) == 1 {
+			return sessions[0], nil
+		}
+		names := make([]string, len(sessions))
+		for i, s := range sessions {
+			names[i] = s.Name
+		}
+		return runningSession{}, fmt.Errorf("%w: %d sessions are running (%s); pick one with --session",
+			errUsage, len(sessions), strings.Join(names, ", "))
+	}
+
+	for _, s := range sessions {
+		if s.Name == target || s.SessionID == target {
+			return s, nil
+		}
+	}
+	return runningSession{}, fmt.Errorf("%w: no running session named '%s'", errSessionNotFound, target)
+}
+
+// sendControl delivers a request to a running session; see findRunning
+// for how target is resolved
+func sendControl(target string, req controlRequest) (*controlResponse, error) {
+	session, err := findRunning(target)
+	if err != nil {
+		return nil, err
+	}
+
+	socketPath := filepath.Join(capturesDir, session.SessionID, controlSocketName)
+	conn, err := net.DialTimeout("unix", socketPath, 2*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("%w: capture session %s is not responding (%v)", errSessionNotFound, session.Name, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var resp controlResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if !resp.OK {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Jira deployments. Cloud serves REST API v3 with comments in Atlassian
+// Document Format; Server and Data Center serve v2 with wiki markup.
+const (
+	jiraCloud  = "cloud"
+	jiraServer = "server"
+)
+
+// jiraClient talks to the Jira REST API. JIRA_URL and JIRA_API_TOKEN
+// configure it; with JIRA_EMAIL set the token is sent as Jira Cloud basic
+// auth, otherwise as a Data Center personal access token. Whether the
+// instance is Cloud or Server/Data Center is detected on first use, or set
+// with JIRA_DEPLOYMENT.
+type jiraClient struct {
+	restClient
+	deployment string // jiraCloud or jiraServer; empty until detected
+}
+
+// newJiraClient returns nil if Jira isn't configured
+func newJiraClient() *jiraClient {
+	base, token := os.Getenv("JIRA_URL"), os.Getenv("JIRA_API_TOKEN")
+	if base == "" || token == "" {
+		return nil
+	}
+
+	email := os.Getenv("JIRA_EMAIL")
+	c := &jiraClient{restClient: newRESTClient("Jira", base, func(req *http.Request) {
+		if email != "" {
+			req.SetBasicAuth(email, token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	})}
+	switch strings.ToLower(os.Getenv("JIRA_DEPLOYMENT")) {
+	case "cloud":
+		c.deployment = jiraCloud
+	case "server", "datacenter", "data-center", "dc":
+		c.deployment = jiraServer
+	}
+	return c
+}
+
+// detect works out whether the instance is Cloud or Server/Data Center from
+// serverInfo, which both serve under v2. If that fails, *.atlassian.net
+// hosts are taken as Cloud.
+func (c *jiraClient) detect() string {
+	if c.deployment != "" {
+		return c.deployment
+	}
+
+	var info struct {
+		DeploymentType string `json:"deploymentType"`
+	}
+	if err := c.do("GET", "/rest/api/2/serverInfo", nil, &info); err == nil && info.DeploymentType != "" {
+		if strings.EqualFold(info.DeploymentType, "Cloud") {
+			c.deployment = jiraCloud
+		} else {
+			c.deployment = jiraServer
+		}
+		return c.deployment
+	}
+
+	c.deployment = jiraServer
+	if u, err := url.Parse(c.base); err == nil && strings.HasSuffix(u.Hostname(), ".atlassian.net") {
+		c.deployment = jiraCloud
+	}
+	return c.deployment
+}
+
+// api returns the REST path for the instance's API version
+func (c *jiraClient) api(path string) string {
+	if c.detect() == jiraCloud {
+		return "/rest/api/3" + path
+	}
+	return "/rest/api/2" + path
+}
+
+// body renders comment text the way the instance expects it
+func (c *jiraClient) body(text string) any {
+	if c.detect() == jiraCloud {
+		return toADF(text)
+	}
+	return toWiki(text)
+}
+
+// jiraIssue is an issue as the REST and Agile APIs return it
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+		TimeTracking struct {
+			OriginalEstimateSeconds  int64 `json:"originalEstimateSeconds"`
+			RemainingEstimateSeconds int64 `json:"remainingEstimateSeconds"`
+			TimeSpentSeconds         int64 `json:"timeSpentSeconds"`
+		} `json:"timetracking"`
+	} `json:"fields"`
+}
+
+// jiraIssueFields are the fields jiraIssue needs
+const jiraIssueFields = "summary,status,timetracking"
+
+// issue converts the response to an Issue
+func (c *jiraClient) issue(i jiraIssue) *Issue {
+	tt := i.Fields.TimeTracking
+	return &Issue{
+		Key:   i.Key,
+		Title: i.Fields.Summary,
+		State: i.Fields.Status.Name,
+		URL:   c.IssueURL(i.Key),
+		Estimate: &Estimate{
+			OriginalSeconds:  tt.OriginalEstimateSeconds,
+			RemainingSeconds: tt.RemainingEstimateSeconds,
+			LoggedSeconds:    tt.TimeSpentSeconds,
+			FetchedAt:        time.Now().Format(time.RFC3339),
+		},
+	}
+}
+
+// GetIssue fetches an issue's summary, status and time tracking
+func (c *jiraClient) GetIssue(key string) (*Issue, error) {
+	var issue jiraIssue
+	if err := c.do("GET", c.api("/issue/"+url.PathEscape(key)+"?fields="+jiraIssueFields), nil, &issue); err != nil {
+		return nil, err
+	}
+	issue.Key = key
+	return c.issue(issue), nil
+}
+
+// Sprint is a Jira Software sprint
+type Sprint struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"` // future, active or closed
+	Goal  string `json:"goal"`
+	Start string `json:"startDate,omitempty"` // RFC 3339; unset for future sprints
+	End   string `json:"endDate,omitempty"`
+}
+
+// FindSprint looks up a board's sprint by ID, by name, or "active" for the
+// one running now
+func (c *jiraClient) FindSprint(board, sprint string) (*Sprint, error) {
+	if id, err := strconv.Atoi(sprint); err == nil {
+		var s Sprint
+		if err := c.do("GET", "/rest/agile/1.0/sprint/"+strconv.Itoa(id), nil, &s); err != nil {
+			return nil, err
+		}
+		return &s, nil
+	}
+
+	path := "/rest/agile/1.0/board/" + url.PathEscape(board) + "/sprint"
+	if strings.EqualFold(sprint, "active") {
+		path += "?state=active"
+	}
+	for start := 0; ; {
+		var page struct {
+			IsLast bool     `json:"isLast"`
+			Values []Sprint `json:"values"`
+		}
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		if err := c.do("GET", fmt.Sprintf("%s%sstartAt=%d", path, sep, start), nil, &page); err != nil {
+			return nil, err
+		}
+		for _, s := range page.Values {
+			if strings.EqualFold(sprint, "active") || strings.EqualFold(s.Name, sprint) {
+				return &s, nil
+			}
+		}
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+		start += len(page.Values)
+	}
+	return nil, fmt.Errorf("%w: board %s has no sprint '%s'", errUsage, board, sprint)
+}
+
+// SprintIssues fetches every issue in a sprint
+func (c *jiraClient) SprintIssues(sprintID int) ([]*Issue, error) {
+	var issues []*Issue
+	for start := 0; ; {
+		var page struct {
+			Total  int         `json:"total"`
+			Issues []jiraIssue `json:"issues"`
+		}
+		path := fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue?fields=%s&startAt=%d", sprintID, jiraIssueFields, start)
+		if err := c.do("GET", path, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, i := range page.Issues {
+			issues = append(issues, c.issue(i))
+		}
+		start += len(page.Issues)
+		if len(page.Issues) == 0 || start >= page.Total {
+			return issues, nil
+		}
+	}
+}
+
+// PostComment posts a comment on an issue
+func (c *jiraClient) PostComment(key, text string) error {
+	return c.do("POST", c.api("/issue/"+url.PathEscape(key)+"/comment"), map[string]any{"body": c.body(text)}, nil)
+}
+
+// PostWorklog logs time on an issue
+func (c *jiraClient) PostWorklog(key string, spent time.Duration, started time.Time, comment string) error {
+	worklog := map[string]any{
+		"timeSpentSeconds": int64(spent.Seconds()),
+		// Jira wants milliseconds and a numeric zone without a colon
+		"started": started.Format("2006-01-02T15:04:05.000-0700"),
+	}
+	if comment != "" {
+		worklog["comment"] = c.body(comment)
+	}
+	return c.do("POST", c.api("/issue/"+url.PathEscape(key)+"/worklog"), worklog, nil)
+}
+
+// IssueURL is the issue's browse page
+func (c *jiraClient) IssueURL(key string) string {
+	return c.base + "/browse/" + key
+}
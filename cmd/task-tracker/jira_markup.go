@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Summaries are written in light markdown: paragraphs, "- " bullets, "#"
+// headings, **bold** and `code`. Jira Cloud wants them as Atlassian
+// Document Format and Server/Data Center as wiki markup.
+
+var (
+	boldPattern = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	codePattern = regexp.MustCompile("`([^`]+)`")
+	inlineMark  = regexp.MustCompile("\\*\\*[^*]+\\*\\*|`[^`]+`")
+)
+
+// markdownLine classifies a line as a heading (level > 0), a bullet or text
+func markdownLine(line string) (text string, heading int, bullet bool) {
+	trimmed := strings.TrimSpace(line)
+	if h := len(trimmed) - len(strings.TrimLeft(trimmed, "#")); h > 0 && h <= 6 && strings.HasPrefix(trimmed[h:], " ") {
+		return strings.TrimSpace(trimmed[h:]), h, false
+	}
+	for _, prefix := range []string{"- ", "* ", "• "} {
+		if rest, ok := strings.CutPrefix(trimmed, prefix); ok {
+			return strings.TrimSpace(rest), 0, true
+		}
+	}
+	return trimmed, 0, false
+}
+
+// toWiki converts summary text to Jira wiki markup
+func toWiki(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		content, heading, bullet := markdownLine(line)
+		content = boldPattern.ReplaceAllString(content, "*$1*")
+		content = codePattern.ReplaceAllString(content, "{{$1}}")
+		switch {
+		case heading > 0:
+			lines[i] = fmt.Sprintf("h%d. %s", heading, content)
+		case bullet:
+			lines[i] = "* " + content
+		default:
+			lines[i] = content
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// adfNode is a node in an Atlassian Document Format tree
+type adfNode struct {
+	Type    string         `json:"type"`
+	Version int            `json:"version,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Content []adfNode      `json:"content,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Marks   []adfMark      `json:"marks,omitempty"`
+}
+
+type adfMark struct {
+	Type string `json:"type"`
+}
+
+// toADF converts summary text to an Atlassian Document Format document
+func toADF(text string) adfNode {
+	doc := adfNode{Type: "doc", Version: 1, Content: []adfNode{}}
+	var paragraph []string
+	var list *adfNode
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			doc.Content = append(doc.Content, adfNode{Type: "paragraph", Content: adfInline(strings.Join(paragraph, " "))})
+			paragraph = nil
+		}
+		if list != nil {
+			doc.Content = append(doc.Content, *list)
+			list = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		content, heading, bullet := markdownLine(line)
+		switch {
+		case content == "":
+			flush()
+		case heading > 0:
+			flush()
+			doc.Content = append(doc.Content, adfNode{
+				Type:    "heading",
+				Attrs:   map[string]any{"level": heading},
+				Content: adfInline(content),
+			})
+		case bullet:
+			if len(paragraph) > 0 {
+				flush()
+			}
+			if list == nil {
+				list = &adfNode{Type: "bulletList"}
+			}
+			list.Content = append(list.Content, adfNode{
+				Type:    "listItem",
+				Content: []adfNode{{Type: "paragraph", Content: adfInline(content)}},
+			})
+		default:
+			if list != nil {
+				flush()
+			}
+			paragraph = append(paragraph, content)
+		}
+	}
+	flush()
+	return doc
+}
+
+// adfInline splits text into text nodes, marking **bold** and `code`
+func adfInline(text string) []adfNode {
+	var nodes []adfNode
+	last := 0
+	for _, m := range inlineMark.FindAllStringIndex(text, -1) {
+		if m[0] > last {
+			nodes = append(nodes, adfNode{Type: "text", Text: text[last:m[0]]})
+		}
+		token := text[m[0]:m[1]]
+		if strings.HasPrefix(token, "**") {
+			nodes = append(nodes, adfNode{Type: "text", Text: token[2 : len(token)-2], Marks: []adfMark{{Type: "strong"}}})
+		} else {
+			nodes = append(nodes, adfNode{Type: "text", Text: token[1 : len(token)-1], Marks: []adfMark{{Type: "code"}}})
+		}
+		last = m[1]
+	}
+	if last < len(text) {
+		nodes = append(nodes, adfNode{Type: "text", Text: text[last:]})
+	}
+	return nodes
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// validateJitter checks that jittered ticks can't come closer than half an
+// interval, which the capture loop would skip as a bunched tick
+func validateJitter(jitter, interval time.Duration) error {
+	if jitter < 0 {
+		return fmt.Errorf("%w: --jitter can't be negative", errUsage)
+	}
+	if 2*jitter >= interval {
+		return fmt.Errorf("%w: --jitter must be less than half the interval (%s)", errUsage, interval)
+	}
+	return nil
+}
+
+// nextInterval is the delay until the next tick: the capture interval,
+// moved by a random amount of up to ±Jitter. Captures then don't keep
+// landing on the same phase of clocks or dashboards that refresh on a
+// fixed period.
+func (t *TaskTracker) nextInterval() time.Duration {
+	t.mu.Lock()
+	interval := t.CaptureInterval
+	t.mu.Unlock()
+	if t.Jitter <= 0 {
+		return interval
+	}
+	return interval - t.Jitter + rand.N(2*t.Jitter+1)
+}
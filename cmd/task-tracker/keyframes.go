@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// sourceKeyframe marks screenshots extracted from a video rather than
+// captured on an interval
+const sourceKeyframe = "keyframe"
+
+// keyframesTmpDir holds ffmpeg's output while keyframes are extracted
+const keyframesTmpDir = ".keyframes"
+
+// showinfoPTS finds a frame's time in ffmpeg's showinfo log
+var showinfoPTS = regexp.MustCompile(`Parsed_showinfo.*\bn:\s*\d+.*\bpts_time:\s*([0-9.eE+-]+)`)
+
+// extractKeyframes pulls the first frame and every frame at a scene change
+// (ffmpeg's scene score above threshold, 0-1) out of a video and adds them
+// to the session as screenshots at their time. It returns how many were
+// added.
+func (t *TaskTracker) extractKeyframes(v *Video, threshold float64) (int, error) {
+	if v.Keyframes > 0 {
+		return 0, fmt.Errorf("%w: keyframes of %s were already extracted (%d)", errUsage, filepath.Base(v.Path), v.Keyframes)
+	}
+
+	tmp := filepath.Join(t.SessionDir, keyframesTmpDir)
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(tmp)
+
+	filter := fmt.Sprintf("select='eq(n\\,0)+gt(scene\\,%g)',showinfo", threshold)
+	cmd := exec.Command("ffmpeg", "-hide_banner", "-loglevel", "info", "-i", v.Path,
+		"-vf", filter, "-vsync", "vfr", filepath.Join(tmp, "kf_%05d.png"))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return 0, fmt.Errorf("ffmpeg failed on %s: %v: %s", filepath.Base(v.Path), err, lastLines(stderr.String(), 3))
+	}
+
+	var times []float64
+	for _, m := range showinfoPTS.FindAllStringSubmatch(stderr.String(), -1) {
+		if s, err := strconv.ParseFloat(m[1], 64); err == nil {
+			times = append(times, s)
+		}
+	}
+	files, _ := filepath.Glob(filepath.Join(tmp, "kf_*.png"))
+	sort.Strings(files)
+	if len(files) != len(times) {
+		return 0, fmt.Errorf("ffmpeg extracted %d frames but timed %d", len(files), len(times))
+	}
+
+	for i, file := range files {
+		at := t.StartTime.Add(time.Duration((v.RelativeTime + times[i]) * float64(time.Second)))
+		name := fmt.Sprintf("keyframe_m%d_%s_%03d.png", v.Monitor, at.Format("150405"), at.Nanosecond()/int(time.Millisecond))
+		path := filepath.Join(t.SessionDir, name)
+		if err := os.Rename(file, path); err != nil {
+			return i, fmt.Errorf("failed to save %s: %w", name, err)
+		}
+
+		resolution := ""
+		if f, err := os.Open(path); err == nil {
+			if cfg, _, err := image.DecodeConfig(f); err == nil {
+				resolution = fmt.Sprintf("%dx%d", cfg.Width, cfg.Height)
+			}
+			f.Close()
+		}
+		sum, _ := fileSHA256(path)
+		t.insertScreenshot(Screenshot{
+			Path:         path,
+			SHA256:       sum,
+			Monitor:      v.Monitor,
+			Timestamp:    at.Format(time.RFC3339),
+			RelativeTime: at.Sub(t.StartTime).Seconds(),
+			Resolution:   resolution,
+			Source:       sourceKeyframe,
+		})
+	}
+	v.Keyframes = len(files)
+	return len(files), nil
+}
+
+// importVideo copies a video recorded elsewhere into the session, starting
+// offset into it on monitor
+func (t *TaskTracker) importVideo(src string, monitor int, offset time.Duration) (*Video, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	path := filepath.Join(t.SessionDir, "import_"+filepath.Base(src))
+	if fileExists(path) {
+		return nil, fmt.Errorf("%w: %s was already imported", errUsage, filepath.Base(src))
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+
+	t.Videos = append(t.Videos, Video{
+		Path:         path,
+		Monitor:      monitor,
+		StartTime:    t.StartTime.Add(offset).Format(time.RFC3339),
+		RelativeTime: offset.Seconds(),
+		Imported:     true,
+	})
+	return &t.Videos[len(t.Videos)-1], nil
+}
+
+// lastLines returns the last n lines of s
+func lastLines(s string, n int) string {
+	lines := bytes.Split(bytes.TrimSpace([]byte(s)), []byte("\n"))
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return string(bytes.Join(lines, []byte("\n")))
+}
+
+// newKeyframesCmd builds the keyframes command
+func newKeyframesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keyframes [session_id]",
+		Short: "Add representative frames of a session's videos as screenshots",
+		Long: `Extract frames at scene changes from the videos of a session recorded with
+--video, or from a video imported with --import, and add them to the session
+as ordinary screenshots at their time, so analyze, report and view work on
+them like on captured ones. Each video's first frame is always taken.
+
+--threshold is ffmpeg's scene change score (0-1): lower picks up smaller
+changes, such as scrolling, and yields more frames. Needs ffmpeg in PATH.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if _, err := exec.LookPath("ffmpeg"); err != nil {
+				ui.Println("❌ Extracting keyframes needs ffmpeg in PATH")
+				exit(exitUsage)
+			}
+			threshold, _ := cmd.Flags().GetFloat64("threshold")
+			if threshold <= 0 || threshold >= 1 {
+				ui.Println("❌ --threshold must be between 0 and 1")
+				exit(exitUsage)
+			}
+			tracker := loadSessionOrExit(args[0])
+
+			videos := []*Video{}
+			if src, _ := cmd.Flags().GetString("import"); src != "" {
+				monitor, _ := cmd.Flags().GetInt("monitor")
+				offset, _ := cmd.Flags().GetDuration("offset")
+				v, err := tracker.importVideo(src, monitor, offset)
+				if err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+				videos = append(videos, v)
+			} else {
+				for i := range tracker.Videos {
+					if tracker.Videos[i].Keyframes == 0 {
+						videos = append(videos, &tracker.Videos[i])
+					}
+				}
+			}
+			if len(videos) == 0 {
+				if len(tracker.Videos) == 0 {
+					ui.Printf("❌ %s has no videos (record with 'start --video' or use --import)\n", tracker.SessionID)
+					exit(exitUsage)
+				}
+				ui.Println("✅ Keyframes of every video were already extracted")
+				return
+			}
+
+			total := 0
+			var failed error
+			for _, v := range videos {
+				ui.Printf("🎞️  %s...\n", filepath.Base(v.Path))
+				n, err := tracker.extractKeyframes(v, threshold)
+				total += n
+				if err != nil {
+					ui.Printf("❌ %v\n", err)
+					failed = err
+					continue
+				}
+				ui.Printf("   %d keyframe(s)\n", n)
+			}
+			if err := tracker.saveMetadata(); err != nil {
+				ui.Printf("❌ Failed to save metadata: %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("✅ Added %d keyframe(s) to %s\n", total, tracker.SessionID)
+			if total > 0 && fileExists(filepath.Join(tracker.SessionDir, "review.md")) {
+				ui.Printf("💡 Run 'task-tracker analyze %s' to include them in the review\n", tracker.SessionID)
+			}
+			if failed != nil {
+				exit(exitCode(failed))
+			}
+		},
+	}
+	cmd.Flags().Float64("threshold", 0.3, "Scene change score (0-1) above which a frame is taken")
+	cmd.Flags().String("import", "", "Copy this video into the session and extract from it")
+	cmd.Flags().Int("monitor", 1, "Monitor an imported video shows")
+	cmd.Flags().Duration("offset", 0, "How far into the session an imported video starts")
+	return cmd
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// linearClient talks to the Linear GraphQL API. LINEAR_API_KEY configures
+// it. Linear has no time tracking, so worklogs are posted as comments.
+type linearClient struct {
+	restClient
+}
+
+// newLinearClient returns nil if Linear isn't configured
+func newLinearClient() *linearClient {
+	key := os.Getenv("LINEAR_API_KEY")
+	if key == "" {
+		return nil
+	}
+	return &linearClient{restClient: newRESTClient("Linear", "https://api.linear.app", func(req *http.Request) {
+		req.Header.Set("Authorization", key)
+	})}
+}
+
+// query runs a GraphQL query, decoding its data into out
+func (c *linearClient) query(q string, vars map[string]any, out any) error {
+	var resp struct {
+		Data   any `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	resp.Data = out
+	if err := c.do("POST", "/graphql", map[string]any{"query": q, "variables": vars}, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("%w: Linear: %s", errIntegration, resp.Errors[0].Message)
+	}
+	return nil
+}
+
+// GetIssue fetches an issue by its identifier, e.g. ENG-42
+func (c *linearClient) GetIssue(key string) (*Issue, error) {
+	var data struct {
+		Issue struct {
+			Title string `json:"title"`
+			URL   string `json:"url"`
+			State struct {
+				Name string `json:"name"`
+			} `json:"state"`
+		} `json:"issue"`
+	}
+	q := `query($id: String!) { issue(id: $id) { title url state { name } } }`
+	if err := c.query(q, map[string]any{"id": key}, &data); err != nil {
+		return nil, err
+	}
+	return &Issue{Key: key, Title: data.Issue.Title, State: data.Issue.State.Name, URL: data.Issue.URL}, nil
+}
+
+// PostComment comments on an issue. Linear renders markdown as is.
+func (c *linearClient) PostComment(key, text string) error {
+	var data struct {
+		CommentCreate struct {
+			Success bool `json:"success"`
+		} `json:"commentCreate"`
+	}
+	q := `mutation($id: String!, $body: String!) { commentCreate(input: {issueId: $id, body: $body}) { success } }`
+	if err := c.query(q, map[string]any{"id": key, "body": text}, &data); err != nil {
+		return err
+	}
+	if !data.CommentCreate.Success {
+		return fmt.Errorf("%w: Linear didn't accept the comment on %s", errIntegration, key)
+	}
+	return nil
+}
+
+// PostWorklog records the time as a comment
+func (c *linearClient) PostWorklog(key string, spent time.Duration, started time.Time, comment string) error {
+	return c.PostComment(key, worklogComment(spent, started, comment))
+}
+
+// IssueURL is empty: Linear links include the workspace, which only the
+// API knows, so the URL is filled in by GetIssue
+func (c *linearClient) IssueURL(key string) string {
+	return ""
+}
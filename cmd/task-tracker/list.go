@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// newListCmd builds the list command
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved capture sessions",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			limit, _ := cmd.Flags().GetInt("limit")
+
+			sessions, err := listSessions()
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			if len(sessions) == 0 {
+				ui.Println("\n📋 No sessions captured yet")
+				ui.Println("\nStart one with:")
+				ui.Println("  task-tracker start 'Task name'")
+				return
+			}
+
+			// Newest first
+			shown := sessions
+			if limit > 0 && len(shown) > limit {
+				shown = shown[len(shown)-limit:]
+			}
+
+			if cmd.Flags().Changed("preview") {
+				protocol, _ := cmd.Flags().GetString("preview")
+				if protocol == "auto" {
+					protocol = ui.DetectProtocol()
+				}
+				if !slices.Contains(ui.Protocols, protocol) {
+					ui.Printf("❌ Invalid --preview '%s' (use auto, %s)\n", protocol, strings.Join(ui.Protocols, ", "))
+					exit(exitUsage)
+				}
+
+				ui.Println()
+				for i := len(shown) - 1; i >= 0; i-- {
+					listPreview(shown[i], protocol)
+				}
+			} else {
+				listTable(shown)
+			}
+
+			if len(shown) < len(sessions) {
+				ui.Printf("\n💡 Showing %d of %d sessions. Use --limit 0 to see all\n", len(shown), len(sessions))
+			}
+		},
+	}
+
+	cmd.Flags().IntP("limit", "n", 20, "Maximum number of sessions to show (0 for all)")
+	cmd.Flags().String("preview", "auto", "Show a thumbnail of each session's first frame (auto, kitty, sixel or ascii)")
+	cmd.Flags().Lookup("preview").NoOptDefVal = "auto"
+	return cmd
+}
+
+// listTable prints sessions newest first as a table
+func listTable(shown []SessionMetadata) {
+	table := ui.NewTable("Session", "Task", "Ticket", "Duration", "Shots", "Tags")
+	for i := len(shown) - 1; i >= 0; i-- {
+		s := shown[i]
+		table.AddRow(
+			ui.Bold(s.SessionID),
+			s.TaskName,
+			ui.Cyan(s.ticket().String()),
+			fmt.Sprintf("%.1f min", s.DurationSeconds/60),
+			fmt.Sprintf("%d", s.ScreenshotCount),
+			ui.Dim(strings.Join(s.Tags, ",")),
+		)
+	}
+
+	ui.Println()
+	table.Render()
+}
+
+// Thumbnail size in terminal cells
+const (
+	previewCols = 40
+	previewRows = 10
+)
+
+// listPreview prints a thumbnail of the session's first keyframe above its
+// details. Sessions without a readable frame get a short placeholder line.
+func listPreview(s SessionMetadata, protocol string) {
+	if img, err := firstKeyframe(s); err != nil {
+		ui.Println(ui.Dim("  (no preview: " + err.Error() + ")"))
+	} else if preview, err := ui.Preview(img, previewCols, previewRows, protocol); err == nil {
+		ui.Print(preview)
+	}
+
+	line := ui.Bold(s.SessionID) + "  " + s.TaskName
+	if s.ticket() != nil {
+		line += "  " + ui.Cyan(s.ticket().String())
+	}
+	line += fmt.Sprintf("  %.1f min  %d shots", s.DurationSeconds/60, s.ScreenshotCount)
+	if len(s.Tags) > 0 {
+		line += "  " + ui.Dim(strings.Join(s.Tags, ","))
+	}
+	ui.Println(line + "\n")
+}
+
+// firstKeyframe loads the first full frame saved in a session
+func firstKeyframe(s SessionMetadata) (image.Image, error) {
+	sessionDir := filepath.Join(capturesDir, s.SessionID)
+	for _, shot := range s.Screenshots {
+		path, _ := resolvePath(sessionDir, s.SessionID, shot.Path)
+		if isDelta(path) {
+			continue
+		}
+		return loadFrame(path)
+	}
+	return nil, fmt.Errorf("no screenshots")
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/ui"
+)
+
+// selectLanguage picks the output language. --lang wins, then
+// TASK_TRACKER_LANG, then the config file, then the system locale.
+func selectLanguage(flag string) error {
+	if flag != "" {
+		return i18n.SetLocale(flag)
+	}
+	if env := os.Getenv("TASK_TRACKER_LANG"); env != "" {
+		if err := i18n.SetLocale(env); err != nil {
+			return fmt.Errorf("TASK_TRACKER_LANG: %w", err)
+		}
+		return nil
+	}
+	// A broken config is reported by the command that loads it
+	if cfg, err := loadConfig(); err == nil && cfg.Language != "" {
+		return i18n.SetLocale(cfg.Language)
+	}
+	return i18n.SetLocale(i18n.Detect())
+}
+
+// bindLangFlag adds --lang to the root command
+func bindLangFlag(root *cobra.Command) {
+	root.PersistentFlags().String("lang", "", "Language for reports and prompts: en, de or ja (or set TASK_TRACKER_LANG)")
+
+	prev := root.PersistentPreRun
+	root.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		if prev != nil {
+			prev(cmd, args)
+		}
+		lang, _ := cmd.Flags().GetString("lang")
+		if err := selectLanguage(lang); err != nil {
+			ui.Printf("❌ %v\n", err)
+			exit(exitUsage)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"task-tracker/internal/disk"
+	"task-tracker/internal/ui"
+)
+
+const (
+	defaultLowSpace  = ByteSize(1 << 30)
+	defaultDiskCheck = time.Minute
+)
+
+// DiskConfig controls free space monitoring during capture
+type DiskConfig struct {
+	// LowSpace is the free space below which capture degrades: the
+	// interval doubles below LowSpace, frames switch to JPEG below half of
+	// it, and capture pauses below a quarter of it
+	LowSpace   ByteSize `json:"low_space"`
+	CheckEvery Duration `json:"check_every"`
+}
+
+// stageFor maps free space to a degradation stage
+func (c DiskConfig) stageFor(free uint64) throttleStage {
+	low := uint64(c.LowSpace)
+	switch {
+	case low == 0 || free >= low:
+		return stageOK
+	case free >= low/2:
+		return stageSlow
+	case free >= low/4:
+		return stageJPEG
+	}
+	return stagePaused
+}
+
+// diskMonitor tracks free space for the capture loop
+type diskMonitor struct {
+	cfg       DiskConfig
+	lastCheck time.Time
+	stage     throttleStage
+}
+
+// check re-reads free space if CheckEvery has passed and reports whether
+// the stage changed
+func (m *diskMonitor) check(t *TaskTracker, now time.Time) bool {
+	if now.Sub(m.lastCheck) < m.cfg.CheckEvery.Duration {
+		return false
+	}
+	m.lastCheck = now
+
+	free, err := disk.Free(t.SessionDir)
+	if err != nil {
+		return false
+	}
+
+	stage := m.cfg.stageFor(free)
+	if stage == m.stage {
+		return false
+	}
+
+	msg := fmt.Sprintf("Low disk space (%s free): %s", formatBytes(int64(free)), stage)
+	if stage == stageOK {
+		msg = fmt.Sprintf("Disk space recovered (%s free): %s", formatBytes(int64(free)), stage)
+	}
+	ui.Printf("⚠️  %s\n", msg)
+	t.addEvent(msg)
+	t.notify(eventDisk, "Disk space", msg)
+
+	m.stage = stage
+	return true
+}
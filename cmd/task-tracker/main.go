@@ -1,255 +1,680 @@
 // Task Tracker - Cross-platform screen capture and AI summarization
-// Build: go build -o task-tracker main.go
-// Linux: go build -o task-tracker-linux main.go
-// Windows: GOOS=windows GOARCH=amd64 go build -o task-tracker.exe main.go
+// Build: go build -o task-tracker ./cmd/task-tracker
+// Linux: go build -o task-tracker-linux ./cmd/task-tracker
+// Windows: GOOS=windows GOARCH=amd64 go build -o task-tracker.exe ./cmd/task-tracker
 
 package main
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"image/png"
+	"image"
+	"net"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/kbinani/screenshot"
 	"github.com/spf13/cobra"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/imaging"
+	"task-tracker/internal/telemetry"
+	"task-tracker/internal/ui"
 )
 
 // Screenshot metadata
 type Screenshot struct {
-	Path         string  `json:"path"`
-	Monitor      int     `json:"monitor"`
-	Timestamp    string  `json:"timestamp"`
-	RelativeTime float64 `json:"relative_time"`
-	Resolution   string  `json:"resolution"`
+	Path         string     `json:"path"`
+	Monitor      int        `json:"monitor"`
+	Timestamp    string     `json:"timestamp"`
+	RelativeTime float64    `json:"relative_time"`
+	Resolution   string     `json:"resolution"`
+	Marked       bool       `json:"marked,omitempty"`
+	Caption      string     `json:"caption,omitempty"`
+	Optimized    bool       `json:"optimized,omitempty"`
+	Cursor       *CursorPos `json:"cursor,omitempty"`
+	Blob         string     `json:"blob,omitempty"`    // sha256 of a shared frame's pixels in blobs/
+	SHA256       string     `json:"sha256,omitempty"`  // of the file as saved
+	Redact       []Region   `json:"redact,omitempty"`  // regions blurred wherever the frame is shared
+	Source       string     `json:"source,omitempty"`  // how it was added when not captured on an interval
+	Format       string     `json:"format,omitempty"`  // png, jpeg or webp; empty for delta frames
+	Quality      int        `json:"quality,omitempty"` // JPEG or WebP quality the frame was encoded at
 }
 
 // Session metadata
 type SessionMetadata struct {
-	SessionID       string       `json:"session_id"`
-	TaskName        string       `json:"task_name"`
-	StartTime       string       `json:"start_time"`
-	EndTime         string       `json:"end_time"`
-	DurationSeconds float64      `json:"duration_seconds"`
-	ScreenshotCount int          `json:"screenshot_count"`
-	Screenshots     []Screenshot `json:"screenshots"`
-	JiraTicket      string       `json:"jira_ticket,omitempty"`
-	TimeSpent       string       `json:"time_spent,omitempty"`
-	JiraComment     string       `json:"jira_comment,omitempty"`
+	SessionID       string              `json:"session_id"`
+	Name            string              `json:"name,omitempty"`
+	Parent          string              `json:"parent,omitempty"`
+	Summary         *Summary            `json:"summary,omitempty"`
+	Estimate        *Estimate           `json:"estimate,omitempty"`
+	TaskName        string              `json:"task_name"`
+	StartTime       string              `json:"start_time"`
+	EndTime         string              `json:"end_time"`
+	DurationSeconds float64             `json:"duration_seconds"` // active time, excluding gaps
+	WallSeconds     float64             `json:"wall_duration_seconds,omitempty"`
+	ScreenshotCount int                 `json:"screenshot_count"`
+	Screenshots     []Screenshot        `json:"screenshots"`
+	Ticket          *TicketRef          `json:"ticket,omitempty"`
+	JiraTicket      string              `json:"jira_ticket,omitempty"` // sessions saved before Ticket
+	TimeSpent       string              `json:"time_spent,omitempty"`
+	TicketComment   string              `json:"jira_comment,omitempty"`
+	LoggedAt        string              `json:"logged_at,omitempty"` // when its time was posted to the ticket
+	Tags            []string            `json:"tags,omitempty"`
+	Backend         string              `json:"backend,omitempty"`
+	Display         string              `json:"display,omitempty"`
+	Notes           []Note              `json:"notes,omitempty"`
+	Markers         []Marker            `json:"markers,omitempty"`
+	IntervalSeconds float64             `json:"interval_seconds,omitempty"`
+	JitterSeconds   float64             `json:"jitter_seconds,omitempty"`
+	MonitorSeconds  map[string]float64  `json:"monitor_intervals,omitempty"` // monitor number -> interval
+	Gaps            []Gap               `json:"gaps,omitempty"`
+	GapSeconds      float64             `json:"gap_seconds,omitempty"`
+	Pauses          []Pause             `json:"pauses,omitempty"` // excluded from DurationSeconds
+	PauseSeconds    float64             `json:"pause_seconds,omitempty"`
+	BytesSaved      int64               `json:"optimized_bytes_saved,omitempty"`
+	Dropped         []DroppedFrame      `json:"dropped,omitempty"`
+	Away            []AwayFrame         `json:"away,omitempty"` // placeholders for paused ticks
+	Quarantined     []QuarantinedFrame  `json:"quarantined,omitempty"`
+	Activity        []ActivityEvent     `json:"activity,omitempty"`
+	Productivity    []ProductivityEntry `json:"productivity,omitempty"`
+	Desktop         *capture.Desktop    `json:"desktop,omitempty"`
+	Displays        []capture.Display   `json:"displays,omitempty"` // by monitor number, from 1
+	Videos          []Video             `json:"videos,omitempty"`
+	Metrics         *WorkMetrics        `json:"metrics,omitempty"`
+	Repo            *RepoActivity       `json:"repo,omitempty"`
+	Duplicates      int                 `json:"skipped_duplicates,omitempty"` // frames not saved as identical to the last
 }
 
 // TaskTracker main structure
 type TaskTracker struct {
 	OutputDir         string
 	SessionID         string
+	Name              string // addresses the running session; defaults to SessionID
+	Parent            string // session this one continues
+	Summary           *Summary
+	Estimate          *Estimate // ticket time tracking, cached by commit and report
 	SessionDir        string
 	TaskName          string
 	Screenshots       []Screenshot
-	IsCapturing       bool
 	CaptureInterval   time.Duration
+	Jitter            time.Duration         // each interval is moved randomly by up to ±Jitter
+	MonitorIntervals  map[int]time.Duration // by 0-based monitor, for monitors captured less often
+	lastCaptured      map[int]time.Time     // by 0-based monitor, with MonitorIntervals
 	MonitorsConfig    string
 	MonitorsToCapture []int
+	Capturer          capture.Capturer
+	Backend           string
+	Display           string
+	Desktop           *capture.Desktop  // login session captured by the screen backend
+	Displays          []capture.Display // monitors by index, with size and scaling, where the backend knows them
 	StartTime         time.Time
 	EndTime           time.Time
-	JiraTicket        string
+	Ticket            *TicketRef // nil without a ticket
 	TimeSpent         string
-	JiraComment       string
+	TicketComment     string
+	LoggedAt          string // when the session's time was posted to its ticket; "" if never
+	Tags              []string
+	Notes             []Note
+	Markers           []Marker
+	Gaps              []Gap
+	Pauses            []Pause
+	Dropped           []DroppedFrame
+	Away              []AwayFrame
+	Quarantined       []QuarantinedFrame
+	Activity          []ActivityEvent
+	Productivity      []ProductivityEntry
+	Rounding          RoundingConfig
+	Watermark         *imaging.Watermark // nil when watermarking is off
+	Optimize          bool               // optimize saved frames in the background
+	OptimizePNGQuant  bool
+	BytesSaved        int64
+	Delta             bool        // store near-identical frames as changed tiles
+	Composite         bool        // save one frame of all monitors per tick
+	DrawCursor        bool        // overlay the mouse pointer on frames
+	Mask              *windowMask // nil when no window is masked
+	Placeholders      bool        // record an away placeholder for each paused tick
+	Dedupe            bool        // store frames once in the shared blob directory
+	KeepDuplicates    bool        // save frames identical to the last one of their monitor
+	Duplicates        int         // frames skipped as identical to the last
+	Format            string      // frame format: png, jpeg or webp
+	Quality           int         // JPEG and WebP quality, 1-100
+	Scale             float64     // resize frames by this factor before encoding, 0-1
+	MaxWidth          int         // resize frames wider than this; 0 for no limit
+	Disk              DiskConfig
+	Battery           BatteryConfig
+	Load              LoadConfig
+	WakaTime          *wakaTime // nil unless heartbeats are enabled
+	Notifier          *notifier // nil unless notification routes are configured
+	Blackout          []BlackoutWindow
+	Rules             *rules // nil without a rules script
+	Capture           CaptureConfig
+	Classifier        *classifier // nil without a privacy classifier
+	QA                bool        // marks are numbered evidence steps, captured right away
+	VideoFPS          float64     // also record each monitor as video at this rate; 0 is off
+	Videos            []Video
+	Clipboard         bool          // save images copied to the clipboard as manual captures
+	Metrics           *WorkMetrics  // nil until frames were compared or a run was seen
+	Repo              *RepoActivity // git repository worked in, from --repo
+	RepoRules         []ComponentRule
+
+	state         atomic.Int32 // captureState
+	mu            sync.Mutex   // guards Screenshots, Notes, Markers, Gaps, Pauses, Dropped, Away, Quarantined, privateUntil and pausedAt
+	listener      net.Listener
+	sessionLog    sessionLog // recent captures, skips and errors, for 'logs'
+	lastTick      time.Time
+	privateUntil  time.Time // screenshots paused until then; zero when off
+	pausedAt      time.Time // when 'pause' paused the session; zero when running
+	blackedOut    bool      // inside a blackout window; capture loop only
+	disconnected  bool      // remote desktop disconnected; capture loop only
+	activeTime    time.Duration
+	optimizing    sync.Mutex // held while a background optimize runs
+	optimizeWG    sync.WaitGroup
+	keyframes     map[int]*keyframe      // last full frame per monitor, for Delta
+	health        map[int]*monitorHealth // capture failures per monitor; capture loop only
+	writeQueue    chan capturedTick
+	throttleJPEG  atomic.Bool // save JPEG frames while disk space or battery is low
+	writerDone    chan struct{}
+	governor      *governor             // nil unless the load policy is on and load can be read
+	stepNow       chan struct{}         // QA steps waiting for a capture
+	video         map[int]*videoStream  // by 0-based monitor; capture loop only
+	videoMonitors []int                 // monitors the last tick allowed; capture loop only
+	forceCapture  bool                  // capture every monitor, due or not; capture loop only
+	clipboardSum  [sha256.Size]byte     // last image seen on the clipboard; capture loop only
+	lastLuma      map[int]*imaging.Luma // last frame per monitor, for Metrics; writer only
+	lastKept      map[int]*imaging.Luma // last frame written per monitor, for duplicates; guarded by mu
+	lastRunTitle  string                // focused window title of the last build or test run; capture loop only
+	cancel        context.CancelFunc    // stops StartCapture; set by the start command
 }
 
-// NewTaskTracker creates a new tracker instance
-func NewTaskTracker(outputDir, monitors string) (*TaskTracker, error) {
-	sessionID := time.Now().Format("20060102_150405")
-	sessionDir := filepath.Join(outputDir, sessionID)
+// NewTaskTracker creates a new tracker instance capturing through capturer
+func NewTaskTracker(outputDir, monitors string, capturer capture.Capturer) (*TaskTracker, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", outputDir, err)
+	}
 
-	if err := os.MkdirAll(sessionDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create session directory: %w", err)
+	// Sessions started in the same second get a suffix
+	base := time.Now().Format("20060102_150405")
+	sessionID := base
+	for n := 2; ; n++ {
+		err := os.Mkdir(filepath.Join(outputDir, sessionID), 0755)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create session directory: %w", err)
+		}
+		sessionID = fmt.Sprintf("%s_%d", base, n)
 	}
+	sessionDir := filepath.Join(outputDir, sessionID)
 
 	tracker := &TaskTracker{
 		OutputDir:       outputDir,
 		SessionID:       sessionID,
 		SessionDir:      sessionDir,
 		Screenshots:     []Screenshot{},
-		IsCapturing:     false,
 		CaptureInterval: 30 * time.Second,
 		MonitorsConfig:  monitors,
+		Capturer:        capturer,
+	}
+
+	if err := tracker.setupMonitors(); err != nil {
+		os.Remove(sessionDir)
+		return nil, err
 	}
 
-	tracker.setupMonitors()
 	return tracker, nil
 }
 
 // Setup monitors
-func (t *TaskTracker) setupMonitors() {
-	numMonitors := screenshot.NumActiveDisplays()
-	fmt.Printf("\n🖥️  Detected %d monitor(s):\n", numMonitors)
+func (t *TaskTracker) setupMonitors() error {
+	numMonitors := t.Capturer.NumDisplays()
+	ui.Printf("\n🖥️  Detected %d monitor(s):\n", numMonitors)
+	if numMonitors == 0 {
+		return fmt.Errorf("%w: no active displays found (for Xvfb or other virtual displays use --backend virtual)", errCaptureUnavailable)
+	}
 
+	var displays []capture.Display
+	if id, ok := t.Capturer.(capture.Identifier); ok {
+		displays, _ = id.Displays()
+	}
+	t.Displays = displays
 	for i := 0; i < numMonitors; i++ {
-		bounds := screenshot.GetDisplayBounds(i)
-		fmt.Printf("  Monitor %d: %dx%d at (%d, %d)\n",
-			i+1, bounds.Dx(), bounds.Dy(), bounds.Min.X, bounds.Min.Y)
+		bounds := t.Capturer.Bounds(i)
+		name := ""
+		if i < len(displays) {
+			d := displays[i]
+			if s := d.String(); s != "" {
+				name = " — " + s
+			}
+			if d.Diagonal > 0 {
+				name += fmt.Sprintf(", %.1f\" %.0f PPI", d.Diagonal, d.PPI)
+			}
+			if d.Scale > 1 {
+				name += fmt.Sprintf(", scaled %.3gx", d.Scale)
+			}
+		}
+		ui.Printf("  Monitor %d: %dx%d at (%d, %d)%s\n",
+			i+1, bounds.Dx(), bounds.Dy(), bounds.Min.X, bounds.Min.Y, name)
 	}
 
 	// Parse monitor configuration
-	t.MonitorsToCapture = []int{}
+	items, err := capture.ParseMonitors(t.MonitorsConfig)
+	if err != nil {
+		return fmt.Errorf("%w: invalid monitors '%s': %v", errUsage, t.MonitorsConfig, err)
+	}
 
-	switch t.MonitorsConfig {
+	switch items[0] {
 	case "all":
+		t.MonitorsToCapture = []int{}
 		for i := 0; i < numMonitors; i++ {
 			t.MonitorsToCapture = append(t.MonitorsToCapture, i)
 		}
-		fmt.Printf("📸 Will capture: ALL monitors\n")
+		ui.Printf("📸 Will capture: ALL monitors\n")
 
 	case "primary":
 		t.MonitorsToCapture = []int{0}
-		fmt.Printf("📸 Will capture: Primary monitor only\n")
+		ui.Printf("📸 Will capture: Primary monitor only\n")
 
 	default:
-		// Parse comma-separated list
-		parts := strings.Split(t.MonitorsConfig, ",")
-		for _, p := range parts {
-			num, err := strconv.Atoi(strings.TrimSpace(p))
-			if err == nil && num >= 1 && num <= numMonitors {
-				t.MonitorsToCapture = append(t.MonitorsToCapture, num-1) // 0-indexed
-			}
+		// Monitor numbers or names
+		t.MonitorsToCapture, err = resolveMonitors(items, numMonitors, displays)
+		if err != nil {
+			return err
+		}
+		monitors := []string{}
+		for _, m := range t.MonitorsToCapture {
+			monitors = append(monitors, fmt.Sprintf("%d", m+1))
 		}
+		ui.Printf("📸 Will capture: Monitor(s) %s\n", strings.Join(monitors, ", "))
+	}
 
-		if len(t.MonitorsToCapture) == 0 {
-			fmt.Printf("⚠️  Invalid monitor config '%s', defaulting to primary\n", t.MonitorsConfig)
-			t.MonitorsToCapture = []int{0}
-		} else {
-			monitors := []string{}
-			for _, m := range t.MonitorsToCapture {
-				monitors = append(monitors, fmt.Sprintf("%d", m+1))
+	return nil
+}
+
+// resolveMonitors turns monitor numbers and names like "1", "DP-2" and
+// "DELL U2720Q" into monitor indexes. Names are matched against the
+// displays connected now, so a preset keeps pointing at the same screens
+// after docking or undocking reorders them; a model name shared by
+// identical monitors selects all of them. A number or name that isn't
+// connected is an error.
+func resolveMonitors(items []string, numMonitors int, displays []capture.Display) ([]int, error) {
+	var monitors []int
+	seen := map[int]bool{}
+	add := func(m int) {
+		if !seen[m] {
+			seen[m] = true
+			monitors = append(monitors, m)
+		}
+	}
+
+	for _, p := range items {
+		if num, err := strconv.Atoi(p); err == nil {
+			if num > numMonitors {
+				return nil, fmt.Errorf("%w: there's no monitor %d (%d connected)", errUsage, num, numMonitors)
 			}
-			fmt.Printf("📸 Will capture: Monitor(s) %s\n", strings.Join(monitors, ", "))
+			add(num - 1) // 0-indexed
+			continue
+		}
+		found := false
+		for i, d := range displays {
+			if d.Matches(p) {
+				add(i)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: no connected monitor named '%s' ('monitor-helper detect' lists them)", errUsage, p)
 		}
 	}
+	return monitors, nil
 }
 
-// Start capturing
-func (t *TaskTracker) StartCapture(taskName string) error {
+// StartCapture captures on every interval until ctx is cancelled
+func (t *TaskTracker) StartCapture(ctx context.Context, taskName string) error {
+	if err := t.transition(stateIdle, stateCapturing); err != nil {
+		return err
+	}
+
 	t.TaskName = taskName
 	if t.TaskName == "" {
 		t.TaskName = fmt.Sprintf("Task_%s", t.SessionID)
 	}
 
-	t.IsCapturing = true
 	t.StartTime = time.Now()
 
-	fmt.Printf("🎬 Started capturing for: %s\n", t.TaskName)
-	fmt.Printf("📁 Saving to: %s\n", t.SessionDir)
-	fmt.Println("Press Ctrl+C when done")
+	if err := t.startControlServer(); err != nil {
+		ui.Printf("⚠️  %v (notes won't be available)\n", err)
+	}
+
+	ui.Printf("🎬 %s\n", i18n.T("start.started", t.TaskName))
+	ui.Printf("📁 %s\n", i18n.T("start.saving", t.SessionDir))
+	ui.Println(i18n.T("start.ctrl_c"))
+	if t.QA {
+		ui.Println("🧪 QA mode: type what you did and press Enter to capture it as the next step (or run 'task-tracker mark \"...\"')")
+		go t.readSteps()
+	}
 
 	// Capture loop
-	ticker := time.NewTicker(t.CaptureInterval)
+	ticker := time.NewTicker(t.nextInterval())
 	defer ticker.Stop()
 
-	// Initial capture
-	t.captureScreenshot()
+	t.startWriter()
+	t.startGovernor()
 
-	for range ticker.C {
-		if !t.IsCapturing {
-			break
-		}
+	// Low disk space or battery may stretch the interval; metadata keeps
+	// the one asked for
+	base := t.CaptureInterval
+	defer t.applyStage(stageOK, base)
+
+	monitor := &throttle{disk: &diskMonitor{cfg: t.Disk}, battery: &batteryMonitor{cfg: t.Battery}}
+	if monitor.check(t, time.Now()) {
+		ticker.Reset(t.applyStage(monitor.stage, base))
+	}
+
+	// Initial capture
+	if monitor.stage != stagePaused {
 		t.captureScreenshot()
 	}
 
-	return nil
+	var videoTick <-chan time.Time
+	if t.VideoFPS > 0 {
+		videoTicker := time.NewTicker(time.Duration(float64(time.Second) / t.VideoFPS))
+		defer videoTicker.Stop()
+		videoTick = videoTicker.C
+	}
+
+	var clipboardTick <-chan time.Time
+	if t.Clipboard {
+		t.primeClipboard()
+		clipboardTicker := time.NewTicker(clipboardPoll)
+		defer clipboardTicker.Stop()
+		clipboardTick = clipboardTicker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-videoTick:
+			if monitor.stage != stagePaused {
+				t.recordVideoFrame(now)
+			}
+		case now := <-clipboardTick:
+			if monitor.stage != stagePaused {
+				t.checkClipboard(now)
+			}
+		case <-t.stepNow:
+			if monitor.stage == stagePaused {
+				ui.Println("⚠️  Capture is paused for low disk space or battery; the step gets the next frame captured")
+				continue
+			}
+			t.captureStep()
+		case now := <-ticker.C:
+			if monitor.check(t, now) {
+				ticker.Reset(t.applyStage(monitor.stage, base))
+			}
+			if t.Jitter > 0 {
+				ticker.Reset(t.nextInterval())
+			}
+			if monitor.stage == stagePaused {
+				// As in privacy mode the work goes on, so the time counts
+				// unless it's blacked out or paused by the user; without a
+				// tick the pause would end as a gap
+				if t.inBlackout(now) || !t.pausedSince().IsZero() {
+					t.skipTick(now)
+				} else {
+					t.recordTick(now)
+				}
+				t.recordAway(now, monitor.pauseReason())
+				continue
+			}
+
+			// The ticker keeps one tick while a slow capture runs; taking
+			// it right away would bunch two captures together
+			if now.Sub(t.lastTickTime()) < t.CaptureInterval/2 {
+				continue
+			}
+			t.captureScreenshot()
+		}
+	}
 }
 
-// Stop capturing
+// StopCapture finalizes the session and saves metadata. Call it after
+// StartCapture has returned.
 func (t *TaskTracker) StopCapture() error {
-	t.IsCapturing = false
+	if err := t.transition(stateCapturing, stateStopped); err != nil {
+		return err
+	}
+
 	t.EndTime = time.Now()
-	duration := t.EndTime.Sub(t.StartTime).Seconds()
+	if _, paused := t.endPause(t.EndTime); !paused {
+		t.recordTick(t.EndTime)
+	}
+	t.stopControlServer()
+	t.stopGovernor()
+	t.stopWriter()
+	t.sessionLog.close()
+	t.stopVideo()
+	if t.Classifier != nil {
+		t.Classifier.close()
+	}
+	t.optimizeWG.Wait()
+	if t.Repo != nil {
+		if err := t.Repo.detect(t.RepoRules); err != nil {
+			ui.Printf("⚠️  Couldn't find the components touched: %v\n", err)
+		}
+	}
+	duration := t.activeDuration().Seconds()
+
+	ui.Printf("\n✅ %s\n", i18n.T("stop.stopped"))
+	ui.Printf("⏱️  %s\n", i18n.T("stop.duration", duration/60))
+	ui.Printf("📊 %s\n", i18n.T("stop.total", len(t.Screenshots)))
+	if n := t.droppedFrames(); n > 0 {
+		ui.Printf("⚠️  %s\n", i18n.T("stop.dropped", n))
+	}
+	if t.Duplicates > 0 {
+		ui.Printf("💤 %s\n", i18n.T("stop.unchanged", t.Duplicates))
+	}
+	if components := t.components(); len(components) > 0 {
+		ui.Printf("🧩 %s: %s\n", i18n.T("report.components"), formatComponents(components))
+	}
 
-	fmt.Printf("\n✅ Capture stopped\n")
-	fmt.Printf("⏱️  Duration: %.1f minutes\n", duration/60)
-	fmt.Printf("📊 Total screenshots: %d\n", len(t.Screenshots))
+	t.notify(eventStopped, "Session stopped: "+t.TaskName,
+		fmt.Sprintf("%s active, %d screenshot(s)", formatTimeSpent(t.activeDuration()), len(t.Screenshots)))
+	err := t.saveMetadata()
+	t.Notifier.wait()
+	return err
+}
 
-	return t.saveMetadata()
+// frameStamp is the time part of a frame's file name
+func (t *TaskTracker) frameStamp(now time.Time) string {
+	if t.forceCapture {
+		// QA steps can come within a second of a tick or of each other
+		return now.Format("150405") + fmt.Sprintf("_%03d", now.Nanosecond()/int(time.Millisecond))
+	}
+	return now.Format("150405")
 }
 
-// Capture screenshot from all configured monitors
-func (t *TaskTracker) captureScreenshot() error {
-	timestamp := time.Now().Format("150405")
+// Capture screenshot from all configured monitors and queue the frames
+// for the writer
+func (t *TaskTracker) captureScreenshot() {
+	now := time.Now()
+	timestamp := t.frameStamp(now)
+	t.videoMonitors = nil
+	if t.inBlackout(now) {
+		t.skipTick(now)
+		t.recordAway(now, awayBlackout)
+		return
+	}
+	if t.desktopAway() {
+		t.skipTick(now)
+		t.recordAway(now, awayDisconnected)
+		return
+	}
+	if !t.pausedSince().IsZero() {
+		t.skipTick(now)
+		return
+	}
+	t.recordTick(now)
+	if t.WakaTime != nil {
+		t.WakaTime.tick(t, now)
+	}
+	if t.private(now) {
+		t.recordAway(now, awayPrivacy)
+		return
+	}
+
+	monitors := t.MonitorsToCapture
+	if t.Rules != nil {
+		d := t.Rules.decide(t, now)
+		if !d.capture {
+			t.recordAway(now, awayRule)
+			return
+		}
+		if d.monitors != nil {
+			monitors = d.monitors
+		}
+		t.addTags(d.tags)
+	}
+	t.observeWindow(t.activeWindow())
+	monitors = t.liveMonitors(monitors, now)
+	t.videoMonitors = monitors
+	monitors = t.dueMonitors(monitors, now)
+	if len(monitors) == 0 {
+		return
+	}
+
+	span := telemetry.Start("capture", telemetry.A("session.id", t.SessionID), telemetry.A("monitors", len(monitors)))
+	defer func() {
+		telemetry.Add("task_tracker.captures", "1", 1)
+		telemetry.Observe("task_tracker.capture.duration", span.End(nil))
+	}()
+
+	if t.Composite {
+		t.captureComposite(now, monitors)
+		return
+	}
 
-	for _, monitorIdx := range t.MonitorsToCapture {
-		img, err := screenshot.CaptureDisplay(monitorIdx)
+	regions := t.maskedRegions()
+	tick := capturedTick{at: now}
+	var pos image.Point
+	var known bool
+	var errs []error
+	for _, monitorIdx := range monitors {
+		img, err := t.Capturer.Capture(monitorIdx)
 		if err != nil {
-			fmt.Printf("❌ Failed to capture monitor %d: %v\n", monitorIdx+1, err)
+			errs = append(errs, &capture.DisplayError{Display: monitorIdx, Err: err})
 			continue
 		}
-
-		bounds := img.Bounds()
-		resolution := fmt.Sprintf("%dx%d", bounds.Dx(), bounds.Dy())
+		maskFrame(img, regions, t.Capturer.Bounds(monitorIdx))
+		luma, skip := t.unchanged(monitorIdx, img)
+		if skip {
+			continue
+		}
+		if !known {
+			pos, known = t.cursor()
+		}
 
 		// Generate filename
 		var filename string
-		if len(t.MonitorsToCapture) > 1 {
+		if len(monitors) > 1 {
 			filename = fmt.Sprintf("screen_m%d_%s.png", monitorIdx+1, timestamp)
 		} else {
 			filename = fmt.Sprintf("screen_%s.png", timestamp)
 		}
 
-		filepath := filepath.Join(t.SessionDir, filename)
-
-		// Save PNG
-		file, err := os.Create(filepath)
-		if err != nil {
-			return fmt.Errorf("failed to create file: %w", err)
-		}
-
-		if err := png.Encode(file, img); err != nil {
-			file.Close()
-			return fmt.Errorf("failed to encode PNG: %w", err)
-		}
-		file.Close()
-
-		// Add to screenshots list
-		t.Screenshots = append(t.Screenshots, Screenshot{
-			Path:         filepath,
-			Monitor:      monitorIdx + 1,
-			Timestamp:    time.Now().Format(time.RFC3339),
-			RelativeTime: time.Since(t.StartTime).Seconds(),
-			Resolution:   resolution,
+		tick.frames = append(tick.frames, capturedFrame{
+			img:        img,
+			monitorIdx: monitorIdx,
+			filename:   filename,
+			cursor:     frameCursor(pos, known, t.Capturer.Bounds(monitorIdx), img),
+			luma:       luma,
 		})
 	}
 
-	totalCount := len(t.Screenshots)
-	monitorsStr := ""
-	if len(t.MonitorsToCapture) > 1 {
-		monitors := []string{}
-		for _, m := range t.MonitorsToCapture {
-			monitors = append(monitors, fmt.Sprintf("%d", m+1))
-		}
-		monitorsStr = fmt.Sprintf(" (monitors: %s)", strings.Join(monitors, ", "))
-	}
+	t.recordCaptures(monitors, errors.Join(errs...), now)
 
-	fmt.Printf("📸 Captured: %s%s (%d total screenshots)\n", timestamp, monitorsStr, totalCount)
-	return nil
+	if len(tick.frames) > 0 {
+		t.queueTick(tick)
+	} else if len(errs) == 0 {
+		ui.Printf("💤 Unchanged: %s (%d frames skipped so far)\n", now.Format("150405"), t.Duplicates)
+	}
 }
 
 // Save session metadata
 func (t *TaskTracker) saveMetadata() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	shots := make([]Screenshot, len(t.Screenshots))
+	for i, shot := range t.Screenshots {
+		shot.Path = storedPath(t.SessionDir, shot.Path)
+		shots[i] = shot
+	}
+	videos := make([]Video, len(t.Videos))
+	for i, v := range t.Videos {
+		v.Path = storedPath(t.SessionDir, v.Path)
+		videos[i] = v
+	}
+	quarantined := make([]QuarantinedFrame, len(t.Quarantined))
+	for i, q := range t.Quarantined {
+		q.Path = storedPath(t.SessionDir, q.Path)
+		quarantined[i] = q
+	}
+
 	metadata := SessionMetadata{
 		SessionID:       t.SessionID,
+		Name:            t.Name,
+		Parent:          t.Parent,
+		Summary:         t.Summary,
+		Estimate:        t.Estimate,
 		TaskName:        t.TaskName,
 		StartTime:       t.StartTime.Format(time.RFC3339),
 		EndTime:         t.EndTime.Format(time.RFC3339),
-		DurationSeconds: t.EndTime.Sub(t.StartTime).Seconds(),
+		DurationSeconds: t.activeDuration().Seconds(),
+		WallSeconds:     t.wallDuration().Seconds(),
 		ScreenshotCount: len(t.Screenshots),
-		Screenshots:     t.Screenshots,
-		JiraTicket:      t.JiraTicket,
+		Screenshots:     shots,
+		Ticket:          t.Ticket,
 		TimeSpent:       t.TimeSpent,
-		JiraComment:     t.JiraComment,
+		TicketComment:   t.TicketComment,
+		LoggedAt:        t.LoggedAt,
+		Tags:            t.Tags,
+		Backend:         t.Backend,
+		Display:         t.Display,
+		Notes:           t.Notes,
+		Markers:         t.Markers,
+		IntervalSeconds: t.CaptureInterval.Seconds(),
+		JitterSeconds:   t.Jitter.Seconds(),
+		MonitorSeconds:  monitorSeconds(t.MonitorIntervals),
+		Gaps:            t.Gaps,
+		GapSeconds:      t.gapSeconds(),
+		Pauses:          t.Pauses,
+		PauseSeconds:    t.pauseSeconds(),
+		BytesSaved:      t.BytesSaved,
+		Dropped:         t.Dropped,
+		Away:            t.Away,
+		Quarantined:     quarantined,
+		Activity:        t.Activity,
+		Productivity:    t.Productivity,
+		Desktop:         t.Desktop,
+		Displays:        t.Displays,
+		Videos:          videos,
+		Metrics:         t.workMetrics(),
+		Repo:            t.Repo,
+		Duplicates:      t.Duplicates,
 	}
 
 	data, err := json.MarshalIndent(metadata, "", "  ")
@@ -258,64 +683,226 @@ func (t *TaskTracker) saveMetadata() error {
 	}
 
 	metadataPath := filepath.Join(t.SessionDir, "metadata.json")
-	return os.WriteFile(metadataPath, data, 0644)
+	if err := os.WriteFile(metadataPath, data, 0644); err != nil {
+		return err
+	}
+	return t.writeSummaryText()
 }
 
-// Generate review file for Claude Code analysis
+// Generate review file for Claude Code analysis. Reviews over the size
+// budget in config are split into review.md, review_part2.md and so on.
 func (t *TaskTracker) GenerateReviewFile(sampleCount int) error {
-	selected := t.sampleScreenshots(sampleCount)
+	names, err := t.writeReview(sampleCount)
+	if err != nil {
+		return err
+	}
+
+	reviewPath := filepath.Join(t.SessionDir, "review.md")
+	if len(names) == 1 {
+		ui.Printf("\n✅ %s\n", i18n.T("review.written", reviewPath))
+	} else {
+		ui.Printf("\n✅ %s\n", i18n.T("review.parts", len(names), strings.Join(names, ", ")))
+		ui.Printf("   %s\n", i18n.T("review.next", reviewPath))
+	}
+	return nil
+}
+
+// writeReview writes the review files without printing anything and
+// returns their names
+func (t *TaskTracker) writeReview(sampleCount int) (names []string, err error) {
+	span := telemetry.Start("review", telemetry.A("session.id", t.SessionID))
+	defer func() { span.End(err) }()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	selected, skipped := t.relevantScreenshots(sampleCount, cfg.Review)
 
-	duration := t.EndTime.Sub(t.StartTime).Minutes()
+	duration := t.activeDuration().Minutes()
+
+	var header strings.Builder
+	header.WriteString(fmt.Sprintf("**%s:** %s\n", i18n.T("review.task"), t.TaskName))
+	header.WriteString(fmt.Sprintf("**%s:** %s\n", i18n.T("review.session"), t.SessionID))
+	header.WriteString(fmt.Sprintf("**%s:** %s\n", i18n.T("review.duration"), i18n.T("review.minutes", duration)))
+	if len(t.Gaps) > 0 {
+		header.WriteString(fmt.Sprintf("**%s:** %s\n", i18n.T("review.wall_clock"),
+			i18n.T("review.gaps", t.wallDuration().Minutes(), t.gapSeconds()/60, len(t.Gaps))))
+	}
+	if len(t.Pauses) > 0 {
+		header.WriteString(fmt.Sprintf("**%s:** %s\n", i18n.T("review.paused"), i18n.T("review.pauses", t.pauseSeconds()/60, len(t.Pauses))))
+	}
+	writeContinues(&header, t)
+	header.WriteString(fmt.Sprintf("**%s:** %d\n", i18n.T("review.total"), len(t.Screenshots)))
+	if n := t.droppedFrames(); n > 0 {
+		header.WriteString(fmt.Sprintf("**%s:** %s\n", i18n.T("review.dropped"), i18n.T("review.dropped_why", n)))
+	}
+	if t.Duplicates > 0 {
+		header.WriteString(fmt.Sprintf("**%s:** %s\n", i18n.T("review.static"), i18n.T("review.static_why", t.Duplicates)))
+	}
+	for _, v := range t.Videos {
+		header.WriteString(fmt.Sprintf("**%s:** %s\n", i18n.T("review.video", v.Monitor),
+			i18n.T("review.video_of", storedPath(t.SessionDir, v.Path), v.Frames, v.FPS)))
+	}
+	header.WriteString(fmt.Sprintf("**%s:** %d\n", i18n.T("review.sampled"), len(selected)))
+	if skipped > 0 {
+		header.WriteString(fmt.Sprintf("**%s:** %s\n", i18n.T("review.skipped"), i18n.T("review.skipped_why", skipped)))
+	}
+	header.WriteString("\n")
 
-	var md strings.Builder
-	md.WriteString("# Task Analysis Review\n\n")
-	md.WriteString(fmt.Sprintf("**Task Name:** %s\n", t.TaskName))
-	md.WriteString(fmt.Sprintf("**Session ID:** %s\n", t.SessionID))
-	md.WriteString(fmt.Sprintf("**Duration:** %.1f minutes\n", duration))
-	md.WriteString(fmt.Sprintf("**Total Screenshots:** %d\n", len(t.Screenshots)))
-	md.WriteString(fmt.Sprintf("**Sampled Screenshots:** %d\n\n", len(selected)))
+	writeMarkers(&header, t.Markers)
+	writeProductivity(&header, t.Productivity)
 
-	md.WriteString("## Screenshots for Analysis\n\n")
+	blocks := make([]reviewBlock, 0, len(selected))
+	timeline := t.timeline()
+	tiled := false
 	for i, shot := range selected {
-		md.WriteString(fmt.Sprintf("### Screenshot %d (%.1f min)\n", i+1, shot.RelativeTime/60))
-		md.WriteString(fmt.Sprintf("- **Monitor:** %d\n", shot.Monitor))
-		md.WriteString(fmt.Sprintf("- **Resolution:** %s\n", shot.Resolution))
-		md.WriteString(fmt.Sprintf("- **Timestamp:** %s\n\n", shot.Timestamp))
-		md.WriteString(fmt.Sprintf("![Screenshot](%s)\n\n", shot.Path))
-	}
-
-	md.WriteString("\n---\n\n")
-	md.WriteString("## Analysis Prompt\n\n")
-	md.WriteString("Please analyze the screenshots above and provide:\n\n")
-	md.WriteString("1. **What was accomplished**: A clear summary of the work done\n")
-	md.WriteString("2. **Key activities**: Main tasks or workflows observed\n")
-	md.WriteString("3. **Technologies/Tools used**: What applications or systems were visible\n")
-	md.WriteString("4. **Workspace organization**: How different monitors/windows were used (if multi-monitor)\n")
-	md.WriteString("5. **Progression**: How the work evolved over time\n")
-	md.WriteString("6. **Suggested Jira summary**: A concise 2-3 sentence summary suitable for a Jira task update\n\n")
-	md.WriteString("Be specific and focus on the actual work visible in the screenshots.\n")
+		var md strings.Builder
 
-	reviewPath := filepath.Join(t.SessionDir, "review.md")
-	if err := os.WriteFile(reviewPath, []byte(md.String()), 0644); err != nil {
-		return fmt.Errorf("failed to save review file: %w", err)
+		// Interleave notes and gaps from before this screenshot
+		n := 0
+		for n < len(timeline) && timeline[n].RelativeTime <= shot.RelativeTime {
+			n++
+		}
+		writeTimeline(&md, timeline[:n])
+		timeline = timeline[n:]
+
+		md.WriteString(fmt.Sprintf("### %s\n", i18n.T("review.screenshot", i+1, shot.RelativeTime/60)))
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("review.monitor"), monitorLabel(shot.Monitor)))
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("review.resolution"), shot.Resolution))
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("review.timestamp"), shot.Timestamp))
+		if shot.Caption != "" {
+			md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("review.caption"), shot.Caption))
+		}
+		if shot.Cursor != nil {
+			md.WriteString(fmt.Sprintf("- **%s:** %d, %d\n", i18n.T("review.cursor"), shot.Cursor.X, shot.Cursor.Y))
+		}
+		if shot.Marked {
+			md.WriteString(fmt.Sprintf("- **%s:** ⭐ %s\n", i18n.T("review.marked"), i18n.T("review.important")))
+		}
+		md.WriteString("\n")
+
+		path, err := t.viewablePath(shot)
+		if err != nil {
+			return nil, err
+		}
+		var tiles []frameTile
+		if cfg.Review.MaxImage > 0 {
+			if tiles, err = t.tileFrame(i+1, path, cfg.Review.MaxImage); err != nil {
+				return nil, err
+			}
+		}
+
+		var size int64
+		var imgTokens int
+		if len(tiles) == 0 {
+			md.WriteString(fmt.Sprintf("![Screenshot](%s)\n\n", path))
+			if info, err := os.Stat(path); err == nil {
+				size = info.Size()
+			}
+			imgTokens = imageTokens(shot.Resolution)
+		} else {
+			tiled = true
+			for _, tile := range tiles {
+				md.WriteString(fmt.Sprintf("![%s](%s)\n\n", tile.label, tile.path))
+				if info, err := os.Stat(tile.path); err == nil {
+					size += info.Size()
+				}
+				imgTokens += imageTokens(fmt.Sprintf("%dx%d", tile.size.X, tile.size.Y))
+			}
+		}
+		blocks = append(blocks, reviewBlock{
+			text:         md.String(),
+			tokens:       textTokens(md.String()) + imgTokens,
+			bytes:        size,
+			index:        i + 1,
+			relativeTime: shot.RelativeTime,
+		})
 	}
 
-	fmt.Printf("\n✅ Review file generated: %s\n", reviewPath)
-	return nil
+	var trailing strings.Builder
+	writeTimeline(&trailing, timeline)
+
+	parts := splitReview(blocks, cfg.Review, textTokens(header.String()))
+	t.removeReviewParts()
+
+	names = make([]string, len(parts))
+	for p, part := range parts {
+		var md strings.Builder
+		if len(parts) == 1 {
+			md.WriteString(fmt.Sprintf("# %s\n\n", i18n.T("review.title")))
+		} else {
+			md.WriteString(fmt.Sprintf("# %s\n\n", i18n.T("review.title_part", p+1, len(parts))))
+		}
+		md.WriteString(header.String())
+		if p > 0 && len(parts[p-1]) > 0 {
+			prev := parts[p-1][len(parts[p-1])-1]
+			md.WriteString(fmt.Sprintf("**%s:** %s\n\n", i18n.T("review.earlier"),
+				i18n.T("review.earlier_in", prev.index, prev.relativeTime/60, strings.Join(names[:p], ", "))))
+		}
+
+		md.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("review.screenshots")))
+		for _, b := range part {
+			md.WriteString(b.text)
+		}
+
+		if p == len(parts)-1 {
+			md.WriteString(trailing.String())
+			writeAnalysisPrompt(&md, len(parts), tiled)
+		} else {
+			md.WriteString("\n---\n\n")
+			md.WriteString(i18n.T("review.part_end", p+1, len(parts), reviewPartName(len(parts))) + "\n")
+		}
+
+		names[p] = reviewPartName(p + 1)
+		reviewPath := filepath.Join(t.SessionDir, names[p])
+		if err := os.WriteFile(reviewPath, []byte(md.String()), 0644); err != nil {
+			return nil, fmt.Errorf("failed to save review file: %w", err)
+		}
+	}
+
+	return names, nil
 }
 
-// Sample screenshots evenly
+// Sample screenshots evenly, always keeping marked ones
 func (t *TaskTracker) sampleScreenshots(count int) []Screenshot {
-	if len(t.Screenshots) <= count {
-		return t.Screenshots
+	return sampleEvenly(t.Screenshots, count)
+}
+
+// sampleEvenly picks count screenshots spread over shots, always keeping
+// marked ones
+func sampleEvenly(shots []Screenshot, count int) []Screenshot {
+	if len(shots) <= count {
+		return shots
 	}
 
-	selected := []Screenshot{}
-	step := float64(len(t.Screenshots)-1) / float64(count-1)
+	keep := make(map[int]bool)
+	unmarked := []int{}
+	for i, shot := range shots {
+		if shot.Marked {
+			keep[i] = true
+		} else {
+			unmarked = append(unmarked, i)
+		}
+	}
+
+	// Fill the remaining slots evenly from unmarked screenshots
+	remaining := count - len(keep)
+	if remaining == 1 && len(unmarked) > 0 {
+		keep[unmarked[0]] = true
+	} else if remaining > 1 && len(unmarked) > 0 {
+		step := float64(len(unmarked)-1) / float64(remaining-1)
+		for i := 0; i < remaining && i < len(unmarked); i++ {
+			keep[unmarked[int(float64(i)*step)]] = true
+		}
+	}
 
-	for i := 0; i < count; i++ {
-		idx := int(float64(i) * step)
-		selected = append(selected, t.Screenshots[idx])
+	selected := []Screenshot{}
+	for i, shot := range shots {
+		if keep[i] {
+			selected = append(selected, shot)
+		}
 	}
 
 	return selected
@@ -323,36 +910,46 @@ func (t *TaskTracker) sampleScreenshots(count int) []Screenshot {
 
 // Generate Bitbucket smart commit message for Jira
 func (t *TaskTracker) GenerateSmartCommit() string {
-	if t.JiraTicket == "" {
+	if t.Ticket == nil || t.Ticket.Provider != providerJira {
 		return ""
 	}
 
 	var commitMsg strings.Builder
-	commitMsg.WriteString(fmt.Sprintf("[%s]", t.JiraTicket))
-
-	// Calculate time spent if not provided
-	timeSpent := t.TimeSpent
-	if timeSpent == "" {
-		duration := t.EndTime.Sub(t.StartTime)
-		hours := int(duration.Hours())
-		minutes := int(duration.Minutes()) % 60
-
-		if hours > 0 {
-			timeSpent = fmt.Sprintf("%dh %dm", hours, minutes)
-		} else {
-			timeSpent = fmt.Sprintf("%dm", minutes)
-		}
+	commitMsg.WriteString(fmt.Sprintf("[%s]", t.Ticket.Key))
+	commitMsg.WriteString(fmt.Sprintf(" #time %s", t.timeSpent()))
+	if comment := t.commitComment(); comment != "" {
+		commitMsg.WriteString(fmt.Sprintf(" #comment %s", comment))
 	}
 
-	commitMsg.WriteString(fmt.Sprintf(" #time %s", timeSpent))
+	return commitMsg.String()
+}
 
-	if t.JiraComment != "" {
-		commitMsg.WriteString(fmt.Sprintf(" #comment %s", t.JiraComment))
-	} else if t.TaskName != "" {
-		commitMsg.WriteString(fmt.Sprintf(" #comment %s", t.TaskName))
+// timeSpent is the time to log, calculated from the rounded active time if
+// it wasn't given
+func (t *TaskTracker) timeSpent() string {
+	if t.TimeSpent != "" {
+		return t.TimeSpent
 	}
+	return formatTimeSpent(t.Rounding.Apply(t.activeDuration()))
+}
 
-	return commitMsg.String()
+// commitComment is the ticket comment for the session: the saved comment or
+// summary, or the task name, followed by tracked time against the estimate
+func (t *TaskTracker) commitComment() string {
+	comment := t.TicketComment
+	if comment == "" {
+		comment = t.summaryText()
+	}
+	if comment == "" {
+		comment = t.TaskName
+	}
+	if delta := t.estimateDelta(); delta != "" {
+		comment = strings.TrimSpace(comment + " - " + delta)
+	}
+	if components := t.components(); len(components) > 0 {
+		comment = strings.TrimSpace(comment + " - Components: " + formatComponents(components))
+	}
+	return comment
 }
 
 // Save smart commit message to file
@@ -366,225 +963,591 @@ func (t *TaskTracker) SaveSmartCommit() error {
 	return os.WriteFile(commitPath, []byte(smartCommit), 0644)
 }
 
-func main() {
-	var rootCmd = &cobra.Command{
-		Use:   "task-tracker",
-		Short: "AI-powered task tracking with screen capture",
+// runStart captures a session until interrupted or stopped, then saves it
+// and generates the review file. parent is the session being continued,
+// or nil.
+func runStart(cmd *cobra.Command, taskName string, parent *TaskTracker) {
+	monitors, _ := cmd.Flags().GetString("monitors")
+	interval, _ := cmd.Flags().GetInt("interval")
+	jitter, _ := cmd.Flags().GetInt("jitter")
+	ticketArg, _ := cmd.Flags().GetString("ticket")
+	timeSpent, _ := cmd.Flags().GetString("time")
+	tags, _ := cmd.Flags().GetStringSlice("tags")
+	backend, _ := cmd.Flags().GetString("backend")
+	display, _ := cmd.Flags().GetString("display")
+	repo, _ := cmd.Flags().GetString("repo")
+
+	// A continued session inherits what the new one doesn't override
+	if parent != nil {
+		if taskName == "" {
+			taskName = parent.TaskName
+		}
+		if !cmd.Flags().Changed("ticket") {
+			ticketArg = parent.Ticket.String()
+		}
+		if !cmd.Flags().Changed("tags") {
+			tags = parent.Tags
+		}
+		if !cmd.Flags().Changed("repo") && parent.Repo != nil {
+			repo = parent.Repo.Path
+		}
 	}
 
-	// Start command
-	var startCmd = &cobra.Command{
-		Use:   "start [task name]",
-		Short: "Start capturing screenshots",
-		Args:  cobra.MaximumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			monitors, _ := cmd.Flags().GetString("monitors")
-			interval, _ := cmd.Flags().GetInt("interval")
-			jiraTicket, _ := cmd.Flags().GetString("ticket")
-			timeSpent, _ := cmd.Flags().GetString("time")
+	ticket, err := parseTicket(ticketArg)
+	if err != nil {
+		ui.Printf("❌ %v\n", err)
+		exit(exitCode(err))
+	}
 
-			tracker, err := NewTaskTracker("task_captures", monitors)
-			if err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
-				os.Exit(1)
-			}
+	cfg, err := loadConfig()
+	if err != nil {
+		ui.Printf("❌ Error: %v\n", err)
+		exit(exitCode(err))
+	}
+	if !cmd.Flags().Changed("interval") && cfg.Capture.Interval.Duration > 0 {
+		interval = int(cfg.Capture.Interval.Seconds())
+	}
+	if !cmd.Flags().Changed("monitors") && cfg.Capture.Monitors != "" {
+		monitors = cfg.Capture.Monitors
+	}
+	if err := validateJitter(time.Duration(jitter)*time.Second, time.Duration(interval)*time.Second); err != nil {
+		ui.Printf("❌ %v\n", err)
+		exit(exitCode(err))
+	}
 
-			tracker.CaptureInterval = time.Duration(interval) * time.Second
-			tracker.JiraTicket = jiraTicket
-			tracker.TimeSpent = timeSpent
+	name, _ := cmd.Flags().GetString("session-name")
+	if name != "" {
+		if !sessionNamePattern.MatchString(name) {
+			ui.Printf("❌ Invalid session name '%s' (use letters, digits, '.', '_' and '-')\n", name)
+			exit(exitUsage)
+		}
+		if s, err := findRunning(name); err == nil && s.Name == name {
+			ui.Printf("❌ A session named '%s' is already running (%s)\n", name, s.SessionID)
+			exit(exitUsage)
+		}
+	}
 
-			taskName := ""
-			if len(args) > 0 {
-				taskName = args[0]
-			}
+	capturer, err := capture.New(backend, display)
+	if err != nil {
+		ui.Printf("❌ Error: %v\n", err)
+		exit(exitCode(fmt.Errorf("%w: %v", errCaptureUnavailable, err)))
+	}
 
-			// Set up signal handling for graceful shutdown
-			sigChan := make(chan os.Signal, 1)
-			signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	tracker, err := NewTaskTracker(capturesDir, monitors, capturer)
+	if err != nil {
+		ui.Printf("❌ Error: %v\n", err)
+		exit(exitCode(err))
+	}
 
-			// Start capture in a goroutine
-			done := make(chan error, 1)
-			go func() {
-				done <- tracker.StartCapture(taskName)
-			}()
+	// The session directory stays empty until capture starts, so exits
+	// before then remove it
+	abort := func(code int) {
+		os.Remove(tracker.SessionDir)
+		exit(code)
+	}
 
-			// Wait for either completion or interrupt signal
-			select {
-			case <-sigChan:
-				fmt.Println("\n\n⏸️  Interrupt received, stopping capture...")
-				tracker.IsCapturing = false
-			case err := <-done:
-				if err != nil {
-					fmt.Printf("❌ Error during capture: %v\n", err)
-					os.Exit(1)
-				}
-			}
+	tracker.Name = name
+	if parent != nil {
+		tracker.Parent = parent.SessionID
+	}
+	tracker.CaptureInterval = time.Duration(interval) * time.Second
+	tracker.Jitter = time.Duration(jitter) * time.Second
+	tracker.Ticket = ticket
+	tracker.TimeSpent = timeSpent
+	tracker.Tags = tags
+	tracker.Backend = backend
+	tracker.Display = display
+	if _, ok := capturer.(capture.Screen); ok {
+		desktop := capture.CurrentDesktop()
+		tracker.Desktop = &desktop
+		if desktop.Type == capture.DesktopRemote {
+			ui.Printf("🖧  Remote desktop session %s: capturing its displays; capture pauses while it's disconnected\n", desktop)
+		}
+	}
+	tracker.Rounding = cfg.Rounding
+	tracker.Disk = cfg.Disk
+	tracker.Battery = cfg.Battery
+	tracker.Load = cfg.Load
+	tracker.Blackout = cfg.Blackout
+	tracker.Capture = cfg.Capture
+	tracker.Notifier = newNotifier(cfg.Notifications)
+	if len(cfg.Classifier.Command) > 0 {
+		tracker.Classifier = &classifier{cfg: cfg.Classifier}
+		ui.Printf("🛡️  Privacy classifier: %s\n", strings.Join(cfg.Classifier.Command, " "))
+	}
+	tracker.Optimize, _ = cmd.Flags().GetBool("optimize")
+	tracker.Delta, _ = cmd.Flags().GetBool("delta")
+	tracker.Composite, _ = cmd.Flags().GetBool("composite")
+	tracker.KeepDuplicates, _ = cmd.Flags().GetBool("keep-duplicates")
+	if video, _ := cmd.Flags().GetBool("video"); video || cmd.Flags().Changed("video-fps") {
+		tracker.VideoFPS, _ = cmd.Flags().GetFloat64("video-fps")
+		if tracker.VideoFPS <= 0 || tracker.VideoFPS > 30 {
+			ui.Println("❌ --video-fps must be between 0 and 30")
+			abort(exitUsage)
+		}
+		if _, err := exec.LookPath("ffmpeg"); err != nil {
+			ui.Println("❌ --video needs ffmpeg in PATH")
+			abort(exitUsage)
+		}
+	}
+	if tracker.QA, _ = cmd.Flags().GetBool("qa"); tracker.QA {
+		tracker.stepNow = make(chan struct{}, 1)
+	}
+	if tracker.Clipboard, _ = cmd.Flags().GetBool("clipboard"); tracker.Clipboard && tracker.clipboardReader() == nil {
+		ui.Printf("❌ The %s backend can't read the clipboard\n", tracker.Backend)
+		abort(exitUsage)
+	}
+	if repo != "" {
+		if tracker.Repo, err = openRepo(repo); err != nil {
+			ui.Printf("❌ %v\n", err)
+			abort(exitCode(err))
+		}
+		tracker.RepoRules = cfg.Repo.Components
+		ui.Printf("🧩 Repository: %s\n", tracker.Repo.Path)
+	}
+	tracker.DrawCursor, _ = cmd.Flags().GetBool("cursor")
+	intervals := cfg.Capture.MonitorIntervals
+	if cmd.Flags().Changed("monitor-intervals") {
+		intervals, _ = cmd.Flags().GetString("monitor-intervals")
+	}
+	if err := tracker.setMonitorIntervals(intervals); err != nil {
+		ui.Printf("❌ %v\n", err)
+		abort(exitCode(err))
+	}
+	if err := tracker.setupMask(cmd, cfg.Mask); err != nil {
+		ui.Printf("❌ %v\n", err)
+		abort(exitCode(err))
+	}
+	tracker.Placeholders, _ = cmd.Flags().GetBool("placeholders")
+	if path, _ := cmd.Flags().GetString("rules"); path != "" || cfg.Rules != "" {
+		if path == "" {
+			path = cfg.Rules
+		}
+		if tracker.Rules, err = loadRules(path); err != nil {
+			ui.Printf("❌ %v\n", err)
+			abort(exitUsage)
+		}
+		ui.Printf("📜 Rules: %s\n", path)
+	}
+	tracker.Dedupe, _ = cmd.Flags().GetBool("dedupe")
+	if tracker.Delta && tracker.Dedupe {
+		ui.Println("❌ --delta and --dedupe can't be combined")
+		abort(exitUsage)
+	}
+	tracker.OptimizePNGQuant, _ = cmd.Flags().GetBool("pngquant")
+	if tracker.OptimizePNGQuant {
+		if _, err := exec.LookPath("pngquant"); err != nil {
+			ui.Println("❌ pngquant not found in PATH")
+			abort(exitUsage)
+		}
+		tracker.Optimize = true
+	}
+	tracker.Format, _ = cmd.Flags().GetString("format")
+	tracker.Quality, _ = cmd.Flags().GetInt("quality")
+	tracker.Scale, _ = cmd.Flags().GetFloat64("scale")
+	tracker.MaxWidth, _ = cmd.Flags().GetInt("max-width")
+	if tracker.Scale <= 0 || tracker.Scale > 1 || tracker.MaxWidth < 0 {
+		ui.Println("❌ --scale must be above 0 and at most 1, and --max-width can't be negative")
+		abort(exitUsage)
+	}
+	if err := tracker.checkFormat(); err != nil {
+		ui.Printf("❌ %v\n", err)
+		abort(exitCode(err))
+	}
+
+	if cmd.Flags().Changed("watermark") {
+		cfg.Watermark.Enabled, _ = cmd.Flags().GetBool("watermark")
+	}
+	if cmd.Flags().Changed("watermark-text") {
+		cfg.Watermark.Text, _ = cmd.Flags().GetString("watermark-text")
+		cfg.Watermark.Enabled = true
+	}
+	if cfg.Watermark.Enabled {
+		if tracker.Watermark, err = cfg.Watermark.build(); err != nil {
+			ui.Printf("❌ Error: %v\n", err)
+			abort(exitCode(fmt.Errorf("%w: %v", errUsage, err)))
+		}
+	}
+
+	if wakatime, _ := cmd.Flags().GetBool("wakatime"); wakatime {
+		if tracker.WakaTime, err = newWakaTime(); err != nil {
+			ui.Printf("❌ %v\n", err)
+			abort(exitCode(err))
+		}
+	}
+
+	planned, _ := cmd.Flags().GetDuration("planned")
+	force, _ := cmd.Flags().GetBool("force")
+	if err := tracker.checkDiskSpace(planned, force); err != nil {
+		ui.Printf("❌ %v\n", err)
+		abort(exitDiskFull)
+	}
+
+	// Capture until interrupted; Ctrl+C cancels the context
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, tracker.cancel = context.WithCancel(ctx)
+
+	if err := tracker.StartCapture(ctx, taskName); err != nil {
+		ui.Printf("❌ Error during capture: %v\n", err)
+		exit(exitCode(err))
+	}
+	stop()
+	ui.Println("\n\n⏸️  " + i18n.T("stop.stopping"))
+
+	// Stop capture and save metadata
+	if err := tracker.StopCapture(); err != nil {
+		ui.Printf("❌ Error stopping capture: %v\n", err)
+		exit(exitCode(err))
+	}
+
+	if tracker.QA {
+		if err := tracker.writeEvidence(); err != nil {
+			ui.Printf("⚠️  Failed to write the evidence document: %v\n", err)
+		} else if len(tracker.steps()) > 0 {
+			ui.Printf("🧪 Evidence (%d steps): %s, %s\n", len(tracker.steps()),
+				filepath.Join(tracker.SessionDir, evidenceFile), filepath.Join(tracker.SessionDir, evidencePDF))
+		}
+	}
 
-			// Stop capture and save metadata
-			if err := tracker.StopCapture(); err != nil {
-				fmt.Printf("❌ Error stopping capture: %v\n", err)
-				os.Exit(1)
+	// Generate review file
+	ui.Println("\n" + strings.Repeat("=", 50))
+	ui.Println(i18n.T("review.writing"))
+
+	if err := tracker.GenerateReviewFile(5); err != nil {
+		ui.Printf("⚠️  Failed to generate review file: %v\n", err)
+	} else {
+		reviewPath := filepath.Join(tracker.SessionDir, "review.md")
+		ui.Println("\n" + strings.Repeat("=", 50))
+		ui.Println("📝 " + i18n.T("next.title"))
+		ui.Println("\n" + i18n.T("next.analyze"))
+		ui.Printf(" claude \"%s\"\n", reviewPath)
+
+		if tracker.Ticket != nil {
+			ui.Println("\n" + i18n.T("next.commit"))
+			post := ""
+			if tracker.Ticket.Provider != providerJira {
+				post = " --post"
 			}
+			ui.Printf("   ./task-tracker commit %s \"%s\"%s\n", tracker.SessionID, i18n.T("next.summary"), post)
+		}
 
-			// Generate review file
-			fmt.Println("\n" + strings.Repeat("=", 50))
-			fmt.Println("Generating review file for Claude Code analysis...")
+		ui.Println("\n" + i18n.T("next.contains"))
+	}
+}
 
-			if err := tracker.GenerateReviewFile(5); err != nil {
-				fmt.Printf("⚠️  Failed to generate review file: %v\n", err)
-			} else {
-				reviewPath := filepath.Join(tracker.SessionDir, "review.md")
-				fmt.Println("\n" + strings.Repeat("=", 50))
-				fmt.Println("📝 NEXT STEPS:")
-				fmt.Println("\n1. Analyze your session in Claude Code:")
-				fmt.Printf(" claude \"%s\"\n", reviewPath)
-
-				if tracker.JiraTicket != "" {
-					fmt.Println("\n2. After getting the AI summary, generate smart commit:")
-					fmt.Printf("   ./task-tracker commit %s \"<AI generated summary>\"\n", tracker.SessionID)
-				}
+// addStartFlags adds the capture flags shared by start and continue
+func addStartFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("monitors", "m", "all", "Monitors to capture (all, primary, 1, 1,2, or names like DP-1, etc.; default from capture.monitors in config)")
+	cmd.Flags().IntP("interval", "i", 30, "Capture interval in seconds (default from capture.interval in config)")
+	cmd.Flags().String("monitor-intervals", "", "Capture some monitors less often, e.g. 2:5m (monitor:interval, comma-separated)")
+	cmd.Flags().Int("jitter", 0, "Move each interval randomly by up to ± this many seconds, so captures don't line up with periodic screen updates")
+	cmd.Flags().StringP("ticket", "t", "", "Ticket: a Jira key (e.g., CYM-2945), github:owner/repo#12, gitlab:group/project#12, linear:ENG-42, azure:Project#1234 or an issue URL")
+	cmd.Flags().String("time", "", "Time spent (e.g., 1h 20m) - auto-calculated if not provided")
+	cmd.Flags().StringSlice("tags", nil, "Comma-separated tags for the session")
+	cmd.Flags().String("backend", capture.BackendScreen, "Capture backend (screen; virtual for Xvfb/virtual X displays; fake for synthetic frames)")
+	cmd.Flags().Bool("composite", false, "Save one frame per tick with all captured monitors in their desktop layout")
+	cmd.Flags().Bool("keep-duplicates", false, "Save every frame, even ones identical to the last of their monitor")
+	cmd.Flags().Bool("video", false, "Also record each monitor continuously as WebM video with ffmpeg; screenshots keep coming every interval as keyframes for review")
+	cmd.Flags().Float64("video-fps", 1, "Video frame rate (implies --video)")
+	cmd.Flags().Bool("qa", false, "QA evidence mode: each mark or line typed here captures a numbered step, written up in evidence.md and evidence.pdf at the end")
+	cmd.Flags().Bool("clipboard", false, "Also save images copied to the clipboard during the session (e.g. snips taken by hand) as manual captures")
+	cmd.Flags().String("repo", "", "Git repository worked in: files changed during the session are mapped to components and owners (CODEOWNERS or repo in config)")
+	cmd.Flags().String("rules", "", "Starlark script deciding per tick whether and what to capture (see rules in config)")
+	cmd.Flags().Bool("cursor", false, "Draw the mouse pointer into frames (its position is recorded either way)")
+	cmd.Flags().Bool("mask-self", true, "Blank out the terminal task-tracker runs in (see mask in config)")
+	cmd.Flags().StringArray("mask-window", nil, "Blank out windows whose title matches this regular expression (repeatable)")
+	cmd.Flags().Bool("placeholders", false, "Record an image-less placeholder for each tick skipped while paused (privacy, blackout, disconnect, low disk, rules)")
+	cmd.Flags().String("format", formatPNG, "Frame format: png, jpeg or webp (webp needs the external cwebp tool)")
+	cmd.Flags().Int("quality", defaultQuality, "JPEG and WebP quality, 1-100 (100 is lossless WebP)")
+	cmd.Flags().Float64("scale", 1, "Resize frames by this factor before saving, e.g. 0.5 (AI review rarely needs full 4K)")
+	cmd.Flags().Int("max-width", 0, "Resize frames wider than this many pixels before saving, keeping the aspect ratio")
+	cmd.Flags().Bool("delta", false, "Store frames as tiles changed since the last keyframe (for mostly static screens)")
+	cmd.Flags().Bool("dedupe", false, "Store identical frames once, shared across sessions (see 'task-tracker gc')")
+	cmd.Flags().Bool("optimize", false, "Losslessly shrink saved frames in the background while capturing")
+	cmd.Flags().Bool("pngquant", false, "Shrink frames with the external pngquant tool (lossy, implies --optimize)")
+	cmd.Flags().Duration("planned", 8*time.Hour, "Planned session length, used to check free disk space before starting")
+	cmd.Flags().Bool("force", false, "Start even if the planned session won't fit on disk")
+	cmd.Flags().Bool("watermark", false, "Stamp task name and timestamp on every frame (see watermark in config)")
+	cmd.Flags().String("watermark-text", "", "Custom watermark text (implies --watermark)")
+	cmd.Flags().Bool("wakatime", false, "Send WakaTime heartbeats while capturing (reads ~/.wakatime.cfg)")
+	cmd.Flags().String("session-name", "", "Name to address this session by in note, mark, privacy and stop (default: session ID)")
+	cmd.Flags().String("display", "", "X11 display to capture, e.g. :99 (defaults to $DISPLAY)")
+}
+
+func main() {
+	var rootCmd = &cobra.Command{
+		Use:     "task-tracker",
+		Short:   "AI-powered task tracking with screen capture",
+		Long:    "AI-powered task tracking with screen capture\n\n" + exitCodesHelp,
+		Version: Version,
+	}
 
-				fmt.Println("\nThe review file contains all screenshots and an analysis prompt.")
+	// Start command
+	var startCmd = &cobra.Command{
+		Use:   "start [task name]",
+		Short: "Start capturing screenshots",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskName := ""
+			if len(args) > 0 {
+				taskName = args[0]
 			}
+			runStart(cmd, taskName, nil)
 		},
 	}
 
-	startCmd.Flags().StringP("monitors", "m", "all", "Monitors to capture (all, primary, 1, 1,2, etc.)")
-	startCmd.Flags().IntP("interval", "i", 30, "Capture interval in seconds")
-	startCmd.Flags().StringP("ticket", "t", "", "Jira ticket ID (e.g., CYM-2945)")
-	startCmd.Flags().String("time", "", "Time spent (e.g., 1h 20m) - auto-calculated if not provided")
+	addStartFlags(startCmd)
 
 	// Stop command (for stopping a running session)
 	var stopCmd = &cobra.Command{
 		Use:   "stop",
-		Short: "Stop the current capture session gracefully",
-		Long: `Stop command is not needed if using Ctrl+C, which now properly saves metadata.
-This command is here for completeness but Ctrl+C is the recommended way to stop.`,
+		Short: "Stop a running capture session gracefully",
+		Long: `Stop a running capture session from another terminal. It saves metadata and
+generates the review file just like pressing Ctrl+C in the session's terminal.
+
+With several sessions running, pick one with --session or stop them all with --all.`,
+		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("💡 Tip: You can stop capture by pressing Ctrl+C")
-			fmt.Println("   Metadata and summary will be generated automatically")
+			all, _ := cmd.Flags().GetBool("all")
+			targets := []string{}
+			if all {
+				for _, s := range runningSessions() {
+					targets = append(targets, s.Name)
+				}
+				if len(targets) == 0 {
+					ui.Println("💡 No capture is running")
+					return
+				}
+			} else {
+				session, _ := cmd.Flags().GetString("session")
+				targets = append(targets, session)
+			}
+
+			failed := false
+			for _, target := range targets {
+				resp, err := sendControl(target, controlRequest{Command: "stop"})
+				if err != nil {
+					ui.Printf("❌ %v\n", err)
+					if !all {
+						exit(exitCode(err))
+					}
+					failed = true
+					continue
+				}
+				ui.Printf("⏹️  %s\n", resp.Message)
+			}
+			if failed {
+				exit(exitError)
+			}
 		},
 	}
+	addSessionFlag(stopCmd)
+	stopCmd.Flags().Bool("all", false, "Stop every running session")
 
 	// Analyze command
 	var analyzeCmd = &cobra.Command{
-		Use:   "analyze [session_id]",
+		Use:   "analyze [session_id]...",
 		Short: "Generate review file for an existing capture session",
-		Args:  cobra.ExactArgs(1),
+		Long: `Generate review.md for a capture session. Give several session IDs, --all or
+--since to regenerate many reviews at once; they're written in parallel.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			sessionID := args[0]
-			sessionDir := filepath.Join("task_captures", sessionID)
-
-			// Load metadata
-			metadataPath := filepath.Join(sessionDir, "metadata.json")
-			data, err := os.ReadFile(metadataPath)
-			if err != nil {
-				fmt.Printf("❌ Failed to load session: %v\n", err)
-				os.Exit(1)
-			}
-
-			var metadata SessionMetadata
-			if err := json.Unmarshal(data, &metadata); err != nil {
-				fmt.Printf("❌ Failed to parse metadata: %v\n", err)
-				os.Exit(1)
+			if len(args) != 1 || cmd.Flags().Changed("all") || cmd.Flags().Changed("since") {
+				ids, err := batchSessions(cmd, args)
+				if err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+				jobs, _ := cmd.Flags().GetInt("jobs")
+				failed := runBatch("📝 Reviews", ids, jobs, func(t *TaskTracker) error {
+					_, err := t.writeReview(5)
+					return err
+				})
+				ui.Printf("✅ Wrote reviews for %d of %d sessions\n", len(ids)-len(failed), len(ids))
+				if len(failed) > 0 {
+					exit(exitError)
+				}
+				return
 			}
 
-			// Reconstruct tracker
-			tracker := &TaskTracker{
-				SessionID:   metadata.SessionID,
-				SessionDir:  sessionDir,
-				TaskName:    metadata.TaskName,
-				Screenshots: metadata.Screenshots,
-				JiraTicket:  metadata.JiraTicket,
-				TimeSpent:   metadata.TimeSpent,
-				JiraComment: metadata.JiraComment,
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
 			}
 
-			tracker.StartTime, _ = time.Parse(time.RFC3339, metadata.StartTime)
-			tracker.EndTime, _ = time.Parse(time.RFC3339, metadata.EndTime)
-
 			// Generate review file
-			fmt.Println("Generating review file for Claude Code analysis...")
+			ui.Println(i18n.T("review.writing"))
 			if err := tracker.GenerateReviewFile(5); err != nil {
-				fmt.Printf("❌ Failed to generate review file: %v\n", err)
-				os.Exit(1)
+				ui.Printf("❌ Failed to generate review file: %v\n", err)
+				exit(exitCode(err))
 			}
 
-			reviewPath := filepath.Join(sessionDir, "review.md")
-			fmt.Println("\n" + strings.Repeat("=", 50))
-			fmt.Println("📝 NEXT STEPS:")
-			fmt.Println("\nTo analyze your session in Claude Code, run:")
-			fmt.Printf("  claude \"%s\"\n", reviewPath)
-			fmt.Println("\nOr open the file in your editor and paste it into Claude Code.")
+			reviewPath := filepath.Join(tracker.SessionDir, "review.md")
+			ui.Println("\n" + strings.Repeat("=", 50))
+			ui.Println("📝 " + i18n.T("next.title"))
+			ui.Println("\n" + i18n.T("next.run"))
+			ui.Printf("  claude \"%s\"\n", reviewPath)
+			ui.Println("\n" + i18n.T("next.paste"))
 		},
 	}
 
+	addBatchFlags(analyzeCmd)
+
 	// Commit command - generate smart commit after AI analysis
 	var commitCmd = &cobra.Command{
 		Use:   "commit [session_id] [summary]",
-		Short: "Generate Bitbucket smart commit message with AI-generated summary",
+		Short: "Generate Bitbucket smart commit message with AI-generated summary, or post it to the ticket",
 		Long: `Generate a Bitbucket smart commit message for Jira integration.
-Use this after analyzing the session with Claude Code to include the AI-generated summary.`,
-		Args: cobra.ExactArgs(2),
+Use this after analyzing the session with Claude Code to include the AI-generated summary.
+With --post the time and summary go straight to the session's ticket instead, on Jira,
+GitHub, GitLab, Linear or Azure Boards. Posting again once the time is logged only
+posts the summary, so a failed comment can be retried without logging the time twice.
+
+The summary is saved in the session's metadata ("-" reads it from stdin). Leave it
+out to reuse the summary saved earlier with 'task-tracker summary' or commit.`,
+		Args: cobra.RangeArgs(1, 2),
 		Run: func(cmd *cobra.Command, args []string) {
-			sessionID := args[0]
-			summary := args[1]
-			sessionDir := filepath.Join("task_captures", sessionID)
-
-			// Load metadata
-			metadataPath := filepath.Join(sessionDir, "metadata.json")
-			data, err := os.ReadFile(metadataPath)
+			tracker, err := loadSession(args[0])
 			if err != nil {
-				fmt.Printf("❌ Failed to load session: %v\n", err)
-				os.Exit(1)
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			if tracker.Ticket == nil {
+				ui.Println("❌ No ticket found for this session")
+				ui.Println("💡 Tip: Use --ticket flag when starting the capture")
+				exit(exitUsage)
+			}
+			post, _ := cmd.Flags().GetBool("post")
+			if tracker.Ticket.Provider != providerJira && !post {
+				ui.Printf("❌ Smart commits are Jira only; use --post to log time on %s\n", tracker.Ticket)
+				exit(exitUsage)
 			}
 
-			var metadata SessionMetadata
-			if err := json.Unmarshal(data, &metadata); err != nil {
-				fmt.Printf("❌ Failed to parse metadata: %v\n", err)
-				os.Exit(1)
+			summary := tracker.summaryText()
+			if len(args) > 1 {
+				if summary, err = readSummaryArg(args[1]); err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+				provider, _ := cmd.Flags().GetString("provider")
+				model, _ := cmd.Flags().GetString("model")
+				tracker.setSummary(summary, provider, model)
+				summary = tracker.summaryText()
+			}
+			if summary == "" {
+				ui.Println("❌ No summary given and none saved for this session")
+				exit(exitUsage)
 			}
 
-			if metadata.JiraTicket == "" {
-				fmt.Println("❌ No Jira ticket found for this session")
-				fmt.Println("💡 Tip: Use --ticket flag when starting the capture")
-				os.Exit(1)
+			cfg, err := loadConfig()
+			if err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				exit(exitCode(err))
 			}
 
-			// Create tracker with updated comment
-			tracker := &TaskTracker{
-				SessionID:   metadata.SessionID,
-				SessionDir:  sessionDir,
-				JiraTicket:  metadata.JiraTicket,
-				TimeSpent:   metadata.TimeSpent,
-				JiraComment: summary,
+			if len(args) > 1 {
+				tracker.announceSummary(cfg)
 			}
 
-			tracker.StartTime, _ = time.Parse(time.RFC3339, metadata.StartTime)
-			tracker.EndTime, _ = time.Parse(time.RFC3339, metadata.EndTime)
+			// Use the AI summary as the comment
+			tracker.TicketComment = summary
+			tracker.Rounding = cfg.Rounding
+			if err := tracker.refreshEstimate(); err != nil {
+				ui.Printf("⚠️  Couldn't fetch the ticket's estimate: %v\n", err)
+			}
+			if err := tracker.saveMetadata(); err != nil {
+				ui.Printf("❌ Failed to save metadata: %v\n", err)
+				exit(exitCode(err))
+			}
 
 			// Generate and save smart commit
-			smartCommit := tracker.GenerateSmartCommit()
-			if err := tracker.SaveSmartCommit(); err != nil {
-				fmt.Printf("❌ Failed to save smart commit: %v\n", err)
-				os.Exit(1)
+			if smartCommit := tracker.GenerateSmartCommit(); smartCommit != "" {
+				if err := tracker.SaveSmartCommit(); err != nil {
+					ui.Printf("❌ Failed to save smart commit: %v\n", err)
+					exit(exitCode(err))
+				}
+
+				commitPath := filepath.Join(tracker.SessionDir, "smart_commit.txt")
+				ui.Println("🎫 BITBUCKET SMART COMMIT:")
+				ui.Printf("\n%s\n", smartCommit)
+				ui.Printf("\nSaved to: %s\n", commitPath)
 			}
 
-			commitPath := filepath.Join(sessionDir, "smart_commit.txt")
-			fmt.Println("🎫 BITBUCKET SMART COMMIT:")
-			fmt.Printf("\n%s\n", smartCommit)
-			fmt.Printf("\nSaved to: %s\n", commitPath)
-			fmt.Println("\nCopy this message to use in your git commit for Bitbucket/Jira integration.")
+			if post {
+				logged := tracker.LoggedAt
+				if err := tracker.postTicket(); err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+				if logged != "" {
+					ui.Printf("\n✅ Posted the summary on %s; the time was already logged at %s\n", tracker.Ticket, logged)
+					return
+				}
+				ui.Printf("\n✅ Logged %s and posted the summary on %s\n", tracker.timeSpent(), tracker.Ticket)
+				return
+			}
+			ui.Println("\nCopy this message to use in your git commit for Bitbucket/Jira integration.")
 		},
 	}
 
+	addSummaryFlags(commitCmd)
+	commitCmd.Flags().Bool("post", false, "Log the time and post the summary to the ticket directly (see ticket providers in the README)")
+
+	ui.BindFlags(rootCmd)
+	bindLangFlag(rootCmd)
+	bindOutputDir(rootCmd)
+
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(analyzeCmd)
 	rootCmd.AddCommand(commitCmd)
+	rootCmd.AddCommand(newContinueCmd())
+	rootCmd.AddCommand(newSummaryCmd())
+	rootCmd.AddCommand(newReportCmd())
+	rootCmd.AddCommand(newCompareCmd())
+	rootCmd.AddCommand(newDigestCmd())
+	rootCmd.AddCommand(newTimesheetCmd())
+	rootCmd.AddCommand(newTicketCmd())
+	rootCmd.AddCommand(newEvidenceCmd())
+	rootCmd.AddCommand(newKeyframesCmd())
 	rootCmd.AddCommand(stopCmd)
+	rootCmd.AddCommand(newEditCmd())
+	rootCmd.AddCommand(newNoteCmd())
+	rootCmd.AddCommand(newMarkCmd())
+	rootCmd.AddCommand(newLogsCmd())
+	rootCmd.AddCommand(newCaptionCmd())
+	rootCmd.AddCommand(newListCmd())
+	rootCmd.AddCommand(newSearchCmd())
+	rootCmd.AddCommand(newReviewRedactionsCmd())
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newViewCmd())
+	rootCmd.AddCommand(newOpenCmd())
+	rootCmd.AddCommand(newOptimizeCmd())
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.AddCommand(newGCCmd())
+	rootCmd.AddCommand(newBenchCmd())
+	rootCmd.AddCommand(newVerifyCmd())
+	rootCmd.AddCommand(newActivityWatchCmd())
+	rootCmd.AddCommand(newRescueTimeCmd())
+	rootCmd.AddCommand(newPrivacyCmd())
+	rootCmd.AddCommand(newPauseCmd())
+	rootCmd.AddCommand(newResumeCmd())
+	rootCmd.AddCommand(newAuditCmd())
+	rootCmd.AddCommand(newQuarantineCmd())
+	rootCmd.AddCommand(newPromptTestCmd())
+	rootCmd.AddCommand(newVersionCmd())
+
+	if err := telemetry.Init(Version, userAgent()); err != nil {
+		ui.Printf("⚠️  Telemetry disabled: %v\n", err)
+	}
+	telemetry.OnError = func(err error) {
+		ui.Printf("⚠️  %v\n", err)
+	}
 
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		ui.Println(err)
+		exit(exitUsage)
 	}
+	exit(exitOK)
 }
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"task-tracker/internal/capture"
+)
+
+// newFakeTracker starts a tracker on fake displays in a temporary
+// directory, which capturesDir points at for the test
+func newFakeTracker(t *testing.T, monitors string, displays ...image.Rectangle) *TaskTracker {
+	t.Helper()
+	dir := t.TempDir()
+	prev := capturesDir
+	capturesDir = dir
+	t.Cleanup(func() { capturesDir = prev })
+
+	tracker, err := NewTaskTracker(dir, monitors, capture.NewFake(displays...))
+	if err != nil {
+		t.Fatalf("NewTaskTracker: %v", err)
+	}
+	return tracker
+}
+
+// runOneTick runs the capture loop for its initial capture only and stops
+func runOneTick(t *testing.T, tracker *TaskTracker, taskName string) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := tracker.StartCapture(ctx, taskName); err != nil {
+		t.Fatalf("StartCapture: %v", err)
+	}
+	if err := tracker.StopCapture(); err != nil {
+		t.Fatalf("StopCapture: %v", err)
+	}
+}
+
+func TestCaptureLoopSavesEveryMonitor(t *testing.T) {
+	tracker := newFakeTracker(t, "all", image.Rect(0, 0, 320, 200), image.Rect(320, 0, 640, 200))
+	runOneTick(t, tracker, "Fake task")
+
+	if got := len(tracker.Screenshots); got != 2 {
+		t.Fatalf("got %d screenshots, want one per monitor (2)", got)
+	}
+	for i, shot := range tracker.Screenshots {
+		if shot.Monitor != i+1 {
+			t.Errorf("screenshot %d is of monitor %d, want %d", i+1, shot.Monitor, i+1)
+		}
+		if shot.Resolution != "320x200" {
+			t.Errorf("screenshot %d resolution = %s, want 320x200", i+1, shot.Resolution)
+		}
+		if _, err := os.Stat(shot.Path); err != nil {
+			t.Errorf("screenshot %d not written: %v", i+1, err)
+		}
+		if shot.SHA256 == "" {
+			t.Errorf("screenshot %d has no checksum", i+1)
+		}
+	}
+}
+
+func TestCaptureLoopRecordsFailedMonitor(t *testing.T) {
+	dir := t.TempDir()
+	fake := capture.NewFake(image.Rect(0, 0, 320, 200), image.Rect(320, 0, 640, 200))
+	fake.FailDisplay(1, errors.New("unplugged"))
+	tracker, err := NewTaskTracker(dir, "all", fake)
+	if err != nil {
+		t.Fatalf("NewTaskTracker: %v", err)
+	}
+	runOneTick(t, tracker, "")
+
+	if got := len(tracker.Screenshots); got != 1 || tracker.Screenshots[0].Monitor != 1 {
+		t.Fatalf("got %d screenshots, want only monitor 1's", got)
+	}
+	if tracker.TaskName != "Task_"+tracker.SessionID {
+		t.Errorf("TaskName = %q, want the default name", tracker.TaskName)
+	}
+}
+
+func TestSaveMetadataRoundTrip(t *testing.T) {
+	tracker := newFakeTracker(t, "primary", image.Rect(0, 0, 320, 200))
+	tracker.Tags = []string{"backend"}
+	runOneTick(t, tracker, "Round trip")
+
+	data, err := os.ReadFile(filepath.Join(tracker.SessionDir, "metadata.json"))
+	if err != nil {
+		t.Fatalf("metadata.json not written: %v", err)
+	}
+	var metadata SessionMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		t.Fatalf("metadata.json is invalid: %v", err)
+	}
+	if metadata.ScreenshotCount != 1 || len(metadata.Screenshots) != 1 {
+		t.Fatalf("metadata has %d screenshots (count %d), want 1", len(metadata.Screenshots), metadata.ScreenshotCount)
+	}
+	if path := metadata.Screenshots[0].Path; filepath.IsAbs(path) {
+		t.Errorf("screenshot path %s is absolute, want it relative to the session", path)
+	}
+	if metadata.IntervalSeconds != 30 {
+		t.Errorf("interval_seconds = %v, want 30", metadata.IntervalSeconds)
+	}
+
+	loaded, err := loadSession(tracker.SessionID)
+	if err != nil {
+		t.Fatalf("loadSession: %v", err)
+	}
+	if loaded.TaskName != "Round trip" || len(loaded.Tags) != 1 || loaded.Tags[0] != "backend" {
+		t.Errorf("loaded task %q tags %v, want %q [backend]", loaded.TaskName, loaded.Tags, "Round trip")
+	}
+	if len(loaded.Screenshots) != 1 {
+		t.Fatalf("loaded %d screenshots, want 1", len(loaded.Screenshots))
+	}
+	if _, err := os.Stat(loaded.Screenshots[0].Path); err != nil {
+		t.Errorf("loaded screenshot path doesn't resolve: %v", err)
+	}
+}
+
+func TestSampleEvenly(t *testing.T) {
+	shots := make([]Screenshot, 10)
+	for i := range shots {
+		shots[i].RelativeTime = float64(i)
+	}
+	shots[4].Marked = true
+
+	tests := []struct {
+		name  string
+		count int
+		want  []float64
+	}{
+		{"all fit", 10, []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"spread with marked", 4, []float64{0, 4, 5, 9}},
+		{"only marked and first", 2, []float64{0, 4}},
+		{"marked wins", 1, []float64{4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sampleEvenly(shots, tt.count)
+			var times []float64
+			for _, s := range got {
+				times = append(times, s.RelativeTime)
+			}
+			if !slices.Equal(times, tt.want) {
+				t.Errorf("sampleEvenly(%d) = %v, want %v", tt.count, times, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTaskTrackerSessionIDs(t *testing.T) {
+	dir := t.TempDir()
+	fake := capture.NewFake(image.Rect(0, 0, 320, 200))
+	first, err := NewTaskTracker(dir, "primary", fake)
+	if err != nil {
+		t.Fatalf("NewTaskTracker: %v", err)
+	}
+	second, err := NewTaskTracker(dir, "primary", fake)
+	if err != nil {
+		t.Fatalf("NewTaskTracker: %v", err)
+	}
+	if first.SessionID == second.SessionID {
+		t.Errorf("two sessions got the ID %s", first.SessionID)
+	}
+
+	if _, err := NewTaskTracker(t.TempDir(), "all", capture.NewFake()); !errors.Is(err, errCaptureUnavailable) {
+		t.Errorf("no displays: got %v, want errCaptureUnavailable", err)
+	}
+}
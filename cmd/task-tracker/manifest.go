@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"task-tracker/internal/i18n"
+)
+
+// summaryTextFile explains a session directory to someone who finds it
+// without the tool at hand. It's rewritten with metadata.json.
+const summaryTextFile = "SUMMARY.txt"
+
+// writeSummaryText writes SUMMARY.txt for a stopped session. The caller
+// holds t.mu.
+func (t *TaskTracker) writeSummaryText() error {
+	if t.EndTime.IsZero() {
+		return nil
+	}
+
+	var s strings.Builder
+	title := i18n.T("summary.title")
+	s.WriteString(title + "\n" + strings.Repeat("=", len([]rune(title))) + "\n\n")
+
+	line := func(label, value string) {
+		s.WriteString(fmt.Sprintf("%s: %s\n", label, value))
+	}
+	line(i18n.T("review.task"), t.TaskName)
+	line(i18n.T("review.session"), t.SessionID)
+	if t.Ticket != nil {
+		ticket := t.Ticket.String()
+		if t.Ticket.URL != "" {
+			ticket += " (" + t.Ticket.URL + ")"
+		}
+		line(i18n.T("report.ticket"), ticket)
+	}
+	if len(t.Tags) > 0 {
+		line(i18n.T("report.tags"), strings.Join(t.Tags, ", "))
+	}
+	line(i18n.T("summary.started"), t.StartTime.Local().Format("2006-01-02 15:04 MST"))
+	line(i18n.T("summary.ended"), t.EndTime.Local().Format("2006-01-02 15:04 MST"))
+	line(i18n.T("review.duration"), i18n.T("review.minutes", t.activeDuration().Minutes()))
+	if len(t.Gaps) > 0 {
+		line(i18n.T("review.wall_clock"), i18n.T("review.gaps", t.wallDuration().Minutes(), t.gapSeconds()/60, len(t.Gaps)))
+	}
+	if len(t.Pauses) > 0 {
+		line(i18n.T("review.paused"), i18n.T("review.pauses", t.pauseSeconds()/60, len(t.Pauses)))
+	}
+
+	seen := map[int]bool{}
+	var monitors []int
+	for _, shot := range t.Screenshots {
+		if !seen[shot.Monitor] {
+			seen[shot.Monitor] = true
+			monitors = append(monitors, shot.Monitor)
+		}
+	}
+	sort.Ints(monitors)
+	labels := make([]string, len(monitors))
+	for i, m := range monitors {
+		labels[i] = monitorLabel(m)
+	}
+	line(i18n.T("review.total"), fmt.Sprintf("%d", len(t.Screenshots)))
+	if len(labels) > 0 {
+		line(i18n.T("summary.monitors"), strings.Join(labels, ", "))
+	}
+	if n := t.droppedFrames(); n > 0 {
+		line(i18n.T("review.dropped"), fmt.Sprintf("%d", n))
+	}
+	if len(t.Notes) > 0 {
+		line(i18n.T("report.notes"), fmt.Sprintf("%d", len(t.Notes)))
+	}
+	if len(t.Markers) > 0 {
+		line(i18n.T("review.markers"), fmt.Sprintf("%d", len(t.Markers)))
+	}
+
+	if text := t.summaryText(); text != "" {
+		s.WriteString("\n" + i18n.T("report.summary") + ":\n")
+		for _, l := range strings.Split(text, "\n") {
+			s.WriteString("  " + l + "\n")
+		}
+	}
+
+	s.WriteString("\n" + i18n.T("summary.files") + "\n")
+
+	next := i18n.T("summary.next")
+	s.WriteString("\n" + next + "\n" + strings.Repeat("-", len([]rune(next))) + "\n")
+	s.WriteString(i18n.T("summary.cwd", capturesDir) + "\n\n")
+	step := func(label, command string) {
+		s.WriteString(label + "\n  " + command + "\n")
+	}
+	step(i18n.T("summary.view"), "task-tracker view "+t.SessionID)
+	step(i18n.T("summary.analyze"), "task-tracker analyze "+t.SessionID)
+	step(i18n.T("summary.report"), "task-tracker report "+t.SessionID)
+	if t.Ticket != nil {
+		step(i18n.T("summary.commit"), fmt.Sprintf("task-tracker commit %s \"%s\"", t.SessionID, i18n.T("next.summary")))
+	}
+	step(i18n.T("summary.continue"), "task-tracker continue "+t.SessionID)
+	step(i18n.T("summary.export"), "task-tracker export "+t.SessionID)
+
+	s.WriteString("\n" + i18n.T("summary.written", Version, time.Now().Format("2006-01-02 15:04")) + "\n")
+
+	path := filepath.Join(t.SessionDir, summaryTextFile)
+	if err := os.WriteFile(path, []byte(s.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", summaryTextFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/ui"
+)
+
+// Marker flags an important moment in a session
+type Marker struct {
+	Timestamp    string  `json:"timestamp"`
+	RelativeTime float64 `json:"relative_time"`
+	Label        string  `json:"label,omitempty"`
+	Screenshots  []int   `json:"screenshots,omitempty"` // 1-based indices of flagged screenshots
+	Step         int     `json:"step,omitempty"`        // QA evidence step number, from 1
+}
+
+// addMarker flags the most recent screenshot of each monitor. If nothing
+// has been captured yet, the marker applies to the next capture.
+func (t *TaskTracker) addMarker(label string) Marker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	marker := Marker{
+		Timestamp:    time.Now().Format(time.RFC3339),
+		RelativeTime: time.Since(t.StartTime).Seconds(),
+		Label:        label,
+	}
+
+	seen := make(map[int]bool)
+	for i := len(t.Screenshots) - 1; i >= 0; i-- {
+		shot := &t.Screenshots[i]
+		if seen[shot.Monitor] {
+			break
+		}
+		seen[shot.Monitor] = true
+		shot.Marked = true
+		marker.Screenshots = append([]int{i + 1}, marker.Screenshots...)
+	}
+
+	t.Markers = append(t.Markers, marker)
+
+	if len(marker.Screenshots) == 0 {
+		ui.Printf("⭐ Marker set: %s (applies to next capture)\n", label)
+	} else {
+		ui.Printf("⭐ Marker set: %s\n", label)
+	}
+	return marker
+}
+
+// applyPendingMarkers flags the screenshots of a capture tick (starting at
+// index first) for markers set before anything was captured. Caller must
+// hold t.mu.
+func (t *TaskTracker) applyPendingMarkers(first int) {
+	for i := range t.Markers {
+		if len(t.Markers[i].Screenshots) > 0 {
+			continue
+		}
+		for idx := first; idx < len(t.Screenshots); idx++ {
+			// A step's frame must show the screen after it was taken
+			if t.Markers[i].Step > 0 && t.Screenshots[idx].RelativeTime < t.Markers[i].RelativeTime {
+				continue
+			}
+			t.Screenshots[idx].Marked = true
+			t.Markers[i].Screenshots = append(t.Markers[i].Screenshots, idx+1)
+		}
+	}
+}
+
+// writeMarkers renders the review file's markers section
+func writeMarkers(md *strings.Builder, markers []Marker) {
+	if len(markers) == 0 {
+		return
+	}
+
+	md.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("review.markers")))
+	for _, m := range markers {
+		label := m.Label
+		if label == "" {
+			label = "(no label)"
+		}
+		md.WriteString(fmt.Sprintf("- ⭐ **%.1f min** - %s", m.RelativeTime/60, label))
+		if len(m.Screenshots) > 0 {
+			nums := []string{}
+			for _, n := range m.Screenshots {
+				nums = append(nums, fmt.Sprintf("#%d", n))
+			}
+			md.WriteString(fmt.Sprintf(" (%s)", i18n.T("review.at_shot", strings.Join(nums, ", "))))
+		}
+		md.WriteString("\n")
+	}
+	md.WriteString("\n")
+}
+
+// newMarkCmd builds the mark command
+func newMarkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mark [label]",
+		Short: "Mark the current moment of the running session as important",
+		Long: `Flag the most recent screenshot of the running session as important.
+Marked screenshots are always included in the review file sample.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			label := ""
+			if len(args) > 0 {
+				label = args[0]
+			}
+
+			session, _ := cmd.Flags().GetString("session")
+			resp, err := sendControl(session, controlRequest{Command: "mark", Text: label})
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("⭐ %s\n", resp.Message)
+		},
+	}
+	addSessionFlag(cmd)
+	return cmd
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/imaging"
+	"task-tracker/internal/ui"
+)
+
+// MaskConfig hides windows in every frame: the terminal task-tracker runs
+// in, so sessions don't start and end with its own output, and windows
+// whose title matches one of the patterns
+type MaskConfig struct {
+	Own     bool     `json:"own"`
+	Windows []string `json:"windows,omitempty"` // regular expressions matched against titles
+}
+
+// Validate compiles the patterns
+func (m MaskConfig) Validate() error {
+	_, err := compileMasks(m.Windows)
+	return err
+}
+
+// compileMasks compiles window title patterns
+func compileMasks(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("mask: invalid window pattern '%s': %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// windowMask decides which windows are masked during a session
+type windowMask struct {
+	own      bool
+	patterns []*regexp.Regexp
+}
+
+// matches reports whether w is masked
+func (m *windowMask) matches(w capture.Window) bool {
+	if m.own && w.Own {
+		return true
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(w.Title) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskedRegions returns the visible parts of masked windows in desktop
+// coordinates. A masked window behind others only hides what isn't
+// covered, so the windows in front stay in the frame.
+func (t *TaskTracker) maskedRegions() []image.Rectangle {
+	lister, ok := t.Capturer.(capture.WindowLister)
+	if t.Mask == nil || !ok {
+		return nil
+	}
+	windows, ok := lister.Windows()
+	if !ok {
+		return nil
+	}
+
+	var regions []image.Rectangle
+	for i, w := range windows {
+		if !t.Mask.matches(w) {
+			continue
+		}
+		visible := []image.Rectangle{w.Bounds}
+		for _, above := range windows[i+1:] {
+			visible = subtractRect(visible, above.Bounds)
+		}
+		regions = append(regions, visible...)
+	}
+	return regions
+}
+
+// subtractRect removes cut from each rectangle, splitting those it
+// overlaps into the up to four pieces around it
+func subtractRect(rects []image.Rectangle, cut image.Rectangle) []image.Rectangle {
+	var out []image.Rectangle
+	for _, r := range rects {
+		if !r.Overlaps(cut) {
+			out = append(out, r)
+			continue
+		}
+		pieces := []image.Rectangle{
+			image.Rect(r.Min.X, r.Min.Y, r.Max.X, cut.Min.Y),                                 // above
+			image.Rect(r.Min.X, cut.Max.Y, r.Max.X, r.Max.Y),                                 // below
+			image.Rect(r.Min.X, max(r.Min.Y, cut.Min.Y), cut.Min.X, min(r.Max.Y, cut.Max.Y)), // left
+			image.Rect(cut.Max.X, max(r.Min.Y, cut.Min.Y), r.Max.X, min(r.Max.Y, cut.Max.Y)), // right
+		}
+		for _, p := range pieces {
+			if p = p.Intersect(r); !p.Empty() {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// maskFrame blanks regions in a frame captured from bounds
+func maskFrame(img *image.RGBA, regions []image.Rectangle, bounds image.Rectangle) {
+	for _, r := range regions {
+		if fr := capture.FrameRect(r, bounds, img.Bounds()); !fr.Empty() {
+			imaging.Mask(img, fr)
+		}
+	}
+}
+
+// setupMask combines the mask config with --mask-self and --mask-window
+func (t *TaskTracker) setupMask(cmd *cobra.Command, cfg MaskConfig) error {
+	if cmd.Flags().Changed("mask-self") {
+		cfg.Own, _ = cmd.Flags().GetBool("mask-self")
+	}
+	extra, _ := cmd.Flags().GetStringArray("mask-window")
+	cfg.Windows = append(cfg.Windows, extra...)
+
+	patterns, err := compileMasks(cfg.Windows)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if !cfg.Own && len(patterns) == 0 {
+		return nil
+	}
+	t.Mask = &windowMask{own: cfg.Own, patterns: patterns}
+
+	// Masking your own terminal is a nicety, but patterns are usually
+	// there to keep something private
+	lister, ok := t.Capturer.(capture.WindowLister)
+	if ok {
+		_, ok = lister.Windows()
+	}
+	if !ok && len(patterns) > 0 {
+		ui.Println("⚠️  Windows can't be listed on this desktop, so --mask-window and mask.windows have no effect")
+	} else if ok && len(patterns) > 0 {
+		ui.Printf("🙈 Masking windows matching %s\n", strings.Join(cfg.Windows, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"image"
+	"regexp"
+
+	"task-tracker/internal/imaging"
+)
+
+// screenChangeMin is the share of a frame that has to differ from the one
+// before on its monitor for the screen to count as changed
+const screenChangeMin = 0.01
+
+// Build and test commands, counted when the focused window's title starts
+// with one, as shells set terminal titles to the running command
+var (
+	buildPattern = regexp.MustCompile(`(?i)^\s*(sudo\s+)?(make|go build|cargo build|(npm|yarn|pnpm)( run)? build|\./gradlew (assemble|build)|gradle (assemble|build)|mvn (compile|package|install)|msbuild|dotnet build|cmake --build|ninja|bazel build|tsc|webpack)\b`)
+	testPattern  = regexp.MustCompile(`(?i)^\s*(sudo\s+)?(go test|cargo test|(npm|yarn|pnpm)( run)? test|pytest|python -m pytest|jest|vitest|mocha|mvn test|\./gradlew test|gradle test|dotnet test|rspec|phpunit|ctest|bazel test|tox|make (test|check))\b`)
+)
+
+// WorkMetrics are rate-of-work signals gathered while capturing, so trend
+// reports don't need to decode frames again. There's no OCR, so typed text
+// isn't estimated.
+type WorkMetrics struct {
+	Frames         int     `json:"frames"`         // frames compared with the one before
+	ScreenChanges  int     `json:"screen_changes"` // of those, frames where the screen changed
+	MeanChange     float64 `json:"mean_change"`    // average share of the screen changed, 0-1
+	ChangesPerHour float64 `json:"changes_per_hour"`
+	BuildRuns      int     `json:"build_runs"`
+	TestRuns       int     `json:"test_runs"`
+
+	changeSum float64
+}
+
+// observeFrame compares a frame with the last one of its monitor. It's
+// called from the writer before the frame is saved.
+func (t *TaskTracker) observeFrame(monitorIdx int, img image.Image) {
+	luma := imaging.NewLuma(img)
+	if t.lastLuma == nil {
+		t.lastLuma = make(map[int]*imaging.Luma)
+	}
+	prev := t.lastLuma[monitorIdx]
+	t.lastLuma[monitorIdx] = luma
+	if prev == nil {
+		return
+	}
+	change := imaging.Score(luma, prev).Change
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Metrics == nil {
+		t.Metrics = &WorkMetrics{}
+	}
+	t.Metrics.Frames++
+	t.Metrics.changeSum += change
+	if change >= screenChangeMin {
+		t.Metrics.ScreenChanges++
+	}
+}
+
+// observeWindow counts a build or test run when one shows in the focused
+// window's title and didn't on the last tick. It's called from the capture
+// loop.
+func (t *TaskTracker) observeWindow(title string) {
+	build, test := buildPattern.MatchString(title), testPattern.MatchString(title)
+	last := t.lastRunTitle
+	t.lastRunTitle = ""
+	if !build && !test {
+		return
+	}
+	t.lastRunTitle = title
+	if title == last {
+		return // the same run, still going
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Metrics == nil {
+		t.Metrics = &WorkMetrics{}
+	}
+	// A test command usually builds too, so it counts as a test run only
+	if test {
+		t.Metrics.TestRuns++
+	} else {
+		t.Metrics.BuildRuns++
+	}
+}
+
+// workMetrics returns the session's metrics with the averages and rates
+// worked out, or nil if none were gathered. Caller must hold t.mu.
+func (t *TaskTracker) workMetrics() *WorkMetrics {
+	if t.Metrics == nil {
+		return nil
+	}
+	m := *t.Metrics
+	if m.Frames > 0 && m.changeSum > 0 {
+		m.MeanChange = m.changeSum / float64(m.Frames)
+	}
+	if hours := t.activeDuration().Hours(); hours > 0 {
+		m.ChangesPerHour = float64(m.ScreenChanges) / hours
+	}
+	return &m
+}
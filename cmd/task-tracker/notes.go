@@ -0,0 +1,66 @@
+package main
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// Note is a timestamped comment added while a session is running
+type Note struct {
+	Timestamp    string  `json:"timestamp"`
+	RelativeTime float64 `json:"relative_time"`
+	Text         string  `json:"text"`
+}
+
+// addNote appends a note to the session timeline
+func (t *TaskTracker) addNote(text string) Note {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	note := Note{
+		Timestamp:    time.Now().Format(time.RFC3339),
+		RelativeTime: time.Since(t.StartTime).Seconds(),
+		Text:         text,
+	}
+	t.Notes = append(t.Notes, note)
+
+	ui.Printf("📝 Note: %s\n", text)
+	return note
+}
+
+// addEvent records something task-tracker did (rather than the user) in
+// the session timeline, without printing it
+func (t *TaskTracker) addEvent(text string) {
+	t.log(logInfo, "event", text)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.Notes = append(t.Notes, Note{
+		Timestamp:    time.Now().Format(time.RFC3339),
+		RelativeTime: time.Since(t.StartTime).Seconds(),
+		Text:         text,
+	})
+}
+
+// newNoteCmd builds the note command
+func newNoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "note [text]",
+		Short: "Add a timestamped note to the running session",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			session, _ := cmd.Flags().GetString("session")
+			resp, err := sendControl(session, controlRequest{Command: "note", Text: args[0]})
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("📝 %s\n", resp.Message)
+		},
+	}
+	addSessionFlag(cmd)
+	return cmd
+}
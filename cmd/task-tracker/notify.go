@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"task-tracker/internal/ui"
+)
+
+// Notification events
+const (
+	eventError   = "error"   // a monitor failed or was dropped from capture
+	eventDisk    = "disk"    // free disk space dropped below or recovered above a threshold
+	eventBattery = "battery" // capture was throttled for battery, or back to normal
+	eventStopped = "stopped" // a session stopped
+	eventSummary = "summary" // a session's summary was saved
+	eventDigest  = "digest"  // 'task-tracker digest --send' compiled a digest
+)
+
+var notifyEvents = []string{eventError, eventDisk, eventBattery, eventStopped, eventSummary, eventDigest}
+
+// Notification channel types
+const (
+	channelDesktop = "desktop"
+	channelSlack   = "slack"
+	channelTeams   = "teams"
+	channelEmail   = "email"
+)
+
+var channelTypes = []string{channelDesktop, channelSlack, channelTeams, channelEmail}
+
+// notifyTimeout bounds how long stopping waits for notifications still
+// being sent
+const notifyTimeout = 15 * time.Second
+
+// Notification is one message about a session
+type Notification struct {
+	Event   string
+	Session string
+	Title   string
+	Body    string
+}
+
+// Notifier delivers notifications on one channel
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// NotificationsConfig names channels and routes events to them, e.g.
+// errors to the desktop and summaries to Slack
+type NotificationsConfig struct {
+	Channels map[string]ChannelConfig `json:"channels,omitempty"`
+	Routes   []NotifyRoute            `json:"routes,omitempty"`
+}
+
+// ChannelConfig configures one channel. Email takes the SMTP password from
+// SMTP_PASSWORD.
+type ChannelConfig struct {
+	Type     string   `json:"type"`
+	URL      string   `json:"url,omitempty"`  // Slack or Teams incoming webhook
+	SMTP     string   `json:"smtp,omitempty"` // host:port
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+	Username string   `json:"username,omitempty"` // SMTP login; none sends unauthenticated
+}
+
+// NotifyRoute sends events ("*" for all) to channels
+type NotifyRoute struct {
+	Events   []string `json:"events"`
+	Channels []string `json:"channels"`
+}
+
+// Validate checks channels are complete and routes name known events and
+// channels
+func (c NotificationsConfig) Validate() error {
+	names := make([]string, 0, len(c.Channels))
+	for name := range c.Channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ch := c.Channels[name]
+		switch ch.Type {
+		case channelDesktop:
+		case channelSlack, channelTeams:
+			if ch.URL == "" {
+				return fmt.Errorf("notifications: channel '%s' needs the webhook url", name)
+			}
+		case channelEmail:
+			if ch.SMTP == "" || ch.From == "" || len(ch.To) == 0 {
+				return fmt.Errorf("notifications: channel '%s' needs smtp, from and to", name)
+			}
+		default:
+			return fmt.Errorf("notifications: channel '%s' has unknown type '%s' (use %s)", name, ch.Type, strings.Join(channelTypes, ", "))
+		}
+	}
+	for i, r := range c.Routes {
+		if len(r.Events) == 0 || len(r.Channels) == 0 {
+			return fmt.Errorf("notifications.routes[%d]: needs events and channels", i)
+		}
+		for _, e := range r.Events {
+			if e != "*" && !slices.Contains(notifyEvents, e) {
+				return fmt.Errorf("notifications.routes[%d]: unknown event '%s' (use %s or *)", i, e, strings.Join(notifyEvents, ", "))
+			}
+		}
+		for _, ch := range r.Channels {
+			if _, ok := c.Channels[ch]; !ok {
+				return fmt.Errorf("notifications.routes[%d]: no channel named '%s'", i, ch)
+			}
+		}
+	}
+	return nil
+}
+
+// notifier sends each event to the channels routed to it, in the
+// background
+type notifier struct {
+	channels map[string]Notifier
+	routes   []NotifyRoute
+	wg       sync.WaitGroup
+}
+
+// newNotifier returns nil when no routes are configured
+func newNotifier(cfg NotificationsConfig) *notifier {
+	if len(cfg.Routes) == 0 {
+		return nil
+	}
+	n := &notifier{channels: map[string]Notifier{}, routes: cfg.Routes}
+	client := &http.Client{Timeout: notifyTimeout, Transport: httpTransport()}
+	for name, ch := range cfg.Channels {
+		switch ch.Type {
+		case channelDesktop:
+			n.channels[name] = desktopNotifier{}
+		case channelSlack:
+			n.channels[name] = webhookNotifier{name: "Slack", url: ch.URL, http: client, format: slackText}
+		case channelTeams:
+			n.channels[name] = webhookNotifier{name: "Teams", url: ch.URL, http: client, format: teamsText}
+		case channelEmail:
+			n.channels[name] = emailNotifier{cfg: ch}
+		}
+	}
+	return n
+}
+
+// routed returns the channels routed to an event, each once
+func (n *notifier) routed(event string) []string {
+	var names []string
+	for _, r := range n.routes {
+		if !slices.Contains(r.Events, event) && !slices.Contains(r.Events, "*") {
+			continue
+		}
+		for _, name := range r.Channels {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// send delivers a notification on every channel routed to its event,
+// each channel once
+func (n *notifier) send(note Notification) {
+	if n == nil {
+		return
+	}
+	for _, name := range n.routed(note.Event) {
+		n.wg.Add(1)
+		go func(name string, ch Notifier) {
+			defer n.wg.Done()
+			if err := ch.Notify(note); err != nil {
+				ui.Printf("⚠️  Notification to %s failed: %v\n", name, err)
+			}
+		}(name, n.channels[name])
+	}
+}
+
+// deliver sends a notification on every channel routed to its event and
+// waits for them, for commands that report failures. It returns the
+// channels it was sent on.
+func (n *notifier) deliver(note Notification) ([]string, error) {
+	if n == nil {
+		return nil, nil
+	}
+	names := n.routed(note.Event)
+	var errs []error
+	for _, name := range names {
+		if err := n.channels[name].Notify(note); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return names, errors.Join(errs...)
+}
+
+// wait waits for notifications still being sent, up to notifyTimeout
+func (n *notifier) wait() {
+	if n == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(notifyTimeout):
+	}
+}
+
+// notify sends a notification about the session
+func (t *TaskTracker) notify(event, title, body string) {
+	t.Notifier.send(Notification{Event: event, Session: t.SessionID, Title: title, Body: body})
+}
+
+// desktopNotifier shows notifications with the platform's notification
+// tool: notify-send, osascript or PowerShell
+type desktopNotifier struct{}
+
+func (desktopNotifier) Notify(n Notification) error {
+	title := "task-tracker: " + n.Title
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", "on run argv", "-e",
+			"display notification (item 2 of argv) with title (item 1 of argv)", "-e", "end run", title, n.Body)
+	case "windows":
+		// Passed through the environment to spare quoting them for PowerShell
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", `
+Add-Type -AssemblyName System.Windows.Forms
+$icon = New-Object System.Windows.Forms.NotifyIcon
+$icon.Icon = [System.Drawing.SystemIcons]::Information
+$icon.Visible = $true
+$icon.ShowBalloonTip(10000, $env:TT_NOTIFY_TITLE, $env:TT_NOTIFY_BODY, 'Info')
+Start-Sleep -Seconds 10
+$icon.Dispose()`)
+		cmd.Env = append(os.Environ(), "TT_NOTIFY_TITLE="+title, "TT_NOTIFY_BODY="+n.Body)
+	default:
+		cmd = exec.Command("notify-send", "--app-name=task-tracker", title, n.Body)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %v: %s", cmd.Args[0], err, msg)
+		}
+		return fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	return nil
+}
+
+// webhookNotifier posts to a Slack or Teams incoming webhook
+type webhookNotifier struct {
+	name   string
+	url    string
+	http   *http.Client
+	format func(Notification) string
+}
+
+// slackText formats a notification in Slack's mrkdwn
+func slackText(n Notification) string {
+	return fmt.Sprintf("*%s* (%s)\n%s", n.Title, n.Session, n.Body)
+}
+
+// teamsText formats a notification in the markdown Teams webhooks render
+func teamsText(n Notification) string {
+	return fmt.Sprintf("**%s** (%s)\n\n%s", n.Title, n.Session, n.Body)
+}
+
+func (w webhookNotifier) Notify(n Notification) error {
+	body, err := json.Marshal(map[string]string{"text": w.format(n)})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent())
+	resp, err := w.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s not reachable: %v", errIntegration, w.name, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s webhook returned %s", errIntegration, w.name, resp.Status)
+	}
+	return nil
+}
+
+// emailNotifier sends notifications by SMTP
+type emailNotifier struct {
+	cfg ChannelConfig
+}
+
+func (e emailNotifier) Notify(n Notification) error {
+	var msg strings.Builder
+	msg.WriteString("From: " + e.cfg.From + "\r\n")
+	msg.WriteString("To: " + strings.Join(e.cfg.To, ", ") + "\r\n")
+	msg.WriteString(fmt.Sprintf("Subject: task-tracker: %s (%s)\r\n", n.Title, n.Session))
+	msg.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(n.Body, "\n", "\r\n") + "\r\n")
+
+	var auth smtp.Auth
+	if e.cfg.Username != "" {
+		host, _, _ := strings.Cut(e.cfg.SMTP, ":")
+		auth = smtp.PlainAuth("", e.cfg.Username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+	if err := smtp.SendMail(e.cfg.SMTP, auth, e.cfg.From, e.cfg.To, []byte(msg.String())); err != nil {
+		audit(auditUpload, "smtp://"+e.cfg.SMTP, "mail failed: "+err.Error(), n.Session)
+		return fmt.Errorf("%w: %v", errIntegration, err)
+	}
+	audit(auditUpload, "smtp://"+e.cfg.SMTP, "mail to "+strings.Join(e.cfg.To, ", "), n.Session)
+	return nil
+}
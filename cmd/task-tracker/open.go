@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// openExternally opens a file, directory or URL in the platform's default
+// application
+func openExternally(target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	// The viewer may outlive us; don't leave a zombie meanwhile
+	go cmd.Wait()
+	return nil
+}
+
+// newOpenCmd builds the open command
+func newOpenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open [session_id] [screenshot_num|last|gallery]",
+		Short: "Open a session folder, a screenshot or the gallery in the default app",
+		Long: `Open a saved session without copying paths around. With only a session
+(any reference: an ID, last, last-N, a prefix or a ticket key), its folder
+opens in the file manager. Add a 1-based screenshot number or last to open
+that screenshot in the default image viewer, or gallery to browse the
+session in the browser as 'task-tracker view' serves it.`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker := loadSessionOrExit(args[0])
+
+			target := tracker.SessionDir
+			if len(args) == 2 {
+				switch args[1] {
+				case "gallery":
+					port, _ := cmd.Flags().GetInt("port")
+					if err := serveViewer(tracker.SessionDir, port, true); err != nil {
+						ui.Printf("❌ %v\n", err)
+						exit(exitUsage)
+					}
+					return
+				case "last":
+					if len(tracker.Screenshots) == 0 {
+						ui.Printf("❌ Session %s has no screenshots\n", tracker.SessionID)
+						exit(exitUsage)
+					}
+					target = tracker.Screenshots[len(tracker.Screenshots)-1].Path
+				default:
+					num, err := strconv.Atoi(args[1])
+					if err != nil || num < 1 || num > len(tracker.Screenshots) {
+						ui.Printf("❌ Invalid screenshot number '%s' (1-%d, last or gallery)\n", args[1], len(tracker.Screenshots))
+						exit(exitUsage)
+					}
+					target = tracker.Screenshots[num-1].Path
+				}
+				if !fileExists(target) {
+					ui.Printf("❌ Screenshot file is missing: %s\n", target)
+					exit(exitError)
+				}
+			}
+
+			if abs, err := filepath.Abs(target); err == nil {
+				target = abs
+			}
+			if err := openExternally(target); err != nil {
+				ui.Printf("❌ Failed to open %s: %v\n", target, err)
+				exit(exitError)
+			}
+			ui.Printf("📂 Opened %s\n", target)
+		},
+	}
+
+	cmd.Flags().Int("port", 0, "Port to serve the gallery on (default: any free port)")
+	return cmd
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/imaging"
+	"task-tracker/internal/ui"
+)
+
+// optimizeFile shrinks a saved PNG in place and returns the bytes saved.
+// With pngquant it's lossy (quantized to 256 colors); otherwise lossless.
+func optimizeFile(path string, pngquant bool) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var out []byte
+	if pngquant {
+		cmd := exec.Command("pngquant", "--skip-if-larger", "--quality", "60-95", "-")
+		cmd.Stdin = bytes.NewReader(data)
+		out, err = cmd.Output()
+		// 98 and 99 mean the result was larger or below quality; keep the original
+		if exitErr, ok := err.(*exec.ExitError); ok && (exitErr.ExitCode() == 98 || exitErr.ExitCode() == 99) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("pngquant failed: %w", err)
+		}
+	} else if out, err = imaging.OptimizePNG(data); err != nil {
+		return 0, err
+	}
+
+	saved := int64(len(data) - len(out))
+	if saved <= 0 {
+		return 0, nil
+	}
+
+	// Write beside the original and rename, so a crash never leaves a
+	// truncated screenshot
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, out, 0644); err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+	return saved, nil
+}
+
+// optimizeScreenshots optimizes up to n screenshots that haven't been yet
+// and returns the bytes saved. It holds t.mu only between files.
+func (t *TaskTracker) optimizeScreenshots(n int, pngquant bool) (int64, error) {
+	var total int64
+	for i := 0; i < n; i++ {
+		t.mu.Lock()
+		if i >= len(t.Screenshots) {
+			t.mu.Unlock()
+			break
+		}
+		shot := t.Screenshots[i]
+		t.mu.Unlock()
+
+		// Blobs are shared, and rewriting one would invalidate the
+		// checksums other sessions recorded for it
+		if shot.Optimized || filepath.Ext(shot.Path) != ".png" || shot.Blob != "" {
+			continue
+		}
+
+		saved, err := optimizeFile(shot.Path, pngquant)
+		if err != nil {
+			return total, fmt.Errorf("failed to optimize %s: %w", shot.Path, err)
+		}
+
+		sum, err := fileSHA256(shot.Path)
+		if err != nil {
+			return total, err
+		}
+
+		t.mu.Lock()
+		t.Screenshots[i].Optimized = true
+		t.Screenshots[i].SHA256 = sum
+		t.BytesSaved += saved
+		t.mu.Unlock()
+		total += saved
+	}
+	return total, nil
+}
+
+// optimizeIdle optimizes frames captured before the current tick in the
+// background, skipping the tick if the previous run hasn't finished or the
+// machine is busy
+func (t *TaskTracker) optimizeIdle(upTo int) {
+	if t.machineBusy() || !t.optimizing.TryLock() {
+		return
+	}
+
+	t.optimizeWG.Add(1)
+	go func() {
+		defer t.optimizeWG.Done()
+		defer t.optimizing.Unlock()
+
+		if _, err := t.optimizeScreenshots(upTo, t.OptimizePNGQuant); err != nil {
+			ui.Printf("⚠️  %v\n", err)
+		}
+	}()
+}
+
+// formatBytes renders a byte count as B, KB, MB or GB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// dirSize sums the size of the regular files in dir
+func dirSize(dir string) int64 {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	var total int64
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && info.Mode().IsRegular() {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
+// newOptimizeCmd builds the optimize command
+func newOptimizeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "optimize [session_id...]",
+		Short: "Shrink saved screenshots",
+		Long: `Recompress the screenshots of saved sessions (all sessions if none are given)
+and report the space saved per session.
+
+By default optimization is lossless: PNGs are re-encoded at the best compression
+level, using a palette when a frame has 256 colors or fewer. With --pngquant the
+external pngquant tool quantizes frames (lossy, usually much smaller).
+
+Use 'task-tracker start --optimize' to optimize frames in the background while
+capturing.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			pngquant, _ := cmd.Flags().GetBool("pngquant")
+			if pngquant {
+				if _, err := exec.LookPath("pngquant"); err != nil {
+					ui.Println("❌ pngquant not found in PATH")
+					exit(exitUsage)
+				}
+			}
+
+			ids := args
+			if len(ids) == 0 {
+				sessions, err := listSessions()
+				if err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+				for _, s := range sessions {
+					ids = append(ids, s.SessionID)
+				}
+			}
+
+			table := ui.NewTable("Session", "Before", "After", "Saved")
+			var before, after int64
+			for _, id := range ids {
+				tracker, err := loadSession(id)
+				if err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+
+				size := dirSize(tracker.SessionDir)
+				saved, err := tracker.optimizeScreenshots(len(tracker.Screenshots), pngquant)
+				if err != nil {
+					ui.Printf("⚠️  %s: %v\n", tracker.SessionID, err)
+				}
+				if err := tracker.saveMetadata(); err != nil {
+					ui.Printf("❌ Failed to save metadata: %v\n", err)
+					exit(exitCode(err))
+				}
+
+				before += size
+				after += size - saved
+				table.AddRow(tracker.SessionID, formatBytes(size), formatBytes(size-saved), savedPercent(saved, size))
+			}
+
+			if len(ids) == 0 {
+				ui.Println("No sessions found.")
+				return
+			}
+			table.Render()
+			ui.Printf("\n✅ Saved %s of %s\n", savedPercent(before-after, before), formatBytes(before))
+		},
+	}
+
+	cmd.Flags().Bool("pngquant", false, "Use the external pngquant tool (lossy)")
+	return cmd
+}
+
+// savedPercent formats saved bytes as a share of total
+func savedPercent(saved, total int64) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%s (%.0f%%)", formatBytes(saved), float64(saved)*100/float64(total))
+}
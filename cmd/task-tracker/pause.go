@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/ui"
+)
+
+// Pause is a stretch of a session paused with 'task-tracker pause'. Unlike
+// privacy mode nothing is captured and the time isn't active time.
+type Pause struct {
+	Start           string  `json:"start"`
+	End             string  `json:"end"`
+	RelativeStart   float64 `json:"relative_start"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// setPaused pauses or resumes the session. Time up to a pause counts;
+// time until it's resumed doesn't. Pausing a paused session or resuming
+// one that isn't is an error.
+func (t *TaskTracker) setPaused(on bool) (string, error) {
+	now := time.Now()
+	if on {
+		// Checking and setting under one lock keeps two pause requests
+		// from both succeeding
+		t.mu.Lock()
+		if since := t.pausedAt; !since.IsZero() {
+			t.mu.Unlock()
+			return "", fmt.Errorf("already paused since %s", since.Format("15:04:05"))
+		}
+		t.tick(now)
+		t.pausedAt = now
+		t.mu.Unlock()
+
+		t.addEvent("Paused: capture and time stopped")
+		ui.Println("⏸️  Paused; 'task-tracker resume' continues")
+		return fmt.Sprintf("Paused at %s; capture and time are stopped until 'task-tracker resume'", now.Format("15:04:05")), nil
+	}
+
+	p, ok := t.endPause(now)
+	if !ok {
+		return "", fmt.Errorf("the session isn't paused")
+	}
+	d := time.Duration(p.DurationSeconds * float64(time.Second))
+	length := d.Round(time.Second).String()
+	if d >= time.Minute {
+		length = formatPlanned(d)
+	}
+	t.addEvent(fmt.Sprintf("Resumed after a %s pause", length))
+	ui.Printf("▶️  Resumed after %s\n", length)
+	return fmt.Sprintf("Resumed after %s", length), nil
+}
+
+// pausedSince returns when the session was paused, zero when it isn't
+func (t *TaskTracker) pausedSince() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.pausedAt
+}
+
+// endPause records the pause in progress as ending at now. The time since
+// the last tick isn't counted as active.
+func (t *TaskTracker) endPause(now time.Time) (Pause, bool) {
+	t.mu.Lock()
+	at := t.pausedAt
+	if at.IsZero() {
+		t.mu.Unlock()
+		return Pause{}, false
+	}
+	p := Pause{
+		Start:           at.Format(time.RFC3339),
+		End:             now.Format(time.RFC3339),
+		RelativeStart:   at.Sub(t.StartTime).Seconds(),
+		DurationSeconds: now.Sub(at).Seconds(),
+	}
+	t.Pauses = append(t.Pauses, p)
+	t.pausedAt = time.Time{}
+	t.mu.Unlock()
+
+	t.skipTick(now)
+	return p, true
+}
+
+// pauseSeconds totals the time the session was paused
+func (t *TaskTracker) pauseSeconds() float64 {
+	total := 0.0
+	for _, p := range t.Pauses {
+		total += p.DurationSeconds
+	}
+	return total
+}
+
+// pauseEntries lists pauses in the review timeline
+func (t *TaskTracker) pauseEntries() []timelineEntry {
+	var entries []timelineEntry
+	for _, p := range t.Pauses {
+		entries = append(entries, timelineEntry{
+			RelativeTime: p.RelativeStart,
+			Text: fmt.Sprintf("> ⏸️ **%s:** %s",
+				i18n.T("timeline.pause", p.RelativeStart/60, (p.RelativeStart+p.DurationSeconds)/60),
+				i18n.T("timeline.pause_why", p.DurationSeconds/60)),
+		})
+	}
+	return entries
+}
+
+// newPauseCmd builds the pause command
+func newPauseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pause",
+		Short: "Pause the running session: no screenshots and no time counted",
+		Long: `Pause the running session to step away or take a call you don't want
+recorded. Nothing is captured and the time isn't counted until
+'task-tracker resume'. Pauses are listed in metadata.json and the review
+timeline.
+
+To keep the clock running and only stop screenshots, use 'task-tracker
+privacy on' instead.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			session, _ := cmd.Flags().GetString("session")
+			resp, err := sendControl(session, controlRequest{Command: "pause"})
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("⏸️  %s\n", resp.Message)
+		},
+	}
+	addSessionFlag(cmd)
+	return cmd
+}
+
+// newResumeCmd builds the resume command
+func newResumeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume a paused session",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			session, _ := cmd.Flags().GetString("session")
+			resp, err := sendControl(session, controlRequest{Command: "resume"})
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("▶️  %s\n", resp.Message)
+		},
+	}
+	addSessionFlag(cmd)
+	return cmd
+}
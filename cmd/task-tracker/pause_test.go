@@ -0,0 +1,72 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPauseExcludedFromActiveTime(t *testing.T) {
+	now := time.Now()
+	tracker := &TaskTracker{StartTime: now.Add(-time.Minute), CaptureInterval: 30 * time.Second}
+
+	if _, err := tracker.setPaused(false); err == nil {
+		t.Error("resuming a running session succeeded, want an error")
+	}
+	if _, err := tracker.setPaused(true); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if _, err := tracker.setPaused(true); err == nil {
+		t.Error("pausing a paused session succeeded, want an error")
+	}
+
+	// Resume ten minutes later, then tick once more
+	resumed := now.Add(10 * time.Minute)
+	p, ok := tracker.endPause(resumed)
+	if !ok {
+		t.Fatal("endPause found no pause in progress")
+	}
+	if p.DurationSeconds < 9*60 {
+		t.Errorf("pause lasted %.0fs, want about ten minutes", p.DurationSeconds)
+	}
+	tracker.recordTick(resumed.Add(30 * time.Second))
+
+	// A minute before the pause and 30 seconds after it
+	if got := tracker.activeDuration(); got < 90*time.Second || got > 95*time.Second {
+		t.Errorf("activeDuration = %s, want about 1m30s", got)
+	}
+	if len(tracker.Pauses) != 1 {
+		t.Errorf("got %d pauses, want 1", len(tracker.Pauses))
+	}
+	if len(tracker.Gaps) != 0 {
+		t.Errorf("the pause was recorded as %d gap(s)", len(tracker.Gaps))
+	}
+	if _, err := tracker.setPaused(false); err == nil {
+		t.Error("resuming a resumed session succeeded, want an error")
+	}
+}
+
+func TestConcurrentPausesOneSucceeds(t *testing.T) {
+	tracker := &TaskTracker{StartTime: time.Now(), CaptureInterval: 30 * time.Second}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = tracker.setPaused(true)
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d concurrent pauses succeeded, want 1", succeeded)
+	}
+}
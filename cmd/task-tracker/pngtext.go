@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"task-tracker/internal/imaging"
+)
+
+// pngText describes a screenshot in PNG text chunks, so a file separated
+// from metadata.json still says which session and monitor it came from
+func (t *TaskTracker) pngText(monitorIdx int, resolution string, at time.Time) []imaging.PNGText {
+	text := []imaging.PNGText{
+		{Key: "Title", Value: t.TaskName},
+		{Key: "Software", Value: userAgent()},
+		{Key: "Creation Time", Value: at.Format(time.RFC1123Z)},
+		{Key: "task-tracker:session", Value: t.SessionID},
+		{Key: "task-tracker:monitor", Value: fmt.Sprintf("%d", monitorIdx+1)},
+		{Key: "task-tracker:resolution", Value: resolution},
+		{Key: "task-tracker:timestamp", Value: at.Format(time.RFC3339)},
+	}
+	if t.Ticket != nil {
+		text = append(text, imaging.PNGText{Key: "task-tracker:ticket", Value: t.Ticket.String()})
+	}
+	return text
+}
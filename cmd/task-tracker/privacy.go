@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// defaultPrivacyFor is how long privacy mode lasts when --for isn't given
+const defaultPrivacyFor = 15 * time.Minute
+
+// privacyForever marks privacy mode that lasts until turned off
+var privacyForever = time.Unix(1<<62, 0)
+
+// setPrivacy turns privacy mode on for d (0 means until turned off) or,
+// with on false, off. Timing continues while it's on; only screenshots stop.
+func (t *TaskTracker) setPrivacy(on bool, d time.Duration) string {
+	t.mu.Lock()
+	wasOn := !t.privateUntil.IsZero()
+	switch {
+	case !on:
+		t.privateUntil = time.Time{}
+	case d == 0:
+		t.privateUntil = privacyForever
+	default:
+		t.privateUntil = time.Now().Add(d)
+	}
+	until := t.privateUntil
+	t.mu.Unlock()
+
+	if !on {
+		if !wasOn {
+			return "Privacy mode was already off"
+		}
+		t.addEvent("Privacy mode off: screenshots resumed")
+		ui.Println("🔓 Privacy mode off, screenshots resumed")
+		return "Privacy mode off; screenshots resumed"
+	}
+
+	if until == privacyForever {
+		t.addEvent("Privacy mode on: screenshots paused")
+		ui.Println("🔒 Privacy mode on until turned off")
+		return "Privacy mode on until 'task-tracker privacy off'"
+	}
+	length := d.Round(time.Second).String()
+	if d >= time.Minute {
+		length = formatPlanned(d)
+	}
+	t.addEvent(fmt.Sprintf("Privacy mode on for %s: screenshots paused", length))
+	ui.Printf("🔒 Privacy mode on until %s\n", until.Format("15:04:05"))
+	return fmt.Sprintf("Privacy mode on until %s; screenshots resume automatically", until.Format("15:04:05"))
+}
+
+// private reports whether privacy mode is on at now, ending it once it
+// has expired
+func (t *TaskTracker) private(now time.Time) bool {
+	t.mu.Lock()
+	until := t.privateUntil
+	if until.IsZero() {
+		t.mu.Unlock()
+		return false
+	}
+	if now.Before(until) {
+		t.mu.Unlock()
+		return true
+	}
+	t.privateUntil = time.Time{}
+	t.mu.Unlock()
+
+	t.addEvent("Privacy mode expired: screenshots resumed")
+	ui.Println("🔓 Privacy mode expired, screenshots resumed")
+	return false
+}
+
+// newPrivacyCmd builds the privacy command
+func newPrivacyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "privacy",
+		Short: "Pause screenshots in the running session without stopping the clock",
+		Long: `Pause screenshots for a quick private interruption (banking, HR, personal
+messages) without ending the session. Time keeps counting, and screenshots
+resume on their own when the window runs out.
+
+There's no built-in global hotkey; bind 'task-tracker privacy on' to a keyboard
+shortcut in your desktop environment instead.`,
+	}
+
+	onCmd := &cobra.Command{
+		Use:   "on",
+		Short: "Pause screenshots, resuming automatically after --for",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			d, _ := cmd.Flags().GetDuration("for")
+			if d < 0 {
+				ui.Println("❌ --for can't be negative")
+				exit(exitUsage)
+			}
+
+			session, _ := cmd.Flags().GetString("session")
+			resp, err := sendControl(session, controlRequest{Command: "privacy", Text: "on", For: d.String()})
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("🔒 %s\n", resp.Message)
+		},
+	}
+	onCmd.Flags().Duration("for", defaultPrivacyFor, "How long to pause screenshots (0 = until 'privacy off')")
+
+	offCmd := &cobra.Command{
+		Use:   "off",
+		Short: "Resume screenshots now",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			session, _ := cmd.Flags().GetString("session")
+			resp, err := sendControl(session, controlRequest{Command: "privacy", Text: "off"})
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("🔓 %s\n", resp.Message)
+		},
+	}
+
+	addSessionFlag(cmd)
+	cmd.AddCommand(onCmd, offCmd)
+	return cmd
+}
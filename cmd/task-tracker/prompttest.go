@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// SummarizerConfig is the command that turns a prompt into a summary, e.g.
+// claude -p. The prompt goes to its stdin, or into a file whose path
+// replaces {prompt_file} in the arguments.
+type SummarizerConfig struct {
+	Command []string `json:"command,omitempty"`
+	Timeout Duration `json:"timeout"`
+}
+
+// defaultSummarizerTimeout bounds a single summarizer run
+const defaultSummarizerTimeout = 5 * time.Minute
+
+// Validate checks the timeout
+func (s SummarizerConfig) Validate() error {
+	if s.Timeout.Duration <= 0 {
+		return fmt.Errorf("summarizer.timeout must be positive")
+	}
+	return nil
+}
+
+// run sends prompt to the summarizer and returns its output
+func (s SummarizerConfig) run(prompt, dir string) (string, error) {
+	args := append([]string(nil), s.Command...)
+	viaFile := false
+	for i, arg := range args {
+		if strings.Contains(arg, "{prompt_file}") {
+			viaFile = true
+			args[i] = strings.ReplaceAll(arg, "{prompt_file}", filepath.Join(dir, "prompt.md"))
+		}
+	}
+	if viaFile {
+		if err := os.WriteFile(filepath.Join(dir, "prompt.md"), []byte(prompt), 0644); err != nil {
+			return "", err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.Timeout.Duration)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if !viaFile {
+		cmd.Stdin = strings.NewReader(prompt)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("%w: %s timed out after %s", errIntegration, args[0], s.Timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%v: %s", err, msg)
+		}
+		return "", fmt.Errorf("%w: %s failed: %v", errIntegration, args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// promptShot is a sampled screenshot as seen by prompt templates
+type promptShot struct {
+	Index   int
+	Minute  float64
+	Path    string // absolute, so the summarizer can open it from anywhere
+	Monitor string
+	Caption string
+	Marked  bool
+}
+
+// promptData is what prompt templates are executed with
+type promptData struct {
+	Session     string
+	Task        string
+	Ticket      string
+	Tags        []string
+	Minutes     float64
+	Review      string // absolute path of review.md
+	Screenshots []promptShot
+	Timeline    []string // notes, gaps and activity, as in the review
+}
+
+// promptData collects the template data for the sampled screenshots
+func (t *TaskTracker) promptData(shots []Screenshot) (promptData, error) {
+	review, _ := filepath.Abs(filepath.Join(t.SessionDir, "review.md"))
+	data := promptData{
+		Session: t.SessionID,
+		Task:    t.TaskName,
+		Ticket:  t.Ticket.String(),
+		Tags:    t.Tags,
+		Minutes: t.activeDuration().Minutes(),
+		Review:  review,
+	}
+	for i, shot := range shots {
+		path, err := t.viewablePath(shot)
+		if err != nil {
+			return data, err
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		data.Screenshots = append(data.Screenshots, promptShot{
+			Index:   i + 1,
+			Minute:  shot.RelativeTime / 60,
+			Path:    path,
+			Monitor: monitorLabel(shot.Monitor),
+			Caption: shot.Caption,
+			Marked:  shot.Marked,
+		})
+	}
+	for _, e := range t.timeline() {
+		data.Timeline = append(data.Timeline, strings.TrimPrefix(e.Text, "> "))
+	}
+	return data, nil
+}
+
+// promptResult is one template's run
+type promptResult struct {
+	Name     string
+	Output   string
+	Err      error
+	Duration time.Duration
+}
+
+// templateName names a template by its file, without the extension
+func templateName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// runPromptTest renders each template, runs it through the summarizer and
+// saves the prompt and output under dir
+func runPromptTest(s SummarizerConfig, data promptData, templates []string, dir string) ([]promptResult, error) {
+	results := make([]promptResult, 0, len(templates))
+	for _, path := range templates {
+		name := templateName(path)
+		tmpl, err := template.New(filepath.Base(path)).ParseFiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: template %s: %v", errUsage, path, err)
+		}
+		var prompt bytes.Buffer
+		if err := tmpl.Execute(&prompt, data); err != nil {
+			return nil, fmt.Errorf("%w: template %s: %v", errUsage, path, err)
+		}
+
+		runDir := filepath.Join(dir, name)
+		if err := os.MkdirAll(runDir, 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(runDir, "prompt.md"), prompt.Bytes(), 0644); err != nil {
+			return nil, err
+		}
+
+		ui.Printf("🧪 Running %s...\n", name)
+		audit(auditSend, strings.Join(s.Command, " "), fmt.Sprintf("prompt %s, %d screenshot(s)", name, len(data.Screenshots)), data.Session)
+		start := time.Now()
+		output, err := s.run(prompt.String(), runDir)
+		result := promptResult{Name: name, Output: output, Err: err, Duration: time.Since(start)}
+		if err != nil {
+			ui.Printf("⚠️  %s: %v\n", name, err)
+		} else if err := os.WriteFile(filepath.Join(runDir, "output.md"), []byte(output+"\n"), 0644); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// tableCell fits multi-line text into a markdown table cell
+func tableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(strings.TrimSpace(s), "\n", "<br>")
+}
+
+// writePromptComparison writes the outputs side by side, with the sampled
+// frames they were given
+func writePromptComparison(path string, data promptData, results []promptResult) error {
+	var md strings.Builder
+	md.WriteString(fmt.Sprintf("# Prompt Test: %s\n\n", data.Task))
+	md.WriteString(fmt.Sprintf("**Session:** %s  \n**Screenshots sampled:** %d\n\n", data.Session, len(data.Screenshots)))
+
+	header, rule, stats, outputs := "|", "|", "|", "|"
+	for _, r := range results {
+		header += " " + r.Name + " |"
+		rule += "---|"
+		stat := fmt.Sprintf("%.1fs, %d words", r.Duration.Seconds(), len(strings.Fields(r.Output)))
+		output := tableCell(r.Output)
+		if r.Err != nil {
+			stat = "failed"
+			output = "_" + tableCell(r.Err.Error()) + "_"
+		}
+		stats += " " + stat + " |"
+		outputs += " " + output + " |"
+	}
+	md.WriteString(header + "\n" + rule + "\n" + stats + "\n" + outputs + "\n\n")
+
+	if len(data.Screenshots) > 0 {
+		md.WriteString("## Sampled Frames\n\n")
+		for _, shot := range data.Screenshots {
+			md.WriteString(fmt.Sprintf("%d. %.1f min, %s: ![](%s)\n", shot.Index, shot.Minute, shot.Monitor, filepath.ToSlash(shot.Path)))
+		}
+	}
+	return os.WriteFile(path, []byte(md.String()), 0644)
+}
+
+// newPromptTestCmd builds the prompt-test command
+func newPromptTestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prompt-test [session_id] [template] [template]...",
+		Short: "Compare summarization prompt templates on the same screenshots",
+		Long: `Render two or more prompt templates for the same sampled screenshots, run each
+through the summarizer command from config (or --command) and write the outputs
+side by side to compare.md.
+
+Templates are Go text/template files. They get .Session, .Task, .Ticket, .Tags,
+.Minutes, .Review (path of review.md), .Timeline (notes, gaps and activity) and
+.Screenshots, each with .Index, .Minute, .Path, .Monitor, .Caption and .Marked:
+
+  Summarize the work on "{{.Task}}" from these screenshots:
+  {{range .Screenshots}}- {{printf "%.1f" .Minute}} min: {{.Path}}
+  {{end}}
+
+Every template sees the same screenshots, so differences come from the prompt.
+Each run's prompt and output are kept next to compare.md.`,
+		Args: cobra.MinimumNArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				exit(exitCode(err))
+			}
+
+			summarizer := cfg.Summarizer
+			if command, _ := cmd.Flags().GetString("command"); command != "" {
+				summarizer.Command = strings.Fields(command)
+			}
+			if len(summarizer.Command) == 0 {
+				ui.Println("❌ No summarizer configured (set summarizer.command in config or use --command)")
+				exit(exitUsage)
+			}
+
+			templates := args[1:]
+			seen := map[string]bool{}
+			for _, path := range templates {
+				if seen[templateName(path)] {
+					ui.Printf("❌ Two templates are named '%s'; rename one\n", templateName(path))
+					exit(exitUsage)
+				}
+				seen[templateName(path)] = true
+			}
+
+			samples, _ := cmd.Flags().GetInt("samples")
+			shots, _ := tracker.relevantScreenshots(samples, cfg.Review)
+			data, err := tracker.promptData(shots)
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			dir, _ := cmd.Flags().GetString("output")
+			if dir == "" {
+				dir = filepath.Join(tracker.SessionDir, "prompt_test", time.Now().Format("20060102_150405"))
+			}
+			results, err := runPromptTest(summarizer, data, templates, dir)
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			path := filepath.Join(dir, "compare.md")
+			if err := writePromptComparison(path, data, results); err != nil {
+				ui.Printf("❌ Failed to write comparison: %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("\n✅ Outputs side by side in %s\n", path)
+
+			failed := 0
+			for _, r := range results {
+				if r.Err != nil {
+					failed++
+				}
+			}
+			if failed == len(results) {
+				exit(exitIntegration)
+			}
+		},
+	}
+
+	cmd.Flags().Int("samples", 5, "Number of screenshots to sample for every template")
+	cmd.Flags().String("command", "", "Summarizer command, overriding summarizer.command in config (e.g. \"claude -p\")")
+	cmd.Flags().StringP("output", "o", "", "Directory for the results (default: prompt_test/<time> in the session)")
+	return cmd
+}
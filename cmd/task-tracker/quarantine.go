@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// releaseQuarantined moves quarantined frames (1-based) back into the
+// session's screenshots, in time order
+func (t *TaskTracker) releaseQuarantined(indices []int) error {
+	release := make(map[int]bool)
+	for _, idx := range indices {
+		if idx < 1 || idx > len(t.Quarantined) {
+			return fmt.Errorf("%w: quarantined frame %d out of range (1-%d)", errUsage, idx, len(t.Quarantined))
+		}
+		release[idx-1] = true
+	}
+
+	kept := []QuarantinedFrame{}
+	for i, q := range t.Quarantined {
+		if !release[i] {
+			kept = append(kept, q)
+			continue
+		}
+
+		path := filepath.Join(t.SessionDir, filepath.Base(q.Path))
+		if err := os.Rename(q.Path, path); err != nil {
+			return fmt.Errorf("failed to release %s: %w", q.Path, err)
+		}
+		sum, _ := fileSHA256(path)
+		t.insertScreenshot(Screenshot{
+			Path:         path,
+			SHA256:       sum,
+			Monitor:      q.Monitor,
+			Timestamp:    q.Timestamp,
+			RelativeTime: q.RelativeTime,
+			Resolution:   q.Resolution,
+		})
+	}
+	t.Quarantined = kept
+	return nil
+}
+
+// insertScreenshot adds a screenshot at its place in time, renumbering
+// the markers after it
+func (t *TaskTracker) insertScreenshot(shot Screenshot) {
+	pos := sort.Search(len(t.Screenshots), func(i int) bool {
+		return t.Screenshots[i].RelativeTime > shot.RelativeTime
+	})
+	t.Screenshots = append(t.Screenshots[:pos], append([]Screenshot{shot}, t.Screenshots[pos:]...)...)
+
+	// Markers refer to screenshots by index
+	for i := range t.Markers {
+		for j, n := range t.Markers[i].Screenshots {
+			if n > pos {
+				t.Markers[i].Screenshots[j] = n + 1
+			}
+		}
+	}
+}
+
+// newQuarantineCmd builds the quarantine command
+func newQuarantineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quarantine",
+		Short: "Inspect frames the privacy classifier withheld",
+		Long: `Frames the privacy classifier flags as sensitive are kept in the session's
+quarantine/ directory instead of becoming screenshots, so reviews, exports and
+uploads never include them. Release false positives back into the session, or
+purge the rest once you've checked them.`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list [session_id]",
+		Short: "List quarantined frames",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker := loadSessionOrExit(args[0])
+			if len(tracker.Quarantined) == 0 {
+				ui.Println("✅ Nothing quarantined")
+				return
+			}
+
+			table := ui.NewTable("#", "Minute", "Monitor", "Label", "Score", "File")
+			for i, q := range tracker.Quarantined {
+				table.AddRow(
+					strconv.Itoa(i+1),
+					fmt.Sprintf("%.1f", q.RelativeTime/60),
+					strconv.Itoa(q.Monitor),
+					q.Label,
+					fmt.Sprintf("%.2f", q.Score),
+					q.Path,
+				)
+			}
+			ui.Println()
+			table.Render()
+		},
+	}
+
+	releaseCmd := &cobra.Command{
+		Use:   "release [session_id] [num]...",
+		Short: "Move quarantined frames back into the session",
+		Args:  cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker := loadSessionOrExit(args[0])
+
+			var indices []int
+			for _, arg := range args[1:] {
+				n, err := strconv.Atoi(arg)
+				if err != nil {
+					ui.Printf("❌ Invalid frame number '%s'\n", arg)
+					exit(exitUsage)
+				}
+				indices = append(indices, n)
+			}
+
+			if err := tracker.releaseQuarantined(indices); err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			if err := tracker.saveMetadata(); err != nil {
+				ui.Printf("❌ Failed to save metadata: %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("🔓 Released %d frame(s) into the session\n", len(indices))
+			if fileExists(filepath.Join(tracker.SessionDir, "review.md")) {
+				ui.Printf("💡 Run 'task-tracker analyze %s' to include them in the review\n", tracker.SessionID)
+			}
+		},
+	}
+
+	purgeCmd := &cobra.Command{
+		Use:   "purge [session_id]",
+		Short: "Delete all quarantined frames",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker := loadSessionOrExit(args[0])
+			for _, q := range tracker.Quarantined {
+				if err := os.Remove(q.Path); err != nil && !os.IsNotExist(err) {
+					ui.Printf("❌ Failed to delete %s: %v\n", q.Path, err)
+					exit(exitCode(err))
+				}
+				audit(auditDelete, q.Path, "quarantined frame purged", tracker.SessionID)
+			}
+			n := len(tracker.Quarantined)
+			tracker.Quarantined = nil
+			if err := tracker.saveMetadata(); err != nil {
+				ui.Printf("❌ Failed to save metadata: %v\n", err)
+				exit(exitCode(err))
+			}
+			os.Remove(filepath.Join(tracker.SessionDir, quarantineDir))
+			ui.Printf("🗑️  Deleted %d quarantined frame(s)\n", n)
+		},
+	}
+
+	cmd.AddCommand(listCmd, releaseCmd, purgeCmd)
+	return cmd
+}
+
+// loadSessionOrExit loads a session, exiting with its error
+func loadSessionOrExit(ref string) *TaskTracker {
+	tracker, err := loadSession(ref)
+	if err != nil {
+		ui.Printf("❌ %v\n", err)
+		exit(exitCode(err))
+	}
+	return tracker
+}
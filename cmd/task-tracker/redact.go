@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/imaging"
+	"task-tracker/internal/ui"
+)
+
+// Region is a rectangle of a frame in image pixels
+type Region struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+func (r Region) rect() image.Rectangle {
+	return image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H)
+}
+
+// redactedDir holds copies of frames with their redactions applied, which
+// the review uses instead of the originals
+const redactedDir = "redacted"
+
+// redact pixelates the regions of img in place
+func redact(img *image.RGBA, regions []Region) {
+	for _, r := range regions {
+		imaging.Redact(img, r.rect())
+	}
+}
+
+// redactedPath is where the redacted copy of a frame is kept. The name
+// differs from the original's so tiles cut from it aren't mixed up.
+func (t *TaskTracker) redactedPath(shot Screenshot) string {
+	base := strings.TrimSuffix(filepath.Base(shot.Path), filepath.Ext(shot.Path))
+	return filepath.Join(t.SessionDir, redactedDir, base+"_redacted.png")
+}
+
+// redactedFrame encodes a frame with its redactions applied
+func redactedFrame(path string, regions []Region) ([]byte, error) {
+	img, err := loadFrame(path)
+	if err != nil {
+		return nil, err
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	copy(rgba.Pix, imaging.ToRGBA(img).Pix)
+	redact(rgba, regions)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, rgba); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// setRedactions replaces a screenshot's redactions (0-based index) and
+// drops copies made for the old ones
+func (t *TaskTracker) setRedactions(i int, regions []Region) {
+	shot := t.Screenshots[i]
+	redacted := t.redactedPath(shot)
+	os.Remove(redacted)
+	base := strings.TrimSuffix(filepath.Base(redacted), ".png")
+	if stale, err := filepath.Glob(filepath.Join(t.SessionDir, tilesDir, base+"_*")); err == nil {
+		for _, path := range stale {
+			os.Remove(path)
+		}
+	}
+	t.Screenshots[i].Redact = regions
+}
+
+//go:embed redact.html
+var redactPage string
+
+// redactFrameInfo describes a frame to the redaction page
+type redactFrameInfo struct {
+	Index   int      `json:"index"` // 1-based screenshot number
+	Minute  float64  `json:"minute"`
+	Monitor string   `json:"monitor"`
+	Caption string   `json:"caption,omitempty"`
+	Regions []Region `json:"regions"`
+}
+
+// redactionServer serves the redaction page for one session
+type redactionServer struct {
+	mu      sync.Mutex
+	tracker *TaskTracker
+	shots   []int // 0-based indexes of the frames on the page
+	token   string
+	done    chan struct{}
+	once    sync.Once
+}
+
+func (s *redactionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The token keeps other local users and web pages from reading frames
+	if r.URL.Query().Get("token") != s.token {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, strings.ReplaceAll(redactPage, "{{TASK}}", htmlEscape(s.tracker.TaskName)))
+
+	case r.URL.Path == "/frames":
+		frames := make([]redactFrameInfo, 0, len(s.shots))
+		for _, i := range s.shots {
+			shot := s.tracker.Screenshots[i]
+			regions := shot.Redact
+			if regions == nil {
+				regions = []Region{}
+			}
+			frames = append(frames, redactFrameInfo{
+				Index:   i + 1,
+				Minute:  shot.RelativeTime / 60,
+				Monitor: monitorLabel(shot.Monitor),
+				Caption: shot.Caption,
+				Regions: regions,
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(frames)
+
+	case strings.HasPrefix(r.URL.Path, "/frame/"):
+		n, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/frame/"))
+		if err != nil || n < 1 || n > len(s.tracker.Screenshots) {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodPost {
+			s.saveRegions(w, r, n-1)
+			return
+		}
+
+		// The original, so earlier redactions can be seen and changed
+		path, err := s.tracker.reconstructedPath(s.tracker.Screenshots[n-1])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		http.ServeFile(w, r, path)
+
+	case r.URL.Path == "/done" && r.Method == http.MethodPost:
+		w.WriteHeader(http.StatusNoContent)
+		s.once.Do(func() { close(s.done) })
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// saveRegions stores the regions posted for a screenshot
+func (s *redactionServer) saveRegions(w http.ResponseWriter, r *http.Request, i int) {
+	var regions []Region
+	if err := json.NewDecoder(r.Body).Decode(&regions); err != nil {
+		http.Error(w, "invalid regions: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	kept := regions[:0]
+	for _, rg := range regions {
+		if rg.W > 0 && rg.H > 0 {
+			kept = append(kept, rg)
+		}
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+
+	s.tracker.setRedactions(i, kept)
+	if err := s.tracker.saveMetadata(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ui.Printf("🖍️  Screenshot %d: %d region(s) redacted\n", i+1, len(kept))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// htmlEscape escapes text for the page
+func htmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
+
+// newReviewRedactionsCmd builds the review-redactions command
+func newReviewRedactionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review-redactions [session_id]",
+		Short: "Blur regions of sampled frames in a local web page before sharing",
+		Long: `Serve a local web page showing the frames the review would sample. Drag
+over anything that shouldn't be shared to blur it, click a blurred region to
+remove it, then press Done.
+
+Redactions are saved in the session's metadata and applied to the review file,
+prompt tests and export archives; the original frames are left untouched. The
+page is only reachable from this machine, through the link printed here.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			if len(tracker.Screenshots) == 0 {
+				ui.Println("❌ Session has no screenshots")
+				exit(exitUsage)
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				exit(exitCode(err))
+			}
+
+			var shots []Screenshot
+			if all, _ := cmd.Flags().GetBool("all"); all {
+				shots = tracker.Screenshots
+			} else {
+				samples, _ := cmd.Flags().GetInt("samples")
+				shots, _ = tracker.relevantScreenshots(samples, cfg.Review)
+			}
+			index := make(map[string]int, len(tracker.Screenshots))
+			for i, shot := range tracker.Screenshots {
+				index[shot.Path] = i
+			}
+
+			token := make([]byte, 16)
+			rand.Read(token)
+			srv := &redactionServer{tracker: tracker, token: hex.EncodeToString(token), done: make(chan struct{})}
+			for _, shot := range shots {
+				srv.shots = append(srv.shots, index[shot.Path])
+			}
+
+			port, _ := cmd.Flags().GetInt("port")
+			listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+			if err != nil {
+				ui.Printf("❌ Failed to listen: %v\n", err)
+				exit(exitUsage)
+			}
+			server := &http.Server{Handler: srv}
+			go server.Serve(listener)
+
+			ui.Printf("🖍️  Redact %d frame(s) at http://%s/?token=%s\n", len(srv.shots), listener.Addr(), srv.token)
+			ui.Println("   Press Done on the page, or Ctrl+C here, when finished")
+
+			interrupt := make(chan os.Signal, 1)
+			signal.Notify(interrupt, os.Interrupt)
+			select {
+			case <-srv.done:
+			case <-interrupt:
+			}
+			server.Shutdown(context.Background())
+
+			redacted := 0
+			for _, shot := range tracker.Screenshots {
+				if len(shot.Redact) > 0 {
+					redacted++
+				}
+			}
+			ui.Printf("\n✅ %d frame(s) have redactions\n", redacted)
+
+			if fileExists(filepath.Join(tracker.SessionDir, "review.md")) {
+				if err := tracker.GenerateReviewFile(5); err != nil {
+					ui.Printf("⚠️  Failed to regenerate review file: %v\n", err)
+				}
+			}
+		},
+	}
+
+	cmd.Flags().Int("samples", 5, "Number of frames to show, sampled like the review")
+	cmd.Flags().Bool("all", false, "Show every frame instead of a sample")
+	cmd.Flags().Int("port", 0, "Port to serve on (default: any free port)")
+	return cmd
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"task-tracker/internal/imaging"
+)
+
+// candidatesPerSlot is how many frames are scored for each one the review
+// shows, so dropped frames leave room for others
+const candidatesPerSlot = 2
+
+// relevantScreenshots samples count screenshots like sampleScreenshots, but
+// first drops low-value frames: blank desktops and screensavers (too little
+// detail) and repeats of the frame before (too little change). Marked
+// frames and clipboard images are always kept. It also returns how many
+// frames were dropped.
+func (t *TaskTracker) relevantScreenshots(count int, cfg ReviewConfig) ([]Screenshot, int) {
+	if cfg.MinDetail <= 0 && cfg.MinChange <= 0 {
+		return t.sampleScreenshots(count), 0
+	}
+
+	candidates := t.sampleScreenshots(count * candidatesPerSlot)
+	kept := make([]Screenshot, 0, len(candidates))
+	last := map[int]*imaging.Luma{} // last kept frame per monitor
+	for _, shot := range candidates {
+		img, err := loadFrame(shot.Path)
+		if err != nil {
+			// Unreadable frames are left for the review to show as they are
+			kept = append(kept, shot)
+			continue
+		}
+
+		luma := imaging.NewLuma(img)
+		score := imaging.Score(luma, last[shot.Monitor])
+		if !shot.Marked && shot.Source != sourceClipboard && (score.Detail < cfg.MinDetail || score.Change < cfg.MinChange) {
+			continue
+		}
+		kept = append(kept, shot)
+		last[shot.Monitor] = luma
+	}
+
+	// Nothing stood out, e.g. a session of a mostly idle screen: show it
+	// as it is rather than not at all
+	if len(kept) == 0 {
+		return t.sampleScreenshots(count), 0
+	}
+	return sampleEvenly(kept, count), len(candidates) - len(kept)
+}
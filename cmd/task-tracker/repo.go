@@ -0,0 +1,406 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// codeownersPaths are where GitHub, GitLab and Bitbucket look for
+// CODEOWNERS, relative to the repository root
+var codeownersPaths = []string{"CODEOWNERS", ".github/CODEOWNERS", ".gitlab/CODEOWNERS", "docs/CODEOWNERS", ".bitbucket/CODEOWNERS"}
+
+// RepoConfig maps paths of a monorepo to components. Rules are tried in
+// order before CODEOWNERS.
+type RepoConfig struct {
+	Components []ComponentRule `json:"components,omitempty"`
+}
+
+// ComponentRule names the component of the files matching any of its
+// CODEOWNERS-style path patterns
+type ComponentRule struct {
+	Name   string   `json:"name"`
+	Paths  []string `json:"paths"`
+	Owners []string `json:"owners,omitempty"` // default: the files' CODEOWNERS
+}
+
+// Validate checks every rule has a name and paths
+func (c RepoConfig) Validate() error {
+	for i, r := range c.Components {
+		if r.Name == "" {
+			return fmt.Errorf("repo.components[%d]: name is required", i)
+		}
+		if len(r.Paths) == 0 {
+			return fmt.Errorf("repo.components: '%s' has no paths", r.Name)
+		}
+		for _, p := range r.Paths {
+			if _, err := compileOwnersPattern(p); err != nil {
+				return fmt.Errorf("repo.components: '%s': %w", r.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RepoActivity is what a session changed in the git repository it was
+// started with --repo on
+type RepoActivity struct {
+	Path        string           `json:"path"` // repository root
+	StartCommit string           `json:"start_commit"`
+	Files       int              `json:"files"` // changed during the session
+	Components  []ComponentTouch `json:"components,omitempty"`
+
+	// Files already changed from StartCommit when the session started, by
+	// blob hash, so only changes made during it count
+	baseline map[string]string
+}
+
+// ComponentTouch is a component the session changed files in
+type ComponentTouch struct {
+	Name   string   `json:"name"`
+	Owners []string `json:"owners,omitempty"`
+	Files  int      `json:"files"`
+}
+
+// git runs git in the repository at root and returns its output
+func git(root string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// openRepo records the repository containing dir as it is at the start of
+// a session
+func openRepo(dir string) (*RepoActivity, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("%w: --repo needs git in PATH", errUsage)
+	}
+	out, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s isn't in a git repository: %v", errUsage, dir, err)
+	}
+	r := &RepoActivity{Path: strings.TrimSpace(string(out))}
+	if out, err = git(r.Path, "rev-parse", "HEAD"); err != nil {
+		return nil, fmt.Errorf("%w: %s has no commits yet", errUsage, r.Path)
+	}
+	r.StartCommit = strings.TrimSpace(string(out))
+	if r.baseline, err = r.changedFiles(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// changedFiles returns the files that differ from StartCommit, committed
+// or not, and untracked ones, with their blob hashes ("" if deleted)
+func (r *RepoActivity) changedFiles() (map[string]string, error) {
+	tracked, err := git(r.Path, "diff", "--name-only", "--no-renames", "-z", r.StartCommit, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git(r.Path, "ls-files", "-z", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]string{}
+	var existing []string
+	for _, f := range strings.Split(string(tracked)+string(untracked), "\x00") {
+		if f == "" {
+			continue
+		}
+		files[f] = ""
+		if _, err := os.Lstat(filepath.Join(r.Path, f)); err == nil {
+			existing = append(existing, f)
+		}
+	}
+	if len(existing) == 0 {
+		return files, nil
+	}
+
+	cmd := exec.Command("git", "-C", r.Path, "hash-object", "--stdin-paths")
+	cmd.Stdin = strings.NewReader(strings.Join(existing, "\n") + "\n")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git hash-object: %w", err)
+	}
+	hashes := strings.Fields(string(out))
+	if len(hashes) != len(existing) {
+		return nil, fmt.Errorf("git hash-object: hashed %d of %d files", len(hashes), len(existing))
+	}
+	for i, f := range existing {
+		files[f] = hashes[i]
+	}
+	return files, nil
+}
+
+// detect finds the files changed since the session started and maps them
+// to components by rules, then CODEOWNERS, falling back to the top-level
+// directory for files only a wildcard owns. Files neither covers only count
+// towards Files.
+func (r *RepoActivity) detect(rules []ComponentRule) error {
+	now, err := r.changedFiles()
+	if err != nil {
+		return err
+	}
+	owners, err := loadCodeowners(r.Path)
+	if err != nil {
+		return err
+	}
+	compiled := make([][]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		for _, p := range rule.Paths {
+			re, err := compileOwnersPattern(p)
+			if err != nil {
+				return err
+			}
+			compiled[i] = append(compiled[i], re)
+		}
+	}
+
+	touched := map[string]*ComponentTouch{}
+	r.Files = 0
+	for f, hash := range now {
+		if before, ok := r.baseline[f]; ok && before == hash {
+			continue // changed before the session and not since
+		}
+		r.Files++
+
+		entry := owners.match(f)
+		var c ComponentTouch
+	rules:
+		for i, rule := range rules {
+			for _, re := range compiled[i] {
+				if re.MatchString(f) {
+					c = ComponentTouch{Name: rule.Name, Owners: rule.Owners}
+					break rules
+				}
+			}
+		}
+		if c.Name == "" && entry != nil {
+			if c.Name = entry.component(); c.Name == "" {
+				c.Name = topLevel(f)
+			}
+		}
+		if c.Name == "" {
+			continue
+		}
+		if c.Owners == nil && entry != nil {
+			c.Owners = entry.owners
+		}
+
+		if t := touched[c.Name]; t != nil {
+			t.Files++
+			t.Owners = mergeOwners(t.Owners, c.Owners)
+		} else {
+			c.Files = 1
+			c.Owners = append([]string(nil), c.Owners...)
+			touched[c.Name] = &c
+		}
+	}
+
+	r.Components = r.Components[:0]
+	for _, c := range touched {
+		r.Components = append(r.Components, *c)
+	}
+	sortComponents(r.Components)
+	return nil
+}
+
+// sortComponents orders components by files changed, most first
+func sortComponents(components []ComponentTouch) {
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Files != components[j].Files {
+			return components[i].Files > components[j].Files
+		}
+		return components[i].Name < components[j].Name
+	})
+}
+
+// mergeOwners adds the owners in more that owners doesn't have yet
+func mergeOwners(owners, more []string) []string {
+	for _, o := range more {
+		found := false
+		for _, have := range owners {
+			found = found || have == o
+		}
+		if !found {
+			owners = append(owners, o)
+		}
+	}
+	return owners
+}
+
+// formatComponents lists components with their owners, e.g.
+// "services/billing (@acme/billing), web"
+func formatComponents(components []ComponentTouch) string {
+	parts := make([]string, 0, len(components))
+	for _, c := range components {
+		if len(c.Owners) > 0 {
+			parts = append(parts, fmt.Sprintf("%s (%s)", c.Name, strings.Join(c.Owners, ", ")))
+		} else {
+			parts = append(parts, c.Name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// components returns what the session changed in its repository, or nil
+func (t *TaskTracker) components() []ComponentTouch {
+	if t.Repo == nil {
+		return nil
+	}
+	return t.Repo.Components
+}
+
+// codeownersEntry is one line of a CODEOWNERS file
+type codeownersEntry struct {
+	pattern string
+	re      *regexp.Regexp
+	owners  []string
+}
+
+// component names the component of the files the entry covers after its
+// pattern, e.g. "services/billing" for "/services/billing/". Patterns that
+// are only wildcards, like "*" or "*.md", don't name one.
+func (e *codeownersEntry) component() string {
+	name := strings.Trim(strings.TrimSuffix(strings.TrimSuffix(e.pattern, "/**"), "/*"), "/")
+	if !strings.Contains(name, "/") && strings.ContainsAny(name, "*?") {
+		return ""
+	}
+	return name
+}
+
+// topLevel names the component of a file nothing else names after its
+// top-level directory
+func topLevel(path string) string {
+	if dir, _, ok := strings.Cut(path, "/"); ok {
+		return dir
+	}
+	return "(root)"
+}
+
+// codeowners is a parsed CODEOWNERS file
+type codeowners []*codeownersEntry
+
+// match returns the entry for path: the last matching one, as on GitHub
+func (c codeowners) match(path string) *codeownersEntry {
+	for i := len(c) - 1; i >= 0; i-- {
+		if c[i].re.MatchString(path) {
+			return c[i]
+		}
+	}
+	return nil
+}
+
+// loadCodeowners reads the repository's CODEOWNERS, if it has one.
+// GitLab sections ("[Docs]") and optional-section markers are skipped.
+func loadCodeowners(root string) (codeowners, error) {
+	for _, p := range codeownersPaths {
+		data, err := os.ReadFile(filepath.Join(root, p))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var entries codeowners
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if i := strings.Index(line, " #"); i >= 0 {
+				line = strings.TrimSpace(line[:i])
+			}
+			if line == "" || line[0] == '#' || line[0] == '[' || line[0] == '^' {
+				continue
+			}
+			fields := strings.Fields(line)
+			re, err := compileOwnersPattern(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", p, err)
+			}
+			entries = append(entries, &codeownersEntry{pattern: fields[0], re: re, owners: fields[1:]})
+		}
+		return entries, nil
+	}
+	return nil, nil
+}
+
+// compileOwnersPattern turns a CODEOWNERS (gitignore-style) pattern into
+// a regular expression matching slash-separated paths relative to the
+// repository root. Patterns with a slash other than at the end are anchored
+// to the root; others match at any depth. A pattern matching a directory
+// matches everything in it.
+func compileOwnersPattern(pattern string) (*regexp.Regexp, error) {
+	p := pattern
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("invalid path pattern '%s'", pattern)
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	if dirOnly {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
+
+// mergedComponents adds up the components the sessions touched
+func mergedComponents(sessions []*TaskTracker) []ComponentTouch {
+	var merged []ComponentTouch
+	index := map[string]int{}
+	for _, t := range sessions {
+		for _, c := range t.components() {
+			if i, ok := index[c.Name]; ok {
+				merged[i].Files += c.Files
+				merged[i].Owners = mergeOwners(merged[i].Owners, c.Owners)
+				continue
+			}
+			index[c.Name] = len(merged)
+			c.Owners = append([]string(nil), c.Owners...)
+			merged = append(merged, c)
+		}
+	}
+	sortComponents(merged)
+	return merged
+}
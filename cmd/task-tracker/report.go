@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/ui"
+)
+
+// reportFile is where 'report -o session' saves a session's report
+const reportFile = "report.md"
+
+// GenerateReport renders a human-readable markdown report of a finished
+// session, built from metadata and the saved summary
+func (t *TaskTracker) GenerateReport() string {
+	var md strings.Builder
+	md.WriteString(fmt.Sprintf("# %s\n\n", t.TaskName))
+
+	md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("report.session"), t.SessionID))
+	if t.Ticket != nil {
+		ticket := t.Ticket.String()
+		if t.Ticket.URL != "" {
+			ticket = fmt.Sprintf("[%s](%s)", ticket, t.Ticket.URL)
+		}
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("report.ticket"), ticket))
+	}
+	if len(t.Tags) > 0 {
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("report.tags"), strings.Join(t.Tags, ", ")))
+	}
+	md.WriteString(fmt.Sprintf("- **%s:** %s - %s\n", i18n.T("report.when"),
+		t.StartTime.Local().Format("2006-01-02 15:04"), t.EndTime.Local().Format("15:04")))
+
+	active := t.activeDuration()
+	md.WriteString(fmt.Sprintf("- **%s:** %s", i18n.T("report.time"), formatTimeSpent(active)))
+	if billed := t.Rounding.Apply(active); billed != active {
+		md.WriteString(fmt.Sprintf(" (%s)", i18n.T("report.billable", formatTimeSpent(billed))))
+	}
+	md.WriteString("\n")
+	if t.Parent != "" {
+		chain := sessionChain(t)
+		total := active
+		for _, s := range chain {
+			total += s.activeDuration()
+		}
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("report.task_total"),
+			i18n.T("report.across", formatTimeSpent(total), len(chain)+1)))
+	}
+	if delta := t.estimateDelta(); t.Ticket != nil && delta != "" {
+		_, sessions := ticketTotal(t.Ticket)
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("report.estimate"), i18n.T("report.estimate_of",
+			delta, sessions, t.Ticket, formatTimeSpent(time.Duration(t.Estimate.RemainingSeconds)*time.Second))))
+	}
+	md.WriteString(fmt.Sprintf("- **%s:** %d\n", i18n.T("report.screenshots"), len(t.Screenshots)))
+	if components := t.components(); len(components) > 0 {
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("report.components"), formatComponents(components)))
+	}
+	if m := t.Metrics; m != nil {
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("report.rate"),
+			i18n.T("report.rate_of", m.ChangesPerHour, m.BuildRuns, m.TestRuns)))
+	}
+	md.WriteString("\n")
+
+	md.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("report.summary")))
+	if t.Summary == nil {
+		md.WriteString("_" + i18n.T("report.no_summary", t.SessionID) + "_\n\n")
+	} else {
+		md.WriteString(t.Summary.Text + "\n\n")
+		source := []string{}
+		for _, s := range []string{t.Summary.Provider, t.Summary.Model} {
+			if s != "" {
+				source = append(source, s)
+			}
+		}
+		if created, err := time.Parse(time.RFC3339, t.Summary.CreatedAt); err == nil {
+			source = append(source, created.Local().Format("2006-01-02 15:04"))
+		}
+		if len(source) > 0 {
+			md.WriteString("_" + i18n.T("report.summary_by", strings.Join(source, ", ")) + "_\n\n")
+		}
+	}
+
+	if len(t.Notes) > 0 {
+		md.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("report.notes")))
+		for _, note := range t.Notes {
+			md.WriteString(fmt.Sprintf("- %.1f min: %s\n", note.RelativeTime/60, note.Text))
+		}
+		md.WriteString("\n")
+	}
+
+	if commit := t.GenerateSmartCommit(); commit != "" {
+		md.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("report.smart_commit")))
+		md.WriteString("```\n" + commit + "\n```\n")
+	}
+	return md.String()
+}
+
+// newReportCmd builds the report command
+func newReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report [session_id]...",
+		Short: "Print a report of a session with its saved summary",
+		Long: `Print a markdown report of a finished session: time spent, ticket, tags, notes,
+the summary saved with 'task-tracker summary' or 'task-tracker commit', and the
+smart commit message. Use -o to also save it as report.md or another file.
+
+Give several session IDs, --all or --since to report on many sessions at once;
+they're processed in parallel. With -o session each gets its own report.md,
+otherwise the reports are joined in session order.
+
+With --totals one report of time per ticket, tag, app and activity class is
+written for all of them instead, e.g. 'report --totals --since 168h' for the
+week. Totals are cached in task_captures/aggregates.json and recomputed only
+for sessions whose metadata changed, so this stays fast over many sessions.
+
+With --sprint board/sprint the issues of a Jira sprint are fetched instead,
+and the time and summaries of sessions on each are rolled up, flagging
+issues worked on with nothing logged in Jira. The sprint is an ID, a name or
+"active", e.g. 'report --sprint 12/active'.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := loadConfig()
+			if err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				exit(exitCode(err))
+			}
+			output, _ := cmd.Flags().GetString("output")
+
+			totals, _ := cmd.Flags().GetBool("totals")
+			sprint, _ := cmd.Flags().GetString("sprint")
+			if totals || sprint != "" {
+				var report string
+				switch {
+				case totals && sprint != "":
+					err = fmt.Errorf("%w: use --totals or --sprint, not both", errUsage)
+				case totals:
+					var aggregates []*SessionAggregates
+					if aggregates, err = selectAggregates(cmd, args); err == nil {
+						report = GenerateTotals(aggregates)
+					}
+				case len(args) > 0 || cmd.Flags().Changed("all") || cmd.Flags().Changed("since"):
+					err = fmt.Errorf("%w: --sprint picks the sessions itself; don't give sessions, --all or --since", errUsage)
+				default:
+					report, err = sprintReport(sprint)
+				}
+				if err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+				if output == "" {
+					ui.Print(report)
+					return
+				}
+				if output == "session" {
+					ui.Println("❌ -o session needs a single session; give a file name with --totals or --sprint")
+					exit(exitUsage)
+				}
+				if err := os.WriteFile(output, []byte(report), 0644); err != nil {
+					ui.Printf("❌ Failed to save report: %v\n", err)
+					exit(exitCode(err))
+				}
+				ui.Printf("✅ Report saved to %s\n", output)
+				return
+			}
+
+			if len(args) != 1 || cmd.Flags().Changed("all") || cmd.Flags().Changed("since") {
+				ids, err := batchSessions(cmd, args)
+				if err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+				jobs, _ := cmd.Flags().GetInt("jobs")
+				if err := batchReports(ids, jobs, cfg, output); err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+				return
+			}
+
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			tracker.Rounding = cfg.Rounding
+
+			if err := tracker.refreshEstimate(); err != nil {
+				ui.Printf("⚠️  Couldn't fetch the ticket's estimate: %v\n", err)
+			} else if tracker.Estimate != nil {
+				if err := tracker.saveMetadata(); err != nil {
+					ui.Printf("⚠️  Failed to save metadata: %v\n", err)
+				}
+			}
+
+			report := tracker.GenerateReport()
+
+			if output == "" {
+				ui.Print(report)
+				return
+			}
+			if output == "session" {
+				output = filepath.Join(tracker.SessionDir, reportFile)
+			}
+			if err := os.WriteFile(output, []byte(report), 0644); err != nil {
+				ui.Printf("❌ Failed to save report: %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("✅ Report saved to %s\n", output)
+		},
+	}
+	cmd.Flags().StringP("output", "o", "", "Write the report to a file (\"session\" for report.md in the session directory)")
+	cmd.Flags().Bool("totals", false, "Report time totals over the sessions instead of each session")
+	cmd.Flags().String("sprint", "", "Roll up session time and summaries per issue of a Jira sprint (board/sprint)")
+	addBatchFlags(cmd)
+	return cmd
+}
+
+// batchReports generates the reports of many sessions in parallel. With
+// output "session" each is saved in its session directory, otherwise they're
+// joined in order and printed or written to output.
+func batchReports(ids []string, jobs int, cfg *Config, output string) error {
+	var mu sync.Mutex
+	reports := map[string]string{}
+	failed := runBatch("📄 Reports", ids, jobs, func(t *TaskTracker) error {
+		t.Rounding = cfg.Rounding
+		if err := t.refreshEstimate(); err != nil {
+			ui.Printf("⚠️  %s: couldn't fetch the ticket's estimate: %v\n", t.SessionID, err)
+		} else if t.Estimate != nil {
+			if err := t.saveMetadata(); err != nil {
+				return fmt.Errorf("failed to save metadata: %w", err)
+			}
+		}
+
+		report := t.GenerateReport()
+		if output == "session" {
+			path := filepath.Join(t.SessionDir, reportFile)
+			if err := os.WriteFile(path, []byte(report), 0644); err != nil {
+				return fmt.Errorf("failed to save report: %w", err)
+			}
+			return nil
+		}
+		mu.Lock()
+		reports[t.SessionID] = report
+		mu.Unlock()
+		return nil
+	})
+
+	if output != "session" {
+		var joined []string
+		for _, id := range ids {
+			if report, ok := reports[id]; ok {
+				joined = append(joined, report)
+			}
+		}
+		all := strings.Join(joined, "\n---\n\n")
+		if output == "" {
+			ui.Print(all)
+		} else if err := os.WriteFile(output, []byte(all), 0644); err != nil {
+			return fmt.Errorf("failed to save report: %w", err)
+		}
+	}
+
+	if output != "" {
+		ui.Printf("✅ Saved reports for %d of %d sessions\n", len(ids)-len(failed), len(ids))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d sessions failed", len(failed), len(ids))
+	}
+	return nil
+}
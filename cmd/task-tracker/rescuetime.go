@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+const (
+	defaultRescueTimeURL = "https://www.rescuetime.com/anapi/data"
+
+	// rescueTimeInterval is the resolution of RescueTime's interval data
+	rescueTimeInterval = 5 * time.Minute
+)
+
+// ProductivityEntry is time RescueTime logged on one activity in a
+// five-minute interval during the session
+type ProductivityEntry struct {
+	Start        string  `json:"start"`
+	RelativeTime float64 `json:"relative_time"`
+	Seconds      float64 `json:"seconds"`
+	Activity     string  `json:"activity"`
+	Category     string  `json:"category"`
+	Productivity int     `json:"productivity"` // -2 very distracting to 2 very productive
+}
+
+// rescueTimeResponse is the Analytic Data API's JSON format
+type rescueTimeResponse struct {
+	RowHeaders []string `json:"row_headers"`
+	Rows       [][]any  `json:"rows"`
+	Error      string   `json:"error"`
+}
+
+// importProductivity replaces the session's productivity data with
+// RescueTime's activity log for the session window
+func (t *TaskTracker) importProductivity(apiURL, key string) error {
+	// RescueTime reports intervals in the account's time zone, which is
+	// assumed to be this machine's
+	start := t.StartTime.Local().Truncate(rescueTimeInterval)
+	end := t.EndTime.Local()
+
+	q := url.Values{}
+	q.Set("key", key)
+	q.Set("format", "json")
+	q.Set("perspective", "interval")
+	q.Set("resolution_time", "minute")
+	q.Set("restrict_kind", "activity")
+	q.Set("restrict_begin", start.Format("2006-01-02"))
+	q.Set("restrict_end", end.Format("2006-01-02"))
+
+	req, err := http.NewRequest("GET", apiURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent())
+
+	resp, err := (&http.Client{Timeout: 30 * time.Second, Transport: httpTransport()}).Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: RescueTime not reachable: %v", errIntegration, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: RescueTime returned %s %s", errIntegration, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var data rescueTimeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return fmt.Errorf("%w: invalid RescueTime response: %v", errIntegration, err)
+	}
+	if data.Error != "" {
+		return fmt.Errorf("%w: RescueTime: %s", errIntegration, data.Error)
+	}
+
+	col := make(map[string]int)
+	for i, h := range data.RowHeaders {
+		col[h] = i
+	}
+	for _, h := range []string{"Date", "Time Spent (seconds)", "Activity", "Category", "Productivity"} {
+		if _, ok := col[h]; !ok {
+			return fmt.Errorf("%w: RescueTime response has no %q column", errIntegration, h)
+		}
+	}
+
+	entries := []ProductivityEntry{}
+	for _, row := range data.Rows {
+		if len(row) < len(data.RowHeaders) {
+			continue
+		}
+		date, _ := row[col["Date"]].(string)
+		at, err := time.ParseInLocation("2006-01-02T15:04:05", date, time.Local)
+		if err != nil || at.Before(start) || !at.Before(end) {
+			continue
+		}
+		seconds, _ := row[col["Time Spent (seconds)"]].(float64)
+		activity, _ := row[col["Activity"]].(string)
+		category, _ := row[col["Category"]].(string)
+		productivity, _ := row[col["Productivity"]].(float64)
+
+		entries = append(entries, ProductivityEntry{
+			Start:        at.Format(time.RFC3339),
+			RelativeTime: at.Sub(t.StartTime).Seconds(),
+			Seconds:      seconds,
+			Activity:     activity,
+			Category:     category,
+			Productivity: int(productivity),
+		})
+	}
+
+	if len(entries) == 0 {
+		return fmt.Errorf("%w: RescueTime has no data for this session yet (it can take up to 30 minutes to sync)", errIntegration)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].RelativeTime < entries[j].RelativeTime
+	})
+	t.Productivity = entries
+	return nil
+}
+
+// productivityLabels names RescueTime's productivity scores
+var productivityLabels = map[int]string{
+	2:  "Very productive",
+	1:  "Productive",
+	0:  "Neutral",
+	-1: "Distracting",
+	-2: "Very distracting",
+}
+
+// productivityPulse is RescueTime's 0-100 score: time weighted from 0 for
+// very distracting to 100 for very productive
+func productivityPulse(entries []ProductivityEntry) float64 {
+	total, weighted := 0.0, 0.0
+	for _, e := range entries {
+		total += e.Seconds
+		weighted += e.Seconds * float64(e.Productivity+2) * 25
+	}
+	if total == 0 {
+		return 0
+	}
+	return weighted / total
+}
+
+// writeProductivity renders time per RescueTime category into the review file
+func writeProductivity(md *strings.Builder, entries []ProductivityEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	type category struct {
+		name         string
+		seconds      float64
+		productivity int
+	}
+	byName := make(map[string]*category)
+	for _, e := range entries {
+		c, ok := byName[e.Category]
+		if !ok {
+			c = &category{name: e.Category, productivity: e.Productivity}
+			byName[e.Category] = c
+		}
+		c.seconds += e.Seconds
+	}
+	categories := make([]*category, 0, len(byName))
+	for _, c := range byName {
+		categories = append(categories, c)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].seconds > categories[j].seconds })
+
+	md.WriteString("## Productivity (RescueTime)\n\n")
+	md.WriteString(fmt.Sprintf("**Productivity Pulse:** %.0f/100\n\n", productivityPulse(entries)))
+	md.WriteString("| Category | Minutes | Productivity |\n|---|---|---|\n")
+	for _, c := range categories {
+		md.WriteString(fmt.Sprintf("| %s | %.1f | %s |\n", c.name, c.seconds/60, productivityLabels[c.productivity]))
+	}
+	md.WriteString("\n")
+}
+
+// newRescueTimeCmd builds the rescuetime command group
+func newRescueTimeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rescuetime",
+		Short: "Import RescueTime productivity data",
+	}
+
+	importCmd := &cobra.Command{
+		Use:   "import [session_id]",
+		Short: "Add RescueTime categories and productivity to a session's review",
+		Long: `Fetch RescueTime's activity log for the session window and add time per
+category and the productivity pulse to the review file.
+
+Needs an Analytic Data API key from RESCUETIME_API_KEY or --key. RescueTime
+logs in five-minute intervals and syncs every few minutes, so import after the
+session has ended.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			key, _ := cmd.Flags().GetString("key")
+			if key == "" {
+				key = os.Getenv("RESCUETIME_API_KEY")
+			}
+			if key == "" {
+				ui.Println("❌ No RescueTime API key (set RESCUETIME_API_KEY or use --key)")
+				exit(exitUsage)
+			}
+
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			apiURL, _ := cmd.Flags().GetString("api-url")
+			if err := tracker.importProductivity(apiURL, key); err != nil {
+				ui.Printf("❌ Import failed: %v\n", err)
+				exit(exitCode(err))
+			}
+			if err := tracker.saveMetadata(); err != nil {
+				ui.Printf("❌ Failed to save metadata: %v\n", err)
+				exit(exitCode(err))
+			}
+			if fileExists(filepath.Join(tracker.SessionDir, "review.md")) {
+				if err := tracker.GenerateReviewFile(5); err != nil {
+					ui.Printf("⚠️  Failed to regenerate review file: %v\n", err)
+				}
+			}
+
+			ui.Printf("✅ Imported %d RescueTime interval(s) into %s (pulse %.0f)\n",
+				len(tracker.Productivity), tracker.SessionID, productivityPulse(tracker.Productivity))
+		},
+	}
+	importCmd.Flags().String("key", "", "RescueTime API key (or set RESCUETIME_API_KEY)")
+	importCmd.Flags().String("api-url", defaultRescueTimeURL, "Analytic Data API endpoint")
+	importCmd.Flags().MarkHidden("api-url")
+
+	cmd.AddCommand(importCmd)
+	return cmd
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"task-tracker/internal/i18n"
+)
+
+// Default review budget. Claude reads about 200k tokens and its API takes
+// requests up to 32MB, so a part stays well under both.
+const (
+	defaultReviewTokens = 100000
+	defaultReviewBytes  = 30 << 20
+)
+
+// Default relevance thresholds: frames with less detail are blank or
+// nearly so, and frames with less change repeat the one before
+const (
+	defaultMinDetail = 0.002
+	defaultMinChange = 0.002
+)
+
+// ReviewConfig limits the size of each review file part and sets which
+// frames are worth including
+type ReviewConfig struct {
+	MaxTokens int      `json:"max_tokens"` // estimated text and image tokens
+	MaxBytes  ByteSize `json:"max_bytes"`  // total size of the referenced images
+	MinDetail float64  `json:"min_detail"` // share of edge pixels; 0 keeps blank frames
+	MinChange float64  `json:"min_change"` // share changed since the last kept frame; 0 keeps repeats
+	MaxImage  int      `json:"max_image"`  // split frames larger than this many pixels a side into tiles; 0 off
+}
+
+// Validate checks the limits aren't negative and the thresholds are shares
+func (r ReviewConfig) Validate() error {
+	if r.MaxTokens < 0 || r.MaxBytes < 0 {
+		return fmt.Errorf("review: max_tokens and max_bytes can't be negative")
+	}
+	if r.MaxImage < 0 {
+		return fmt.Errorf("review: max_image can't be negative")
+	}
+	if r.MinDetail < 0 || r.MinDetail > 1 || r.MinChange < 0 || r.MinChange > 1 {
+		return fmt.Errorf("review: min_detail and min_change must be between 0 and 1")
+	}
+	return nil
+}
+
+// textTokens roughly estimates the tokens in text
+func textTokens(s string) int {
+	return len(s) / 4
+}
+
+// imageTokens estimates what a screenshot costs a vision model. Images are
+// scaled to at most 1568px on the long edge and about 1.15 megapixels, and
+// cost about one token per 750 pixels.
+func imageTokens(resolution string) int {
+	var w, h float64
+	if _, err := fmt.Sscanf(resolution, "%fx%f", &w, &h); err != nil || w <= 0 || h <= 0 {
+		return 1600
+	}
+	if long := max(w, h); long > 1568 {
+		w, h = w*1568/long, h*1568/long
+	}
+	if area := w * h; area > 1150000 {
+		return 1150000 / 750
+	}
+	return int(w * h / 750)
+}
+
+// reviewBlock is one screenshot of the review file with the timeline
+// entries before it
+type reviewBlock struct {
+	text         string
+	tokens       int
+	bytes        int64
+	index        int // 1-based screenshot number
+	relativeTime float64
+}
+
+// splitReview packs blocks into parts that stay within the budget once the
+// header is added. A block that's over budget on its own gets a part to
+// itself.
+func splitReview(blocks []reviewBlock, budget ReviewConfig, headerTokens int) [][]reviewBlock {
+	parts := [][]reviewBlock{}
+	var current []reviewBlock
+	tokens, bytes := headerTokens, int64(0)
+
+	for _, b := range blocks {
+		over := (budget.MaxTokens > 0 && tokens+b.tokens > budget.MaxTokens) ||
+			(budget.MaxBytes > 0 && bytes+b.bytes > int64(budget.MaxBytes))
+		if over && len(current) > 0 {
+			parts = append(parts, current)
+			current, tokens, bytes = nil, headerTokens, 0
+		}
+		current = append(current, b)
+		tokens += b.tokens
+		bytes += b.bytes
+	}
+	if len(current) > 0 || len(parts) == 0 {
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+// reviewPartName is the file name of a review part; the first is always
+// review.md
+func reviewPartName(n int) string {
+	if n == 1 {
+		return "review.md"
+	}
+	return fmt.Sprintf("review_part%d.md", n)
+}
+
+// removeReviewParts deletes parts left over from a previous, longer review
+func (t *TaskTracker) removeReviewParts() {
+	old, _ := filepath.Glob(filepath.Join(t.SessionDir, "review_part*.md"))
+	for _, path := range old {
+		os.Remove(path)
+	}
+}
+
+// writeAnalysisPrompt ends the review with the questions for the AI
+func writeAnalysisPrompt(md *strings.Builder, parts int, tiled bool) {
+	md.WriteString("\n---\n\n")
+	md.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("prompt.title")))
+	if parts > 1 {
+		md.WriteString(i18n.T("prompt.parts", parts) + "\n\n")
+	}
+	md.WriteString(i18n.T("prompt.intro") + "\n\n")
+	if tiled {
+		md.WriteString(i18n.T("prompt.tiles") + "\n\n")
+	}
+	for i, key := range []string{"prompt.done", "prompt.tasks", "prompt.tools", "prompt.layout", "prompt.flow", "prompt.jira"} {
+		md.WriteString(fmt.Sprintf("%d. %s\n", i+1, i18n.T(key)))
+	}
+	md.WriteString("\n" + i18n.T("prompt.focus") + "\n")
+}
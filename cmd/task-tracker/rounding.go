@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rounding modes for billable time
+const (
+	RoundNone    = "none"
+	RoundNearest = "nearest"
+	RoundUp      = "up"
+	RoundDown    = "down"
+)
+
+// RoundingConfig controls how tracked durations are rounded before they
+// appear in #time values, e.g. {"mode": "up", "increment": "15m", "minimum": "30m"}
+type RoundingConfig struct {
+	Mode      string   `json:"mode"`
+	Increment Duration `json:"increment"`
+	Minimum   Duration `json:"minimum"`
+}
+
+// Validate checks the rounding mode and increment
+func (r RoundingConfig) Validate() error {
+	// The minimum applies without a rounding mode too
+	if r.Minimum.Duration < 0 {
+		return fmt.Errorf("rounding.minimum cannot be negative")
+	}
+	if r.Increment.Duration < 0 {
+		return fmt.Errorf("rounding.increment cannot be negative")
+	}
+
+	switch r.Mode {
+	case "", RoundNone:
+	case RoundNearest, RoundUp, RoundDown:
+		if r.Increment.Duration <= 0 {
+			return fmt.Errorf("rounding mode '%s' requires a positive increment", r.Mode)
+		}
+	default:
+		return fmt.Errorf("unknown rounding mode '%s' (use none, nearest, up or down)", r.Mode)
+	}
+	return nil
+}
+
+// Apply rounds d to the configured increment and enforces the minimum
+// billable amount
+func (r RoundingConfig) Apply(d time.Duration) time.Duration {
+	inc := r.Increment.Duration
+
+	switch r.Mode {
+	case RoundNearest:
+		d = d.Round(inc)
+	case RoundUp:
+		if rem := d % inc; rem != 0 {
+			d += inc - rem
+		}
+	case RoundDown:
+		d = d.Truncate(inc)
+	}
+
+	if d < r.Minimum.Duration {
+		d = r.Minimum.Duration
+	}
+	return d
+}
+
+// formatTimeSpent renders a duration in Jira's "1h 20m" notation
+func formatTimeSpent(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// parseTimeSpent reads Jira's time notation, e.g. "1h 20m" or "2d", with
+// Jira's default 8 hour days and 5 day weeks
+func parseTimeSpent(s string) (time.Duration, error) {
+	units := map[byte]time.Duration{'w': 40 * time.Hour, 'd': 8 * time.Hour, 'h': time.Hour, 'm': time.Minute, 's': time.Second}
+
+	var total time.Duration
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("%w: empty time spent", errUsage)
+	}
+	for _, f := range fields {
+		unit, ok := units[f[len(f)-1]]
+		n, err := strconv.ParseFloat(f[:len(f)-1], 64)
+		if !ok || err != nil || n < 0 {
+			return 0, fmt.Errorf("%w: invalid time spent '%s' (use e.g. 1h 20m)", errUsage, s)
+		}
+		total += time.Duration(n * float64(unit))
+	}
+	return total, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoundingApply(t *testing.T) {
+	quarter := Duration{15 * time.Minute}
+	tests := []struct {
+		name string
+		cfg  RoundingConfig
+		in   time.Duration
+		want time.Duration
+	}{
+		{"none", RoundingConfig{}, 7 * time.Minute, 7 * time.Minute},
+		{"explicit none", RoundingConfig{Mode: RoundNone, Increment: quarter}, 7 * time.Minute, 7 * time.Minute},
+		{"nearest down", RoundingConfig{Mode: RoundNearest, Increment: quarter}, 22 * time.Minute, 15 * time.Minute},
+		{"nearest half up", RoundingConfig{Mode: RoundNearest, Increment: quarter}, 22*time.Minute + 30*time.Second, 30 * time.Minute},
+		{"up", RoundingConfig{Mode: RoundUp, Increment: quarter}, 16 * time.Minute, 30 * time.Minute},
+		{"up exact", RoundingConfig{Mode: RoundUp, Increment: quarter}, 30 * time.Minute, 30 * time.Minute},
+		{"down", RoundingConfig{Mode: RoundDown, Increment: quarter}, 29 * time.Minute, 15 * time.Minute},
+		{"minimum", RoundingConfig{Mode: RoundUp, Increment: quarter, Minimum: Duration{30 * time.Minute}}, 5 * time.Minute, 30 * time.Minute},
+		{"minimum without rounding", RoundingConfig{Minimum: Duration{time.Hour}}, 5 * time.Minute, time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Apply(tt.in); got != tt.want {
+				t.Errorf("Apply(%s) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundingValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     RoundingConfig
+		wantErr bool
+	}{
+		{"empty", RoundingConfig{}, false},
+		{"up", RoundingConfig{Mode: RoundUp, Increment: Duration{6 * time.Minute}}, false},
+		{"no increment", RoundingConfig{Mode: RoundNearest}, true},
+		{"unknown mode", RoundingConfig{Mode: "ceil", Increment: Duration{time.Minute}}, true},
+		{"negative minimum", RoundingConfig{Mode: RoundDown, Increment: Duration{time.Minute}, Minimum: Duration{-time.Minute}}, true},
+		{"negative minimum without mode", RoundingConfig{Minimum: Duration{-5 * time.Minute}}, true},
+		{"negative increment without mode", RoundingConfig{Mode: RoundNone, Increment: Duration{-time.Minute}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseConfigNegativeMinimum(t *testing.T) {
+	_, errs := parseConfig("config.json", []byte(`{"rounding": {"minimum": "-5m"}}`))
+	if len(errs) != 1 {
+		t.Fatalf("got %d problems %v, want the negative minimum", len(errs), errs)
+	}
+	var cerr *configError
+	if !errors.As(errs[0], &cerr) || cerr.line != 1 || cerr.col != 15 || !strings.Contains(cerr.err.Error(), "rounding.minimum") {
+		t.Errorf("problem = %v, want config.json:1:15: rounding.minimum...", errs[0])
+	}
+}
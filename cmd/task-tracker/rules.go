@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"strings"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/ui"
+)
+
+// ruleSteps bounds the work a rule may do per tick, so a runaway loop
+// can't stall capture
+const ruleSteps = 1_000_000
+
+// rules runs the tick function of a user's Starlark script before every
+// capture. Errors never stop capture: the tick is captured as configured
+// and the error is shown once until it changes.
+type rules struct {
+	path string
+	tick *starlark.Function
+
+	lastErr    string
+	skipping   bool
+	lastCursor image.Point
+}
+
+// ruleDecision is what a rule returned for one tick
+type ruleDecision struct {
+	capture  bool
+	monitors []int // 0-based; nil keeps the configured monitors
+	tags     []string
+}
+
+// loadRules runs a rules script once and picks up its tick function
+func loadRules(path string) (*rules, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+
+	thread := ruleThread(path)
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{While: true, Set: true}, thread, path, src, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rules: %w", ruleError(err))
+	}
+	tick, ok := globals["tick"].(*starlark.Function)
+	if !ok {
+		return nil, fmt.Errorf("rules %s: no tick(ctx) function defined", path)
+	}
+	if tick.NumParams() != 1 {
+		return nil, fmt.Errorf("rules %s: tick must take one argument (ctx)", path)
+	}
+	return &rules{path: path, tick: tick}, nil
+}
+
+// ruleThread makes a thread for one call; print() goes to the terminal
+func ruleThread(path string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: path,
+		Print: func(_ *starlark.Thread, msg string) {
+			ui.Printf("📜 %s\n", msg)
+		},
+	}
+	thread.SetMaxExecutionSteps(ruleSteps)
+	return thread
+}
+
+// ruleError adds the Starlark backtrace to evaluation errors
+func ruleError(err error) error {
+	if evalErr, ok := err.(*starlark.EvalError); ok {
+		return fmt.Errorf("%s", evalErr.Backtrace())
+	}
+	return err
+}
+
+// context describes the tick for the script
+func (r *rules) context(t *TaskTracker, now time.Time) *starlarkstruct.Struct {
+	window, idle := "", -1.0
+	if in, ok := t.Capturer.(capture.Inspector); ok {
+		window, _ = in.ActiveWindow()
+		if d, ok := in.Idle(); ok {
+			idle = d.Seconds()
+		}
+	}
+
+	moved := false
+	if pos, ok := t.cursor(); ok {
+		moved = pos != r.lastCursor
+		r.lastCursor = pos
+	}
+
+	t.mu.Lock()
+	active := t.activeDuration().Minutes()
+	screenshots := len(t.Screenshots)
+	tags := make([]starlark.Value, len(t.Tags))
+	for i, tag := range t.Tags {
+		tags[i] = starlark.String(tag)
+	}
+	t.mu.Unlock()
+
+	return starlarkstruct.FromStringDict(starlark.String("ctx"), starlark.StringDict{
+		"window":       starlark.String(window),
+		"idle":         starlark.Float(idle),
+		"cursor_moved": starlark.Bool(moved),
+		"time":         starlark.String(now.Format(time.RFC3339)),
+		"hour":         starlark.MakeInt(now.Hour()),
+		"minute":       starlark.MakeInt(now.Minute()),
+		"weekday":      starlark.String(strings.ToLower(now.Weekday().String()[:3])),
+		"elapsed":      starlark.Float(now.Sub(t.StartTime).Minutes()),
+		"active":       starlark.Float(active),
+		"screenshots":  starlark.MakeInt(screenshots),
+		"monitors":     starlark.MakeInt(t.Capturer.NumDisplays()),
+		"session":      starlark.String(t.SessionID),
+		"task":         starlark.String(t.TaskName),
+		"ticket":       starlark.String(t.Ticket.String()),
+		"tags":         starlark.NewList(tags),
+	})
+}
+
+// call runs tick(ctx) and interprets what it returned: None or True to
+// capture as configured, False to skip, or a dict with any of capture,
+// monitors (1-based) and tags
+func (r *rules) call(t *TaskTracker, now time.Time) (ruleDecision, error) {
+	d := ruleDecision{capture: true}
+
+	result, err := starlark.Call(ruleThread(r.path), r.tick, starlark.Tuple{r.context(t, now)}, nil)
+	if err != nil {
+		return d, ruleError(err)
+	}
+
+	switch v := result.(type) {
+	case starlark.NoneType:
+		return d, nil
+	case starlark.Bool:
+		d.capture = bool(v)
+		return d, nil
+	case *starlark.Dict:
+		for _, item := range v.Items() {
+			key, ok := starlark.AsString(item[0])
+			if !ok {
+				return d, fmt.Errorf("tick returned a dict with non-string key %s", item[0])
+			}
+			switch key {
+			case "capture":
+				d.capture = bool(item[1].Truth())
+			case "monitors":
+				if d.monitors, err = ruleMonitors(item[1], t.Capturer.NumDisplays()); err != nil {
+					return d, err
+				}
+			case "tags":
+				if d.tags, err = ruleStrings(item[1]); err != nil {
+					return d, fmt.Errorf("tags: %w", err)
+				}
+			default:
+				return d, fmt.Errorf("tick returned unknown key '%s' (use capture, monitors or tags)", key)
+			}
+		}
+		return d, nil
+	}
+	return d, fmt.Errorf("tick must return None, a bool or a dict, not %s", result.Type())
+}
+
+// ruleMonitors converts 1-based monitor numbers to indexes
+func ruleMonitors(v starlark.Value, count int) ([]int, error) {
+	iter, ok := v.(starlark.Iterable)
+	if !ok {
+		return nil, fmt.Errorf("monitors must be a list of numbers, not %s", v.Type())
+	}
+	monitors := []int{}
+	it := iter.Iterate()
+	defer it.Done()
+	var x starlark.Value
+	for it.Next(&x) {
+		n, err := starlark.AsInt32(x)
+		if err != nil || n < 1 || n > count {
+			return nil, fmt.Errorf("monitors: %s isn't a monitor (1-%d)", x, count)
+		}
+		monitors = append(monitors, n-1)
+	}
+	return monitors, nil
+}
+
+// ruleStrings converts a list of strings
+func ruleStrings(v starlark.Value) ([]string, error) {
+	iter, ok := v.(starlark.Iterable)
+	if !ok {
+		return nil, fmt.Errorf("expected a list of strings, not %s", v.Type())
+	}
+	out := []string{}
+	it := iter.Iterate()
+	defer it.Done()
+	var x starlark.Value
+	for it.Next(&x) {
+		s, ok := starlark.AsString(x)
+		if !ok {
+			return nil, fmt.Errorf("%s isn't a string", x)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// decide asks the rules about this tick, reporting errors and changes
+// between capturing and skipping once rather than on every tick
+func (r *rules) decide(t *TaskTracker, now time.Time) ruleDecision {
+	d, err := r.call(t, now)
+	if err != nil {
+		if msg := err.Error(); msg != r.lastErr {
+			ui.Printf("⚠️  Rules error (capturing as configured): %v\n", err)
+			r.lastErr = msg
+		}
+		return ruleDecision{capture: true}
+	}
+	r.lastErr = ""
+
+	if !d.capture != r.skipping {
+		r.skipping = !d.capture
+		if r.skipping {
+			ui.Println("📜 Rules: capture skipped")
+		} else {
+			ui.Println("📜 Rules: capture resumed")
+		}
+	}
+	return d
+}
+
+// addTags adds tags from the rules to the session, ignoring ones it
+// already has
+func (t *TaskTracker) addTags(tags []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+next:
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		for _, have := range t.Tags {
+			if strings.EqualFold(have, tag) {
+				continue next
+			}
+		}
+		t.Tags = append(t.Tags, tag)
+		ui.Printf("🏷️  Rules added tag: %s\n", tag)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// searchHit is a frame or session that matched a search
+type searchHit struct {
+	session SessionMetadata
+	shot    int // 1-based, 0 for a session-level match
+	minute  float64
+	score   float64
+	detail  string
+}
+
+// textSearch finds sessions whose task, name, ticket, tags, notes or
+// captions contain the query
+func textSearch(sessions []SessionMetadata, query string) []searchHit {
+	query = strings.ToLower(query)
+	contains := func(s string) bool { return strings.Contains(strings.ToLower(s), query) }
+
+	var hits []searchHit
+	for i := len(sessions) - 1; i >= 0; i-- {
+		s := sessions[i]
+		if contains(s.TaskName) || contains(s.Name) || contains(s.ticket().String()) || contains(strings.Join(s.Tags, " ")) {
+			hits = append(hits, searchHit{session: s, detail: s.TaskName})
+		}
+		for _, n := range s.Notes {
+			if contains(n.Text) {
+				hits = append(hits, searchHit{session: s, minute: n.RelativeTime / 60, detail: "📝 " + n.Text})
+			}
+		}
+		for j, shot := range s.Screenshots {
+			if contains(shot.Caption) {
+				hits = append(hits, searchHit{session: s, shot: j + 1, minute: shot.RelativeTime / 60, detail: "💬 " + shot.Caption})
+			}
+		}
+	}
+	return hits
+}
+
+// visualSearch ranks indexed frames by similarity to the query
+func visualSearch(e EmbeddingsConfig, idx *embeddingIndex, sessions []SessionMetadata, query string) ([]searchHit, error) {
+	vectors, err := e.embed([]embedInput{{Text: query}})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]SessionMetadata, len(sessions))
+	for _, s := range sessions {
+		byID[s.SessionID] = s
+	}
+
+	var hits []searchHit
+	for _, f := range idx.Frames {
+		s := byID[f.Session]
+		for j, shot := range s.Screenshots {
+			if shot.Path != f.Path {
+				continue
+			}
+			path, _ := resolvePath(filepath.Join(capturesDir, s.SessionID), s.SessionID, shot.Path)
+			hits = append(hits, searchHit{
+				session: s,
+				shot:    j + 1,
+				minute:  shot.RelativeTime / 60,
+				score:   similarity(vectors[0], f.Vector),
+				detail:  path,
+			})
+			break
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	return hits, nil
+}
+
+// newSearchCmd builds the search command
+func newSearchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search [query]",
+		Short: "Search sessions by text, or screenshots by what they show",
+		Long: `Search all saved sessions. By default the query is matched against task
+names, tickets, tags, notes and captions.
+
+With --visual the query describes what a screenshot shows, e.g.
+"terminal with stack trace", and frames are ranked by similarity using the
+local embedding model from config (embeddings.command). Keyframes of new
+sessions are embedded on the first visual search after they're saved and kept
+in task_captures/embeddings.json.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			visual, _ := cmd.Flags().GetBool("visual")
+			limit, _ := cmd.Flags().GetInt("limit")
+
+			sessions, err := listSessions()
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			if len(sessions) == 0 {
+				ui.Println("\n📋 No sessions captured yet")
+				return
+			}
+
+			var hits []searchHit
+			if visual {
+				hits, err = runVisualSearch(cmd, sessions, args[0])
+				if err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+			} else {
+				hits = textSearch(sessions, args[0])
+			}
+
+			if len(hits) == 0 {
+				ui.Printf("\n🔍 Nothing matches '%s'\n", args[0])
+				return
+			}
+			total := len(hits)
+			if limit > 0 && len(hits) > limit {
+				hits = hits[:limit]
+			}
+
+			headers := []string{"Session", "Shot", "Minute", "Match"}
+			if visual {
+				headers = append([]string{"Score"}, headers...)
+			}
+			table := ui.NewTable(headers...)
+			for _, h := range hits {
+				shot, minute := "", ""
+				if h.shot > 0 {
+					shot = fmt.Sprintf("%d", h.shot)
+				}
+				if h.shot > 0 || h.minute > 0 {
+					minute = fmt.Sprintf("%.1f", h.minute)
+				}
+				row := []string{ui.Bold(h.session.SessionID), shot, minute, h.detail}
+				if visual {
+					row = append([]string{fmt.Sprintf("%.3f", h.score)}, row...)
+				}
+				table.AddRow(row...)
+			}
+			ui.Println()
+			table.Render()
+
+			if len(hits) < total {
+				ui.Printf("\n💡 Showing %d of %d matches. Use --limit 0 to see all\n", len(hits), total)
+			}
+		},
+	}
+
+	cmd.Flags().Bool("visual", false, "Find screenshots that look like the query, using the local embedding model")
+	cmd.Flags().Bool("reindex", false, "Embed every keyframe again before a visual search")
+	cmd.Flags().IntP("limit", "n", 10, "Maximum number of matches to show (0 for all)")
+	return cmd
+}
+
+// runVisualSearch brings the embedding index up to date and ranks frames
+func runVisualSearch(cmd *cobra.Command, sessions []SessionMetadata, query string) ([]searchHit, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Embeddings.Command) == 0 {
+		return nil, fmt.Errorf("%w: no embedding model configured (set embeddings.command in config)", errUsage)
+	}
+
+	idx, err := loadEmbeddingIndex(cfg.Embeddings.model())
+	if err != nil {
+		return nil, err
+	}
+	if reindex, _ := cmd.Flags().GetBool("reindex"); reindex {
+		idx.Frames = nil
+	}
+
+	ui.Println("🧠 Updating the embedding index...")
+	n, err := idx.update(cfg.Embeddings, sessions)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		ui.Printf("   Embedded %d new frames (%d indexed)\n", n, len(idx.Frames))
+	}
+	return visualSearch(cfg.Embeddings, idx, sessions, query)
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultCapturesDir holds capture sessions unless output_dir is set in
+// config
+const defaultCapturesDir = "task_captures"
+
+// capturesDir is the directory for capture sessions, set from config
+// before any command runs
+var capturesDir = defaultCapturesDir
+
+// bindOutputDir points capturesDir at output_dir from config
+func bindOutputDir(root *cobra.Command) {
+	prev := root.PersistentPreRun
+	root.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		if prev != nil {
+			prev(cmd, args)
+		}
+		// A broken config is reported by the command that loads it
+		if cfg, err := loadConfig(); err == nil && cfg.OutputDir != "" {
+			capturesDir = cfg.OutputDir
+			if rest, ok := strings.CutPrefix(capturesDir, "~/"); ok {
+				if home, err := os.UserHomeDir(); err == nil {
+					capturesDir = filepath.Join(home, rest)
+				}
+			}
+		}
+	}
+}
+
+// resolveSession turns a session reference into a session ID. Besides a
+// full ID it accepts "last" or "last-N" (N sessions before the latest), a
+// unique prefix of an ID, or a ticket key, which picks that ticket's latest
+// session.
+func resolveSession(ref string) (string, error) {
+	if fileExists(filepath.Join(capturesDir, ref, "metadata.json")) {
+		return ref, nil
+	}
+
+	sessions, err := listSessions()
+	if err != nil {
+		return "", err
+	}
+
+	if rest, ok := strings.CutPrefix(ref, "last"); ok {
+		back := 0
+		if rest != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(rest, "-"))
+			if err != nil || !strings.HasPrefix(rest, "-") || n < 0 {
+				return "", fmt.Errorf("%w: invalid selector '%s' (use last or last-N)", errUsage, ref)
+			}
+			back = n
+		}
+		if back >= len(sessions) {
+			return "", fmt.Errorf("%w: only %d session(s) saved, no %s", errSessionNotFound, len(sessions), ref)
+		}
+		return sessions[len(sessions)-1-back].SessionID, nil
+	}
+
+	var matches []string
+	for _, s := range sessions {
+		if strings.HasPrefix(s.SessionID, ref) {
+			matches = append(matches, s.SessionID)
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) > 1:
+		shown := matches
+		if len(shown) > 5 {
+			shown = append(shown[:5:5], "...")
+		}
+		return "", fmt.Errorf("%w: '%s' matches %d sessions (%s)", errUsage, ref, len(matches), strings.Join(shown, ", "))
+	}
+
+	for i := len(sessions) - 1; i >= 0; i-- {
+		if sessions[i].ticket().Matches(ref) {
+			return sessions[i].SessionID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", errSessionNotFound, ref)
+}
+
+// loadSession reconstructs a tracker from a saved session's metadata.json.
+// sessionID may be any reference resolveSession accepts.
+func loadSession(sessionID string) (*TaskTracker, error) {
+	sessionID, err := resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	sessionDir := filepath.Join(capturesDir, sessionID)
+
+	metadataPath := filepath.Join(sessionDir, "metadata.json")
+	data, err := os.ReadFile(metadataPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", errSessionNotFound, sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	var metadata SessionMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+
+	tracker := &TaskTracker{
+		OutputDir:     capturesDir,
+		SessionID:     metadata.SessionID,
+		Name:          metadata.Name,
+		Parent:        metadata.Parent,
+		Summary:       metadata.Summary,
+		Estimate:      metadata.Estimate,
+		SessionDir:    sessionDir,
+		TaskName:      metadata.TaskName,
+		Screenshots:   metadata.Screenshots,
+		Ticket:        metadata.ticket(),
+		TimeSpent:     metadata.TimeSpent,
+		TicketComment: metadata.TicketComment,
+		LoggedAt:      metadata.LoggedAt,
+		Tags:          metadata.Tags,
+		Backend:       metadata.Backend,
+		Display:       metadata.Display,
+		Notes:         metadata.Notes,
+		Markers:       metadata.Markers,
+		Gaps:          metadata.Gaps,
+		Pauses:        metadata.Pauses,
+		BytesSaved:    metadata.BytesSaved,
+		Dropped:       metadata.Dropped,
+		Away:          metadata.Away,
+		Quarantined:   metadata.Quarantined,
+		Activity:      metadata.Activity,
+		Productivity:  metadata.Productivity,
+		Desktop:       metadata.Desktop,
+		Displays:      metadata.Displays,
+		Videos:        metadata.Videos,
+		Metrics:       metadata.Metrics,
+		Repo:          metadata.Repo,
+		Duplicates:    metadata.Duplicates,
+	}
+
+	if metadata.IntervalSeconds > 0 {
+		tracker.CaptureInterval = time.Duration(metadata.IntervalSeconds * float64(time.Second))
+	}
+	tracker.Jitter = time.Duration(metadata.JitterSeconds * float64(time.Second))
+	for monitor, seconds := range metadata.MonitorSeconds {
+		if m, err := strconv.Atoi(monitor); err == nil {
+			if tracker.MonitorIntervals == nil {
+				tracker.MonitorIntervals = make(map[int]time.Duration)
+			}
+			tracker.MonitorIntervals[m-1] = time.Duration(seconds * float64(time.Second))
+		}
+	}
+
+	if tracker.Screenshots == nil {
+		tracker.Screenshots = []Screenshot{}
+	}
+
+	tracker.StartTime, _ = time.Parse(time.RFC3339, metadata.StartTime)
+	tracker.EndTime, _ = time.Parse(time.RFC3339, metadata.EndTime)
+
+	// Sessions saved before active time was tracked only have the wall
+	// clock duration
+	tracker.activeTime = time.Duration(metadata.DurationSeconds * float64(time.Second))
+	if metadata.DurationSeconds == 0 && metadata.WallSeconds == 0 {
+		tracker.activeTime = tracker.wallDuration()
+	}
+
+	migrated := false
+	for i := range tracker.Screenshots {
+		path, old := resolvePath(sessionDir, tracker.SessionID, tracker.Screenshots[i].Path)
+		tracker.Screenshots[i].Path = path
+		migrated = migrated || old
+	}
+	for i := range tracker.Videos {
+		tracker.Videos[i].Path = filepath.Join(sessionDir, filepath.FromSlash(tracker.Videos[i].Path))
+	}
+	for i := range tracker.Quarantined {
+		tracker.Quarantined[i].Path, _ = resolvePath(sessionDir, tracker.SessionID, tracker.Quarantined[i].Path)
+	}
+	if migrated {
+		if err := tracker.saveMetadata(); err != nil {
+			return nil, fmt.Errorf("failed to migrate screenshot paths: %w", err)
+		}
+	}
+
+	return tracker, nil
+}
+
+// storedPath converts a screenshot path to the form kept in metadata.json:
+// relative to the session directory with forward slashes, so the session
+// still works from another working directory or after being moved
+func storedPath(sessionDir, path string) string {
+	if rel, err := filepath.Rel(sessionDir, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+// resolvePath turns a path from metadata.json into one usable from the
+// current directory. Sessions saved before paths were stored relative to
+// the session directory have task_captures/<id>/screen.png style paths;
+// those are recognised and converted, and the second result reports it.
+func resolvePath(sessionDir, sessionID, stored string) (string, bool) {
+	segments := strings.Split(filepath.ToSlash(stored), "/")
+	rel := filepath.ToSlash(stored)
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] == sessionID {
+			rel = strings.Join(segments[i+1:], "/")
+			break
+		}
+		if segments[i] == blobsDir && i > 0 {
+			rel = "../" + strings.Join(segments[i:], "/")
+			break
+		}
+	}
+
+	migrated := rel != filepath.ToSlash(stored)
+	if filepath.IsAbs(filepath.FromSlash(rel)) {
+		return filepath.FromSlash(rel), migrated
+	}
+	return filepath.Join(sessionDir, filepath.FromSlash(rel)), migrated
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// ticket returns the session's ticket, converting the Jira key sessions
+// saved before ticket providers recorded
+func (m *SessionMetadata) ticket() *TicketRef {
+	if m.Ticket == nil && m.JiraTicket != "" {
+		m.Ticket = &TicketRef{Provider: providerJira, Key: m.JiraTicket}
+	}
+	return m.Ticket
+}
+
+// listSessions returns the metadata of every saved session, oldest first
+func listSessions() ([]SessionMetadata, error) {
+	entries, err := os.ReadDir(capturesDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", capturesDir, err)
+	}
+
+	sessions := []SessionMetadata{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(capturesDir, entry.Name(), "metadata.json"))
+		if err != nil {
+			continue // still running or not a session
+		}
+
+		var metadata SessionMetadata
+		if err := json.Unmarshal(data, &metadata); err != nil {
+			continue
+		}
+		sessions = append(sessions, metadata)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].SessionID < sessions[j].SessionID
+	})
+	return sessions, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// saveSessions writes minimal metadata for each session ID into a
+// temporary capturesDir
+func saveSessions(t *testing.T, sessions ...SessionMetadata) {
+	t.Helper()
+	dir := t.TempDir()
+	prev := capturesDir
+	capturesDir = dir
+	t.Cleanup(func() { capturesDir = prev })
+
+	for _, m := range sessions {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessionDir := filepath.Join(dir, m.SessionID)
+		if err := os.MkdirAll(sessionDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(sessionDir, "metadata.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A session still running has no metadata yet
+	if err := os.Mkdir(filepath.Join(dir, "20260105_120000"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveSession(t *testing.T) {
+	saveSessions(t,
+		SessionMetadata{SessionID: "20260105_090000", Ticket: &TicketRef{Provider: providerJira, Key: "PROJ-1"}},
+		SessionMetadata{SessionID: "20260105_093000", JiraTicket: "PROJ-2"},
+		SessionMetadata{SessionID: "20260106_100000", Ticket: &TicketRef{Provider: providerJira, Key: "PROJ-1"}},
+	)
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr error
+	}{
+		{"20260105_093000", "20260105_093000", nil},
+		{"last", "20260106_100000", nil},
+		{"last-0", "20260106_100000", nil},
+		{"last-2", "20260105_090000", nil},
+		{"last-3", "", errSessionNotFound},
+		{"last2", "", errUsage},
+		{"last--1", "", errUsage},
+		{"20260106", "20260106_100000", nil},
+		{"20260105", "", errUsage},
+		{"proj-1", "20260106_100000", nil},
+		{"PROJ-2", "20260105_093000", nil},
+		{"PROJ-3", "", errSessionNotFound},
+		{"20260105_120000", "", errSessionNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := resolveSession(tt.ref)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("resolveSession(%q) error = %v, want %v", tt.ref, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolveSession(%q) = %q, %v, want %q", tt.ref, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSessionWithoutSessions(t *testing.T) {
+	prev := capturesDir
+	capturesDir = filepath.Join(t.TempDir(), "missing")
+	t.Cleanup(func() { capturesDir = prev })
+
+	if _, err := resolveSession("last"); !errors.Is(err, errSessionNotFound) {
+		t.Errorf("resolveSession(\"last\") error = %v, want errSessionNotFound", err)
+	}
+}
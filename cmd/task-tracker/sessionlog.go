@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// Log levels
+const (
+	logInfo  = "info"
+	logWarn  = "warn"
+	logError = "error"
+)
+
+const (
+	// logBacklog is how many entries a running session keeps for 'logs'
+	logBacklog = 500
+
+	// logFollowBuffer is how many entries a follower may fall behind before
+	// it misses some; capture never waits for a slow reader
+	logFollowBuffer = 64
+)
+
+// LogEntry is one line of a running session's log
+type LogEntry struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Event   string `json:"event"` // capture, skip, drop, error, gap or event
+	Message string `json:"message"`
+}
+
+// sessionLog keeps recent entries and hands new ones to followers
+type sessionLog struct {
+	mu        sync.Mutex
+	entries   []LogEntry
+	followers map[chan LogEntry]struct{}
+	closed    bool
+}
+
+// log records an entry in the session's log
+func (t *TaskTracker) log(level, event, message string) {
+	l := &t.sessionLog
+	e := LogEntry{Time: time.Now().Format(time.RFC3339), Level: level, Event: event, Message: message}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
+	if len(l.entries) == logBacklog {
+		l.entries = append(l.entries[:0], l.entries[1:]...)
+	}
+	l.entries = append(l.entries, e)
+	for ch := range l.followers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// follow returns the last n entries (all for n <= 0) and, with live set, a
+// channel of new ones that's closed when the session stops. Call the
+// returned func when done.
+func (l *sessionLog) follow(n int, live bool) ([]LogEntry, <-chan LogEntry, func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := l.entries
+	if n > 0 && len(recent) > n {
+		recent = recent[len(recent)-n:]
+	}
+	recent = append([]LogEntry(nil), recent...)
+	if !live || l.closed {
+		return recent, nil, func() {}
+	}
+
+	ch := make(chan LogEntry, logFollowBuffer)
+	if l.followers == nil {
+		l.followers = make(map[chan LogEntry]struct{})
+	}
+	l.followers[ch] = struct{}{}
+	return recent, ch, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, ok := l.followers[ch]; ok {
+			delete(l.followers, ch)
+			close(ch)
+		}
+	}
+}
+
+// close ends every follow as the session stops
+func (l *sessionLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	for ch := range l.followers {
+		delete(l.followers, ch)
+		close(ch)
+	}
+}
+
+// serveLogs streams log entries to a 'logs' client as JSON lines until the
+// session stops or the client goes away
+func (t *TaskTracker) serveLogs(conn net.Conn, req controlRequest) {
+	recent, live, done := t.sessionLog.follow(req.Lines, req.Follow)
+	defer done()
+
+	conn.SetDeadline(time.Time{})
+	enc := json.NewEncoder(conn)
+	for _, e := range recent {
+		if enc.Encode(e) != nil {
+			return
+		}
+	}
+	if live == nil {
+		return
+	}
+
+	// A follower only writes, so a read returning means it hung up
+	gone := make(chan struct{})
+	go func() {
+		var buf [1]byte
+		conn.Read(buf[:])
+		close(gone)
+	}()
+	for {
+		select {
+		case e, ok := <-live:
+			if !ok || enc.Encode(e) != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
+
+// logSymbols prefixes log lines by level
+var logSymbols = map[string]string{logInfo: "  ", logWarn: "⚠️ ", logError: "❌"}
+
+// newLogsCmd builds the logs command
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show a running session's log: captures, skips and errors",
+		Long: `Show the recent log of a running session over its control socket, to check
+a background capture is healthy. With -f, keep printing new entries until
+the session stops or you press Ctrl+C.
+
+--json prints the entries as JSON lines.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			target, _ := cmd.Flags().GetString("session")
+			follow, _ := cmd.Flags().GetBool("follow")
+			lines, _ := cmd.Flags().GetInt("lines")
+			asJSON, _ := cmd.Flags().GetBool("json")
+
+			session, err := findRunning(target)
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			conn, err := net.DialTimeout("unix", filepath.Join(capturesDir, session.SessionID, controlSocketName), 2*time.Second)
+			if err != nil {
+				ui.Printf("❌ Capture session %s is not responding (%v)\n", session.Name, err)
+				exit(exitSessionNotFound)
+			}
+			defer conn.Close()
+
+			req := controlRequest{Command: "logs", Lines: lines, Follow: follow}
+			if err := json.NewEncoder(conn).Encode(req); err != nil {
+				ui.Printf("❌ Failed to send request: %v\n", err)
+				exit(exitError)
+			}
+
+			dec := json.NewDecoder(conn)
+			for {
+				var line struct {
+					LogEntry
+					Error string `json:"error"`
+				}
+				if err := dec.Decode(&line); err != nil {
+					break
+				}
+				if line.Error != "" {
+					ui.Printf("❌ %s\n", line.Error)
+					exit(exitError)
+				}
+				if asJSON {
+					out, _ := json.Marshal(line.LogEntry)
+					fmt.Println(string(out))
+					continue
+				}
+				at, _ := time.Parse(time.RFC3339, line.Time)
+				ui.Printf("%s %s %-7s %s\n", ui.Dim(at.Format("15:04:05")), logSymbols[line.Level], line.Event, line.Message)
+			}
+			if follow {
+				ui.Printf("✅ %s stopped\n", session.Name)
+			}
+		},
+	}
+	addSessionFlag(cmd)
+	cmd.Flags().BoolP("follow", "f", false, "Keep printing new entries until the session stops")
+	cmd.Flags().IntP("lines", "n", 20, "How many recent entries to show first (0 for all kept)")
+	cmd.Flags().Bool("json", false, "Print entries as JSON lines")
+	return cmd
+}
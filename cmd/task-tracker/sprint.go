@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-tracker/internal/i18n"
+)
+
+// parseSprintSpec splits --sprint's board/sprint. The sprint is an ID, a
+// name or "active"; a bare sprint ID needs no board.
+func parseSprintSpec(spec string) (board, sprint string, err error) {
+	board, sprint, ok := strings.Cut(spec, "/")
+	if !ok {
+		board, sprint = "", spec
+	}
+	board, sprint = strings.TrimSpace(board), strings.TrimSpace(sprint)
+	if sprint == "" {
+		return "", "", fmt.Errorf("%w: --sprint needs board/sprint, e.g. 12/active or 12/'Sprint 42'", errUsage)
+	}
+	if board == "" {
+		if _, err := strconv.Atoi(sprint); err != nil {
+			return "", "", fmt.Errorf("%w: give the board too when naming a sprint, e.g. 12/%s", errUsage, sprint)
+		}
+	}
+	return board, sprint, nil
+}
+
+// sprintIssue is an issue's row in a sprint report
+type sprintIssue struct {
+	*Issue
+	sessions []SessionMetadata
+	tracked  time.Duration
+}
+
+// missingWorklog reports whether time was tracked on the issue but none is
+// logged in Jira
+func (i *sprintIssue) missingWorklog() bool {
+	return i.tracked > 0 && (i.Estimate == nil || i.Estimate.LoggedSeconds == 0)
+}
+
+// sprintReport fetches a sprint's issues from Jira and renders the report
+func sprintReport(spec string) (string, error) {
+	board, name, err := parseSprintSpec(spec)
+	if err != nil {
+		return "", err
+	}
+	c := newJiraClient()
+	if c == nil {
+		return "", notConfiguredError{provider: providerJira, env: "JIRA_URL and JIRA_API_TOKEN"}
+	}
+	sprint, err := c.FindSprint(board, name)
+	if err != nil {
+		return "", err
+	}
+	issues, err := c.SprintIssues(sprint.ID)
+	if err != nil {
+		return "", err
+	}
+	sessions, err := listSessions()
+	if err != nil {
+		return "", err
+	}
+	return GenerateSprintReport(sprint, issues, sessions), nil
+}
+
+// GenerateSprintReport rolls up tracked session time and summaries per
+// sprint issue, counting sessions within the sprint's dates when it has
+// them, and flags issues worked on without a worklog in Jira
+func GenerateSprintReport(sprint *Sprint, issues []*Issue, sessions []SessionMetadata) string {
+	start, _ := time.Parse(time.RFC3339, sprint.Start)
+	end, _ := time.Parse(time.RFC3339, sprint.End)
+
+	rows := make([]*sprintIssue, len(issues))
+	byKey := map[string]*sprintIssue{}
+	for i, issue := range issues {
+		rows[i] = &sprintIssue{Issue: issue}
+		byKey[strings.ToUpper(issue.Key)] = rows[i]
+	}
+	var total time.Duration
+	for _, s := range sessions {
+		ticket := s.ticket()
+		if ticket == nil || ticket.Provider != providerJira {
+			continue
+		}
+		row := byKey[strings.ToUpper(ticket.Key)]
+		if row == nil {
+			continue
+		}
+		started, err := time.Parse(time.RFC3339, s.StartTime)
+		if err != nil || (!start.IsZero() && started.Before(start)) || (!end.IsZero() && started.After(end)) {
+			continue
+		}
+		spent := time.Duration(s.DurationSeconds * float64(time.Second))
+		row.sessions = append(row.sessions, s)
+		row.tracked += spent
+		total += spent
+	}
+
+	var md strings.Builder
+	md.WriteString(fmt.Sprintf("# %s\n\n", i18n.T("sprint.title", sprint.Name)))
+	if sprint.Goal != "" {
+		md.WriteString(fmt.Sprintf("> %s\n\n", sprint.Goal))
+	}
+	md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("sprint.state"), sprint.State))
+	if !start.IsZero() && !end.IsZero() {
+		md.WriteString(fmt.Sprintf("- **%s:** %s – %s\n", i18n.T("sprint.dates"), start.Local().Format("2006-01-02"), end.Local().Format("2006-01-02")))
+	}
+	worked, missing := 0, 0
+	for _, r := range rows {
+		if len(r.sessions) > 0 {
+			worked++
+		}
+		if r.missingWorklog() {
+			missing++
+		}
+	}
+	md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("sprint.issues"), i18n.T("sprint.worked_on", len(rows), worked)))
+	md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("sprint.tracked_time"), formatTimeSpent(total)))
+	if missing > 0 {
+		md.WriteString(fmt.Sprintf("- **%s:** %d\n", i18n.T("sprint.missing"), missing))
+	}
+	md.WriteString("\n")
+
+	md.WriteString(fmt.Sprintf("## %s\n\n| %s | %s | %s | %s | %s | %s |\n|---|---|---|---|---|---|\n", i18n.T("sprint.issues"),
+		i18n.T("sprint.issue"), i18n.T("report.summary"), i18n.T("sprint.status"), i18n.T("totals.sessions"), i18n.T("sprint.tracked"), i18n.T("sprint.logged")))
+	for _, r := range rows {
+		logged := "–"
+		if r.Estimate != nil && r.Estimate.LoggedSeconds > 0 {
+			logged = formatTimeSpent(time.Duration(r.Estimate.LoggedSeconds) * time.Second)
+		}
+		if r.missingWorklog() {
+			logged = "⚠️ " + i18n.T("sprint.none")
+		}
+		tracked := "–"
+		if r.tracked > 0 {
+			tracked = formatTimeSpent(r.tracked)
+		}
+		md.WriteString(fmt.Sprintf("| [%s](%s) | %s | %s | %d | %s | %s |\n",
+			r.Key, r.URL, r.Title, r.State, len(r.sessions), tracked, logged))
+	}
+	md.WriteString("\n")
+
+	if missing > 0 {
+		md.WriteString(fmt.Sprintf("## %s\n\n%s\n\n", i18n.T("sprint.missing_title"), i18n.T("sprint.missing_why")))
+		for _, r := range rows {
+			if !r.missingWorklog() {
+				continue
+			}
+			ids := make([]string, len(r.sessions))
+			for i, s := range r.sessions {
+				ids[i] = s.SessionID
+			}
+			md.WriteString(fmt.Sprintf("- **%s** (%s): %s\n", r.Key, formatTimeSpent(r.tracked), strings.Join(ids, ", ")))
+		}
+		md.WriteString("\n")
+	}
+
+	wroteHeading := false
+	for _, r := range rows {
+		if len(r.sessions) == 0 {
+			continue
+		}
+		if !wroteHeading {
+			md.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("sprint.work")))
+			wroteHeading = true
+		}
+		md.WriteString(fmt.Sprintf("### %s: %s\n\n", r.Key, r.Title))
+		for _, s := range r.sessions {
+			started, _ := time.Parse(time.RFC3339, s.StartTime)
+			md.WriteString(fmt.Sprintf("- **%s** %s (%s)", started.Local().Format("2006-01-02"), s.TaskName,
+				formatTimeSpent(time.Duration(s.DurationSeconds*float64(time.Second)))))
+			if s.Summary != nil && s.Summary.Text != "" {
+				md.WriteString(": " + strings.Join(strings.Fields(s.Summary.Text), " "))
+			}
+			md.WriteString("\n")
+		}
+		md.WriteString("\n")
+	}
+	return md.String()
+}
@@ -0,0 +1,45 @@
+package main
+
+import "fmt"
+
+// captureState is the tracker's lifecycle state. It's stored in an
+// atomic.Int32 so the capture loop, control server and signal handling can
+// read it without holding t.mu.
+type captureState int32
+
+const (
+	stateIdle      captureState = iota // created, not started
+	stateCapturing                     // capture loop running
+	stateStopped                       // capture finished, metadata saved
+)
+
+func (s captureState) String() string {
+	switch s {
+	case stateIdle:
+		return "idle"
+	case stateCapturing:
+		return "capturing"
+	case stateStopped:
+		return "stopped"
+	}
+	return fmt.Sprintf("state(%d)", int32(s))
+}
+
+// State returns the current lifecycle state
+func (t *TaskTracker) State() captureState {
+	return captureState(t.state.Load())
+}
+
+// IsCapturing reports whether the capture loop is running
+func (t *TaskTracker) IsCapturing() bool {
+	return t.State() == stateCapturing
+}
+
+// transition moves from one state to another, failing if the tracker
+// isn't in the expected state
+func (t *TaskTracker) transition(from, to captureState) error {
+	if !t.state.CompareAndSwap(int32(from), int32(to)) {
+		return fmt.Errorf("cannot change capture state to %s while %s", to, t.State())
+	}
+	return nil
+}
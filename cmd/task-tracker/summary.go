@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/telemetry"
+	"task-tracker/internal/ui"
+)
+
+// Summary is the AI-generated (or hand-written) description of a session's
+// work, kept in metadata so commit and report can reuse it
+type Summary struct {
+	Text      string `json:"text"`
+	Provider  string `json:"provider,omitempty"` // e.g. claude-code
+	Model     string `json:"model,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// setSummary replaces the session summary
+func (t *TaskTracker) setSummary(text, provider, model string) {
+	span := telemetry.Start("summarize", telemetry.A("session.id", t.SessionID),
+		telemetry.A("summary.provider", provider), telemetry.A("summary.model", model))
+	defer span.End(nil)
+
+	t.Summary = &Summary{
+		Text:      strings.TrimSpace(text),
+		Provider:  provider,
+		Model:     model,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+}
+
+// announceSummary sends the saved summary to the channels summaries are
+// routed to and waits for it to go out
+func (t *TaskTracker) announceSummary(cfg *Config) {
+	t.Notifier = newNotifier(cfg.Notifications)
+	t.notify(eventSummary, "Summary: "+t.TaskName, t.summaryText())
+	t.Notifier.wait()
+}
+
+// summaryText returns the stored summary, or "" if there isn't one
+func (t *TaskTracker) summaryText() string {
+	if t.Summary == nil {
+		return ""
+	}
+	return t.Summary.Text
+}
+
+// readSummaryArg returns the summary text argument, reading stdin for "-"
+func readSummaryArg(arg string) (string, error) {
+	if arg != "-" {
+		return arg, nil
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read summary from stdin: %w", err)
+	}
+	return string(data), nil
+}
+
+// addSummaryFlags adds the flags describing where a summary came from
+func addSummaryFlags(cmd *cobra.Command) {
+	cmd.Flags().String("provider", "", "Tool that wrote the summary (e.g. claude-code)")
+	cmd.Flags().String("model", "", "Model that wrote the summary")
+}
+
+// newSummaryCmd builds the summary command
+func newSummaryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "summary [session_id] [text]",
+		Short: "Save or show the summary of a session",
+		Long: `Save the AI-generated summary of a session into its metadata, so commit and
+report can use it later. Pass "-" as text to read it from stdin, e.g.
+
+  claude -p "summarize task_captures/.../review.md" | task-tracker summary last - --provider claude-code
+
+Without text, the stored summary is printed.`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			if len(args) == 1 {
+				if tracker.Summary == nil {
+					ui.Printf("💡 No summary saved for %s\n", tracker.SessionID)
+					exit(exitError)
+				}
+				ui.Println(tracker.Summary.Text)
+				return
+			}
+
+			text, err := readSummaryArg(args[1])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			if strings.TrimSpace(text) == "" {
+				ui.Println("❌ Summary cannot be empty")
+				exit(exitUsage)
+			}
+
+			provider, _ := cmd.Flags().GetString("provider")
+			model, _ := cmd.Flags().GetString("model")
+			tracker.setSummary(text, provider, model)
+			if err := tracker.saveMetadata(); err != nil {
+				ui.Printf("❌ Failed to save metadata: %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("✅ Saved summary for %s\n", tracker.SessionID)
+			if cfg, err := loadConfig(); err != nil {
+				ui.Printf("⚠️  Summary not announced: %v\n", err)
+			} else {
+				tracker.announceSummary(cfg)
+			}
+		},
+	}
+	addSummaryFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,74 @@
+package main
+
+import "time"
+
+// throttleJPEGQuality is used for frames saved while capture is throttled
+const throttleJPEGQuality = 70
+
+// throttleStage is how far capture has degraded to save disk space or
+// battery
+type throttleStage int
+
+const (
+	stageOK     throttleStage = iota
+	stageSlow                 // interval doubled
+	stageJPEG                 // interval doubled, JPEG frames
+	stagePaused               // not capturing
+)
+
+func (s throttleStage) String() string {
+	switch s {
+	case stageSlow:
+		return "capturing at half rate"
+	case stageJPEG:
+		return "capturing at half rate as JPEG"
+	case stagePaused:
+		return "capture paused"
+	}
+	return "capturing normally"
+}
+
+// throttle tracks disk space and battery for the capture loop, which
+// degrades to the stricter of their stages
+type throttle struct {
+	disk    *diskMonitor
+	battery *batteryMonitor
+	stage   throttleStage
+}
+
+// check re-reads disk space and battery when due and reports whether the
+// stage changed
+func (th *throttle) check(t *TaskTracker, now time.Time) bool {
+	disk, battery := th.disk.check(t, now), th.battery.check(t, now)
+	if !disk && !battery {
+		return false
+	}
+	stage := max(th.disk.stage, th.battery.stage)
+	changed := stage != th.stage
+	th.stage = stage
+	return changed
+}
+
+// pauseReason is the placeholder reason while capture is paused
+func (th *throttle) pauseReason() string {
+	if th.disk.stage == stagePaused {
+		return awayLowDisk
+	}
+	return awayBattery
+}
+
+// applyStage adjusts the interval and frame format for a stage and
+// returns the interval the ticker should use
+func (t *TaskTracker) applyStage(stage throttleStage, base time.Duration) time.Duration {
+	t.throttleJPEG.Store(stage >= stageJPEG)
+
+	interval := base
+	if stage >= stageSlow {
+		interval = 2 * base
+	}
+
+	t.mu.Lock()
+	t.CaptureInterval = interval
+	t.mu.Unlock()
+	return interval
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/ui"
+)
+
+// ticketRollup is every saved session of one ticket
+type ticketRollup struct {
+	ticket   *TicketRef
+	issue    *Issue // nil when the provider isn't configured or reachable
+	sessions []*TaskTracker
+	rounding RoundingConfig
+}
+
+// loadTicketRollup loads the sessions of a ticket, oldest first
+func loadTicketRollup(ticket *TicketRef, rounding RoundingConfig) (*ticketRollup, error) {
+	all, err := listSessions()
+	if err != nil {
+		return nil, err
+	}
+	r := &ticketRollup{ticket: ticket, rounding: rounding}
+	for _, m := range all {
+		if !m.ticket().Same(ticket) {
+			continue
+		}
+		t, err := loadSession(m.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		t.Rounding = rounding
+		r.sessions = append(r.sessions, t)
+	}
+	return r, nil
+}
+
+// fetchIssue fetches the ticket's issue when its provider is configured
+func (r *ticketRollup) fetchIssue() error {
+	p, err := ticketProvider(r.ticket.Provider)
+	if err != nil {
+		return nil
+	}
+	r.issue, err = p.GetIssue(r.ticket.Key)
+	return err
+}
+
+// unlogged returns the sessions whose time hasn't been posted to the
+// ticket
+func (r *ticketRollup) unlogged() []*TaskTracker {
+	var sessions []*TaskTracker
+	for _, t := range r.sessions {
+		if t.LoggedAt == "" {
+			sessions = append(sessions, t)
+		}
+	}
+	return sessions
+}
+
+// spent is the time to log for sessions: each one's time spent when it was
+// given, its active time otherwise, rounded once for the total
+func (r *ticketRollup) spent(sessions []*TaskTracker) time.Duration {
+	var total time.Duration
+	for _, t := range sessions {
+		if d, err := parseTimeSpent(t.TimeSpent); t.TimeSpent != "" && err == nil {
+			total += d
+		} else {
+			total += t.activeDuration()
+		}
+	}
+	return r.rounding.Apply(total)
+}
+
+// tracked is the active time of every session
+func (r *ticketRollup) tracked() time.Duration {
+	var total time.Duration
+	for _, t := range r.sessions {
+		total += t.activeDuration()
+	}
+	return total
+}
+
+// combinedSummary lists what each session did: its summary, or its task
+// name without one
+func combinedSummary(sessions []*TaskTracker) string {
+	var b strings.Builder
+	for _, t := range sessions {
+		b.WriteString(fmt.Sprintf("- **%s** %s (%s)", t.StartTime.Local().Format("2006-01-02"), t.TaskName,
+			formatTimeSpent(t.activeDuration())))
+		if summary := t.summaryText(); summary != "" {
+			b.WriteString(": " + strings.Join(strings.Fields(summary), " "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// comment is the aggregate comment posted for sessions
+func (r *ticketRollup) comment(sessions []*TaskTracker) string {
+	text := i18n.T("ticket.work_across", len(sessions)) + "\n\n" + combinedSummary(sessions)
+	if components := mergedComponents(sessions); len(components) > 0 {
+		text += "\n" + i18n.T("report.components") + ": " + formatComponents(components) + "\n"
+	}
+	if delta := r.estimateDelta(); delta != "" {
+		text += "\n" + delta
+	}
+	return text
+}
+
+// estimateDelta describes tracked time against the issue's estimate, or
+// "" without one
+func (r *ticketRollup) estimateDelta() string {
+	if r.issue == nil || r.issue.Estimate == nil || r.issue.Estimate.OriginalSeconds <= 0 {
+		return ""
+	}
+	return formatEstimateDelta(r.tracked(), time.Duration(r.issue.Estimate.OriginalSeconds)*time.Second)
+}
+
+// GenerateTicketReport renders every session of a ticket with the total
+// time and the combined summary
+func GenerateTicketReport(r *ticketRollup) string {
+	var md strings.Builder
+	title := r.ticket.String()
+	if r.issue != nil && r.issue.Title != "" {
+		title += ": " + r.issue.Title
+	}
+	md.WriteString(fmt.Sprintf("# %s\n\n", i18n.T("ticket.title", title)))
+
+	url := r.ticket.URL
+	if r.issue != nil {
+		if r.issue.URL != "" {
+			url = r.issue.URL
+		}
+		if r.issue.State != "" {
+			md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("sprint.status"), r.issue.State))
+		}
+	}
+	if url != "" {
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("ticket.link"), url))
+	}
+	md.WriteString(fmt.Sprintf("- **%s:** %d\n", i18n.T("totals.sessions"), len(r.sessions)))
+	md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("sprint.tracked_time"), formatTimeSpent(r.tracked())))
+	if delta := r.estimateDelta(); delta != "" {
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("report.estimate"), delta))
+	}
+	if r.issue != nil && r.issue.Estimate != nil && r.issue.Estimate.LoggedSeconds > 0 {
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("ticket.logged"),
+			formatTimeSpent(time.Duration(r.issue.Estimate.LoggedSeconds)*time.Second)))
+	}
+	if unlogged := r.unlogged(); len(unlogged) > 0 {
+		md.WriteString(fmt.Sprintf("- **%s:** %s\n", i18n.T("ticket.unlogged"), i18n.T("ticket.unlogged_of", formatTimeSpent(r.spent(unlogged)), len(unlogged))))
+	}
+	md.WriteString("\n")
+
+	md.WriteString(fmt.Sprintf("## %s\n\n| %s | %s | %s | %s | %s | %s |\n|---|---|---|---|---|---|\n", i18n.T("totals.sessions"),
+		i18n.T("report.session"), i18n.T("digest.started"), i18n.T("digest.task"), i18n.T("sprint.tracked"), i18n.T("report.screenshots"), i18n.T("ticket.logged_on")))
+	for _, t := range r.sessions {
+		logged := "–"
+		if at, err := time.Parse(time.RFC3339, t.LoggedAt); err == nil {
+			logged = at.Local().Format("2006-01-02")
+		}
+		md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %d | %s |\n", t.SessionID,
+			t.StartTime.Local().Format("2006-01-02 15:04"), t.TaskName, formatTimeSpent(t.activeDuration()),
+			len(t.Screenshots), logged))
+	}
+	md.WriteString(fmt.Sprintf("\n## %s\n\n", i18n.T("ticket.combined")))
+	md.WriteString(combinedSummary(r.sessions))
+	return md.String()
+}
+
+// post logs the time of the sessions not logged yet as one worklog starting
+// when the first of them did, marks them logged and posts their combined
+// summary as a comment. They're marked before the comment, so a failed
+// comment never gets the same time logged twice.
+func (r *ticketRollup) post() (time.Duration, int, error) {
+	sessions := r.unlogged()
+	if len(sessions) == 0 {
+		return 0, 0, nil
+	}
+	p, err := ticketProvider(r.ticket.Provider)
+	if err != nil {
+		return 0, 0, err
+	}
+	var tasks []string
+	seen := map[string]bool{}
+	for _, t := range sessions {
+		if !seen[t.TaskName] {
+			seen[t.TaskName] = true
+			tasks = append(tasks, t.TaskName)
+		}
+	}
+	spent := r.spent(sessions)
+	// Trackers refuse empty worklogs
+	if spent > 0 {
+		if err := p.PostWorklog(r.ticket.Key, spent, sessions[0].StartTime, strings.Join(tasks, "; ")); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	for _, t := range sessions {
+		t.LoggedAt = now
+		if err := t.saveMetadata(); err != nil {
+			return spent, len(sessions), fmt.Errorf("logged, but failed to mark %s logged: %w", t.SessionID, err)
+		}
+	}
+	if err := p.PostComment(r.ticket.Key, r.comment(sessions)); err != nil {
+		return spent, len(sessions), fmt.Errorf("logged %s, but failed to post the summary: %w", formatTimeSpent(spent), err)
+	}
+	return spent, len(sessions), nil
+}
+
+// newTicketCmd builds the ticket command
+func newTicketCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ticket <ticket>",
+		Short: "Show every session of a ticket with the total time and combined summary",
+		Long: `Show every session linked to a ticket (e.g. CYM-2945, github:owner/repo#12 or
+an issue URL): when each ran, its time, whether it was logged, the total time
+against the issue's estimate and the sessions' summaries combined.
+
+With --post the time of the sessions not logged yet goes to the ticket as one
+worklog with their combined summary as the comment, and they're marked logged,
+so running it again only posts newer sessions. Sessions logged one by one with
+'commit --post' count as logged too.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ticket, err := parseTicket(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				exit(exitCode(err))
+			}
+			rollup, err := loadTicketRollup(ticket, cfg.Rounding)
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			if len(rollup.sessions) == 0 {
+				ui.Printf("❌ No sessions found for %s\n", ticket)
+				exit(exitCode(errSessionNotFound))
+			}
+			if err := rollup.fetchIssue(); err != nil {
+				ui.Printf("⚠️  Couldn't fetch %s: %v\n", ticket, err)
+			}
+
+			if post, _ := cmd.Flags().GetBool("post"); post {
+				spent, n, err := rollup.post()
+				if err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+				if n == 0 {
+					ui.Printf("✅ Every session of %s is logged already\n", ticket)
+					return
+				}
+				ui.Printf("✅ Logged %s from %d session(s) and posted their summary on %s\n", formatTimeSpent(spent), n, ticket)
+				return
+			}
+
+			report := GenerateTicketReport(rollup)
+			output, _ := cmd.Flags().GetString("output")
+			if output == "" {
+				ui.Print(report)
+				return
+			}
+			if err := os.WriteFile(output, []byte(report), 0644); err != nil {
+				ui.Printf("❌ Failed to save report: %v\n", err)
+				exit(exitCode(err))
+			}
+			ui.Printf("✅ Report saved to %s\n", output)
+		},
+	}
+	cmd.Flags().Bool("post", false, "Log the time of sessions not logged yet and post their combined summary to the ticket")
+	cmd.Flags().StringP("output", "o", "", "Write the report to a file")
+	return cmd
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingComments fakes a GitHub API that takes the worklog comment and
+// fails every comment after it. The audit log goes to a temporary directory.
+func failingComments(t *testing.T) *atomic.Int32 {
+	t.Helper()
+	prev := capturesDir
+	capturesDir = t.TempDir()
+	t.Cleanup(func() { capturesDir = prev })
+
+	var posts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if posts.Add(1) > 1 {
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("GITHUB_TOKEN", "test")
+	t.Setenv("GITHUB_API_URL", srv.URL)
+	return &posts
+}
+
+func TestTicketPostMarksLoggedBeforeComment(t *testing.T) {
+	posts := failingComments(t)
+	dir := t.TempDir()
+	session := &TaskTracker{
+		SessionID:  "20260105_090000",
+		SessionDir: dir,
+		TaskName:   "Fix login",
+		TimeSpent:  "1h",
+		StartTime:  time.Date(2026, 1, 5, 9, 0, 0, 0, time.Local),
+	}
+	rollup := &ticketRollup{
+		ticket:   &TicketRef{Provider: providerGitHub, Key: "owner/repo#12"},
+		sessions: []*TaskTracker{session},
+	}
+
+	if _, _, err := rollup.post(); err == nil {
+		t.Fatal("post succeeded although the comment failed")
+	}
+	if session.LoggedAt == "" {
+		t.Fatal("session not marked logged after its worklog was posted")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "metadata.json")); err != nil {
+		t.Errorf("logged session not saved: %v", err)
+	}
+
+	// A retry has nothing left to log
+	if _, n, err := rollup.post(); err != nil || n != 0 {
+		t.Errorf("retry posted %d session(s) (%v), want none", n, err)
+	}
+	if got := posts.Load(); got != 2 {
+		t.Errorf("%d requests, want the worklog and one failed comment", got)
+	}
+}
+
+func TestPostTicketMarksLoggedBeforeComment(t *testing.T) {
+	failingComments(t)
+	tracker := &TaskTracker{
+		SessionDir: t.TempDir(),
+		TaskName:   "Fix login",
+		TimeSpent:  "30m",
+		Ticket:     &TicketRef{Provider: providerGitHub, Key: "owner/repo#12"},
+	}
+	if err := tracker.postTicket(); err == nil {
+		t.Fatal("postTicket succeeded although the comment failed")
+	}
+	if tracker.LoggedAt == "" {
+		t.Error("session not marked logged after its worklog was posted")
+	}
+}
+
+func TestPostTicketTwiceLogsTimeOnce(t *testing.T) {
+	prev := capturesDir
+	capturesDir = t.TempDir()
+	t.Cleanup(func() { capturesDir = prev })
+
+	var worklogs, comments atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Body string }
+		json.NewDecoder(r.Body).Decode(&body)
+		if strings.HasPrefix(body.Body, "⏱️ Logged") {
+			worklogs.Add(1)
+		} else {
+			comments.Add(1)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("GITHUB_TOKEN", "test")
+	t.Setenv("GITHUB_API_URL", srv.URL)
+
+	tracker := &TaskTracker{
+		SessionDir: t.TempDir(),
+		TaskName:   "Fix login",
+		TimeSpent:  "30m",
+		Ticket:     &TicketRef{Provider: providerGitHub, Key: "owner/repo#12"},
+	}
+	for i := 0; i < 2; i++ {
+		if err := tracker.postTicket(); err != nil {
+			t.Fatalf("post %d: %v", i+1, err)
+		}
+	}
+	if got := worklogs.Load(); got != 1 {
+		t.Errorf("%d worklogs, want 1", got)
+	}
+	if got := comments.Load(); got != 2 {
+		t.Errorf("%d comments, want the summary posted twice", got)
+	}
+}
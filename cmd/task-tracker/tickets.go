@@ -0,0 +1,410 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"task-tracker/internal/i18n"
+)
+
+// Ticket providers
+const (
+	providerJira   = "jira"
+	providerGitHub = "github"
+	providerGitLab = "gitlab"
+	providerLinear = "linear"
+	providerAzure  = "azure"
+)
+
+// TicketRef identifies the ticket a session is for. Key is in the
+// provider's own notation: ABC-123 for Jira and Linear, owner/repo#12 for
+// GitHub and GitLab, Project#1234 for Azure Boards.
+type TicketRef struct {
+	Provider string `json:"provider"`
+	Key      string `json:"key"`
+	URL      string `json:"url,omitempty"`
+}
+
+// String is the reference as given on the command line: the bare key for
+// Jira, provider:key for the rest. It's "" for a nil ref.
+func (r *TicketRef) String() string {
+	if r == nil {
+		return ""
+	}
+	if r.Provider == providerJira {
+		return r.Key
+	}
+	return r.Provider + ":" + r.Key
+}
+
+// Matches reports whether s refers to this ticket, with or without its
+// provider prefix
+func (r *TicketRef) Matches(s string) bool {
+	if r == nil || s == "" {
+		return false
+	}
+	return strings.EqualFold(s, r.Key) || strings.EqualFold(s, r.String())
+}
+
+// Same reports whether two refs are the same ticket
+func (r *TicketRef) Same(o *TicketRef) bool {
+	return r != nil && o != nil && r.Provider == o.Provider && strings.EqualFold(r.Key, o.Key)
+}
+
+var (
+	jiraKeyPattern   = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*-[0-9]+$`)
+	repoIssuePattern = regexp.MustCompile(`^[^\s#]+/[^\s#]+#[0-9]+$`)
+	azureKeyPattern  = regexp.MustCompile(`^[^#]+#[0-9]+$`)
+)
+
+// parseTicket reads a ticket reference: a bare Jira key (ABC-123), a
+// provider-prefixed key (github:owner/repo#12, gitlab:group/project#12,
+// linear:ENG-42, azure:Project#1234) or an issue URL from any of them.
+// Empty input returns nil.
+func parseTicket(s string) (*TicketRef, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		return parseTicketURL(s)
+	}
+
+	provider, key, ok := strings.Cut(s, ":")
+	if !ok {
+		provider, key = providerJira, s
+	}
+	provider = strings.ToLower(provider)
+
+	var valid bool
+	switch provider {
+	case providerJira, providerLinear:
+		valid = jiraKeyPattern.MatchString(key)
+		key = strings.ToUpper(key)
+	case providerGitHub, providerGitLab:
+		valid = repoIssuePattern.MatchString(key)
+	case providerAzure:
+		valid = azureKeyPattern.MatchString(key)
+	default:
+		return nil, fmt.Errorf("%w: unknown ticket provider '%s' (use jira, github, gitlab, linear or azure)", errUsage, provider)
+	}
+	if !valid {
+		return nil, fmt.Errorf("%w: invalid %s ticket '%s' (e.g. %s)", errUsage, provider, key, ticketExample(provider))
+	}
+
+	ref := &TicketRef{Provider: provider, Key: key}
+	if p, err := ticketProvider(provider); err == nil {
+		ref.URL = p.IssueURL(key)
+	}
+	return ref, nil
+}
+
+// ticketExample shows a valid key for a provider
+func ticketExample(provider string) string {
+	switch provider {
+	case providerGitHub, providerGitLab:
+		return "owner/repo#12"
+	case providerAzure:
+		return "Project#1234"
+	}
+	return "ABC-123"
+}
+
+// parseTicketURL recognises issue URLs from each provider
+func parseTicketURL(s string) (*TicketRef, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid ticket URL '%s'", errUsage, s)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	ref := &TicketRef{URL: s}
+
+	switch {
+	case len(parts) >= 2 && parts[len(parts)-2] == "browse":
+		ref.Provider, ref.Key = providerJira, strings.ToUpper(parts[len(parts)-1])
+	case u.Host == "linear.app" && len(parts) >= 3 && parts[1] == "issue":
+		ref.Provider, ref.Key = providerLinear, strings.ToUpper(parts[2])
+	case len(parts) >= 4 && parts[len(parts)-3] == "_workitems" && parts[len(parts)-2] == "edit":
+		// dev.azure.com/<org>/<project>/_workitems/edit/<id>
+		ref.Provider, ref.Key = providerAzure, parts[len(parts)-4]+"#"+parts[len(parts)-1]
+	case len(parts) >= 5 && parts[len(parts)-3] == "-" && parts[len(parts)-2] == "issues":
+		ref.Provider = providerGitLab
+		ref.Key = strings.Join(parts[:len(parts)-3], "/") + "#" + parts[len(parts)-1]
+	case len(parts) == 4 && parts[2] == "issues":
+		ref.Provider, ref.Key = providerGitHub, parts[0]+"/"+parts[1]+"#"+parts[3]
+	default:
+		return nil, fmt.Errorf("%w: unrecognised ticket URL '%s'", errUsage, s)
+	}
+	return ref, nil
+}
+
+// Issue is a ticket as fetched from its provider
+type Issue struct {
+	Key      string
+	Title    string
+	State    string
+	URL      string
+	Estimate *Estimate // nil if the provider has no time tracking
+}
+
+// TicketProvider is an issue tracker sessions can log time and comments to.
+// Trackers without time tracking record worklogs as comments.
+type TicketProvider interface {
+	// GetIssue fetches an issue, including its time tracking if any
+	GetIssue(key string) (*Issue, error)
+
+	// PostWorklog logs time spent on an issue starting at started, with an
+	// optional comment
+	PostWorklog(key string, spent time.Duration, started time.Time, comment string) error
+
+	// PostComment adds a comment, written in light markdown, to an issue
+	PostComment(key, text string) error
+
+	// IssueURL is the issue's web page
+	IssueURL(key string) string
+}
+
+// notConfiguredError is returned by ticketProvider for providers whose
+// credentials aren't set
+type notConfiguredError struct {
+	provider string
+	env      string
+}
+
+func (e notConfiguredError) Error() string {
+	return fmt.Sprintf("%s isn't configured (set %s)", e.provider, e.env)
+}
+
+func (e notConfiguredError) Unwrap() error { return errUsage }
+
+// ticketProvider returns the configured client for a provider
+func ticketProvider(provider string) (TicketProvider, error) {
+	var p TicketProvider
+	var env string
+	switch provider {
+	case providerJira:
+		if c := newJiraClient(); c != nil {
+			p = c
+		}
+		env = "JIRA_URL and JIRA_API_TOKEN"
+	case providerGitHub:
+		if c := newGitHubClient(); c != nil {
+			p = c
+		}
+		env = "GITHUB_TOKEN"
+	case providerGitLab:
+		if c := newGitLabClient(); c != nil {
+			p = c
+		}
+		env = "GITLAB_TOKEN"
+	case providerLinear:
+		if c := newLinearClient(); c != nil {
+			p = c
+		}
+		env = "LINEAR_API_KEY"
+	case providerAzure:
+		if c := newAzureClient(); c != nil {
+			p = c
+		}
+		env = "AZURE_DEVOPS_ORG_URL and AZURE_DEVOPS_TOKEN"
+	default:
+		return nil, fmt.Errorf("%w: unknown ticket provider '%s'", errUsage, provider)
+	}
+	if p == nil {
+		return nil, notConfiguredError{provider: provider, env: env}
+	}
+	return p, nil
+}
+
+// restClient sends JSON requests to a tracker's REST API
+type restClient struct {
+	name string // service name for errors
+	base string
+	auth func(*http.Request)
+	http *http.Client
+}
+
+func newRESTClient(name, base string, auth func(*http.Request)) restClient {
+	return restClient{
+		name: name,
+		base: strings.TrimSuffix(base, "/"),
+		auth: auth,
+		http: &http.Client{Timeout: 15 * time.Second, Transport: httpTransport()},
+	}
+}
+
+// do sends body as JSON and decodes the JSON response into out unless
+// it's nil
+func (c *restClient) do(method, path string, body, out any) error {
+	return c.send(method, path, "application/json", body, out)
+}
+
+// send is do with a custom request content type
+func (c *restClient) send(method, path, contentType string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.base+path, reader)
+	if err != nil {
+		return err
+	}
+	c.auth(req)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent())
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s not reachable: %v", errIntegration, c.name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: %s %s %s: %s %s", errIntegration, c.name, method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: invalid %s response: %v", errIntegration, c.name, err)
+	}
+	return nil
+}
+
+// worklogComment is the comment trackers without time tracking get for a
+// worklog
+func worklogComment(spent time.Duration, started time.Time, comment string) string {
+	text := fmt.Sprintf("⏱️ Logged %s from %s", formatTimeSpent(spent), started.Local().Format("2006-01-02 15:04"))
+	if comment != "" {
+		text += "\n\n" + comment
+	}
+	return text
+}
+
+// splitRepoIssue splits owner/repo#12 into its repository and number
+func splitRepoIssue(key string) (repo, number string) {
+	i := strings.LastIndex(key, "#")
+	if i < 0 {
+		return key, ""
+	}
+	return key[:i], key[i+1:]
+}
+
+// Estimate is an issue's time tracking, as of FetchedAt
+type Estimate struct {
+	OriginalSeconds  int64  `json:"original_seconds"`
+	RemainingSeconds int64  `json:"remaining_seconds"`
+	LoggedSeconds    int64  `json:"logged_seconds"` // time logged in the tracker
+	FetchedAt        string `json:"fetched_at"`
+}
+
+// refreshEstimate fetches the ticket's time tracking when its provider is
+// configured and has any, keeping the stored estimate if it can't. Failures
+// are returned for the caller to report as a warning.
+func (t *TaskTracker) refreshEstimate() error {
+	if t.Ticket == nil {
+		return nil
+	}
+	p, err := ticketProvider(t.Ticket.Provider)
+	if err != nil {
+		return nil
+	}
+	issue, err := p.GetIssue(t.Ticket.Key)
+	if err != nil {
+		return err
+	}
+	if issue.URL != "" {
+		t.Ticket.URL = issue.URL
+	}
+	if issue.Estimate != nil {
+		t.Estimate = issue.Estimate
+	}
+	return nil
+}
+
+// postTicket logs the session's time on its ticket, marks it logged and
+// posts the comment, as the smart commit would once pushed. It's marked
+// before the comment, so a failed comment never gets the time logged twice:
+// a session already logged only has its comment posted again.
+func (t *TaskTracker) postTicket() error {
+	p, err := ticketProvider(t.Ticket.Provider)
+	if err != nil {
+		return err
+	}
+	spent, err := parseTimeSpent(t.timeSpent())
+	if err != nil {
+		return err
+	}
+
+	if t.LoggedAt == "" {
+		// Trackers refuse empty worklogs
+		if spent > 0 {
+			if err := p.PostWorklog(t.Ticket.Key, spent, t.StartTime, t.TaskName); err != nil {
+				return err
+			}
+		}
+		t.LoggedAt = time.Now().Format(time.RFC3339)
+		if err := t.saveMetadata(); err != nil {
+			return fmt.Errorf("logged, but failed to mark the session logged: %w", err)
+		}
+	}
+	if err := p.PostComment(t.Ticket.Key, t.commitComment()); err != nil {
+		return fmt.Errorf("logged %s, but failed to post the summary: %w", formatTimeSpent(spent), err)
+	}
+	return nil
+}
+
+// ticketTotal sums the active time of every saved session for a ticket
+func ticketTotal(ticket *TicketRef) (time.Duration, int) {
+	sessions, err := listSessions()
+	if err != nil {
+		return 0, 0
+	}
+	var total float64
+	count := 0
+	for _, s := range sessions {
+		if s.ticket().Same(ticket) {
+			total += s.DurationSeconds
+			count++
+		}
+	}
+	return time.Duration(total * float64(time.Second)), count
+}
+
+// estimateDelta describes tracked time against the original estimate,
+// e.g. "5h 20m tracked of 4h 0m estimate (+1h 20m, 133%)". It returns ""
+// without a ticket or an estimate.
+func (t *TaskTracker) estimateDelta() string {
+	if t.Ticket == nil || t.Estimate == nil || t.Estimate.OriginalSeconds <= 0 {
+		return ""
+	}
+	tracked, _ := ticketTotal(t.Ticket)
+	return formatEstimateDelta(tracked, time.Duration(t.Estimate.OriginalSeconds)*time.Second)
+}
+
+// formatEstimateDelta describes tracked time against an estimate
+func formatEstimateDelta(tracked, estimate time.Duration) string {
+	delta := tracked - estimate
+	sign := "+"
+	if delta < 0 {
+		sign, delta = "-", -delta
+	}
+	return i18n.T("ticket.estimate_delta",
+		formatTimeSpent(tracked), formatTimeSpent(estimate), sign, formatTimeSpent(delta),
+		tracked.Seconds()/estimate.Seconds()*100)
+}
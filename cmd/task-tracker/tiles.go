@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/imaging"
+)
+
+// tilesDir holds tiles cut from large frames for the review
+const tilesDir = "tiles"
+
+// frameTile is one labeled piece of a frame too large for the vision model
+type frameTile struct {
+	path  string
+	label string
+	size  image.Point
+}
+
+// tileFrame cuts the frame at path into labeled tiles no larger than
+// maxSize, so models with low image limits see it at full detail instead of
+// downscaled. Frames that already fit return no tiles. Tiles are kept in
+// tiles/ and reused.
+func (t *TaskTracker) tileFrame(index int, path string, maxSize int) ([]frameTile, error) {
+	img, err := loadFrame(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to tile %s: %w", path, err)
+	}
+	rects, rows, cols := imaging.Tiles(img.Bounds(), maxSize)
+	if len(rects) == 1 {
+		return nil, nil
+	}
+
+	dir := filepath.Join(t.SessionDir, tilesDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	tiles := make([]frameTile, 0, len(rects))
+	for i, r := range rects {
+		row, col := i/cols+1, i%cols+1
+		label := i18n.T("review.tile", index, i+1, len(rects), row, rows, col, cols)
+		tile := frameTile{
+			path:  filepath.Join(dir, fmt.Sprintf("%s_%dx%d_r%dc%d.png", base, rows, cols, row, col)),
+			label: label,
+			size:  r.Size(),
+		}
+		if !fileExists(tile.path) {
+			crop := image.NewRGBA(image.Rectangle{Max: r.Size()})
+			draw.Draw(crop, crop.Bounds(), img, r.Min, draw.Src)
+			imaging.Label(crop, label)
+			if err := writePNG(tile.path, crop, nil); err != nil {
+				return nil, err
+			}
+		}
+		tiles = append(tiles, tile)
+	}
+	return tiles, nil
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/i18n"
+	"task-tracker/internal/ui"
+)
+
+// Timesheet formats
+const (
+	timesheetPivot   = "pivot"    // one row per project, one column per day
+	timesheetSAPCATS = "sap-cats" // one row per project and day, CATS upload columns
+)
+
+// SAP CATS receiver kinds: which CATS field a mapping's project goes in
+const (
+	receiverWBS        = "wbs"
+	receiverOrder      = "order"
+	receiverCostCenter = "cost_center"
+)
+
+// catsTextMax is the length of the CATS short text (LTXA1)
+const catsTextMax = 40
+
+// TimesheetConfig maps tracked time to the projects of a corporate
+// timesheet system for 'task-tracker timesheet'
+type TimesheetConfig struct {
+	Employee     string             `json:"employee,omitempty"`      // personnel number, for sap-cats
+	ActivityType string             `json:"activity_type,omitempty"` // SAP activity type unless a mapping sets one
+	Delimiter    string             `json:"delimiter,omitempty"`     // CSV field separator; default ","
+	Mappings     []TimesheetMapping `json:"mappings,omitempty"`
+}
+
+// TimesheetMapping books the sessions of a ticket or with a tag to a
+// project. The first matching mapping wins.
+type TimesheetMapping struct {
+	Ticket       string `json:"ticket,omitempty"` // ticket key; * and ? are wildcards
+	Tag          string `json:"tag,omitempty"`
+	Project      string `json:"project"`            // WBS element, order or cost center
+	Receiver     string `json:"receiver,omitempty"` // wbs (default), order or cost_center
+	ActivityType string `json:"activity_type,omitempty"`
+}
+
+// Validate checks every mapping matches something and books it somewhere
+func (c TimesheetConfig) Validate() error {
+	if c.Delimiter != "" {
+		r, size := utf8.DecodeRuneInString(c.Delimiter)
+		if size != len(c.Delimiter) || r == '"' || r == '\r' || r == '\n' {
+			return fmt.Errorf("timesheet.delimiter must be one character other than a quote or newline")
+		}
+	}
+	for i, m := range c.Mappings {
+		if m.Ticket == "" && m.Tag == "" {
+			return fmt.Errorf("timesheet.mappings[%d]: set ticket or tag", i)
+		}
+		if m.Project == "" {
+			return fmt.Errorf("timesheet.mappings[%d]: project is required", i)
+		}
+		if _, err := path.Match(m.Ticket, ""); err != nil {
+			return fmt.Errorf("timesheet.mappings[%d]: invalid ticket pattern '%s'", i, m.Ticket)
+		}
+		switch m.Receiver {
+		case "", receiverWBS, receiverOrder, receiverCostCenter:
+		default:
+			return fmt.Errorf("timesheet.mappings[%d]: unknown receiver '%s' (use wbs, order or cost_center)", i, m.Receiver)
+		}
+	}
+	return nil
+}
+
+// mapping finds the mapping for a session, or nil
+func (c TimesheetConfig) mapping(a *SessionAggregates) *TimesheetMapping {
+	for i, m := range c.Mappings {
+		if m.Ticket != "" && a.Ticket != "" {
+			if ok, _ := path.Match(strings.ToUpper(m.Ticket), strings.ToUpper(a.Ticket)); ok {
+				return &c.Mappings[i]
+			}
+		}
+		for _, tag := range a.Tags {
+			if m.Tag != "" && strings.EqualFold(m.Tag, tag) {
+				return &c.Mappings[i]
+			}
+		}
+	}
+	return nil
+}
+
+// timesheetEntry is the time booked to one project on one day
+type timesheetEntry struct {
+	project  string
+	mapping  *TimesheetMapping // nil for unmapped time
+	day      string            // 2006-01-02, local time
+	active   time.Duration
+	sessions []*SessionAggregates
+}
+
+// timesheetEntries groups sessions by project and the day they started.
+// Unmapped sessions are booked to their ticket, or to "(no ticket)" in the
+// selected language.
+func timesheetEntries(aggregates []*SessionAggregates, cfg TimesheetConfig) []*timesheetEntry {
+	byKey := map[string]*timesheetEntry{}
+	var entries []*timesheetEntry
+	for _, a := range aggregates {
+		m := cfg.mapping(a)
+		project := a.Ticket
+		if m != nil {
+			project = m.Project
+		} else if project == "" {
+			project = i18n.T("timesheet.no_ticket")
+		}
+		day := a.StartTime.Local().Format("2006-01-02")
+
+		key := project + "\x00" + day
+		e := byKey[key]
+		if e == nil {
+			e = &timesheetEntry{project: project, mapping: m, day: day}
+			byKey[key] = e
+			entries = append(entries, e)
+		}
+		e.active += time.Duration(a.ActiveMinutes * float64(time.Minute))
+		e.sessions = append(e.sessions, a)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].day != entries[j].day {
+			return entries[i].day < entries[j].day
+		}
+		return entries[i].project < entries[j].project
+	})
+	return entries
+}
+
+// decimalHours formats a duration as decimal hours
+func decimalHours(d time.Duration) string {
+	return fmt.Sprintf("%.2f", d.Hours())
+}
+
+// pivotTimesheet writes one row per project with its hours on each day
+// from the first to the last, and totals
+func pivotTimesheet(w *csv.Writer, entries []*timesheetEntry, rounding RoundingConfig) error {
+	var days []string
+	if len(entries) > 0 {
+		first, _ := time.ParseInLocation("2006-01-02", entries[0].day, time.Local)
+		last, _ := time.ParseInLocation("2006-01-02", entries[len(entries)-1].day, time.Local)
+		for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+			days = append(days, d.Format("2006-01-02"))
+		}
+	}
+
+	booked := map[string]map[string]time.Duration{}
+	for _, e := range entries {
+		if booked[e.project] == nil {
+			booked[e.project] = map[string]time.Duration{}
+		}
+		booked[e.project][e.day] = rounding.Apply(e.active)
+	}
+	projects := make([]string, 0, len(booked))
+	for p := range booked {
+		projects = append(projects, p)
+	}
+	sort.Strings(projects)
+
+	if err := w.Write(append(append([]string{i18n.T("timesheet.project")}, days...), i18n.T("timesheet.total"))); err != nil {
+		return err
+	}
+	dayTotals := make([]time.Duration, len(days))
+	var total time.Duration
+	for _, p := range projects {
+		row := []string{p}
+		var sum time.Duration
+		for i, day := range days {
+			d, ok := booked[p][day]
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, decimalHours(d))
+			dayTotals[i] += d
+			sum += d
+		}
+		total += sum
+		if err := w.Write(append(row, decimalHours(sum))); err != nil {
+			return err
+		}
+	}
+
+	row := []string{i18n.T("timesheet.total")}
+	for _, d := range dayTotals {
+		row = append(row, decimalHours(d))
+	}
+	return w.Write(append(row, decimalHours(total)))
+}
+
+// catsTimesheet writes a row per project and day in the columns of an SAP
+// CATS upload. Unmapped time has no receiver for CATS, so it's left out
+// and returned.
+func catsTimesheet(w *csv.Writer, entries []*timesheetEntry, cfg TimesheetConfig, rounding RoundingConfig) ([]*timesheetEntry, error) {
+	err := w.Write([]string{"PERNR", "WORKDATE", "LSTAR", "RPROJ", "RAUFNR", "RKOSTL", "CATSHOURS", "UNIT", "LTXA1"})
+	if err != nil {
+		return nil, err
+	}
+
+	var unmapped []*timesheetEntry
+	for _, e := range entries {
+		if e.mapping == nil {
+			unmapped = append(unmapped, e)
+			continue
+		}
+		var wbs, order, costCenter string
+		switch e.mapping.Receiver {
+		case receiverOrder:
+			order = e.project
+		case receiverCostCenter:
+			costCenter = e.project
+		default:
+			wbs = e.project
+		}
+		activity := e.mapping.ActivityType
+		if activity == "" {
+			activity = cfg.ActivityType
+		}
+		day, _ := time.ParseInLocation("2006-01-02", e.day, time.Local)
+
+		err := w.Write([]string{cfg.Employee, day.Format("20060102"), activity, wbs, order, costCenter,
+			decimalHours(rounding.Apply(e.active)), "H", catsText(e.sessions)})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return unmapped, nil
+}
+
+// catsText describes what the time went to in the short text CATS allows:
+// the tickets, or the task names without one
+func catsText(sessions []*SessionAggregates) string {
+	seen := map[string]bool{}
+	var parts []string
+	for _, a := range sessions {
+		part := a.Ticket
+		if part == "" {
+			part = a.TaskName
+		}
+		if !seen[part] {
+			seen[part] = true
+			parts = append(parts, part)
+		}
+	}
+	text := strings.Join(parts, ", ")
+	if utf8.RuneCountInString(text) > catsTextMax {
+		text = string([]rune(text)[:catsTextMax-1]) + "…"
+	}
+	return text
+}
+
+// newTimesheetCmd builds the timesheet command
+func newTimesheetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "timesheet [session_id]...",
+		Short: "Export tracked hours as a timesheet CSV for ERP imports",
+		Long: `Export the time of the given sessions, --all or --since as CSV for bulk
+loading into a corporate timesheet system. Sessions are booked to projects
+by the mappings under "timesheet" in the config file (by ticket or tag;
+unmapped ones to their ticket) and to the day they started, and each
+project's hours per day are rounded as configured under "rounding".
+
+Formats:
+  pivot     one row per project, a column per day and totals (default)
+  sap-cats  SAP CATS upload rows: PERNR, WORKDATE, LSTAR, RPROJ, RAUFNR,
+            RKOSTL, CATSHOURS, UNIT, LTXA1. Needs timesheet.employee;
+            unmapped time is left out and listed.
+
+For example the week's hours: 'task-tracker timesheet --since 168h -o week.csv'.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := loadConfig()
+			if err != nil {
+				ui.Printf("❌ Error: %v\n", err)
+				exit(exitCode(err))
+			}
+			format, _ := cmd.Flags().GetString("format")
+			if format != timesheetPivot && format != timesheetSAPCATS {
+				ui.Printf("❌ Unknown format '%s' (use %s or %s)\n", format, timesheetPivot, timesheetSAPCATS)
+				exit(exitUsage)
+			}
+			if format == timesheetSAPCATS && cfg.Timesheet.Employee == "" {
+				ui.Println("❌ sap-cats needs your personnel number as timesheet.employee in the config file")
+				exit(exitUsage)
+			}
+
+			aggregates, err := selectAggregates(cmd, args)
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+			entries := timesheetEntries(aggregates, cfg.Timesheet)
+
+			out := os.Stdout
+			output, _ := cmd.Flags().GetString("output")
+			if output != "" {
+				if out, err = os.Create(output); err != nil {
+					ui.Printf("❌ Failed to save timesheet: %v\n", err)
+					exit(exitCode(err))
+				}
+				defer out.Close()
+			}
+			w := csv.NewWriter(out)
+			if cfg.Timesheet.Delimiter != "" {
+				w.Comma, _ = utf8.DecodeRuneInString(cfg.Timesheet.Delimiter)
+			}
+
+			var unmapped []*timesheetEntry
+			if format == timesheetSAPCATS {
+				unmapped, err = catsTimesheet(w, entries, cfg.Timesheet, cfg.Rounding)
+			} else {
+				err = pivotTimesheet(w, entries, cfg.Rounding)
+			}
+			if err == nil {
+				w.Flush()
+				err = w.Error()
+			}
+			if err != nil {
+				ui.Printf("❌ Failed to write timesheet: %v\n", err)
+				exit(exitCode(err))
+			}
+
+			// On stderr, so they don't end up in a CSV written to stdout
+			for _, e := range unmapped {
+				fmt.Fprintf(os.Stderr, "⚠️  Left out %s on %s for %s: no mapping books it to a project\n",
+					formatTimeSpent(e.active), e.day, e.project)
+			}
+			if output != "" {
+				ui.Printf("✅ Timesheet of %d session(s) saved to %s\n", len(aggregates), output)
+			}
+		},
+	}
+	cmd.Flags().String("format", timesheetPivot, "Timesheet format: pivot or sap-cats")
+	cmd.Flags().StringP("output", "o", "", "Write the CSV to a file instead of stdout")
+	cmd.Flags().Bool("all", false, "Export every saved session")
+	cmd.Flags().Duration("since", 0, "Export sessions started within the last duration, e.g. 168h")
+	return cmd
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// timesheetSessions spans three days: two tickets matching one mapping,
+// a tagged meeting mapped to a cost center and an unmapped session
+func timesheetSessions() ([]*SessionAggregates, TimesheetConfig) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 1, day, hour, 0, 0, 0, time.Local)
+	}
+	sessions := []*SessionAggregates{
+		{SessionID: "a", TaskName: "Login fix", Ticket: "PROJ-1", StartTime: at(5, 9), ActiveMinutes: 90},
+		{SessionID: "b", TaskName: "Review", Ticket: "PROJ-2", StartTime: at(5, 14), ActiveMinutes: 30},
+		{SessionID: "c", TaskName: "Email", StartTime: at(6, 10), ActiveMinutes: 20},
+		{SessionID: "d", TaskName: "Standup", Tags: []string{"Meetings"}, StartTime: at(7, 9), ActiveMinutes: 60},
+		{SessionID: "e", TaskName: "Login fix", Ticket: "proj-1", StartTime: at(7, 10), ActiveMinutes: 45},
+	}
+	cfg := TimesheetConfig{
+		Employee:     "00001234",
+		ActivityType: "DEV",
+		Mappings: []TimesheetMapping{
+			{Ticket: "PROJ-*", Project: "P-100"},
+			{Tag: "meetings", Project: "CC-1", Receiver: receiverCostCenter, ActivityType: "MEET"},
+		},
+	}
+	return sessions, cfg
+}
+
+func TestPivotTimesheet(t *testing.T) {
+	sessions, cfg := timesheetSessions()
+	rounding := RoundingConfig{Mode: RoundUp, Increment: Duration{15 * time.Minute}}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := pivotTimesheet(w, timesheetEntries(sessions, cfg), rounding); err != nil {
+		t.Fatalf("pivotTimesheet: %v", err)
+	}
+	w.Flush()
+
+	want := strings.Join([]string{
+		"Project,2026-01-05,2026-01-06,2026-01-07,Total",
+		"(no ticket),,0.50,,0.50",
+		"CC-1,,,1.00,1.00",
+		"P-100,2.00,,0.75,2.75",
+		"Total,2.00,0.50,1.75,4.25",
+	}, "\n") + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("pivot timesheet:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCATSTimesheet(t *testing.T) {
+	sessions, cfg := timesheetSessions()
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	unmapped, err := catsTimesheet(w, timesheetEntries(sessions, cfg), cfg, RoundingConfig{})
+	if err != nil {
+		t.Fatalf("catsTimesheet: %v", err)
+	}
+	w.Flush()
+
+	want := strings.Join([]string{
+		"PERNR,WORKDATE,LSTAR,RPROJ,RAUFNR,RKOSTL,CATSHOURS,UNIT,LTXA1",
+		`00001234,20260105,DEV,P-100,,,2.00,H,"PROJ-1, PROJ-2"`,
+		"00001234,20260107,MEET,,,CC-1,1.00,H,Standup",
+		"00001234,20260107,DEV,P-100,,,0.75,H,proj-1",
+	}, "\n") + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("CATS timesheet:\n%s\nwant:\n%s", got, want)
+	}
+
+	if len(unmapped) != 1 || unmapped[0].project != "(no ticket)" || unmapped[0].active != 20*time.Minute {
+		t.Errorf("unmapped = %+v, want the 20 minutes without a ticket", unmapped)
+	}
+}
+
+func TestCATSTextTruncated(t *testing.T) {
+	var sessions []*SessionAggregates
+	for _, key := range []string{"PROJ-1001", "PROJ-1002", "PROJ-1003", "PROJ-1004", "PROJ-1001"} {
+		sessions = append(sessions, &SessionAggregates{Ticket: key})
+	}
+	text := catsText(sessions)
+	if n := utf8.RuneCountInString(text); n != catsTextMax {
+		t.Errorf("catsText = %q (%d characters), want %d", text, n, catsTextMax)
+	}
+	if !strings.HasPrefix(text, "PROJ-1001, PROJ-1002") || !strings.HasSuffix(text, "…") {
+		t.Errorf("catsText = %q, want the tickets in order, cut with an ellipsis", text)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// corruptDir is where verify --repair moves screenshots that don't decode
+const corruptDir = "corrupt"
+
+// frameProblem describes a screenshot that failed verification
+type frameProblem struct {
+	index  int // 1-based
+	path   string
+	status string
+	err    error
+}
+
+// fileSHA256 returns the hex SHA-256 of a file's contents
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyScreenshots re-reads every screenshot and returns the ones that
+// are missing, don't decode or don't match their checksum
+func (t *TaskTracker) verifyScreenshots() []frameProblem {
+	var problems []frameProblem
+	for i, shot := range t.Screenshots {
+		if _, err := os.Stat(shot.Path); os.IsNotExist(err) {
+			problems = append(problems, frameProblem{index: i + 1, path: shot.Path, status: "missing"})
+			continue
+		}
+
+		if _, err := loadFrame(shot.Path); err != nil {
+			problems = append(problems, frameProblem{index: i + 1, path: shot.Path, status: "corrupt", err: err})
+			continue
+		}
+
+		// Sessions from before checksums were recorded have none
+		if shot.SHA256 == "" {
+			continue
+		}
+		sum, err := fileSHA256(shot.Path)
+		if err != nil {
+			problems = append(problems, frameProblem{index: i + 1, path: shot.Path, status: "unreadable", err: err})
+		} else if sum != shot.SHA256 {
+			problems = append(problems, frameProblem{index: i + 1, path: shot.Path, status: "modified",
+				err: fmt.Errorf("checksum %.12s, expected %.12s", sum, shot.SHA256)})
+		}
+	}
+	return problems
+}
+
+// untrackedFrames lists image files in the session directory that the
+// metadata doesn't mention
+func (t *TaskTracker) untrackedFrames() []string {
+	tracked := make(map[string]bool)
+	for _, shot := range t.Screenshots {
+		tracked[filepath.Base(shot.Path)] = true
+	}
+
+	entries, err := os.ReadDir(t.SessionDir)
+	if err != nil {
+		return nil
+	}
+
+	var untracked []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "screen_") || tracked[name] {
+			continue
+		}
+		untracked = append(untracked, name)
+	}
+	sort.Strings(untracked)
+	return untracked
+}
+
+// repairScreenshots removes failed screenshots from the session, moving
+// corrupt and modified files aside instead of deleting them
+func (t *TaskTracker) repairScreenshots(problems []frameProblem) error {
+	indices := []int{}
+	for _, p := range problems {
+		indices = append(indices, p.index)
+		if p.status == "missing" || t.Screenshots[p.index-1].Blob != "" {
+			continue
+		}
+
+		dest := filepath.Join(t.SessionDir, corruptDir, filepath.Base(p.path))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := os.Rename(p.path, dest); err != nil {
+			return fmt.Errorf("failed to move %s aside: %w", p.path, err)
+		}
+	}
+	// Their files are missing or moved aside, so there's nothing to delete
+	_, err := t.dropScreenshots(indices)
+	return err
+}
+
+// newVerifyCmd builds the verify command
+func newVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [session_id]",
+		Short: "Check that every screenshot of a session is intact",
+		Long: `Re-read every screenshot of a session and check that it exists, decodes and
+matches the SHA-256 checksum recorded when it was saved, before archiving or
+submitting it. A checksum mismatch means the file was changed after capture.
+
+With --repair, missing screenshots are removed from the metadata and corrupt or
+modified ones are moved to the session's corrupt/ directory, then the review
+file is regenerated.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			repair, _ := cmd.Flags().GetBool("repair")
+
+			tracker, err := loadSession(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitCode(err))
+			}
+
+			ui.Printf("🔍 Verifying %d screenshot(s) in %s...\n", len(tracker.Screenshots), tracker.SessionID)
+			problems := tracker.verifyScreenshots()
+
+			if untracked := tracker.untrackedFrames(); len(untracked) > 0 {
+				ui.Printf("⚠️  %d file(s) not in metadata: %s\n", len(untracked), strings.Join(untracked, ", "))
+			}
+
+			if len(problems) == 0 {
+				ui.Printf("✅ All %d screenshot(s) OK\n", len(tracker.Screenshots))
+				return
+			}
+
+			table := ui.NewTable("#", "File", "Problem")
+			for _, p := range problems {
+				detail := p.status
+				if p.err != nil {
+					detail = fmt.Sprintf("%s: %v", p.status, p.err)
+				}
+				table.AddRow(fmt.Sprintf("%d", p.index), filepath.Base(p.path), ui.Red(detail))
+			}
+			ui.Println()
+			table.Render()
+			ui.Println()
+
+			if !repair {
+				ui.Printf("❌ %d of %d screenshot(s) failed verification\n", len(problems), len(tracker.Screenshots))
+				ui.Printf("💡 Run 'task-tracker verify %s --repair' to remove them from the session\n", tracker.SessionID)
+				exit(exitError)
+			}
+
+			if err := tracker.repairScreenshots(problems); err != nil {
+				ui.Printf("❌ Repair failed: %v\n", err)
+				exit(exitCode(err))
+			}
+			if err := tracker.saveMetadata(); err != nil {
+				ui.Printf("❌ Failed to save metadata: %v\n", err)
+				exit(exitCode(err))
+			}
+			if fileExists(filepath.Join(tracker.SessionDir, "review.md")) {
+				if err := tracker.GenerateReviewFile(5); err != nil {
+					ui.Printf("⚠️  Failed to regenerate review file: %v\n", err)
+				}
+			}
+
+			ui.Printf("🔧 Removed %d screenshot(s); %d remain\n", len(problems), len(tracker.Screenshots))
+		},
+	}
+
+	cmd.Flags().Bool("repair", false, "Remove missing and corrupt screenshots from the session")
+	return cmd
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+)
+
+// Build metadata, injected by the Makefile via -ldflags "-X main.Version=..."
+var (
+	Version   = "dev"
+	BuildTime = "unknown"
+	GitCommit = "unknown"
+)
+
+// userAgent identifies task-tracker to external services
+func userAgent() string {
+	return fmt.Sprintf("task-tracker/%s (%s; %s; %s)", Version, runtime.GOOS, runtime.GOARCH, GitCommit)
+}
+
+// newVersionCmd builds the version command
+func newVersionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show version and build information",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if short, _ := cmd.Flags().GetBool("short"); short {
+				ui.Println(Version)
+				return
+			}
+
+			ui.Printf("task-tracker %s\n", Version)
+			ui.Printf("  Commit:     %s\n", GitCommit)
+			ui.Printf("  Built:      %s\n", BuildTime)
+			ui.Printf("  Go:         %s\n", runtime.Version())
+			ui.Printf("  Platform:   %s/%s\n", runtime.GOOS, runtime.GOARCH)
+			ui.Printf("  User-Agent: %s\n", userAgent())
+		},
+	}
+
+	cmd.Flags().Bool("short", false, "Print only the version number")
+	return cmd
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/ui"
+)
+
+// videoKeyframeSeconds is how often the encoder writes a keyframe, so
+// players can seek without decoding from the start
+const videoKeyframeSeconds = 10
+
+// Video is a monitor's continuous recording in video mode
+type Video struct {
+	Path         string  `json:"path"` // relative to the session directory
+	Monitor      int     `json:"monitor"`
+	FPS          float64 `json:"fps"`
+	Width        int     `json:"width"`
+	Height       int     `json:"height"`
+	Frames       int     `json:"frames"`
+	Dropped      int     `json:"dropped,omitempty"` // frames skipped while the encoder was busy
+	StartTime    string  `json:"start_time"`
+	RelativeTime float64 `json:"relative_time"`       // seconds into the session of the first frame
+	Imported     bool    `json:"imported,omitempty"`  // recorded elsewhere and added with keyframes --import
+	Keyframes    int     `json:"keyframes,omitempty"` // screenshots extracted from it
+}
+
+// videoStream pipes one monitor's frames to an ffmpeg process. Frames carry
+// wall-clock timestamps, so time skipped while capture was paused stays
+// skipped in the video instead of being squashed.
+type videoStream struct {
+	video  Video
+	size   image.Point
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr bytes.Buffer
+	frames chan *image.RGBA
+	done   chan struct{}
+	err    error // first write error; the stream discards frames after it
+	warned bool  // a frame of the wrong size was reported
+}
+
+// newVideoStream starts ffmpeg encoding frames of size to path as VP9
+func newVideoStream(path string, size image.Point, fps float64) (*videoStream, error) {
+	args := []string{
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-f", "rawvideo", "-pix_fmt", "rgba", "-s", fmt.Sprintf("%dx%d", size.X, size.Y),
+		"-use_wallclock_as_timestamps", "1", "-i", "-",
+		// yuv420p needs even dimensions
+		"-vf", "pad=ceil(iw/2)*2:ceil(ih/2)*2",
+		"-c:v", "libvpx-vp9", "-pix_fmt", "yuv420p", "-deadline", "realtime", "-cpu-used", "8",
+		"-row-mt", "1", "-b:v", "0", "-crf", "40",
+		"-g", strconv.Itoa(max(1, int(fps*videoKeyframeSeconds))),
+		"-vsync", "vfr", path,
+	}
+	s := &videoStream{
+		size:   size,
+		cmd:    exec.Command("ffmpeg", args...),
+		frames: make(chan *image.RGBA, 1),
+		done:   make(chan struct{}),
+	}
+	s.cmd.Stderr = &s.stderr
+	stdin, err := s.cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	s.stdin = stdin
+	if err := s.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	go func() {
+		defer close(s.done)
+		for img := range s.frames {
+			if s.err == nil {
+				s.err = writeRaw(s.stdin, img)
+			}
+			capture.Release(img)
+		}
+	}()
+	return s, nil
+}
+
+// writeRaw writes img's pixels row by row unless they're contiguous
+func writeRaw(w io.Writer, img *image.RGBA) error {
+	width := 4 * img.Rect.Dx()
+	if img.Stride == width {
+		_, err := w.Write(img.Pix[:width*img.Rect.Dy()])
+		return err
+	}
+	for y := 0; y < img.Rect.Dy(); y++ {
+		if _, err := w.Write(img.Pix[y*img.Stride : y*img.Stride+width]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add queues a frame, dropping it if the encoder hasn't taken the last one
+func (s *videoStream) add(img *image.RGBA) {
+	if img.Rect.Size() != s.size {
+		if !s.warned {
+			s.warned = true
+			ui.Printf("⚠️  Monitor %d changed size to %dx%d; its video skips frames until it's back to %dx%d\n",
+				s.video.Monitor, img.Rect.Dx(), img.Rect.Dy(), s.size.X, s.size.Y)
+		}
+		capture.Release(img)
+		return
+	}
+	select {
+	case s.frames <- img:
+		s.video.Frames++
+	default:
+		s.video.Dropped++
+		capture.Release(img)
+	}
+}
+
+// close flushes the queued frame and waits for ffmpeg to finish the file
+func (s *videoStream) close() error {
+	close(s.frames)
+	<-s.done
+	s.stdin.Close()
+	err := s.cmd.Wait()
+	if s.err != nil {
+		err = s.err
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(s.stderr.String()); msg != "" {
+			return fmt.Errorf("ffmpeg failed on %s: %v: %s", filepath.Base(s.video.Path), err, msg)
+		}
+		return fmt.Errorf("ffmpeg failed on %s: %w", filepath.Base(s.video.Path), err)
+	}
+	return nil
+}
+
+// recordVideoFrame captures a video frame of each monitor the last capture
+// tick allowed. The capture loop calls it between ticks, so privacy,
+// blackouts, disconnects and rules pause the video just like screenshots.
+func (t *TaskTracker) recordVideoFrame(now time.Time) {
+	if t.VideoFPS <= 0 || len(t.videoMonitors) == 0 || t.private(now) || !t.pausedSince().IsZero() {
+		return
+	}
+	if t.video == nil {
+		t.video = make(map[int]*videoStream)
+	}
+	regions := t.maskedRegions()
+	for _, m := range t.videoMonitors {
+		img, err := t.Capturer.Capture(m)
+		if err != nil {
+			continue // the next capture tick records monitor failures
+		}
+		maskFrame(img, regions, t.Capturer.Bounds(m))
+		if t.Watermark != nil {
+			t.Watermark.Draw(img)
+		}
+
+		s := t.video[m]
+		if s == nil {
+			path := filepath.Join(t.SessionDir, fmt.Sprintf("screen_m%d.webm", m+1))
+			if s, err = newVideoStream(path, img.Rect.Size(), t.VideoFPS); err != nil {
+				ui.Printf("❌ Video of monitor %d: %v; recording screenshots only\n", m+1, err)
+				capture.Release(img)
+				t.VideoFPS = 0
+				return
+			}
+			s.video = Video{
+				Path:         path,
+				Monitor:      m + 1,
+				FPS:          t.VideoFPS,
+				Width:        img.Rect.Dx(),
+				Height:       img.Rect.Dy(),
+				StartTime:    now.Format(time.RFC3339),
+				RelativeTime: now.Sub(t.StartTime).Seconds(),
+			}
+			t.video[m] = s
+		}
+		s.add(img)
+	}
+}
+
+// stopVideo finishes every recording and adds them to the session
+func (t *TaskTracker) stopVideo() {
+	monitors := make([]int, 0, len(t.video))
+	for m := range t.video {
+		monitors = append(monitors, m)
+	}
+	sort.Ints(monitors)
+	for _, m := range monitors {
+		s := t.video[m]
+		if err := s.close(); err != nil {
+			ui.Printf("⚠️  %v\n", err)
+		}
+		if s.video.Dropped > 0 {
+			ui.Printf("⚠️  Video of monitor %d: %d frame(s) dropped while the encoder was busy\n", s.video.Monitor, s.video.Dropped)
+		}
+		t.mu.Lock()
+		t.Videos = append(t.Videos, s.video)
+		t.mu.Unlock()
+	}
+	t.video = nil
+}
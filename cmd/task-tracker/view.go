@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+	"task-tracker/internal/viewer"
+)
+
+// newViewCmd builds the view command
+func newViewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "view [session_id|bundle]",
+		Short: "Browse a session or exported bundle read-only in a local web page",
+		Long: `Serve a read-only gallery of a session: its screenshots with captions and
+markers, notes, ticket and summary. The argument is a session in task_captures
+or a bundle made with 'task-tracker export' (.ttsession or .zip).
+
+Nothing is changed and redactions are applied. For reviewers who don't capture,
+the task-viewer binary does the same without any capture code.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			target := args[0]
+			if info, err := os.Stat(target); err != nil || !info.Mode().IsRegular() {
+				id, err := resolveSession(target)
+				if err != nil {
+					ui.Printf("❌ %v\n", err)
+					exit(exitCode(err))
+				}
+				target = filepath.Join(capturesDir, id)
+			}
+
+			port, _ := cmd.Flags().GetInt("port")
+			if err := serveViewer(target, port, false); err != nil {
+				ui.Printf("❌ %v\n", err)
+				exit(exitUsage)
+			}
+		},
+	}
+
+	cmd.Flags().Int("port", 0, "Port to serve on (default: any free port)")
+	return cmd
+}
+
+// serveViewer serves target until interrupted, opening it in the browser
+// if browse is set
+func serveViewer(target string, port int, browse bool) error {
+	v, err := viewer.Open(target)
+	if err != nil {
+		return err
+	}
+	defer v.Close()
+
+	srv, err := v.Listen(port)
+	if err != nil {
+		return err
+	}
+	go srv.Serve()
+	defer srv.Close()
+
+	ui.Printf("👀 %s: %s\n", v.Session.SessionID, v.Session.TaskName)
+	ui.Printf("   Open %s\n", srv.URL())
+	if browse {
+		if err := openExternally(srv.URL()); err != nil {
+			ui.Printf("⚠️  Couldn't open the browser: %v\n", err)
+		}
+	}
+	ui.Println("   Press Ctrl+C to stop")
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	<-interrupt
+	return nil
+}
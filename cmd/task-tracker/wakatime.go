@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"task-tracker/internal/ui"
+)
+
+const (
+	defaultWakaTimeURL = "https://api.wakatime.com/api/v1"
+
+	// wakaTimeEvery is how often heartbeats are sent; WakaTime joins
+	// heartbeats less than 15 minutes apart into one duration
+	wakaTimeEvery = 2 * time.Minute
+)
+
+// wakaTime sends heartbeats for a capture session so WakaTime counts the
+// same time task-tracker does
+type wakaTime struct {
+	apiKey string
+	apiURL string
+	http   *http.Client
+
+	mu     sync.Mutex
+	last   time.Time
+	warned bool
+}
+
+// wakaTimeHeartbeat is the request body of the heartbeats endpoint
+type wakaTimeHeartbeat struct {
+	Entity   string  `json:"entity"`
+	Type     string  `json:"type"`
+	Category string  `json:"category"`
+	Time     float64 `json:"time"`
+	Project  string  `json:"project,omitempty"`
+}
+
+// newWakaTime reads the API key from WAKATIME_API_KEY or the [settings]
+// section of ~/.wakatime.cfg, the file WakaTime's editor plugins use
+func newWakaTime() (*wakaTime, error) {
+	w := &wakaTime{
+		apiKey: os.Getenv("WAKATIME_API_KEY"),
+		apiURL: defaultWakaTimeURL,
+		http:   &http.Client{Timeout: 10 * time.Second, Transport: httpTransport()},
+	}
+
+	settings, err := readWakaTimeConfig()
+	if err != nil {
+		return nil, err
+	}
+	if w.apiKey == "" {
+		w.apiKey = settings["api_key"]
+	}
+	if u := settings["api_url"]; u != "" {
+		w.apiURL = strings.TrimSuffix(u, "/")
+	}
+
+	if w.apiKey == "" {
+		return nil, fmt.Errorf("%w: no WakaTime API key (set WAKATIME_API_KEY or api_key in ~/.wakatime.cfg)", errUsage)
+	}
+	return w, nil
+}
+
+// readWakaTimeConfig returns the [settings] keys of .wakatime.cfg, which
+// lives in $WAKATIME_HOME or the home directory
+func readWakaTimeConfig() (map[string]string, error) {
+	settings := map[string]string{}
+
+	dir := os.Getenv("WAKATIME_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return settings, nil
+		}
+		dir = home
+	}
+
+	f, err := os.Open(filepath.Join(dir, ".wakatime.cfg"))
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WakaTime config: %w", err)
+	}
+	defer f.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+		case section == "settings":
+			if key, value, ok := strings.Cut(line, "="); ok {
+				settings[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return settings, scanner.Err()
+}
+
+// tick sends a heartbeat if the last one was long enough ago. It never
+// blocks the capture loop, and only the first failure is reported.
+func (w *wakaTime) tick(t *TaskTracker, now time.Time) {
+	w.mu.Lock()
+	if !w.last.IsZero() && now.Sub(w.last) < wakaTimeEvery {
+		w.mu.Unlock()
+		return
+	}
+	w.last = now
+	w.mu.Unlock()
+
+	project := t.Ticket.String()
+	if project == "" {
+		project = t.TaskName
+	}
+	hb := wakaTimeHeartbeat{
+		Entity:   t.TaskName,
+		Type:     "app",
+		Category: "coding",
+		Time:     float64(now.UnixNano()) / 1e9,
+		Project:  project,
+	}
+
+	go func() {
+		if err := w.send(hb); err != nil {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			if !w.warned {
+				ui.Printf("⚠️  WakaTime heartbeat failed: %v\n", err)
+				w.warned = true
+			}
+		}
+	}()
+}
+
+func (w *wakaTime) send(hb wakaTimeHeartbeat) error {
+	body, err := json.Marshal(hb)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", w.apiURL+"/users/current/heartbeats", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(w.apiKey)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent())
+
+	resp, err := w.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errIntegration, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: WakaTime returned %s", errIntegration, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"time"
+
+	"task-tracker/internal/imaging"
+)
+
+// WatermarkConfig stamps frames with the task name, timestamp and optional
+// custom text or logo, for audit and evidence use
+type WatermarkConfig struct {
+	Enabled bool    `json:"enabled"`
+	Text    string  `json:"text,omitempty"`
+	Logo    string  `json:"logo,omitempty"`    // path to a PNG
+	Corner  string  `json:"corner,omitempty"`  // top-left, top-right, bottom-left, bottom-right
+	Opacity float64 `json:"opacity,omitempty"` // 0-1, default 0.8
+}
+
+// Validate checks corner and opacity
+func (w WatermarkConfig) Validate() error {
+	if _, err := imaging.ParseCorner(w.Corner); err != nil {
+		return fmt.Errorf("watermark: %w", err)
+	}
+	if w.Opacity < 0 || w.Opacity > 1 {
+		return fmt.Errorf("watermark: opacity must be between 0 and 1, got %g", w.Opacity)
+	}
+	return nil
+}
+
+// build loads the logo and returns the watermark template
+func (w WatermarkConfig) build() (*imaging.Watermark, error) {
+	corner, err := imaging.ParseCorner(w.Corner)
+	if err != nil {
+		return nil, err
+	}
+
+	wm := &imaging.Watermark{Corner: corner, Opacity: w.Opacity}
+	if wm.Opacity == 0 {
+		wm.Opacity = 0.8
+	}
+	if w.Text != "" {
+		wm.Lines = []string{w.Text}
+	}
+
+	if w.Logo != "" {
+		f, err := os.Open(w.Logo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open watermark logo: %w", err)
+		}
+		defer f.Close()
+
+		if wm.Logo, err = png.Decode(f); err != nil {
+			return nil, fmt.Errorf("failed to decode watermark logo %s: %w", w.Logo, err)
+		}
+	}
+	return wm, nil
+}
+
+// stampWatermark draws the session watermark onto a captured frame
+func (t *TaskTracker) stampWatermark(img *image.RGBA, at time.Time) {
+	if t.Watermark == nil {
+		return
+	}
+
+	wm := *t.Watermark
+	wm.Lines = append([]string{t.TaskName, at.Format("2006-01-02 15:04:05 MST")}, t.Watermark.Lines...)
+	wm.Draw(img)
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"task-tracker/internal/capture"
+	"task-tracker/internal/imaging"
+	"task-tracker/internal/telemetry"
+	"task-tracker/internal/ui"
+)
+
+// writeQueueSize is how many ticks may wait for the disk before new ones
+// are dropped
+const writeQueueSize = 2
+
+// capturedFrame is a frame waiting to be written
+type capturedFrame struct {
+	img        *image.RGBA
+	monitorIdx int
+	filename   string
+	cursor     *image.Point  // mouse position in img, if it was on this monitor
+	luma       *imaging.Luma // for duplicates once written; nil to not compare with it
+}
+
+// capturedTick holds every monitor's frame from one tick
+type capturedTick struct {
+	at     time.Time
+	frames []capturedFrame
+	window string // focused window, for the privacy classifier
+	source string // Screenshot.Source of its frames
+}
+
+// DroppedFrame records a tick whose frames were discarded because they
+// couldn't be written in time
+type DroppedFrame struct {
+	Timestamp    string  `json:"timestamp"`
+	RelativeTime float64 `json:"relative_time"`
+	Frames       int     `json:"frames"`
+	Reason       string  `json:"reason"`
+}
+
+// startWriter starts the goroutine that encodes and saves frames, so a
+// slow disk delays writes instead of the capture ticker
+func (t *TaskTracker) startWriter() {
+	t.writeQueue = make(chan capturedTick, writeQueueSize)
+	t.writerDone = make(chan struct{})
+
+	go func() {
+		defer close(t.writerDone)
+		for tick := range t.writeQueue {
+			t.deferWhileBusy()
+			t.writeTick(tick)
+		}
+	}()
+}
+
+// stopWriter waits for queued frames to be written
+func (t *TaskTracker) stopWriter() {
+	if t.writeQueue == nil {
+		return
+	}
+	close(t.writeQueue)
+	<-t.writerDone
+	t.writeQueue = nil
+}
+
+// queueTick hands a tick to the writer, dropping it if the writer is
+// still busy with earlier ticks
+func (t *TaskTracker) queueTick(tick capturedTick) {
+	if t.Classifier != nil {
+		tick.window = t.activeWindow()
+	}
+	select {
+	case t.writeQueue <- tick:
+	default:
+		for _, f := range tick.frames {
+			capture.Release(f.img)
+		}
+		t.recordDrop(tick.at, len(tick.frames), "disk not keeping up")
+	}
+}
+
+// recordDrop notes discarded frames in the session
+func (t *TaskTracker) recordDrop(at time.Time, frames int, reason string) {
+	t.mu.Lock()
+	t.Dropped = append(t.Dropped, DroppedFrame{
+		Timestamp:    at.Format(time.RFC3339),
+		RelativeTime: at.Sub(t.StartTime).Seconds(),
+		Frames:       frames,
+		Reason:       reason,
+	})
+	t.mu.Unlock()
+
+	ui.Printf("⚠️  Dropped %d frame(s) at %s: %s\n", frames, at.Format("15:04:05"), reason)
+	t.log(logWarn, "drop", fmt.Sprintf("Dropped %d frame(s): %s", frames, reason))
+	telemetry.Add("task_tracker.frames.dropped", "1", int64(frames), telemetry.A("reason", reason))
+}
+
+// droppedFrames counts all dropped frames
+func (t *TaskTracker) droppedFrames() int {
+	n := 0
+	for _, d := range t.Dropped {
+		n += d.Frames
+	}
+	return n
+}
+
+// writeTick saves a tick's frames and adds them to the session
+func (t *TaskTracker) writeTick(tick capturedTick) {
+	t.mu.Lock()
+	first := len(t.Screenshots)
+	t.mu.Unlock()
+
+	for i, f := range tick.frames {
+		f = t.downscale(f)
+		t.stampWatermark(f.img, tick.at)
+		t.drawCursor(f)
+		cursor := cursorPos(f)
+		if t.Classifier != nil && t.quarantine(f, tick.at, tick.window) {
+			capture.Release(f.img)
+			continue
+		}
+
+		if tick.source == "" {
+			t.observeFrame(f.monitorIdx, f.img)
+		}
+
+		bounds := f.img.Bounds()
+		resolution := fmt.Sprintf("%dx%d", bounds.Dx(), bounds.Dy())
+
+		span := telemetry.Start("encode", telemetry.A("session.id", t.SessionID), telemetry.A("monitor", f.monitorIdx+1))
+		path, blob, quality, err := t.saveFrame(f.monitorIdx, f.filename, f.img, t.pngText(f.monitorIdx, resolution, tick.at))
+		t.releaseFrame(f.monitorIdx, f.img)
+		if info, statErr := os.Stat(path); err == nil && statErr == nil {
+			span.Set("file.size", info.Size())
+			span.Set("file.extension", strings.TrimPrefix(filepath.Ext(path), "."))
+			telemetry.Add("task_tracker.bytes_written", "By", info.Size())
+		}
+		telemetry.Observe("task_tracker.encode.duration", span.End(err))
+		if err != nil {
+			ui.Printf("❌ Failed to save monitor %d: %v\n", f.monitorIdx+1, err)
+			t.log(logError, "error", fmt.Sprintf("Failed to save monitor %d: %v", f.monitorIdx+1, err))
+			for _, rest := range tick.frames[i+1:] {
+				capture.Release(rest.img)
+			}
+			t.recordDrop(tick.at, len(tick.frames)-i, "write failed")
+			break
+		}
+
+		sum, err := fileSHA256(path)
+		if err != nil {
+			ui.Printf("⚠️  Failed to checksum %s: %v\n", path, err)
+		}
+
+		// Times come from the tick, not from when the write finished
+		t.mu.Lock()
+		t.Screenshots = append(t.Screenshots, Screenshot{
+			Path:         path,
+			Blob:         blob,
+			SHA256:       sum,
+			Format:       frameFormat(path),
+			Quality:      quality,
+			Monitor:      f.monitorIdx + 1,
+			Timestamp:    tick.at.Format(time.RFC3339),
+			RelativeTime: tick.at.Sub(t.StartTime).Seconds(),
+			Resolution:   resolution,
+			Cursor:       cursor,
+			Source:       tick.source,
+		})
+		t.mu.Unlock()
+		t.keep(f)
+	}
+
+	t.mu.Lock()
+	t.applyPendingMarkers(first)
+	totalCount := len(t.Screenshots)
+	t.mu.Unlock()
+
+	// Frames from earlier ticks are done being written, so they can be
+	// shrunk while we wait for the next one
+	if t.Optimize {
+		t.optimizeIdle(first)
+	}
+
+	monitorsStr := ""
+	if len(t.MonitorsToCapture) > 1 && !t.Composite {
+		monitors := []string{}
+		for _, f := range tick.frames {
+			monitors = append(monitors, fmt.Sprintf("%d", f.monitorIdx+1))
+		}
+		monitorsStr = fmt.Sprintf(" (monitors: %s)", strings.Join(monitors, ", "))
+	}
+
+	if tick.source == sourceClipboard {
+		ui.Printf("📋 Saved clipboard image: %s (%d total screenshots)\n", tick.at.Format("150405"), totalCount)
+		t.log(logInfo, "capture", fmt.Sprintf("Saved clipboard image (%d total screenshots)", totalCount))
+		return
+	}
+	ui.Printf("📸 Captured: %s%s (%d total screenshots)\n", tick.at.Format("150405"), monitorsStr, totalCount)
+	t.log(logInfo, "capture", fmt.Sprintf("Captured%s (%d total screenshots)", monitorsStr, totalCount))
+}
@@ -0,0 +1,58 @@
+// Task Viewer - Browse task-tracker sessions read-only, without capture
+// Build: go build -o task-viewer ./cmd/task-viewer
+
+package main
+
+import (
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"task-tracker/internal/ui"
+	"task-tracker/internal/viewer"
+)
+
+func main() {
+	var port int
+	rootCmd := &cobra.Command{
+		Use:   "task-viewer [session_dir|bundle]",
+		Short: "Browse a task-tracker session or bundle read-only",
+		Long: `Serve a read-only gallery of a session directory or of a bundle made with
+'task-tracker export' (.ttsession or .zip): screenshots with captions and
+markers, notes, ticket and summary. Redactions are applied.
+
+task-viewer can't capture anything; it's meant for reviewing sessions others
+submitted.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			v, err := viewer.Open(args[0])
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				os.Exit(2)
+			}
+			defer v.Close()
+
+			srv, err := v.Listen(port)
+			if err != nil {
+				ui.Printf("❌ %v\n", err)
+				os.Exit(2)
+			}
+			go srv.Serve()
+			defer srv.Close()
+
+			ui.Printf("👀 %s: %s\n", v.Session.SessionID, v.Session.TaskName)
+			ui.Printf("   Open %s\n", srv.URL())
+			ui.Println("   Press Ctrl+C to stop")
+
+			interrupt := make(chan os.Signal, 1)
+			signal.Notify(interrupt, os.Interrupt)
+			<-interrupt
+		},
+	}
+	rootCmd.Flags().IntVar(&port, "port", 0, "Port to serve on (default: any free port)")
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(2)
+	}
+}
@@ -1,18 +1,21 @@
 module task-tracker
 
-go 1.25
+go 1.25.0
 
 require (
+	github.com/jezek/xgb v1.1.0
 	github.com/kbinani/screenshot v0.0.0-20230812210009-b87d31814237
+	github.com/klauspost/compress v1.20.1
+	github.com/pierrec/lz4/v4 v4.1.30
 	github.com/spf13/cobra v1.8.0
+	go.starlark.net v0.0.0-20260908191801-89a6a09411d5
 	golang.org/x/image v0.31.0
+	golang.org/x/sys v0.42.0
 )
 
 require (
 	github.com/gen2brain/shm v0.0.0-20230802011745-f2460f5984f7 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/jezek/xgb v1.1.0 // indirect
 	github.com/lxn/win v0.0.0-20210218163916-a377121e959e // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/sys v0.12.0 // indirect
 )
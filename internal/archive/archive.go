@@ -0,0 +1,124 @@
+// Package archive selects how files in session bundles are compressed.
+// Bundles stay zip files so they open with standard tools and io/fs, but
+// each entry can use a codec other than deflate:
+//
+//	store    no compression; screenshots are PNGs and barely shrink
+//	deflate  zip's standard method, levels 1-9
+//	zstd     Zstandard as zip method 93 (WinZip, 7-Zip), levels 1-22
+//	lz4      LZ4 frames, levels 0 (fast) to 9, under a private method
+//	         that only task-tracker and task-viewer read
+package archive
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// Codec names
+const (
+	Store   = "store"
+	Deflate = "deflate"
+	Zstd    = "zstd"
+	LZ4     = "lz4"
+)
+
+// Codecs lists every codec, fastest to write first
+var Codecs = []string{Store, LZ4, Deflate, Zstd}
+
+// methodLZ4 marks LZ4 entries. The zip spec assigns no method to LZ4, so
+// this is picked from the unassigned range.
+const methodLZ4 uint16 = 0x4C34
+
+// lz4Levels maps levels 0-9 to the library's
+var lz4Levels = []lz4.CompressionLevel{lz4.Fast, lz4.Level1, lz4.Level2, lz4.Level3,
+	lz4.Level4, lz4.Level5, lz4.Level6, lz4.Level7, lz4.Level8, lz4.Level9}
+
+// DefaultLevel is the level used when none is configured
+func DefaultLevel(codec string) int {
+	switch codec {
+	case Deflate:
+		return 6
+	case Zstd:
+		return 3
+	}
+	return 0
+}
+
+// Levels are the levels worth comparing in a benchmark. Zstd levels are
+// grouped into four speeds, so one of each is enough.
+func Levels(codec string) []int {
+	switch codec {
+	case Deflate:
+		return []int{1, 6, 9}
+	case Zstd:
+		return []int{1, 3, 7, 19}
+	case LZ4:
+		return []int{0, 5, 9}
+	}
+	return []int{0}
+}
+
+// Validate checks a codec name and level
+func Validate(codec string, level int) error {
+	var lo, hi int
+	switch codec {
+	case Store:
+	case Deflate:
+		lo, hi = 1, 9
+	case Zstd:
+		lo, hi = 1, 22
+	case LZ4:
+		lo, hi = 0, 9
+	default:
+		return fmt.Errorf("unknown compression '%s' (use %s)", codec, strings.Join(Codecs, ", "))
+	}
+	if codec != Store && (level < lo || level > hi) {
+		return fmt.Errorf("%s level must be between %d and %d", codec, lo, hi)
+	}
+	return nil
+}
+
+// Writer registers the codec on zw and returns the method to put in each
+// entry's header
+func Writer(zw *zip.Writer, codec string, level int) (uint16, error) {
+	if err := Validate(codec, level); err != nil {
+		return 0, err
+	}
+
+	switch codec {
+	case Store:
+		return zip.Store, nil
+	case Deflate:
+		zw.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(w, level)
+		})
+		return zip.Deflate, nil
+	case Zstd:
+		zw.RegisterCompressor(zstd.ZipMethodWinZip, zstd.ZipCompressor(
+			zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level))))
+		return zstd.ZipMethodWinZip, nil
+	default:
+		zw.RegisterCompressor(methodLZ4, func(w io.Writer) (io.WriteCloser, error) {
+			lw := lz4.NewWriter(w)
+			if err := lw.Apply(lz4.CompressionLevelOption(lz4Levels[level])); err != nil {
+				return nil, err
+			}
+			return lw, nil
+		})
+		return methodLZ4, nil
+	}
+}
+
+// Reader registers the decompressors zip doesn't know on zr
+func Reader(zr *zip.Reader) {
+	zr.RegisterDecompressor(zstd.ZipMethodWinZip, zstd.ZipDecompressor())
+	zr.RegisterDecompressor(methodLZ4, func(r io.Reader) io.ReadCloser {
+		return io.NopCloser(lz4.NewReader(r))
+	})
+}
@@ -0,0 +1,85 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	files := map[string][]byte{
+		"metadata.json":        []byte(strings.Repeat(`{"task_name":"Fix login","screenshots":[]}`, 200)),
+		"screenshots/0001.png": bytes.Repeat([]byte{0x89, 'P', 'N', 'G', 0, 1, 2, 3}, 4096),
+		"empty.txt":            {},
+	}
+
+	for _, codec := range Codecs {
+		for _, level := range Levels(codec) {
+			var buf bytes.Buffer
+			zw := zip.NewWriter(&buf)
+			method, err := Writer(zw, codec, level)
+			if err != nil {
+				t.Fatalf("%s level %d: Writer: %v", codec, level, err)
+			}
+			for name, data := range files {
+				w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+				if err != nil {
+					t.Fatalf("%s level %d: CreateHeader: %v", codec, level, err)
+				}
+				if _, err := w.Write(data); err != nil {
+					t.Fatalf("%s level %d: write %s: %v", codec, level, name, err)
+				}
+			}
+			if err := zw.Close(); err != nil {
+				t.Fatalf("%s level %d: Close: %v", codec, level, err)
+			}
+
+			zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatalf("%s level %d: NewReader: %v", codec, level, err)
+			}
+			Reader(zr)
+			for _, f := range zr.File {
+				if f.Method != method {
+					t.Errorf("%s level %d: %s has method %d, want %d", codec, level, f.Name, f.Method, method)
+				}
+				rc, err := f.Open()
+				if err != nil {
+					t.Fatalf("%s level %d: open %s: %v", codec, level, f.Name, err)
+				}
+				got, err := io.ReadAll(rc)
+				rc.Close()
+				if err != nil {
+					t.Fatalf("%s level %d: read %s: %v", codec, level, f.Name, err)
+				}
+				if !bytes.Equal(got, files[f.Name]) {
+					t.Errorf("%s level %d: %s differs after the round trip", codec, level, f.Name)
+				}
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		codec string
+		level int
+		ok    bool
+	}{
+		{Store, 0, true},
+		{Deflate, 9, true},
+		{Deflate, 0, false},
+		{Zstd, 22, true},
+		{Zstd, 23, false},
+		{LZ4, 0, true},
+		{LZ4, 10, false},
+		{"brotli", 1, false},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.codec, tt.level); (err == nil) != tt.ok {
+			t.Errorf("Validate(%s, %d) = %v, want ok %v", tt.codec, tt.level, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Package capture abstracts screen capture so the tracker can run against
+// real displays or a fake backend that needs no display at all.
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"runtime"
+
+	"github.com/kbinani/screenshot"
+)
+
+// Capturer grabs images from displays, indexed from 0
+type Capturer interface {
+	NumDisplays() int
+	Bounds(display int) image.Rectangle
+	Capture(display int) (*image.RGBA, error)
+}
+
+// Backend names accepted by New
+const (
+	BackendScreen  = "screen"
+	BackendVirtual = "virtual"
+	BackendFake    = "fake"
+)
+
+// New returns the capturer for a backend name. display selects an X11
+// display (e.g. ":99") for the screen and virtual backends; empty means
+// $DISPLAY.
+func New(backend, display string) (Capturer, error) {
+	switch backend {
+	case "", BackendScreen:
+		if CurrentDesktop().Type == DesktopServices {
+			return nil, fmt.Errorf("running in Windows session 0 (services), which has no desktop; start task-tracker from the user's login session")
+		}
+		if display != "" {
+			if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+				return nil, fmt.Errorf("--display is only supported on X11 platforms")
+			}
+			// kbinani/screenshot connects to $DISPLAY
+			os.Setenv("DISPLAY", display)
+		}
+		return Screen{}, nil
+	case BackendVirtual:
+		return NewVirtual(display)
+	case BackendFake:
+		return NewFake(image.Rect(0, 0, 1280, 720)), nil
+	}
+	return nil, fmt.Errorf("unknown capture backend '%s' (use %s, %s or %s)",
+		backend, BackendScreen, BackendVirtual, BackendFake)
+}
+
+// DisplayError is a failed capture of one display
+type DisplayError struct {
+	Display int // 0-based
+	Err     error
+}
+
+func (e *DisplayError) Error() string {
+	return fmt.Sprintf("monitor %d: %v", e.Display+1, e.Err)
+}
+
+func (e *DisplayError) Unwrap() error {
+	return e.Err
+}
+
+// DisplayErrors lists the display failures in err, which may join several
+func DisplayErrors(err error) []*DisplayError {
+	var out []*DisplayError
+	var de *DisplayError
+	switch e := err.(type) {
+	case nil:
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			out = append(out, DisplayErrors(inner)...)
+		}
+	default:
+		if errors.As(err, &de) {
+			out = append(out, de)
+		}
+	}
+	return out
+}
+
+// Screen captures real displays via kbinani/screenshot
+type Screen struct{}
+
+func (Screen) NumDisplays() int {
+	return screenshot.NumActiveDisplays()
+}
+
+func (Screen) Bounds(display int) image.Rectangle {
+	return screenshot.GetDisplayBounds(display)
+}
+
+func (Screen) Capture(display int) (*image.RGBA, error) {
+	return screenshot.CaptureDisplay(display)
+}
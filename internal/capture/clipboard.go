@@ -0,0 +1,13 @@
+package capture
+
+// ClipboardReader is implemented by capturers that can read images copied
+// to the clipboard of the desktop they capture
+type ClipboardReader interface {
+	// ClipboardImage returns the image on the clipboard, encoded as PNG or
+	// BMP, or false when it holds none or can't be read right now
+	ClipboardImage() ([]byte, bool)
+}
+
+func (Screen) ClipboardImage() ([]byte, bool) {
+	return clipboardImage()
+}
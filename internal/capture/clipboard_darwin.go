@@ -0,0 +1,52 @@
+//go:build darwin && cgo
+
+package capture
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+#import <AppKit/AppKit.h>
+#include <stdlib.h>
+#include <string.h>
+
+// clipboardPNG copies the image on the general pasteboard as PNG into a
+// buffer the caller frees, or returns NULL. Screenshots copied with
+// Cmd-Ctrl-Shift-4 and many apps only put TIFF there, which is converted.
+static void *clipboardPNG(int *length) {
+	@autoreleasepool {
+		NSPasteboard *pb = [NSPasteboard generalPasteboard];
+		NSData *data = [pb dataForType:NSPasteboardTypePNG];
+		if (data == nil) {
+			NSData *tiff = [pb dataForType:NSPasteboardTypeTIFF];
+			if (tiff == nil) {
+				return NULL;
+			}
+			NSBitmapImageRep *rep = [NSBitmapImageRep imageRepWithData:tiff];
+			if (rep == nil) {
+				return NULL;
+			}
+			data = [rep representationUsingType:NSBitmapImageFileTypePNG properties:@{}];
+			if (data == nil) {
+				return NULL;
+			}
+		}
+		*length = (int)[data length];
+		void *buf = malloc(*length);
+		if (buf != NULL) {
+			memcpy(buf, [data bytes], *length);
+		}
+		return buf;
+	}
+}
+*/
+import "C"
+
+func clipboardImage() ([]byte, bool) {
+	var length C.int
+	buf := C.clipboardPNG(&length)
+	if buf == nil {
+		return nil, false
+	}
+	defer C.free(buf)
+	return C.GoBytes(buf, length), true
+}
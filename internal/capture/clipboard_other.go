@@ -0,0 +1,7 @@
+//go:build !(windows || darwin || linux || freebsd || openbsd || netbsd) || (darwin && !cgo)
+
+package capture
+
+func clipboardImage() ([]byte, bool) {
+	return nil, false
+}
@@ -0,0 +1,106 @@
+//go:build windows
+
+package capture
+
+import (
+	"encoding/binary"
+	"unsafe"
+
+	_ "golang.org/x/image/bmp" // decodes images copied as bitmaps
+	"golang.org/x/sys/windows"
+)
+
+var (
+	procOpenClipboard              = user32.NewProc("OpenClipboard")
+	procCloseClipboard             = user32.NewProc("CloseClipboard")
+	procGetClipboardData           = user32.NewProc("GetClipboardData")
+	procIsClipboardFormatAvailable = user32.NewProc("IsClipboardFormatAvailable")
+	procRegisterClipboardFormatW   = user32.NewProc("RegisterClipboardFormatW")
+	procGlobalLock                 = windows.NewLazySystemDLL("kernel32.dll").NewProc("GlobalLock")
+	procGlobalUnlock               = windows.NewLazySystemDLL("kernel32.dll").NewProc("GlobalUnlock")
+	procGlobalSize                 = windows.NewLazySystemDLL("kernel32.dll").NewProc("GlobalSize")
+	procRtlMoveMemory              = windows.NewLazySystemDLL("kernel32.dll").NewProc("RtlMoveMemory")
+)
+
+// Clipboard format CF_DIB and the DIB compression BI_BITFIELDS
+const (
+	cfDIB       = 8
+	biBitfields = 3
+)
+
+func clipboardImage() ([]byte, bool) {
+	// Another app may hold the clipboard open for a moment; the next poll
+	// tries again
+	if ok, _, _ := procOpenClipboard.Call(0); ok == 0 {
+		return nil, false
+	}
+	defer procCloseClipboard.Call()
+
+	// Browsers and the Snipping Tool also offer PNG, which keeps
+	// transparency
+	name, _ := windows.UTF16PtrFromString("PNG")
+	if format, _, _ := procRegisterClipboardFormatW.Call(uintptr(unsafe.Pointer(name))); format != 0 {
+		if data, ok := clipboardData(format); ok {
+			return data, true
+		}
+	}
+	dib, ok := clipboardData(cfDIB)
+	if !ok {
+		return nil, false
+	}
+	return dibToBMP(dib)
+}
+
+// clipboardData copies the clipboard's data in format
+func clipboardData(format uintptr) ([]byte, bool) {
+	if ok, _, _ := procIsClipboardFormatAvailable.Call(format); ok == 0 {
+		return nil, false
+	}
+	h, _, _ := procGetClipboardData.Call(format)
+	if h == 0 {
+		return nil, false
+	}
+	size, _, _ := procGlobalSize.Call(h)
+	ptr, _, _ := procGlobalLock.Call(h)
+	if ptr == 0 || size == 0 {
+		return nil, false
+	}
+	defer procGlobalUnlock.Call(h)
+	data := make([]byte, size)
+	procRtlMoveMemory.Call(uintptr(unsafe.Pointer(&data[0])), ptr, size)
+	return data, true
+}
+
+// dibToBMP turns a device-independent bitmap into a BMP file by adding the
+// file header. 32-bit DIBs usually declare the standard channel masks as
+// bitfields, which BMP decoders only take as plain RGB, so those are
+// converted.
+func dibToBMP(dib []byte) ([]byte, bool) {
+	if len(dib) < 40 {
+		return nil, false
+	}
+	header := binary.LittleEndian.Uint32(dib[0:])
+	bits := binary.LittleEndian.Uint16(dib[14:])
+	compression := binary.LittleEndian.Uint32(dib[16:])
+	colors := binary.LittleEndian.Uint32(dib[32:])
+	if colors == 0 && bits <= 8 {
+		colors = 1 << bits
+	}
+
+	masks := uint32(0)
+	if header == 40 && compression == biBitfields && len(dib) >= 52 {
+		masks = 12
+		if binary.LittleEndian.Uint32(dib[40:]) == 0xff0000 && binary.LittleEndian.Uint32(dib[44:]) == 0xff00 &&
+			binary.LittleEndian.Uint32(dib[48:]) == 0xff {
+			dib = append(dib[:40:40], dib[52:]...)
+			binary.LittleEndian.PutUint32(dib[16:], 0)
+			masks = 0
+		}
+	}
+
+	bmp := make([]byte, 14, 14+len(dib))
+	bmp[0], bmp[1] = 'B', 'M'
+	binary.LittleEndian.PutUint32(bmp[2:], uint32(14+len(dib)))
+	binary.LittleEndian.PutUint32(bmp[10:], 14+header+masks+4*colors)
+	return append(bmp, dib...), true
+}
@@ -0,0 +1,203 @@
+//go:build linux || freebsd || openbsd || netbsd
+
+package capture
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// clipboardTimeout bounds how long the clipboard owner may take to answer
+// each request
+const clipboardTimeout = time.Second
+
+// maxClipboardSize bounds how much image data is read from the clipboard
+const maxClipboardSize = 64 << 20
+
+// clipboardProperty is the property of our window the owner writes to
+const clipboardProperty = "TASK_TRACKER_CLIPBOARD"
+
+// x11Clipboard reads the CLIPBOARD selection over a connection of its own,
+// since reading it means waiting for events the owner sends
+type x11Clipboard struct {
+	mu     sync.Mutex
+	conn   *xgb.Conn
+	win    xproto.Window
+	events chan xgb.Event
+}
+
+// newX11Clipboard connects to display and creates the hidden window that
+// receives the clipboard
+func newX11Clipboard(display string) (*x11Clipboard, error) {
+	conn, err := xgb.NewConnDisplay(display)
+	if err != nil {
+		return nil, err
+	}
+	screen := xproto.Setup(conn).DefaultScreen(conn)
+	win, err := xproto.NewWindowId(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := xproto.CreateWindowChecked(conn, 0, win, screen.Root, 0, 0, 1, 1, 0,
+		xproto.WindowClassInputOnly, screen.RootVisual, xproto.CwEventMask,
+		[]uint32{xproto.EventMaskPropertyChange}).Check(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c := &x11Clipboard{conn: conn, win: win, events: make(chan xgb.Event, 64)}
+	go func() {
+		defer close(c.events)
+		for {
+			ev, err := conn.WaitForEvent()
+			if ev == nil && err == nil {
+				return // connection closed
+			}
+			if ev != nil {
+				c.events <- ev
+			}
+		}
+	}()
+	return c, nil
+}
+
+// intern returns the atom for name, creating it if needed
+func (c *x11Clipboard) intern(name string) xproto.Atom {
+	reply, err := xproto.InternAtom(c.conn, false, uint16(len(name)), name).Reply()
+	if err != nil {
+		return xproto.AtomNone
+	}
+	return reply.Atom
+}
+
+// wait returns the next event accept takes, or false on timeout
+func (c *x11Clipboard) wait(accept func(xgb.Event) bool) bool {
+	timeout := time.After(clipboardTimeout)
+	for {
+		select {
+		case ev, ok := <-c.events:
+			if !ok {
+				return false
+			}
+			if accept(ev) {
+				return true
+			}
+		case <-timeout:
+			return false
+		}
+	}
+}
+
+// readProperty reads a property of the window and deletes it, which tells
+// the owner it was received
+func (c *x11Clipboard) readProperty(prop xproto.Atom) ([]byte, xproto.Atom, bool) {
+	var data []byte
+	for offset := uint32(0); ; {
+		// 1 MB at a time; offset and length count 32-bit units
+		reply, err := xproto.GetProperty(c.conn, false, c.win, prop, xproto.GetPropertyTypeAny, offset, 1<<18).Reply()
+		if err != nil {
+			return nil, 0, false
+		}
+		data = append(data, reply.Value...)
+		if len(data) > maxClipboardSize {
+			return nil, 0, false
+		}
+		if reply.BytesAfter == 0 {
+			xproto.DeleteProperty(c.conn, c.win, prop)
+			return data, reply.Type, true
+		}
+		offset += uint32(len(reply.Value)) / 4
+	}
+}
+
+// image asks the clipboard owner for the clipboard as PNG, which GTK, Qt
+// and browsers offer for copied images
+func (c *x11Clipboard) image() ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Events left over from a request that timed out
+	for len(c.events) > 0 {
+		<-c.events
+	}
+	selection, target, prop, incr := c.intern("CLIPBOARD"), c.intern("image/png"), c.intern(clipboardProperty), c.intern("INCR")
+	if selection == xproto.AtomNone || target == xproto.AtomNone || prop == xproto.AtomNone || incr == xproto.AtomNone {
+		return nil, false
+	}
+
+	xproto.ConvertSelection(c.conn, c.win, selection, target, prop, xproto.TimeCurrentTime)
+	converted := false
+	if !c.wait(func(ev xgb.Event) bool {
+		n, ok := ev.(xproto.SelectionNotifyEvent)
+		if ok && n.Requestor == c.win {
+			// No property means no owner, or no image on the clipboard
+			converted = n.Property != xproto.AtomNone
+			return true
+		}
+		return false
+	}) || !converted {
+		return nil, false
+	}
+
+	data, typ, ok := c.readProperty(prop)
+	if !ok {
+		return nil, false
+	}
+	if typ != incr {
+		return data, len(data) > 0
+	}
+
+	// Large images come in chunks: the owner sets the next one each time
+	// the last is deleted, and ends with an empty one
+	data = nil
+	for {
+		if !c.wait(func(ev xgb.Event) bool {
+			n, ok := ev.(xproto.PropertyNotifyEvent)
+			return ok && n.Window == c.win && n.Atom == prop && n.State == xproto.PropertyNewValue
+		}) {
+			return nil, false
+		}
+		chunk, _, ok := c.readProperty(prop)
+		if !ok || len(data)+len(chunk) > maxClipboardSize {
+			return nil, false
+		}
+		if len(chunk) == 0 {
+			return data, len(data) > 0
+		}
+		data = append(data, chunk...)
+	}
+}
+
+// close disconnects from the X server
+func (c *x11Clipboard) close() {
+	c.conn.Close()
+}
+
+var (
+	x11ClipboardOnce sync.Once
+	x11ClipboardConn *x11Clipboard
+)
+
+func clipboardImage() ([]byte, bool) {
+	x11ClipboardOnce.Do(func() {
+		x11ClipboardConn, _ = newX11Clipboard("")
+	})
+	if x11ClipboardConn == nil {
+		return nil, false
+	}
+	return x11ClipboardConn.image()
+}
+
+func (v *Virtual) ClipboardImage() ([]byte, bool) {
+	v.clipboardOnce.Do(func() {
+		v.clipboard, _ = newX11Clipboard(v.display)
+	})
+	if v.clipboard == nil {
+		return nil, false
+	}
+	return v.clipboard.image()
+}
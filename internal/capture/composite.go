@@ -0,0 +1,68 @@
+package capture
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Composite captures displays into one image laid out as they sit on the
+// virtual desktop, offsets included, with uncovered areas black. Displays
+// whose captures have more pixels than their bounds (HiDPI scaling, where
+// bounds are in points) set the scale of the whole image, and lower
+// density displays are scaled up to match, so every display keeps its
+// relative size. Displays that fail are left out and reported in the
+// error; the image is nil only if all of them failed.
+func Composite(c Capturer, displays []int) (*image.RGBA, error) {
+	type shot struct {
+		img    *image.RGBA
+		bounds image.Rectangle
+	}
+
+	var shots []shot
+	var errs []error
+	scale := 1.0
+	for _, d := range displays {
+		img, err := c.Capture(d)
+		if err != nil {
+			errs = append(errs, &DisplayError{Display: d, Err: err})
+			continue
+		}
+		b := c.Bounds(d)
+		if b.Empty() {
+			b = img.Bounds()
+		}
+		if s := float64(img.Bounds().Dx()) / float64(b.Dx()); s > scale {
+			scale = s
+		}
+		shots = append(shots, shot{img: img, bounds: b})
+	}
+	if len(shots) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	union := image.Rectangle{}
+	for _, s := range shots {
+		union = union.Union(s.bounds)
+	}
+	scaled := func(r image.Rectangle) image.Rectangle {
+		r = r.Sub(union.Min)
+		return image.Rect(int(float64(r.Min.X)*scale), int(float64(r.Min.Y)*scale),
+			int(float64(r.Max.X)*scale), int(float64(r.Max.Y)*scale))
+	}
+
+	canvas := newRGBA(scaled(union))
+	draw.Draw(canvas, canvas.Bounds(), image.Black, image.Point{}, draw.Src)
+	for _, s := range shots {
+		dst := scaled(s.bounds)
+		if dst.Size() == s.img.Bounds().Size() {
+			draw.Draw(canvas, dst, s.img, s.img.Bounds().Min, draw.Src)
+		} else {
+			xdraw.ApproxBiLinear.Scale(canvas, dst, s.img, s.img.Bounds(), draw.Src, nil)
+		}
+		Release(s.img)
+	}
+	return canvas, errors.Join(errs...)
+}
@@ -0,0 +1,27 @@
+package capture
+
+import "image"
+
+// Pointer is implemented by capturers that can report where the mouse is
+type Pointer interface {
+	// Cursor returns the mouse position in the same coordinates as Bounds
+	Cursor() (image.Point, bool)
+}
+
+// Cursor returns the mouse position on the virtual desktop
+func (Screen) Cursor() (image.Point, bool) {
+	return cursorPosition()
+}
+
+// FramePoint maps a desktop position into a frame captured from bounds,
+// scaling for frames with more pixels than their bounds (HiDPI, or a
+// composite). It reports false if the position is outside the frame.
+func FramePoint(p image.Point, bounds, frame image.Rectangle) (image.Point, bool) {
+	if !p.In(bounds) || bounds.Empty() {
+		return image.Point{}, false
+	}
+	scale := float64(frame.Dx()) / float64(bounds.Dx())
+	rel := p.Sub(bounds.Min)
+	q := image.Pt(int(float64(rel.X)*scale), int(float64(rel.Y)*scale)).Add(frame.Min)
+	return q, q.In(frame)
+}
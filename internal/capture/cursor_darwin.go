@@ -0,0 +1,32 @@
+//go:build darwin && cgo
+
+package capture
+
+/*
+#cgo LDFLAGS: -framework CoreGraphics -framework CoreFoundation
+#include <CoreGraphics/CoreGraphics.h>
+
+static int cursorPosition(double *x, double *y) {
+	CGEventRef event = CGEventCreate(NULL);
+	if (event == NULL) {
+		return 0;
+	}
+	CGPoint p = CGEventGetLocation(event);
+	CFRelease(event);
+	*x = p.x;
+	*y = p.y;
+	return 1;
+}
+*/
+import "C"
+
+import "image"
+
+// The position is in points, like the display bounds
+func cursorPosition() (image.Point, bool) {
+	var x, y C.double
+	if C.cursorPosition(&x, &y) == 0 {
+		return image.Point{}, false
+	}
+	return image.Pt(int(x), int(y)), true
+}
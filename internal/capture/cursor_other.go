@@ -0,0 +1,9 @@
+//go:build !(windows || darwin || linux || freebsd || openbsd || netbsd) || (darwin && !cgo)
+
+package capture
+
+import "image"
+
+func cursorPosition() (image.Point, bool) {
+	return image.Point{}, false
+}
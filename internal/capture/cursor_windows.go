@@ -0,0 +1,20 @@
+//go:build windows
+
+package capture
+
+import (
+	"image"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procGetCursorPos = windows.NewLazySystemDLL("user32.dll").NewProc("GetCursorPos")
+
+func cursorPosition() (image.Point, bool) {
+	var pt struct{ X, Y int32 }
+	if ok, _, _ := procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt))); ok == 0 {
+		return image.Point{}, false // no desktop, e.g. a disconnected session
+	}
+	return image.Pt(int(pt.X), int(pt.Y)), true
+}
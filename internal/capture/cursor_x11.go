@@ -0,0 +1,51 @@
+//go:build linux || freebsd || openbsd || netbsd
+
+package capture
+
+import (
+	"image"
+	"sync"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// The screen backend captures through its own X connection, so the
+// pointer and windows are queried on a separate one, opened on first use
+var (
+	x11Once sync.Once
+	x11Conn *xgb.Conn
+)
+
+// x11 returns the shared connection to $DISPLAY, or nil without one
+func x11() *xgb.Conn {
+	x11Once.Do(func() {
+		x11Conn, _ = xgb.NewConn()
+	})
+	return x11Conn
+}
+
+func cursorPosition() (image.Point, bool) {
+	if x11() == nil {
+		return image.Point{}, false
+	}
+	return queryPointer(x11())
+}
+
+// queryPointer returns the pointer position on the default screen's root
+// window, which spans every monitor
+func queryPointer(conn *xgb.Conn) (image.Point, bool) {
+	root := xproto.Setup(conn).DefaultScreen(conn).Root
+	reply, err := xproto.QueryPointer(conn, root).Reply()
+	if err != nil || !reply.SameScreen {
+		return image.Point{}, false
+	}
+	return image.Pt(int(reply.RootX), int(reply.RootY)), true
+}
+
+// Cursor returns the pointer position on the display's first X screen
+func (v *Virtual) Cursor() (image.Point, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return queryPointer(v.conn)
+}
@@ -0,0 +1,46 @@
+package capture
+
+import "fmt"
+
+// Desktop session types
+const (
+	DesktopConsole  = "console"  // the machine's own screen
+	DesktopRemote   = "remote"   // RDP/Terminal Server or a forwarded X display
+	DesktopServices = "services" // Windows session 0, which has no desktop
+	DesktopLocal    = "local"    // any other local login
+)
+
+// Desktop describes the login session the tracker captures
+type Desktop struct {
+	Type    string `json:"type"`
+	ID      uint32 `json:"id,omitempty"`      // Windows session ID
+	Station string `json:"station,omitempty"` // e.g. Console or RDP-Tcp#3 on Windows, x11 or wayland elsewhere
+}
+
+func (d Desktop) String() string {
+	switch {
+	case d.Station != "" && d.ID != 0:
+		return fmt.Sprintf("%s (session %d)", d.Station, d.ID)
+	case d.Station != "":
+		return d.Station
+	}
+	return d.Type
+}
+
+// Connector is implemented by capturers whose desktop can go away while
+// the tracker keeps running, like a disconnected remote session
+type Connector interface {
+	Connected() bool
+}
+
+// CurrentDesktop describes the session this process runs in
+func CurrentDesktop() Desktop {
+	return currentDesktop()
+}
+
+// Connected reports whether the session's desktop is attached to a screen.
+// Capturing a disconnected Terminal Server session fails until the user
+// reconnects.
+func (Screen) Connected() bool {
+	return desktopConnected()
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package capture
+
+import "os"
+
+func currentDesktop() Desktop {
+	d := Desktop{Type: DesktopLocal, Station: os.Getenv("XDG_SESSION_TYPE")}
+	if os.Getenv("SSH_CONNECTION") != "" {
+		d.Type = DesktopRemote // X forwarding
+	}
+	return d
+}
+
+// Local displays don't disconnect; a locked screen still captures
+func desktopConnected() bool {
+	return true
+}
@@ -0,0 +1,75 @@
+//go:build windows
+
+package capture
+
+import (
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// smRemoteSession is the GetSystemMetrics index that's non-zero in a
+// remote desktop session
+const smRemoteSession = 0x1000
+
+var procGetSystemMetrics = windows.NewLazySystemDLL("user32.dll").NewProc("GetSystemMetrics")
+
+// sessionID is the Terminal Services session of this process
+func sessionID() (uint32, error) {
+	var id uint32
+	err := windows.ProcessIdToSessionId(windows.GetCurrentProcessId(), &id)
+	return id, err
+}
+
+// sessionInfo looks up this process's session among the server's sessions
+func sessionInfo(id uint32) (windows.WTS_SESSION_INFO, bool) {
+	var sessions *windows.WTS_SESSION_INFO
+	var count uint32
+	if err := windows.WTSEnumerateSessions(0, 0, 1, &sessions, &count); err != nil {
+		return windows.WTS_SESSION_INFO{}, false
+	}
+	defer windows.WTSFreeMemory(uintptr(unsafe.Pointer(sessions)))
+
+	for _, s := range unsafe.Slice(sessions, count) {
+		if s.SessionID == id {
+			return s, true
+		}
+	}
+	return windows.WTS_SESSION_INFO{}, false
+}
+
+func currentDesktop() Desktop {
+	id, err := sessionID()
+	if err != nil {
+		return Desktop{Type: DesktopConsole}
+	}
+	d := Desktop{Type: DesktopConsole, ID: id}
+	if info, ok := sessionInfo(id); ok {
+		d.Station = windows.UTF16PtrToString(info.WindowStationName)
+	}
+
+	switch {
+	case id == 0:
+		d.Type = DesktopServices
+	case strings.HasPrefix(strings.ToUpper(d.Station), "RDP-"):
+		d.Type = DesktopRemote
+	default:
+		if remote, _, _ := procGetSystemMetrics.Call(smRemoteSession); remote != 0 {
+			d.Type = DesktopRemote
+		}
+	}
+	return d
+}
+
+func desktopConnected() bool {
+	id, err := sessionID()
+	if err != nil {
+		return true
+	}
+	info, ok := sessionInfo(id)
+	if !ok {
+		return true // can't tell; let the capture itself fail
+	}
+	return info.State == windows.WTSActive
+}
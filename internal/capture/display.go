@@ -0,0 +1,191 @@
+package capture
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Display identifies a monitor by what it is rather than where it sits in
+// the display order, which changes when docking or undocking
+type Display struct {
+	Name   string          `json:"name,omitempty"`    // connector or device, e.g. "DP-1" or "DISPLAY2"
+	Model  string          `json:"model,omitempty"`   // model name from the EDID, e.g. "DELL U2720Q"
+	ID     string          `json:"edid_id,omitempty"` // EDID manufacturer and product code, e.g. "DEL41A8"
+	Serial string          `json:"serial,omitempty"`  // EDID serial number, when the monitor has one
+	Bounds image.Rectangle `json:"-"`
+
+	// Physical size, zero when the monitor doesn't report one (projectors,
+	// virtual displays)
+	WidthMM  int     `json:"width_mm,omitempty"`
+	HeightMM int     `json:"height_mm,omitempty"`
+	Diagonal float64 `json:"diagonal_inches,omitempty"`
+	PPI      float64 `json:"ppi,omitempty"` // native pixels per inch
+
+	// Scale is the OS display scaling, native pixels per logical pixel:
+	// 2 on Retina, 1.5 at 150% on Windows. Window and cursor positions
+	// are in logical pixels when the OS scales for this process.
+	Scale float64 `json:"scale,omitempty"`
+}
+
+// Identifier is implemented by capturers that can tell which monitor is
+// behind each display index
+type Identifier interface {
+	// Displays returns one entry per display index, in index order.
+	// Displays that couldn't be identified have only Bounds set.
+	Displays() ([]Display, bool)
+}
+
+// Matches reports whether name refers to the display by its connector,
+// model, EDID ID or serial, ignoring case
+func (d Display) Matches(name string) bool {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false
+	}
+	for _, field := range []string{d.Name, d.Model, d.ID, d.Serial} {
+		if field != "" && strings.EqualFold(field, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMonitors checks the syntax of a monitor selection and splits it:
+// "all", "primary", or a comma-separated list of monitor numbers and names.
+// Whether the monitors are attached is up to the caller.
+func ParseMonitors(spec string) ([]string, error) {
+	var items []string
+	for _, p := range strings.Split(spec, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if num, err := strconv.Atoi(p); err == nil && num < 1 {
+			return nil, fmt.Errorf("invalid monitor '%s' (monitor numbers start at 1)", p)
+		}
+		items = append(items, p)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no monitors given (use all, primary, numbers or names)")
+	}
+	for _, p := range items {
+		if (p == "all" || p == "primary") && len(items) > 1 {
+			return nil, fmt.Errorf("'%s' can't be combined with other monitors", p)
+		}
+	}
+	return items, nil
+}
+
+// String describes the display as "Model (Name)", or whichever is known
+func (d Display) String() string {
+	switch {
+	case d.Model != "" && d.Name != "":
+		return fmt.Sprintf("%s (%s)", d.Model, d.Name)
+	case d.Model != "":
+		return d.Model
+	case d.Name != "":
+		return d.Name
+	}
+	return d.ID
+}
+
+// setSize records a display's physical size in millimetres and derives its
+// diagonal and pixel density from its native resolution
+func (d *Display) setSize(widthMM, heightMM, pixelsWide, pixelsHigh int) {
+	// EDIDs of projectors and some TVs put an aspect ratio here instead
+	if widthMM < 50 || heightMM < 50 {
+		return
+	}
+	d.WidthMM, d.HeightMM = widthMM, heightMM
+	d.Diagonal = math.Hypot(float64(widthMM), float64(heightMM)) / 25.4
+	if pixelsWide > 0 && pixelsHigh > 0 {
+		d.PPI = math.Hypot(float64(pixelsWide), float64(pixelsHigh)) / d.Diagonal
+	}
+}
+
+func (Screen) Displays() ([]Display, bool) {
+	found, ok := listDisplays()
+	if !ok {
+		return nil, false
+	}
+	return byBounds(Screen{}, found), true
+}
+
+// byBounds orders displays found by the platform like c's display indexes,
+// matching them by position since each API enumerates in its own order.
+// Mirrored outputs share bounds; the first one found names the display.
+func byBounds(c Capturer, found []Display) []Display {
+	displays := make([]Display, c.NumDisplays())
+	for i := range displays {
+		displays[i].Bounds = c.Bounds(i)
+		for _, d := range found {
+			if d.Bounds == displays[i].Bounds {
+				displays[i] = d
+				break
+			}
+		}
+	}
+	return displays
+}
+
+// edidID formats a manufacturer and product code the way Windows names
+// monitors, e.g. "DEL41A8". The manufacturer is three letters of five bits
+// each, 'A' being 1.
+func edidID(mfg, product uint16) string {
+	letters := []byte{
+		byte(mfg>>10&0x1f) + 'A' - 1,
+		byte(mfg>>5&0x1f) + 'A' - 1,
+		byte(mfg&0x1f) + 'A' - 1,
+	}
+	return fmt.Sprintf("%s%04X", letters, product)
+}
+
+// parseEDID fills in a display's model, ID and serial from its EDID block
+func parseEDID(d *Display, edid []byte) {
+	if len(edid) < 128 || binary.BigEndian.Uint64(edid) != 0x00ffffffffffff00 {
+		return
+	}
+
+	d.ID = edidID(binary.BigEndian.Uint16(edid[8:]), binary.LittleEndian.Uint16(edid[10:]))
+	if serial := binary.LittleEndian.Uint32(edid[12:]); serial != 0 {
+		d.Serial = fmt.Sprint(serial)
+	}
+
+	// The image size in centimetres, refined by the preferred timing's
+	// size in millimetres when it has one. The timing also holds the
+	// native resolution.
+	widthMM, heightMM := int(edid[21])*10, int(edid[22])*10
+	var pixelsWide, pixelsHigh int
+	if timing := edid[54:72]; timing[0] != 0 || timing[1] != 0 {
+		pixelsWide = int(timing[2]) | int(timing[4]&0xf0)<<4
+		pixelsHigh = int(timing[5]) | int(timing[7]&0xf0)<<4
+		if w, h := int(timing[12])|int(timing[14]&0xf0)<<4, int(timing[13])|int(timing[14]&0x0f)<<8; w > 0 && h > 0 {
+			widthMM, heightMM = w, h
+		}
+	}
+	d.setSize(widthMM, heightMM, pixelsWide, pixelsHigh)
+
+	// Four 18-byte descriptors; text ones hold up to 13 characters ended
+	// by a newline and padded with spaces
+	for off := 54; off+18 <= 126; off += 18 {
+		desc := edid[off : off+18]
+		if desc[0] != 0 || desc[1] != 0 {
+			continue // a detailed timing, not a descriptor
+		}
+		text := string(desc[5:])
+		text, _, _ = strings.Cut(text, "\n")
+		text = strings.TrimSpace(text)
+		switch desc[3] {
+		case 0xfc:
+			d.Model = text
+		case 0xff:
+			if text != "" {
+				d.Serial = text
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+//go:build darwin && cgo
+
+package capture
+
+/*
+#cgo LDFLAGS: -framework CoreGraphics
+#include <CoreGraphics/CoreGraphics.h>
+
+// pixelScale is how many pixels the display's current mode has per point
+static double pixelScale(CGDirectDisplayID id) {
+	CGDisplayModeRef mode = CGDisplayCopyDisplayMode(id);
+	if (mode == NULL) {
+		return 1;
+	}
+	double scale = 1;
+	size_t points = CGDisplayModeGetWidth(mode);
+	if (points > 0) {
+		scale = (double)CGDisplayModeGetPixelWidth(mode) / points;
+	}
+	CGDisplayModeRelease(mode);
+	return scale;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"image"
+)
+
+// maxDisplays bounds how many displays are listed
+const maxDisplays = 32
+
+// CoreGraphics reports the vendor, model, serial and size from each
+// display's EDID but not its model name, which needs IOKit
+func listDisplays() ([]Display, bool) {
+	ids := make([]C.CGDirectDisplayID, maxDisplays)
+	var n C.uint32_t
+	if C.CGGetActiveDisplayList(C.uint32_t(len(ids)), &ids[0], &n) != C.kCGErrorSuccess {
+		return nil, false
+	}
+
+	displays := make([]Display, 0, int(n))
+	for _, id := range ids[:n] {
+		// Display bounds are already top-left based, like the capturer's
+		r := C.CGDisplayBounds(id)
+		d := Display{
+			Name: fmt.Sprint(uint32(id)),
+			ID:   edidID(uint16(C.CGDisplayVendorNumber(id)), uint16(C.CGDisplayModelNumber(id))),
+			Bounds: image.Rect(int(r.origin.x), int(r.origin.y),
+				int(r.origin.x+r.size.width), int(r.origin.y+r.size.height)),
+		}
+		if serial := uint32(C.CGDisplaySerialNumber(id)); serial != 0 {
+			d.Serial = fmt.Sprint(serial)
+		}
+		d.Scale = float64(C.pixelScale(id))
+		size := C.CGDisplayScreenSize(id)
+		d.setSize(int(size.width), int(size.height),
+			int(float64(r.size.width)*d.Scale), int(float64(r.size.height)*d.Scale))
+		if C.CGDisplayIsBuiltin(id) != 0 {
+			d.Model = "Built-in Display"
+		}
+		displays = append(displays, d)
+	}
+	return displays, true
+}
@@ -0,0 +1,7 @@
+//go:build !(windows || darwin || linux || freebsd || openbsd || netbsd) || (darwin && !cgo)
+
+package capture
+
+func listDisplays() ([]Display, bool) {
+	return nil, false
+}
@@ -0,0 +1,130 @@
+//go:build windows
+
+package capture
+
+import (
+	"image"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+var (
+	procEnumDisplayMonitors  = user32.NewProc("EnumDisplayMonitors")
+	procGetMonitorInfoW      = user32.NewProc("GetMonitorInfoW")
+	procEnumDisplayDevicesW  = user32.NewProc("EnumDisplayDevicesW")
+	procEnumDisplaySettingsW = user32.NewProc("EnumDisplaySettingsW")
+	procGetDpiForMonitor     = windows.NewLazySystemDLL("shcore.dll").NewProc("GetDpiForMonitor")
+)
+
+// enumCurrentSettings is ENUM_CURRENT_SETTINGS
+const enumCurrentSettings = 0xFFFFFFFF
+
+// devMode is the display part of DEVMODEW
+type devMode struct {
+	_        [68]byte
+	size     uint16
+	_        [6]byte
+	position struct{ x, y int32 }
+	_        [86]byte
+	width    uint32
+	height   uint32
+	_        [40]byte
+}
+
+// eddGetDeviceInterfaceName makes EnumDisplayDevices return the monitor's
+// device path, which names its registry key
+const eddGetDeviceInterfaceName = 1
+
+// monitorInfoEx is MONITORINFOEXW
+type monitorInfoEx struct {
+	size    uint32
+	monitor windows.Rect
+	work    windows.Rect
+	flags   uint32
+	device  [32]uint16
+}
+
+// displayDevice is DISPLAY_DEVICEW
+type displayDevice struct {
+	size   uint32
+	name   [32]uint16
+	str    [128]uint16
+	flags  uint32
+	id     [128]uint16
+	regKey [128]uint16
+}
+
+// monitorEDID reads the EDID Windows keeps for a monitor device path like
+// \\?\DISPLAY#DEL41A8#5&2a2e1b0&0&UID4353#{e6f07b5f-...}
+func monitorEDID(path string) []byte {
+	parts := strings.Split(strings.TrimPrefix(path, `\\?\`), "#")
+	if len(parts) < 3 {
+		return nil
+	}
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SYSTEM\CurrentControlSet\Enum\`+parts[0]+`\`+parts[1]+`\`+parts[2]+`\Device Parameters`,
+		registry.QUERY_VALUE)
+	if err != nil {
+		return nil
+	}
+	defer key.Close()
+	edid, _, err := key.GetBinaryValue("EDID")
+	if err != nil {
+		return nil
+	}
+	return edid
+}
+
+func listDisplays() ([]Display, bool) {
+	var displays []Display
+	callback := windows.NewCallback(func(hmon, _ uintptr, _ *windows.Rect, _ uintptr) uintptr {
+		info := monitorInfoEx{size: uint32(unsafe.Sizeof(monitorInfoEx{}))}
+		if ok, _, _ := procGetMonitorInfoW.Call(hmon, uintptr(unsafe.Pointer(&info))); ok == 0 {
+			return 1
+		}
+		r := info.monitor
+		d := Display{
+			Name:   strings.TrimPrefix(windows.UTF16ToString(info.device[:]), `\\.\`),
+			Bounds: image.Rect(int(r.Left), int(r.Top), int(r.Right), int(r.Bottom)),
+			Scale:  1,
+		}
+
+		// The screen backend uses the mode's real pixels, which differ
+		// from the monitor rectangle when Windows scales for this process
+		mode := devMode{size: uint16(unsafe.Sizeof(devMode{}))}
+		if ok, _, _ := procEnumDisplaySettingsW.Call(uintptr(unsafe.Pointer(&info.device[0])),
+			enumCurrentSettings, uintptr(unsafe.Pointer(&mode))); ok != 0 && mode.width > 0 {
+			d.Scale = float64(mode.width) / float64(d.Bounds.Dx())
+			d.Bounds = image.Rect(int(mode.position.x), int(mode.position.y),
+				int(mode.position.x)+int(mode.width), int(mode.position.y)+int(mode.height))
+		}
+		// A DPI-aware process sees real pixels everywhere, so ask for the
+		// scaling setting instead (Windows 8.1 and later)
+		if d.Scale == 1 && procGetDpiForMonitor.Find() == nil {
+			var dpiX, dpiY uint32
+			if r, _, _ := procGetDpiForMonitor.Call(hmon, 0, uintptr(unsafe.Pointer(&dpiX)),
+				uintptr(unsafe.Pointer(&dpiY))); r == 0 && dpiX > 0 {
+				d.Scale = float64(dpiX) / 96
+			}
+		}
+
+		// The first device under the adapter is the monitor attached to it
+		dev := displayDevice{size: uint32(unsafe.Sizeof(displayDevice{}))}
+		if ok, _, _ := procEnumDisplayDevicesW.Call(uintptr(unsafe.Pointer(&info.device[0])), 0,
+			uintptr(unsafe.Pointer(&dev)), eddGetDeviceInterfaceName); ok != 0 {
+			parseEDID(&d, monitorEDID(windows.UTF16ToString(dev.id[:])))
+			if d.Model == "" {
+				d.Model = windows.UTF16ToString(dev.str[:])
+			}
+		}
+		displays = append(displays, d)
+		return 1
+	})
+	if ok, _, _ := procEnumDisplayMonitors.Call(0, 0, callback, 0); ok == 0 {
+		return nil, false
+	}
+	return displays, true
+}
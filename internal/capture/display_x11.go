@@ -0,0 +1,71 @@
+//go:build linux || freebsd || openbsd || netbsd
+
+package capture
+
+import (
+	"image"
+	"sync"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/randr"
+	"github.com/jezek/xgb/xproto"
+)
+
+// The RandR extension is set up once per connection
+var randrInit sync.Map // *xgb.Conn -> error
+
+// outputsOn lists the connected RandR outputs that are showing part of
+// the desktop, with their EDIDs where the driver exposes them
+func outputsOn(conn *xgb.Conn) ([]Display, bool) {
+	err, done := randrInit.Load(conn)
+	if !done {
+		err = randr.Init(conn)
+		randrInit.Store(conn, err)
+	}
+	if err != nil {
+		return nil, false
+	}
+	root := xproto.Setup(conn).DefaultScreen(conn).Root
+	res, rerr := randr.GetScreenResourcesCurrent(conn, root).Reply()
+	if rerr != nil {
+		return nil, false
+	}
+	edidAtom, hasEDID := atom(conn, "EDID")
+
+	var displays []Display
+	for _, output := range res.Outputs {
+		info, err := randr.GetOutputInfo(conn, output, res.ConfigTimestamp).Reply()
+		if err != nil || info.Connection != randr.ConnectionConnected || info.Crtc == 0 {
+			continue // unplugged or turned off
+		}
+		crtc, err := randr.GetCrtcInfo(conn, info.Crtc, res.ConfigTimestamp).Reply()
+		if err != nil {
+			continue
+		}
+
+		d := Display{
+			Name:   string(info.Name),
+			Bounds: image.Rect(int(crtc.X), int(crtc.Y), int(crtc.X)+int(crtc.Width), int(crtc.Y)+int(crtc.Height)),
+		}
+		if hasEDID {
+			prop, err := randr.GetOutputProperty(conn, output, edidAtom, xproto.AtomAny, 0, 64, false, false).Reply()
+			if err == nil {
+				parseEDID(&d, prop.Data)
+			}
+		}
+		if d.PPI == 0 {
+			d.setSize(int(info.MmWidth), int(info.MmHeight), int(crtc.Width), int(crtc.Height))
+		}
+		// X11 doesn't scale; toolkits scale themselves from Xft.dpi
+		d.Scale = 1
+		displays = append(displays, d)
+	}
+	return displays, true
+}
+
+func listDisplays() ([]Display, bool) {
+	if x11() == nil {
+		return nil, false
+	}
+	return outputsOn(x11())
+}
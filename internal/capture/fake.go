@@ -0,0 +1,137 @@
+package capture
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+	"time"
+)
+
+// Fake generates synthetic frames for the displays it was created with.
+// Each capture moves a block across a gradient so consecutive frames
+// differ slightly, like a real screen.
+type Fake struct {
+	displays []image.Rectangle
+
+	mu     sync.Mutex
+	frames map[int]int
+	fail   map[int]error
+}
+
+// NewFake creates a fake capturer with the given display bounds
+func NewFake(displays ...image.Rectangle) *Fake {
+	return &Fake{
+		displays: displays,
+		frames:   make(map[int]int),
+		fail:     make(map[int]error),
+	}
+}
+
+// FailDisplay makes captures of display return err (nil to clear)
+func (f *Fake) FailDisplay(display int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		delete(f.fail, display)
+	} else {
+		f.fail[display] = err
+	}
+}
+
+func (f *Fake) NumDisplays() int {
+	return len(f.displays)
+}
+
+func (f *Fake) Bounds(display int) image.Rectangle {
+	if display < 0 || display >= len(f.displays) {
+		return image.Rectangle{}
+	}
+	return f.displays[display]
+}
+
+func (f *Fake) Capture(display int) (*image.RGBA, error) {
+	if display < 0 || display >= len(f.displays) {
+		return nil, fmt.Errorf("display %d not found", display)
+	}
+
+	f.mu.Lock()
+	if err := f.fail[display]; err != nil {
+		f.mu.Unlock()
+		return nil, err
+	}
+	frame := f.frames[display]
+	f.frames[display]++
+	f.mu.Unlock()
+
+	bounds := f.displays[display]
+	img := newRGBA(bounds)
+	w, h := bounds.Dx(), bounds.Dy()
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(bounds.Min.X+x, bounds.Min.Y+y, color.RGBA{
+				R: uint8(x * 255 / w),
+				G: uint8(y * 255 / h),
+				B: uint8(display * 80),
+				A: 255,
+			})
+		}
+	}
+
+	// Moving block
+	size := h / 8
+	offset := (frame * size) % (w - size)
+	block := image.Rect(offset, h/2-size/2, offset+size, h/2+size/2).Add(bounds.Min)
+	for y := block.Min.Y; y < block.Max.Y; y++ {
+		for x := block.Min.X; x < block.Max.X; x++ {
+			img.SetRGBA(x, y, color.RGBA{255, 255, 255, 255})
+		}
+	}
+
+	return img, nil
+}
+
+// Cursor follows the moving block on the first display
+func (f *Fake) Cursor() (image.Point, bool) {
+	if len(f.displays) == 0 {
+		return image.Point{}, false
+	}
+	f.mu.Lock()
+	frame := f.frames[0]
+	f.mu.Unlock()
+
+	bounds := f.displays[0]
+	w, h := bounds.Dx(), bounds.Dy()
+	size := h / 8
+	offset := (max(frame-1, 0) * size) % (w - size)
+	return image.Pt(offset+size/2, h/2).Add(bounds.Min), true
+}
+
+// ActiveWindow reports a fixed title
+func (f *Fake) ActiveWindow() (string, bool) {
+	return "Fake Window", true
+}
+
+// Idle reports no idle time
+func (f *Fake) Idle() (time.Duration, bool) {
+	return 0, true
+}
+
+// Displays names each display FAKE-1, FAKE-2, ... with its number as
+// serial, sized like a 15.6" laptop screen
+func (f *Fake) Displays() ([]Display, bool) {
+	displays := make([]Display, len(f.displays))
+	for i, bounds := range f.displays {
+		displays[i] = Display{
+			Name:   fmt.Sprintf("FAKE-%d", i+1),
+			Model:  "Fake Display",
+			ID:     "FAK0001",
+			Serial: fmt.Sprint(i + 1),
+			Bounds: bounds,
+			Scale:  1,
+		}
+		displays[i].setSize(344, 194, bounds.Dx(), bounds.Dy())
+	}
+	return displays, true
+}
@@ -0,0 +1,36 @@
+package capture
+
+import (
+	"image"
+	"sync"
+)
+
+// Frames are large (8MB for a 1080p display) and captured every tick, so
+// backends that fill their own buffers take them from a pool keyed by
+// pixel buffer size. Callers hand frames back with Release once they've
+// been encoded.
+var rgbaPools sync.Map // int (len(Pix)) -> *sync.Pool
+
+// newRGBA returns an RGBA image for r, reusing a released buffer of the
+// same size when one is available. The pixels are not cleared.
+func newRGBA(r image.Rectangle) *image.RGBA {
+	size := 4 * r.Dx() * r.Dy()
+	if p, ok := rgbaPools.Load(size); ok {
+		if img, ok := p.(*sync.Pool).Get().(*image.RGBA); ok {
+			img.Rect = r
+			img.Stride = 4 * r.Dx()
+			return img
+		}
+	}
+	return image.NewRGBA(r)
+}
+
+// Release returns a frame's buffer for reuse by later captures. The image
+// must not be used afterwards.
+func Release(img *image.RGBA) {
+	if img == nil || len(img.Pix) == 0 || img.Stride != 4*img.Rect.Dx() {
+		return
+	}
+	p, _ := rgbaPools.LoadOrStore(len(img.Pix), &sync.Pool{})
+	p.(*sync.Pool).Put(img)
+}
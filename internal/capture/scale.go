@@ -0,0 +1,18 @@
+package capture
+
+import (
+	"image"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Downscale resizes a frame to w×h at the origin, in a pooled buffer, and
+// releases the original
+func Downscale(img *image.RGBA, w, h int) *image.RGBA {
+	dst := newRGBA(image.Rect(0, 0, w, h))
+	// BiLinear widens its kernel when shrinking, so text doesn't alias
+	// the way ApproxBiLinear's does
+	xdraw.BiLinear.Scale(dst, dst.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+	Release(img)
+	return dst
+}
@@ -0,0 +1,22 @@
+//go:build !(linux || freebsd || openbsd || netbsd)
+
+package capture
+
+import (
+	"fmt"
+	"image"
+	"runtime"
+)
+
+// Virtual is only available on X11 platforms
+type Virtual struct{}
+
+// NewVirtual always fails outside X11 platforms
+func NewVirtual(display string) (*Virtual, error) {
+	return nil, fmt.Errorf("the virtual backend needs an X11 display and isn't available on %s", runtime.GOOS)
+}
+
+func (v *Virtual) NumDisplays() int                         { return 0 }
+func (v *Virtual) Bounds(display int) image.Rectangle       { return image.Rectangle{} }
+func (v *Virtual) Capture(display int) (*image.RGBA, error) { return nil, fmt.Errorf("not supported") }
+func (v *Virtual) Close()                                   {}
@@ -0,0 +1,88 @@
+//go:build linux || freebsd || openbsd || netbsd
+
+package capture
+
+import (
+	"fmt"
+	"image"
+	"sync"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// Virtual captures the root window of each X screen on an explicit
+// display. Unlike Screen it doesn't need Xinerama, so it works with Xvfb
+// and other virtual framebuffers (e.g. Xvfb :99 -screen 0 1920x1080x24).
+type Virtual struct {
+	display string
+
+	mu   sync.Mutex
+	conn *xgb.Conn
+
+	clipboardOnce sync.Once
+	clipboard     *x11Clipboard // opened on first use
+}
+
+// NewVirtual connects to an X display such as ":99". An empty display
+// uses $DISPLAY.
+func NewVirtual(display string) (*Virtual, error) {
+	conn, err := xgb.NewConnDisplay(display)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to X display '%s': %w", display, err)
+	}
+	return &Virtual{display: display, conn: conn}, nil
+}
+
+func (v *Virtual) screens() []xproto.ScreenInfo {
+	return xproto.Setup(v.conn).Roots
+}
+
+func (v *Virtual) NumDisplays() int {
+	return len(v.screens())
+}
+
+func (v *Virtual) Bounds(display int) image.Rectangle {
+	screens := v.screens()
+	if display < 0 || display >= len(screens) {
+		return image.Rectangle{}
+	}
+	return image.Rect(0, 0, int(screens[display].WidthInPixels), int(screens[display].HeightInPixels))
+}
+
+func (v *Virtual) Capture(display int) (*image.RGBA, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	screens := v.screens()
+	if display < 0 || display >= len(screens) {
+		return nil, fmt.Errorf("X screen %d not found on display '%s'", display, v.display)
+	}
+	screen := screens[display]
+	w, h := int(screen.WidthInPixels), int(screen.HeightInPixels)
+
+	reply, err := xproto.GetImage(v.conn, xproto.ImageFormatZPixmap, xproto.Drawable(screen.Root),
+		0, 0, uint16(w), uint16(h), 0xffffffff).Reply()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read X screen %d: %w", display, err)
+	}
+	if len(reply.Data) < w*h*4 {
+		return nil, fmt.Errorf("unsupported X screen depth %d", reply.Depth)
+	}
+
+	// ZPixmap at depth 24/32 is BGRX
+	img := newRGBA(image.Rect(0, 0, w, h))
+	data, pix := reply.Data, img.Pix
+	for i := 0; i < w*h*4; i += 4 {
+		pix[i], pix[i+1], pix[i+2], pix[i+3] = data[i+2], data[i+1], data[i], 255
+	}
+	return img, nil
+}
+
+// Close disconnects from the X server
+func (v *Virtual) Close() {
+	v.conn.Close()
+	if v.clipboard != nil {
+		v.clipboard.close()
+	}
+}
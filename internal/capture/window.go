@@ -0,0 +1,55 @@
+package capture
+
+import (
+	"image"
+	"time"
+)
+
+// Inspector is implemented by capturers that can describe what the user
+// is doing on the desktop they capture
+type Inspector interface {
+	// ActiveWindow returns the title of the focused window
+	ActiveWindow() (string, bool)
+	// Idle returns the time since the last keyboard or mouse input
+	Idle() (time.Duration, bool)
+}
+
+// Window is a visible top-level window
+type Window struct {
+	Title  string
+	Bounds image.Rectangle // in the same coordinates as monitor bounds
+	Own    bool            // belongs to the terminal or app this process runs in
+}
+
+// WindowLister is implemented by capturers that can list the windows on
+// the desktop they capture
+type WindowLister interface {
+	// Windows returns the visible windows, bottom of the stacking order
+	// first, so later windows cover earlier ones
+	Windows() ([]Window, bool)
+}
+
+func (Screen) ActiveWindow() (string, bool) {
+	return activeWindow()
+}
+
+func (Screen) Idle() (time.Duration, bool) {
+	return idleTime()
+}
+
+func (Screen) Windows() ([]Window, bool) {
+	return listWindows()
+}
+
+// FrameRect maps a desktop rectangle into a frame captured from bounds,
+// like FramePoint, clipped to the frame
+func FrameRect(r, bounds, frame image.Rectangle) image.Rectangle {
+	r = r.Intersect(bounds)
+	if r.Empty() {
+		return image.Rectangle{}
+	}
+	scale := float64(frame.Dx()) / float64(bounds.Dx())
+	min, max := r.Min.Sub(bounds.Min), r.Max.Sub(bounds.Min)
+	return image.Rect(int(float64(min.X)*scale), int(float64(min.Y)*scale),
+		int(float64(max.X)*scale+0.5), int(float64(max.Y)*scale+0.5)).Add(frame.Min).Intersect(frame)
+}
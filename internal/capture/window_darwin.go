@@ -0,0 +1,125 @@
+//go:build darwin && cgo
+
+package capture
+
+/*
+#cgo LDFLAGS: -framework CoreGraphics -framework CoreFoundation
+#include <CoreGraphics/CoreGraphics.h>
+
+static double idleSeconds(void) {
+	return CGEventSourceSecondsSinceLastEventType(kCGEventSourceStateCombinedSessionState, kCGAnyInputEventType);
+}
+
+typedef struct {
+	double x, y, w, h;
+	int pid;
+	char title[256];
+} ttWindow;
+
+// listWindows fills out with on-screen windows of the normal layer, front
+// to back, and returns how many there are or -1
+static int listWindows(ttWindow *out, int max) {
+	CFArrayRef list = CGWindowListCopyWindowInfo(
+		kCGWindowListOptionOnScreenOnly | kCGWindowListExcludeDesktopElements, kCGNullWindowID);
+	if (list == NULL) {
+		return -1;
+	}
+	int n = 0;
+	for (CFIndex i = 0; i < CFArrayGetCount(list) && n < max; i++) {
+		CFDictionaryRef w = CFArrayGetValueAtIndex(list, i);
+
+		// Layer 0 holds app windows; the menu bar, Dock and overlays sit above
+		int layer = 0;
+		CFNumberRef layerRef = CFDictionaryGetValue(w, kCGWindowLayer);
+		if (layerRef != NULL) {
+			CFNumberGetValue(layerRef, kCFNumberIntType, &layer);
+		}
+		if (layer != 0) {
+			continue;
+		}
+		CGRect r;
+		CFDictionaryRef boundsRef = CFDictionaryGetValue(w, kCGWindowBounds);
+		if (boundsRef == NULL || !CGRectMakeWithDictionaryRepresentation(boundsRef, &r)) {
+			continue;
+		}
+
+		ttWindow *o = &out[n++];
+		o->x = r.origin.x;
+		o->y = r.origin.y;
+		o->w = r.size.width;
+		o->h = r.size.height;
+		o->pid = 0;
+		CFNumberRef pidRef = CFDictionaryGetValue(w, kCGWindowOwnerPID);
+		if (pidRef != NULL) {
+			CFNumberGetValue(pidRef, kCFNumberIntType, &o->pid);
+		}
+		// Titles of other apps' windows need the screen recording
+		// permission, which capturing already requires
+		o->title[0] = 0;
+		CFStringRef name = CFDictionaryGetValue(w, kCGWindowName);
+		if (name != NULL) {
+			CFStringGetCString(name, o->title, sizeof o->title, kCFStringEncodingUTF8);
+		}
+	}
+	CFRelease(list);
+	return n;
+}
+*/
+import "C"
+
+import (
+	"image"
+	"os"
+	"slices"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// maxWindows bounds how many windows are listed
+const maxWindows = 512
+
+// Window titles need the accessibility permission, which a command line
+// tool can't ask for
+func activeWindow() (string, bool) {
+	return "", false
+}
+
+func idleTime() (time.Duration, bool) {
+	return time.Duration(float64(C.idleSeconds()) * float64(time.Second)), true
+}
+
+// ancestors returns this process and its parents, up to the terminal app
+func ancestors() map[int]bool {
+	pids := map[int]bool{os.Getpid(): true}
+	for pid := os.Getppid(); pid > 1 && !pids[pid] && len(pids) < 64; {
+		pids[pid] = true
+		info, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+		if err != nil {
+			break
+		}
+		pid = int(info.Eproc.Ppid)
+	}
+	return pids
+}
+
+func listWindows() ([]Window, bool) {
+	buf := make([]C.ttWindow, maxWindows)
+	n := int(C.listWindows(&buf[0], C.int(len(buf))))
+	if n < 0 {
+		return nil, false
+	}
+	pids := ancestors()
+
+	list := make([]Window, 0, n)
+	for _, w := range buf[:n] {
+		list = append(list, Window{
+			Title:  C.GoString(&w.title[0]),
+			Bounds: image.Rect(int(w.x), int(w.y), int(w.x+w.w), int(w.y+w.h)),
+			Own:    pids[int(w.pid)],
+		})
+	}
+	// The window server lists windows front to back
+	slices.Reverse(list)
+	return list, true
+}
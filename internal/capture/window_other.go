@@ -0,0 +1,17 @@
+//go:build !(windows || darwin || linux || freebsd || openbsd || netbsd) || (darwin && !cgo)
+
+package capture
+
+import "time"
+
+func activeWindow() (string, bool) {
+	return "", false
+}
+
+func idleTime() (time.Duration, bool) {
+	return 0, false
+}
+
+func listWindows() ([]Window, bool) {
+	return nil, false
+}
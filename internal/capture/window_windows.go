@@ -0,0 +1,127 @@
+//go:build windows
+
+package capture
+
+import (
+	"image"
+	"slices"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32                    = windows.NewLazySystemDLL("user32.dll")
+	procGetWindowTextW        = user32.NewProc("GetWindowTextW")
+	procGetLastInputInfo      = user32.NewProc("GetLastInputInfo")
+	procEnumWindows           = user32.NewProc("EnumWindows")
+	procIsWindowVisible       = user32.NewProc("IsWindowVisible")
+	procIsIconic              = user32.NewProc("IsIconic")
+	procGetWindowRect         = user32.NewProc("GetWindowRect")
+	procGetTickCount          = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetTickCount")
+	procGetConsoleWindow      = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetConsoleWindow")
+	procDwmGetWindowAttribute = windows.NewLazySystemDLL("dwmapi.dll").NewProc("DwmGetWindowAttribute")
+)
+
+// dwmCloaked is DWMWA_CLOAKED: set for windows that are "visible" but not
+// drawn, such as suspended store apps and windows on other virtual desktops
+const dwmCloaked = 14
+
+func activeWindow() (string, bool) {
+	hwnd := windows.GetForegroundWindow()
+	if hwnd == 0 {
+		return "", false // locked, or a disconnected session
+	}
+	return windowText(hwnd), true
+}
+
+// windowText reads a window's title
+func windowText(hwnd windows.HWND) string {
+	buf := make([]uint16, 512)
+	n, _, _ := procGetWindowTextW.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	return windows.UTF16ToString(buf[:n])
+}
+
+// ancestors returns this process and its parents, so a terminal several
+// levels up (Windows Terminal, then a shell, then us) is found
+func ancestors() map[uint32]bool {
+	pids := map[uint32]bool{windows.GetCurrentProcessId(): true}
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return pids
+	}
+	defer windows.CloseHandle(snapshot)
+
+	parent := make(map[uint32]uint32)
+	entry := windows.ProcessEntry32{Size: uint32(unsafe.Sizeof(windows.ProcessEntry32{}))}
+	for err = windows.Process32First(snapshot, &entry); err == nil; err = windows.Process32Next(snapshot, &entry) {
+		parent[entry.ProcessID] = entry.ParentProcessID
+	}
+	for pid := windows.GetCurrentProcessId(); len(pids) < 64; {
+		ppid, ok := parent[pid]
+		if !ok || ppid == 0 || pids[ppid] {
+			break
+		}
+		pids[ppid] = true
+		pid = ppid
+	}
+	return pids
+}
+
+func listWindows() ([]Window, bool) {
+	console, _, _ := procGetConsoleWindow.Call()
+	pids := ancestors()
+
+	var list []Window
+	callback := windows.NewCallback(func(hwnd windows.HWND, _ uintptr) uintptr {
+		if visible, _, _ := procIsWindowVisible.Call(uintptr(hwnd)); visible == 0 {
+			return 1
+		}
+		if iconic, _, _ := procIsIconic.Call(uintptr(hwnd)); iconic != 0 {
+			return 1
+		}
+		var cloaked uint32
+		if r, _, _ := procDwmGetWindowAttribute.Call(uintptr(hwnd), dwmCloaked,
+			uintptr(unsafe.Pointer(&cloaked)), unsafe.Sizeof(cloaked)); r == 0 && cloaked != 0 {
+			return 1
+		}
+		var rect windows.Rect
+		if ok, _, _ := procGetWindowRect.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&rect))); ok == 0 {
+			return 1
+		}
+		bounds := image.Rect(int(rect.Left), int(rect.Top), int(rect.Right), int(rect.Bottom))
+		if bounds.Empty() {
+			return 1
+		}
+
+		var pid uint32
+		windows.GetWindowThreadProcessId(hwnd, &pid)
+		list = append(list, Window{
+			Title:  windowText(hwnd),
+			Bounds: bounds,
+			Own:    uintptr(hwnd) == console || pids[pid],
+		})
+		return 1
+	})
+	if ok, _, _ := procEnumWindows.Call(callback, 0); ok == 0 {
+		return nil, false
+	}
+
+	// EnumWindows goes from the top of the z-order down
+	slices.Reverse(list)
+	return list, true
+}
+
+func idleTime() (time.Duration, bool) {
+	info := struct {
+		size uint32
+		time uint32
+	}{size: 8}
+	if ok, _, _ := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); ok == 0 {
+		return 0, false
+	}
+	now, _, _ := procGetTickCount.Call()
+	// Both are milliseconds since boot and wrap together every 49.7 days
+	return time.Duration(uint32(now)-info.time) * time.Millisecond, true
+}
@@ -0,0 +1,213 @@
+//go:build linux || freebsd || openbsd || netbsd
+
+package capture
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/screensaver"
+	"github.com/jezek/xgb/xproto"
+)
+
+// atom looks up an X atom by name
+func atom(conn *xgb.Conn, name string) (xproto.Atom, bool) {
+	reply, err := xproto.InternAtom(conn, true, uint16(len(name)), name).Reply()
+	if err != nil || reply.Atom == xproto.AtomNone {
+		return 0, false
+	}
+	return reply.Atom, true
+}
+
+// windowTitle reads the EWMH focused window's title, falling back to the
+// legacy WM_NAME
+func windowTitle(conn *xgb.Conn) (string, bool) {
+	active, ok := atom(conn, "_NET_ACTIVE_WINDOW")
+	if !ok {
+		return "", false
+	}
+	root := xproto.Setup(conn).DefaultScreen(conn).Root
+	reply, err := xproto.GetProperty(conn, false, root, active, xproto.AtomWindow, 0, 1).Reply()
+	if err != nil || len(reply.Value) < 4 {
+		return "", false
+	}
+	win := xproto.Window(xgb.Get32(reply.Value))
+	if win == 0 {
+		return "", false
+	}
+	return windowName(conn, win)
+}
+
+// windowName reads a window's title, falling back to the legacy WM_NAME
+func windowName(conn *xgb.Conn, win xproto.Window) (string, bool) {
+	if name, ok := atom(conn, "_NET_WM_NAME"); ok {
+		if utf8, ok := atom(conn, "UTF8_STRING"); ok {
+			reply, err := xproto.GetProperty(conn, false, win, name, utf8, 0, 1024).Reply()
+			if err == nil && len(reply.Value) > 0 {
+				return string(reply.Value), true
+			}
+		}
+	}
+	reply, err := xproto.GetProperty(conn, false, win, xproto.AtomWmName, xproto.AtomString, 0, 1024).Reply()
+	if err != nil {
+		return "", false
+	}
+	return string(reply.Value), true
+}
+
+// ownWindows returns $WINDOWID, which most X terminals set to the window
+// the shell runs in, and the windows above it up to the root. The client
+// window in _NET_CLIENT_LIST is one of them.
+func ownWindows(conn *xgb.Conn) map[xproto.Window]bool {
+	own := make(map[xproto.Window]bool)
+	id, err := strconv.ParseUint(os.Getenv("WINDOWID"), 0, 32)
+	if err != nil || id == 0 {
+		return own
+	}
+	for win := xproto.Window(id); win != 0 && len(own) < 32; {
+		own[win] = true
+		tree, err := xproto.QueryTree(conn, win).Reply()
+		if err != nil || tree.Parent == tree.Root {
+			break
+		}
+		win = tree.Parent
+	}
+	return own
+}
+
+// ancestors returns this process and its parents. /proc only exists on
+// Linux; elsewhere only the direct parent is known.
+func ancestors() map[int]bool {
+	pids := map[int]bool{os.Getpid(): true, os.Getppid(): true}
+	for pid := os.Getppid(); pid > 1 && len(pids) < 64; {
+		data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		if err != nil {
+			break
+		}
+		// pid (comm) state ppid ...; comm may contain spaces and parentheses
+		fields := strings.Fields(string(data[bytes.LastIndexByte(data, ')')+1:]))
+		if len(fields) < 2 {
+			break
+		}
+		if pid, err = strconv.Atoi(fields[1]); err != nil {
+			break
+		}
+		pids[pid] = true
+	}
+	return pids
+}
+
+// windowsOn lists the mapped client windows in stacking order
+func windowsOn(conn *xgb.Conn) ([]Window, bool) {
+	stacking, ok := atom(conn, "_NET_CLIENT_LIST_STACKING")
+	if !ok {
+		return nil, false
+	}
+	root := xproto.Setup(conn).DefaultScreen(conn).Root
+	reply, err := xproto.GetProperty(conn, false, root, stacking, xproto.AtomWindow, 0, 4096).Reply()
+	if err != nil {
+		return nil, false
+	}
+
+	own := ownWindows(conn)
+	pids := ancestors()
+	pidAtom, hasPid := atom(conn, "_NET_WM_PID")
+
+	var windows []Window
+	for i := 0; i+4 <= len(reply.Value); i += 4 {
+		win := xproto.Window(xgb.Get32(reply.Value[i:]))
+		attrs, err := xproto.GetWindowAttributes(conn, win).Reply()
+		if err != nil || attrs.MapState != xproto.MapStateViewable {
+			continue // minimized or on another workspace
+		}
+		geom, err := xproto.GetGeometry(conn, xproto.Drawable(win)).Reply()
+		if err != nil {
+			continue
+		}
+		pos, err := xproto.TranslateCoordinates(conn, win, root, 0, 0).Reply()
+		if err != nil {
+			continue
+		}
+
+		w := Window{
+			Bounds: image.Rect(int(pos.DstX), int(pos.DstY), int(pos.DstX)+int(geom.Width), int(pos.DstY)+int(geom.Height)),
+			Own:    own[win],
+		}
+		w.Title, _ = windowName(conn, win)
+		if hasPid && !w.Own {
+			reply, err := xproto.GetProperty(conn, false, win, pidAtom, xproto.AtomCardinal, 0, 1).Reply()
+			if err == nil && len(reply.Value) >= 4 {
+				w.Own = pids[int(xgb.Get32(reply.Value))]
+			}
+		}
+		windows = append(windows, w)
+	}
+	return windows, true
+}
+
+// The screensaver extension is set up once per connection
+var screensaverInit sync.Map // *xgb.Conn -> error
+
+// idleSince asks the screensaver extension how long input has been idle
+func idleSince(conn *xgb.Conn) (time.Duration, bool) {
+	err, done := screensaverInit.Load(conn)
+	if !done {
+		err = screensaver.Init(conn)
+		screensaverInit.Store(conn, err)
+	}
+	if err != nil {
+		return 0, false
+	}
+	root := xproto.Setup(conn).DefaultScreen(conn).Root
+	reply, qerr := screensaver.QueryInfo(conn, xproto.Drawable(root)).Reply()
+	if qerr != nil {
+		return 0, false
+	}
+	return time.Duration(reply.MsSinceUserInput) * time.Millisecond, true
+}
+
+func activeWindow() (string, bool) {
+	if x11() == nil {
+		return "", false
+	}
+	return windowTitle(x11())
+}
+
+func listWindows() ([]Window, bool) {
+	if x11() == nil {
+		return nil, false
+	}
+	return windowsOn(x11())
+}
+
+func idleTime() (time.Duration, bool) {
+	if x11() == nil {
+		return 0, false
+	}
+	return idleSince(x11())
+}
+
+func (v *Virtual) ActiveWindow() (string, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return windowTitle(v.conn)
+}
+
+func (v *Virtual) Idle() (time.Duration, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return idleSince(v.conn)
+}
+
+func (v *Virtual) Windows() ([]Window, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return windowsOn(v.conn)
+}
@@ -0,0 +1,223 @@
+// Package delta stores a frame as the tiles that changed relative to a
+// keyframe. Mostly static screens (terminals, editors, dashboards) change a
+// few percent of their pixels between captures, so a delta is a fraction
+// of the size of a full PNG.
+//
+// File layout (big-endian):
+//
+//	"TTD1"
+//	uint32 width, height, tile size
+//	uint16 length + keyframe file name (relative to the delta's directory)
+//	uint32 tile count
+//	flate stream of: uint32 tile index, then the tile's RGBA rows
+package delta
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"sync"
+)
+
+// Ext is the file extension of delta frames
+const Ext = ".ttd"
+
+// DefaultTileSize is the edge length of a tile in pixels
+const DefaultTileSize = 32
+
+var magic = []byte("TTD1")
+
+// tiles returns the rectangles a frame of the given size is split into,
+// relative to the frame origin
+func tiles(size image.Point, tileSize int) []image.Rectangle {
+	var rects []image.Rectangle
+	for y := 0; y < size.Y; y += tileSize {
+		for x := 0; x < size.X; x += tileSize {
+			rects = append(rects, image.Rect(x, y, min(x+tileSize, size.X), min(y+tileSize, size.Y)))
+		}
+	}
+	return rects
+}
+
+// Diff returns the indices of tiles that differ between base and cur.
+// Frames of different sizes can't be diffed and return ok false.
+func Diff(base, cur *image.RGBA, tileSize int) (changed []int, total int, ok bool) {
+	size := cur.Bounds().Size()
+	if base.Bounds().Size() != size {
+		return nil, 0, false
+	}
+
+	rects := tiles(size, tileSize)
+	for i, r := range rects {
+		if !tileEqual(base, cur, r) {
+			changed = append(changed, i)
+		}
+	}
+	return changed, len(rects), true
+}
+
+func tileEqual(a, b *image.RGBA, r image.Rectangle) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		ra := rowBytes(a, r, y)
+		rb := rowBytes(b, r, y)
+		if !bytes.Equal(ra, rb) {
+			return false
+		}
+	}
+	return true
+}
+
+// rowBytes returns the pixels of row y of r, relative to img's origin
+func rowBytes(img *image.RGBA, r image.Rectangle, y int) []byte {
+	min := img.Bounds().Min
+	start := img.PixOffset(min.X+r.Min.X, min.Y+y)
+	return img.Pix[start : start+r.Dx()*4]
+}
+
+// Encode writes the changed tiles of cur, as returned by Diff, referring
+// to the keyframe file ref
+func Encode(w io.Writer, ref string, cur *image.RGBA, tileSize int, changed []int) error {
+	size := cur.Bounds().Size()
+	if len(ref) > 0xFFFF {
+		return fmt.Errorf("keyframe name too long")
+	}
+
+	header := make([]byte, 0, 24+len(ref))
+	header = append(header, magic...)
+	header = binary.BigEndian.AppendUint32(header, uint32(size.X))
+	header = binary.BigEndian.AppendUint32(header, uint32(size.Y))
+	header = binary.BigEndian.AppendUint32(header, uint32(tileSize))
+	header = binary.BigEndian.AppendUint16(header, uint16(len(ref)))
+	header = append(header, ref...)
+	header = binary.BigEndian.AppendUint32(header, uint32(len(changed)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	fw := getFlateWriter(w)
+	defer flateWriters.Put(fw)
+
+	rects := tiles(size, tileSize)
+	var idx [4]byte
+	for _, i := range changed {
+		if i < 0 || i >= len(rects) {
+			return fmt.Errorf("tile %d out of range", i)
+		}
+		binary.BigEndian.PutUint32(idx[:], uint32(i))
+		if _, err := fw.Write(idx[:]); err != nil {
+			return err
+		}
+		r := rects[i]
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			if _, err := fw.Write(rowBytes(cur, r, y)); err != nil {
+				return err
+			}
+		}
+	}
+	return fw.Close()
+}
+
+// A flate writer allocates ~1MB of state, so writers are reused
+var flateWriters sync.Pool
+
+func getFlateWriter(w io.Writer) *flate.Writer {
+	if fw, ok := flateWriters.Get().(*flate.Writer); ok {
+		fw.Reset(w)
+		return fw
+	}
+	fw, _ := flate.NewWriter(w, flate.DefaultCompression) // only fails for invalid levels
+	return fw
+}
+
+// Header describes a delta frame
+type Header struct {
+	Width, Height int
+	TileSize      int
+	Ref           string // keyframe file name
+	Tiles         int    // number of changed tiles stored
+}
+
+// ReadHeader reads the header of a delta frame
+func ReadHeader(r io.Reader) (*Header, error) {
+	var fixed [16]byte
+	if _, err := io.ReadFull(r, fixed[:]); err != nil {
+		return nil, fmt.Errorf("failed to read delta header: %w", err)
+	}
+	if !bytes.Equal(fixed[:4], magic) {
+		return nil, fmt.Errorf("not a delta frame")
+	}
+
+	h := &Header{
+		Width:    int(binary.BigEndian.Uint32(fixed[4:8])),
+		Height:   int(binary.BigEndian.Uint32(fixed[8:12])),
+		TileSize: int(binary.BigEndian.Uint32(fixed[12:16])),
+	}
+	if h.TileSize <= 0 || h.Width <= 0 || h.Height <= 0 {
+		return nil, fmt.Errorf("invalid delta header")
+	}
+
+	var n [2]byte
+	if _, err := io.ReadFull(r, n[:]); err != nil {
+		return nil, fmt.Errorf("failed to read delta header: %w", err)
+	}
+	ref := make([]byte, binary.BigEndian.Uint16(n[:]))
+	if _, err := io.ReadFull(r, ref); err != nil {
+		return nil, fmt.Errorf("failed to read delta header: %w", err)
+	}
+	h.Ref = string(ref)
+
+	var count [4]byte
+	if _, err := io.ReadFull(r, count[:]); err != nil {
+		return nil, fmt.Errorf("failed to read delta header: %w", err)
+	}
+	h.Tiles = int(binary.BigEndian.Uint32(count[:]))
+	return h, nil
+}
+
+// Decode reconstructs a frame, loading its keyframe with loadRef
+func Decode(r io.Reader, loadRef func(name string) (image.Image, error)) (*image.RGBA, error) {
+	br := bufio.NewReader(r)
+	h, err := ReadHeader(br)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := loadRef(h.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load keyframe %s: %w", h.Ref, err)
+	}
+	if base.Bounds().Dx() != h.Width || base.Bounds().Dy() != h.Height {
+		return nil, fmt.Errorf("keyframe %s is %dx%d, delta expects %dx%d",
+			h.Ref, base.Bounds().Dx(), base.Bounds().Dy(), h.Width, h.Height)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, h.Width, h.Height))
+	draw.Draw(img, img.Bounds(), base, base.Bounds().Min, draw.Src)
+
+	rects := tiles(image.Pt(h.Width, h.Height), h.TileSize)
+	fr := flate.NewReader(br)
+	defer fr.Close()
+
+	var idx [4]byte
+	for t := 0; t < h.Tiles; t++ {
+		if _, err := io.ReadFull(fr, idx[:]); err != nil {
+			return nil, fmt.Errorf("truncated delta frame: %w", err)
+		}
+		i := int(binary.BigEndian.Uint32(idx[:]))
+		if i >= len(rects) {
+			return nil, fmt.Errorf("tile %d out of range", i)
+		}
+		r := rects[i]
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			if _, err := io.ReadFull(fr, rowBytes(img, r, y)); err != nil {
+				return nil, fmt.Errorf("truncated delta frame: %w", err)
+			}
+		}
+	}
+	return img, nil
+}
@@ -0,0 +1,147 @@
+package delta
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// frame returns a w x h frame with a gradient, so tiles differ from each other
+func frame(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, color.RGBA{uint8(x), uint8(y), uint8(x ^ y), 255})
+		}
+	}
+	return img
+}
+
+// paint fills r of a copy of img with c
+func paint(img *image.RGBA, r image.Rectangle, c color.RGBA) *image.RGBA {
+	out := image.NewRGBA(img.Bounds())
+	copy(out.Pix, img.Pix)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			out.SetRGBA(x, y, c)
+		}
+	}
+	return out
+}
+
+// encode diffs cur against key and encodes the delta
+func encode(t *testing.T, key, cur *image.RGBA, tileSize int) []byte {
+	t.Helper()
+	changed, _, ok := Diff(key, cur, tileSize)
+	if !ok {
+		t.Fatal("Diff: frames of the same size can't be diffed")
+	}
+	var buf bytes.Buffer
+	if err := Encode(&buf, "key.png", cur, tileSize, changed); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func loadKey(key image.Image) func(string) (image.Image, error) {
+	return func(name string) (image.Image, error) {
+		if name != "key.png" {
+			return nil, fmt.Errorf("unexpected keyframe %s", name)
+		}
+		return key, nil
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	// 100x70 doesn't divide into 32px tiles, so the edge tiles are partial
+	key := frame(100, 70)
+	frames := []struct {
+		name  string
+		cur   *image.RGBA
+		tiles int
+	}{
+		{"unchanged", key, 0},
+		{"one tile", paint(key, image.Rect(40, 40, 50, 50), color.RGBA{255, 0, 0, 255}), 1},
+		{"edge tiles", paint(key, image.Rect(96, 64, 100, 70), color.RGBA{0, 0, 255, 255}), 1},
+		{"across tiles", paint(key, image.Rect(20, 20, 70, 45), color.RGBA{0, 255, 0, 255}), 6},
+		{"everything", paint(key, key.Bounds(), color.RGBA{9, 9, 9, 255}), 12},
+	}
+
+	for _, f := range frames {
+		t.Run(f.name, func(t *testing.T) {
+			data := encode(t, key, f.cur, DefaultTileSize)
+
+			h, err := ReadHeader(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("ReadHeader: %v", err)
+			}
+			if h.Width != 100 || h.Height != 70 || h.TileSize != DefaultTileSize || h.Ref != "key.png" || h.Tiles != f.tiles {
+				t.Errorf("header = %+v, want 100x70, tile size %d, key.png, %d tiles", *h, DefaultTileSize, f.tiles)
+			}
+
+			got, err := Decode(bytes.NewReader(data), loadKey(key))
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if !bytes.Equal(got.Pix, f.cur.Pix) {
+				t.Error("decoded frame differs from the encoded one")
+			}
+		})
+	}
+}
+
+func TestDiffSizeMismatch(t *testing.T) {
+	if _, _, ok := Diff(frame(64, 64), frame(64, 32), DefaultTileSize); ok {
+		t.Error("Diff of frames of different sizes reported ok")
+	}
+}
+
+func TestDecodeCorrupt(t *testing.T) {
+	key := frame(64, 64)
+	data := encode(t, key, paint(key, image.Rect(0, 0, 64, 40), color.RGBA{1, 2, 3, 255}), DefaultTileSize)
+	headerLen := 16 + 2 + len("key.png") + 4
+
+	// Tile 3 of a 64x64 frame, in a header patched to claim a 32x64 frame
+	// of two tiles
+	var buf bytes.Buffer
+	if err := Encode(&buf, "key.png", key, DefaultTileSize, []int{3}); err != nil {
+		t.Fatal(err)
+	}
+	badTile := buf.Bytes()
+	badTile[7] = 32
+
+	tests := []struct {
+		name string
+		data []byte
+		load func(string) (image.Image, error)
+		want string
+	}{
+		{"empty", nil, loadKey(key), "failed to read delta header"},
+		{"not a delta", append([]byte("PNG!"), data[4:]...), loadKey(key), "not a delta frame"},
+		{"zero size", append(append([]byte{}, data[:4]...), make([]byte, 12)...), loadKey(key), "invalid delta header"},
+		{"truncated header", data[:headerLen-2], loadKey(key), "failed to read delta header"},
+		{"truncated tiles", data[:headerLen+(len(data)-headerLen)/2], loadKey(key), "truncated delta frame"},
+		{"no tile data", data[:headerLen], loadKey(key), "truncated delta frame"},
+		{"tile out of range", badTile, loadKey(frame(32, 64)), "tile 3 out of range"},
+		{"wrong keyframe size", data, loadKey(frame(32, 32)), "delta expects 64x64"},
+		{"missing keyframe", data, func(string) (image.Image, error) { return nil, fmt.Errorf("gone") }, "failed to load keyframe key.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(bytes.NewReader(tt.data), tt.load)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Decode = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeTileOutOfRange(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, "key.png", frame(64, 64), DefaultTileSize, []int{4}); err == nil {
+		t.Error("Encode of tile 4 of a 2x2 tile frame succeeded")
+	}
+}
@@ -0,0 +1,8 @@
+// Package disk reports free space on the filesystem holding a path.
+package disk
+
+// Free returns the bytes available to the current user on the
+// filesystem containing path
+func Free(path string) (uint64, error) {
+	return free(path)
+}
@@ -0,0 +1,220 @@
+package i18n
+
+var de = map[string]string{
+	// Capture lifecycle
+	"start.started":  "Aufzeichnung gestartet für: %s",
+	"start.saving":   "Speichern in: %s",
+	"start.ctrl_c":   "Zum Beenden Strg+C drücken",
+	"stop.stopping":  "Aufzeichnung wird beendet...",
+	"stop.stopped":   "Aufzeichnung beendet",
+	"stop.duration":  "Dauer: %.1f Minuten",
+	"stop.total":     "Screenshots insgesamt: %d",
+	"stop.dropped":   "Verworfene Frames: %d",
+	"stop.unchanged": "Unveränderte Frames übersprungen: %d",
+	"next.title":     "NÄCHSTE SCHRITTE:",
+	"next.analyze":   "1. Sitzung in Claude Code analysieren:",
+	"next.commit":    "2. Mit der KI-Zusammenfassung den Smart Commit erzeugen:",
+	"next.summary":   "<KI-Zusammenfassung>",
+	"next.contains":  "Die Review-Datei enthält alle Screenshots und einen Analyse-Prompt.",
+	"next.run":       "Zum Analysieren der Sitzung in Claude Code ausführen:",
+	"next.paste":     "Oder die Datei im Editor öffnen und in Claude Code einfügen.",
+	"review.writing": "Review-Datei für die Analyse in Claude Code wird erstellt...",
+	"review.written": "Review-Datei erstellt: %s",
+	"review.parts":   "Review-Datei passend zum Größenbudget in %d Teilen erstellt: %s",
+	"review.next":    "Mit %s beginnen und die weiteren Teile in derselben Unterhaltung folgen lassen",
+
+	// Review file
+	"review.title":       "Aufgabenanalyse",
+	"review.title_part":  "Aufgabenanalyse (Teil %d von %d)",
+	"review.task":        "Aufgabe",
+	"review.session":     "Sitzungs-ID",
+	"review.duration":    "Dauer",
+	"review.minutes":     "%.1f Minuten",
+	"review.wall_clock":  "Gesamtzeit",
+	"review.gaps":        "%.1f Minuten (%.1f Minuten in %d Lücke(n) nicht gezählt)",
+	"review.paused":      "Pausiert",
+	"review.pauses":      "%.1f Minuten in %d Pause(n) nicht gezählt",
+	"review.continues":   "Fortsetzung von",
+	"review.chain":       "%s (%.1f Minuten in %d Sitzung(en) dieser Aufgabe)",
+	"review.total":       "Screenshots insgesamt",
+	"review.dropped":     "Verworfene Frames",
+	"review.dropped_why": "%d (die Festplatte kam nicht hinterher; mit Lücken in der Zeitleiste rechnen)",
+	"review.static":      "Unveränderte Frames",
+	"review.static_why":  "%d nicht gespeichert (gleich dem vorherigen Frame)",
+	"review.video":       "Video (Monitor %d)",
+	"review.video_of":    "%s, %d Frames mit %g fps; die Screenshots hier sind seine Schlüsselbilder",
+	"review.sampled":     "Ausgewählte Screenshots",
+	"review.tile":        "Screenshot %d, Kachel %d/%d (Zeile %d von %d, Spalte %d von %d)",
+	"review.skipped":     "Übersprungene Frames",
+	"review.skipped_why": "%d leer oder unverändert (nicht gezeigt)",
+	"review.earlier":     "Frühere Teile",
+	"review.earlier_in":  "Screenshots 1-%d (bis %.1f min) stehen in %s",
+	"review.screenshots": "Screenshots zur Analyse",
+	"review.screenshot":  "Screenshot %d (%.1f min)",
+	"review.monitor":     "Monitor",
+	"review.resolution":  "Auflösung",
+	"review.timestamp":   "Zeitpunkt",
+	"review.caption":     "Beschriftung",
+	"review.cursor":      "Mauszeiger",
+	"review.marked":      "Markiert",
+	"review.important":   "als wichtig gekennzeichnet",
+	"review.part_end":    "Dies ist Teil %d von %d. Halte fest, was jeder Screenshot zeigt; der Analyse-Prompt steht in %s.",
+	"review.markers":     "Markierungen",
+	"review.at_shot":     "Screenshot %s",
+
+	// Timeline entries between screenshots
+	"timeline.note":            "Notiz (%.1f min)",
+	"timeline.gap":             "Lücke (%.1f - %.1f min)",
+	"timeline.gap_why":         "%.1f Minuten ohne Aufnahmen (Ruhezustand, Sperre oder Absturz)",
+	"timeline.pause":           "Pausiert (%.1f - %.1f min)",
+	"timeline.pause_why":       "mit 'task-tracker pause'; nichts aufgezeichnet und %.1f Minuten nicht gezählt",
+	"timeline.away":            "Abwesend (%.1f - %.1f min)",
+	"timeline.away_why":        "ActivityWatch meldete keine Eingaben",
+	"timeline.paused":          "Pausiert (%.1f - %.1f min)",
+	"timeline.paused_why":      "%s (%d Platzhalter-Frames)",
+	"timeline.quarantined":     "Zurückgehalten (%.1f Min.)",
+	"timeline.quarantined_why": "ein als vertraulich erkannter Frame wurde unter Quarantäne gestellt und wird nicht gezeigt",
+	"away.privacy":             "Privatmodus",
+	"away.blackout":            "Sperrzeitfenster",
+	"away.disconnected":        "Remotedesktop getrennt",
+	"away.low_disk":            "wenig Speicherplatz",
+	"away.battery":             "niedriger Akkustand",
+	"away.rule":                "von Aufnahmeregeln übersprungen",
+
+	// Analysis prompt
+	"prompt.title":  "Analyse-Prompt",
+	"prompt.parts":  "Dies ist der letzte von %d Teilen. Berücksichtige die Screenshots aller Teile zusammen.",
+	"prompt.intro":  "Bitte analysiere die Screenshots oben und liefere:",
+	"prompt.done":   "**Was erreicht wurde**: Eine klare Zusammenfassung der geleisteten Arbeit",
+	"prompt.tasks":  "**Wichtigste Tätigkeiten**: Beobachtete Hauptaufgaben oder Arbeitsabläufe",
+	"prompt.tools":  "**Verwendete Technologien/Werkzeuge**: Welche Anwendungen oder Systeme sichtbar waren",
+	"prompt.layout": "**Organisation des Arbeitsplatzes**: Wie die Monitore/Fenster genutzt wurden (bei mehreren Monitoren)",
+	"prompt.flow":   "**Verlauf**: Wie sich die Arbeit mit der Zeit entwickelt hat",
+	"prompt.jira":   "**Vorschlag für die Jira-Zusammenfassung**: 2-3 prägnante Sätze für ein Jira-Update",
+	"prompt.focus":  "Sei konkret und konzentriere dich auf die tatsächlich sichtbare Arbeit.",
+	"prompt.tiles":  "Große Screenshots sind in beschriftete Kacheln geteilt. Behandle die Kacheln eines Screenshots als ein Bild und verweise auf ihn mit seiner Screenshot-Nummer.",
+
+	// Session report
+	"report.session":      "Sitzung",
+	"report.ticket":       "Ticket",
+	"report.tags":         "Tags",
+	"report.when":         "Wann",
+	"report.time":         "Zeit",
+	"report.billable":     "%s abrechenbar",
+	"report.task_total":   "Aufgabe gesamt",
+	"report.across":       "%s in %d Sitzung(en)",
+	"report.estimate":     "Schätzung",
+	"report.estimate_of":  "%s in %d Sitzung(en) von %s; %s verbleibend",
+	"report.screenshots":  "Screenshots",
+	"report.components":   "Betroffene Komponenten",
+	"report.rate":         "Arbeitstempo",
+	"report.rate_of":      "%.0f Bildschirmänderungen/h, %d Build(s), %d Testlauf/-läufe",
+	"report.summary":      "Zusammenfassung",
+	"report.no_summary":   "Noch keine Zusammenfassung gespeichert. Speichern mit `task-tracker summary %s \"...\"`.",
+	"report.summary_by":   "Zusammenfassung von %s",
+	"report.notes":        "Notizen",
+	"report.smart_commit": "Smart Commit",
+
+	// Session folder SUMMARY.txt
+	"summary.title":    "Task-Tracker-Sitzung",
+	"summary.started":  "Beginn",
+	"summary.ended":    "Ende",
+	"summary.monitors": "Monitore",
+	"summary.files":    "metadata.json enthält alle Details dieser Sitzung; review.md enthält die Screenshots und einen Analyse-Prompt für Claude Code.",
+	"summary.next":     "Nächste Schritte",
+	"summary.cwd":      "Diese Befehle im Ordner ausführen, der %s/ enthält.",
+	"summary.view":     "Screenshots ansehen:",
+	"summary.analyze":  "review.md neu erzeugen:",
+	"summary.report":   "Bericht schreiben:",
+	"summary.commit":   "Smart Commit zum Ticket senden:",
+	"summary.continue": "In einer neuen Sitzung an der Aufgabe weiterarbeiten:",
+	"summary.export":   "Sitzung als ZIP-Datei bündeln:",
+	"summary.written":  "Geschrieben von task-tracker %s am %s und bei jeder Änderung der Sitzung erneuert.",
+
+	// QA evidence documents
+	"evidence.title":          "Testnachweis: %s",
+	"evidence.steps":          "Schritte",
+	"evidence.step":           "Schritt %d",
+	"evidence.at":             "Aufgenommen um %s, %s nach Sitzungsbeginn",
+	"evidence.no_description": "(keine Beschreibung)",
+	"evidence.no_frame":       "Für diesen Schritt wurde kein Bild aufgenommen.",
+
+	// Weekly digest
+	"digest.title":      "Wochenübersicht: %s – %s",
+	"digest.subject":    "Wochenübersicht",
+	"digest.empty":      "Diese Woche wurden keine Sitzungen aufgezeichnet.",
+	"digest.sessions":   "Sitzungen",
+	"digest.active":     "Aktive Zeit",
+	"digest.tickets":    "Tickets",
+	"digest.per_ticket": "Stunden pro Ticket",
+	"digest.top":        "Häufigste Tätigkeiten",
+	"digest.activity":   "Tätigkeit",
+	"digest.notable":    "Auffällige Sitzungen",
+	"digest.task":       "Aufgabe",
+	"digest.started":    "Beginn",
+	"digest.no_ticket":  "(keins)",
+
+	// Totals tables
+	"totals.sessions":  "Sitzungen",
+	"totals.time":      "Zeit",
+	"totals.title":     "Summen: %s – %s",
+	"totals.wall":      "Gesamtdauer",
+	"totals.by_ticket": "Nach Ticket",
+	"totals.by_tag":    "Nach Tags",
+	"totals.tag":       "Tag",
+
+	// Sprint report
+	"sprint.title":         "Sprint-Bericht: %s",
+	"sprint.state":         "Status",
+	"sprint.dates":         "Zeitraum",
+	"sprint.issues":        "Vorgänge",
+	"sprint.worked_on":     "%d (%d bearbeitet)",
+	"sprint.tracked_time":  "Erfasste Zeit",
+	"sprint.missing":       "Fehlende Arbeitsprotokolle",
+	"sprint.issue":         "Vorgang",
+	"sprint.status":        "Status",
+	"sprint.tracked":       "Erfasst",
+	"sprint.logged":        "In Jira protokolliert",
+	"sprint.none":          "keine",
+	"sprint.missing_title": "Fehlende Arbeitsprotokolle",
+	"sprint.missing_why":   "Für diese Vorgänge wurde Zeit erfasst, aber in Jira ist nichts protokolliert. Eine Sitzung protokollieren mit `task-tracker commit <session> --post`.",
+	"sprint.work":          "Arbeit pro Vorgang",
+
+	// Ticket report and roll-up comment
+	"ticket.title":          "Ticket %s",
+	"ticket.link":           "Link",
+	"ticket.logged":         "Im Ticket protokolliert",
+	"ticket.unlogged":       "Noch nicht protokolliert",
+	"ticket.unlogged_of":    "%s in %d Sitzung(en)",
+	"ticket.logged_on":      "Protokolliert",
+	"ticket.combined":       "Gesamtzusammenfassung",
+	"ticket.work_across":    "Arbeit in %d Sitzung(en):",
+	"ticket.estimate_delta": "%s erfasst von %s geschätzt (%s%s, %.0f %%)",
+
+	// Pivot timesheet; the SAP CATS columns are field names and stay as they are
+	"timesheet.project":   "Projekt",
+	"timesheet.total":     "Summe",
+	"timesheet.no_ticket": "(kein Ticket)",
+
+	// Session comparison
+	"compare.title":      "Sitzungsvergleich: %s und %s",
+	"compare.change":     "Änderung",
+	"compare.wall":       "Gesamtdauer",
+	"compare.gaps":       "Unterbrechungen",
+	"compare.pulse":      "Produktivitätspuls",
+	"compare.min":        "%.1f Min.",
+	"compare.min_delta":  "%+.1f Min.",
+	"compare.apps":       "App-Nutzung (ActivityWatch)",
+	"compare.app":        "App",
+	"compare.categories": "Tätigkeitsklassifizierung (RescueTime)",
+	"compare.category":   "Kategorie",
+	"compare.frames":     "Ausgewählte Frames",
+
+	// Rate of work per week
+	"rate.title":   "Arbeitstempo",
+	"rate.week":    "Woche",
+	"rate.active":  "Aktiv",
+	"rate.changes": "Bildschirmänderungen/h",
+	"rate.builds":  "Builds",
+	"rate.tests":   "Testläufe",
+}
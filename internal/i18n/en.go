@@ -0,0 +1,220 @@
+package i18n
+
+var en = map[string]string{
+	// Capture lifecycle
+	"start.started":  "Started capturing for: %s",
+	"start.saving":   "Saving to: %s",
+	"start.ctrl_c":   "Press Ctrl+C when done",
+	"stop.stopping":  "Stopping capture...",
+	"stop.stopped":   "Capture stopped",
+	"stop.duration":  "Duration: %.1f minutes",
+	"stop.total":     "Total screenshots: %d",
+	"stop.dropped":   "Dropped frames: %d",
+	"stop.unchanged": "Unchanged frames skipped: %d",
+	"next.title":     "NEXT STEPS:",
+	"next.analyze":   "1. Analyze your session in Claude Code:",
+	"next.commit":    "2. After getting the AI summary, generate smart commit:",
+	"next.summary":   "<AI generated summary>",
+	"next.contains":  "The review file contains all screenshots and an analysis prompt.",
+	"next.run":       "To analyze your session in Claude Code, run:",
+	"next.paste":     "Or open the file in your editor and paste it into Claude Code.",
+	"review.writing": "Generating review file for Claude Code analysis...",
+	"review.written": "Review file generated: %s",
+	"review.parts":   "Review file generated in %d parts to fit the size budget: %s",
+	"review.next":    "Start with %s and continue with the next part in the same conversation",
+
+	// Review file
+	"review.title":       "Task Analysis Review",
+	"review.title_part":  "Task Analysis Review (Part %d of %d)",
+	"review.task":        "Task Name",
+	"review.session":     "Session ID",
+	"review.duration":    "Duration",
+	"review.minutes":     "%.1f minutes",
+	"review.wall_clock":  "Wall Clock",
+	"review.gaps":        "%.1f minutes (%.1f minutes in %d gap(s) not counted)",
+	"review.paused":      "Paused",
+	"review.pauses":      "%.1f minutes in %d pause(s) not counted",
+	"review.continues":   "Continues",
+	"review.chain":       "%s (%.1f minutes across %d session(s) of this task)",
+	"review.total":       "Total Screenshots",
+	"review.dropped":     "Dropped Frames",
+	"review.dropped_why": "%d (the disk couldn't keep up; expect holes in the timeline)",
+	"review.static":      "Unchanged Frames",
+	"review.static_why":  "%d not saved (identical to the frame before)",
+	"review.video":       "Video (Monitor %d)",
+	"review.video_of":    "%s, %d frames at %g fps; the screenshots here are its keyframes",
+	"review.sampled":     "Sampled Screenshots",
+	"review.tile":        "Screenshot %d, tile %d/%d (row %d of %d, column %d of %d)",
+	"review.skipped":     "Skipped Frames",
+	"review.skipped_why": "%d blank or unchanged (not shown)",
+	"review.earlier":     "Earlier Parts",
+	"review.earlier_in":  "screenshots 1-%d (up to %.1f min) are in %s",
+	"review.screenshots": "Screenshots for Analysis",
+	"review.screenshot":  "Screenshot %d (%.1f min)",
+	"review.monitor":     "Monitor",
+	"review.resolution":  "Resolution",
+	"review.timestamp":   "Timestamp",
+	"review.caption":     "Caption",
+	"review.cursor":      "Cursor",
+	"review.marked":      "Marked",
+	"review.important":   "flagged as important",
+	"review.part_end":    "This is part %d of %d. Note what each screenshot shows; the analysis prompt is in %s.",
+	"review.markers":     "Markers",
+	"review.at_shot":     "screenshot %s",
+
+	// Timeline entries between screenshots
+	"timeline.note":            "Note (%.1f min)",
+	"timeline.gap":             "Gap (%.1f - %.1f min)",
+	"timeline.gap_why":         "no captures for %.1f minutes (sleep, lock or crash)",
+	"timeline.pause":           "Paused (%.1f - %.1f min)",
+	"timeline.pause_why":       "with 'task-tracker pause'; nothing recorded and %.1f minutes not counted",
+	"timeline.away":            "Away (%.1f - %.1f min)",
+	"timeline.away_why":        "ActivityWatch reported no input",
+	"timeline.paused":          "Paused (%.1f - %.1f min)",
+	"timeline.paused_why":      "%s (%d placeholder frames)",
+	"timeline.quarantined":     "Withheld (%.1f min)",
+	"timeline.quarantined_why": "a frame flagged as sensitive was quarantined and isn't shown",
+	"away.privacy":             "privacy mode",
+	"away.blackout":            "blackout window",
+	"away.disconnected":        "remote desktop disconnected",
+	"away.low_disk":            "low disk space",
+	"away.battery":             "low battery",
+	"away.rule":                "skipped by capture rules",
+
+	// Analysis prompt
+	"prompt.title":  "Analysis Prompt",
+	"prompt.parts":  "This is the last of %d parts. Consider the screenshots from all parts together.",
+	"prompt.intro":  "Please analyze the screenshots above and provide:",
+	"prompt.done":   "**What was accomplished**: A clear summary of the work done",
+	"prompt.tasks":  "**Key activities**: Main tasks or workflows observed",
+	"prompt.tools":  "**Technologies/Tools used**: What applications or systems were visible",
+	"prompt.layout": "**Workspace organization**: How different monitors/windows were used (if multi-monitor)",
+	"prompt.flow":   "**Progression**: How the work evolved over time",
+	"prompt.jira":   "**Suggested Jira summary**: A concise 2-3 sentence summary suitable for a Jira task update",
+	"prompt.focus":  "Be specific and focus on the actual work visible in the screenshots.",
+	"prompt.tiles":  "Large screenshots are split into labeled tiles. Treat a screenshot's tiles as one image and refer to it by its screenshot number.",
+
+	// Session report
+	"report.session":      "Session",
+	"report.ticket":       "Ticket",
+	"report.tags":         "Tags",
+	"report.when":         "When",
+	"report.time":         "Time",
+	"report.billable":     "%s billable",
+	"report.task_total":   "Task Total",
+	"report.across":       "%s across %d session(s)",
+	"report.estimate":     "Estimate",
+	"report.estimate_of":  "%s across %d session(s) of %s; %s remaining",
+	"report.screenshots":  "Screenshots",
+	"report.components":   "Components touched",
+	"report.rate":         "Rate of work",
+	"report.rate_of":      "%.0f screen changes/h, %d build(s), %d test run(s)",
+	"report.summary":      "Summary",
+	"report.no_summary":   "No summary saved yet. Save one with `task-tracker summary %s \"...\"`.",
+	"report.summary_by":   "Summary by %s",
+	"report.notes":        "Notes",
+	"report.smart_commit": "Smart Commit",
+
+	// Session folder SUMMARY.txt
+	"summary.title":    "Task Tracker Session",
+	"summary.started":  "Started",
+	"summary.ended":    "Ended",
+	"summary.monitors": "Monitors",
+	"summary.files":    "metadata.json holds every detail of this session; review.md has the screenshots and an analysis prompt for Claude Code.",
+	"summary.next":     "Next Steps",
+	"summary.cwd":      "Run these from the folder that contains %s/.",
+	"summary.view":     "Browse the screenshots:",
+	"summary.analyze":  "Regenerate review.md:",
+	"summary.report":   "Write a report:",
+	"summary.commit":   "Post a smart commit to the ticket:",
+	"summary.continue": "Keep working on the task in a new session:",
+	"summary.export":   "Bundle the session into a zip file:",
+	"summary.written":  "Written by task-tracker %s on %s, and again whenever the session changes.",
+
+	// QA evidence documents
+	"evidence.title":          "Test Evidence: %s",
+	"evidence.steps":          "Steps",
+	"evidence.step":           "Step %d",
+	"evidence.at":             "Captured at %s, %s into the session",
+	"evidence.no_description": "(no description)",
+	"evidence.no_frame":       "No frame was captured for this step.",
+
+	// Weekly digest
+	"digest.title":      "Weekly Digest: %s – %s",
+	"digest.subject":    "Weekly digest",
+	"digest.empty":      "No sessions were recorded this week.",
+	"digest.sessions":   "Sessions",
+	"digest.active":     "Active time",
+	"digest.tickets":    "Tickets",
+	"digest.per_ticket": "Hours per Ticket",
+	"digest.top":        "Top Activities",
+	"digest.activity":   "Activity",
+	"digest.notable":    "Notable Sessions",
+	"digest.task":       "Task",
+	"digest.started":    "Started",
+	"digest.no_ticket":  "(none)",
+
+	// Totals tables
+	"totals.sessions":  "Sessions",
+	"totals.time":      "Time",
+	"totals.title":     "Totals: %s – %s",
+	"totals.wall":      "Wall-clock time",
+	"totals.by_ticket": "By Ticket",
+	"totals.by_tag":    "By Tag",
+	"totals.tag":       "Tag",
+
+	// Sprint report
+	"sprint.title":         "Sprint Report: %s",
+	"sprint.state":         "State",
+	"sprint.dates":         "Dates",
+	"sprint.issues":        "Issues",
+	"sprint.worked_on":     "%d (%d worked on)",
+	"sprint.tracked_time":  "Tracked time",
+	"sprint.missing":       "Missing worklogs",
+	"sprint.issue":         "Issue",
+	"sprint.status":        "Status",
+	"sprint.tracked":       "Tracked",
+	"sprint.logged":        "Logged in Jira",
+	"sprint.none":          "none",
+	"sprint.missing_title": "Missing Worklogs",
+	"sprint.missing_why":   "Time was tracked on these issues but nothing is logged in Jira. Log a session with `task-tracker commit <session> --post`.",
+	"sprint.work":          "Work per Issue",
+
+	// Ticket report and roll-up comment
+	"ticket.title":          "Ticket %s",
+	"ticket.link":           "Link",
+	"ticket.logged":         "Logged on the ticket",
+	"ticket.unlogged":       "Not logged yet",
+	"ticket.unlogged_of":    "%s in %d session(s)",
+	"ticket.logged_on":      "Logged",
+	"ticket.combined":       "Combined Summary",
+	"ticket.work_across":    "Work across %d session(s):",
+	"ticket.estimate_delta": "%s tracked of %s estimate (%s%s, %.0f%%)",
+
+	// Pivot timesheet; the SAP CATS columns are field names and stay as they are
+	"timesheet.project":   "Project",
+	"timesheet.total":     "Total",
+	"timesheet.no_ticket": "(no ticket)",
+
+	// Session comparison
+	"compare.title":      "Session Comparison: %s vs %s",
+	"compare.change":     "Change",
+	"compare.wall":       "Wall time",
+	"compare.gaps":       "Gaps",
+	"compare.pulse":      "Productivity pulse",
+	"compare.min":        "%.1f min",
+	"compare.min_delta":  "%+.1f min",
+	"compare.apps":       "App Usage (ActivityWatch)",
+	"compare.app":        "App",
+	"compare.categories": "Activity Classification (RescueTime)",
+	"compare.category":   "Category",
+	"compare.frames":     "Sampled Frames",
+
+	// Rate of work per week
+	"rate.title":   "Rate of Work",
+	"rate.week":    "Week",
+	"rate.active":  "Active",
+	"rate.changes": "Screen changes/h",
+	"rate.builds":  "Builds",
+	"rate.tests":   "Test runs",
+}
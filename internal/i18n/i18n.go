@@ -0,0 +1,101 @@
+// Package i18n looks up user-facing strings in per-language message
+// catalogs. Keys are dotted names; a key missing from a catalog falls back
+// to English and then to the key itself.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Default is the language used when nothing else is selected
+const Default = "en"
+
+var catalogs = map[string]map[string]string{
+	"en": en,
+	"de": de,
+	"ja": ja,
+}
+
+var (
+	mu     sync.RWMutex
+	locale = Default
+)
+
+// Supported lists the available languages
+func Supported() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// normalize reduces a locale such as de_DE.UTF-8 or ja-JP to its language
+func normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "_-.@"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// Supports reports whether there's a catalog for tag's language
+func Supports(tag string) bool {
+	_, ok := catalogs[normalize(tag)]
+	return ok
+}
+
+// SetLocale selects the language for T. Region and encoding suffixes are
+// ignored, so de_DE.UTF-8 selects German.
+func SetLocale(tag string) error {
+	if !Supports(tag) {
+		return fmt.Errorf("unsupported language '%s' (use %s)", tag, strings.Join(Supported(), ", "))
+	}
+	mu.Lock()
+	locale = normalize(tag)
+	mu.Unlock()
+	return nil
+}
+
+// Locale returns the selected language
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// Detect picks a supported language from the POSIX locale variables, in
+// their usual order of precedence. It returns Default when none match.
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		if Supports(value) {
+			return normalize(value)
+		}
+		return Default // C, POSIX or a language without a catalog
+	}
+	return Default
+}
+
+// T returns the message for key in the selected language, formatted with
+// args when there are any
+func T(key string, args ...any) string {
+	msg, ok := catalogs[Locale()][key]
+	if !ok {
+		if msg, ok = en[key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
@@ -0,0 +1,220 @@
+package i18n
+
+var ja = map[string]string{
+	// Capture lifecycle
+	"start.started":  "キャプチャを開始しました: %s",
+	"start.saving":   "保存先: %s",
+	"start.ctrl_c":   "終了するには Ctrl+C を押してください",
+	"stop.stopping":  "キャプチャを停止しています...",
+	"stop.stopped":   "キャプチャを停止しました",
+	"stop.duration":  "作業時間: %.1f 分",
+	"stop.total":     "スクリーンショット合計: %d",
+	"stop.dropped":   "欠落フレーム: %d",
+	"stop.unchanged": "変化のないフレームをスキップ: %d",
+	"next.title":     "次のステップ:",
+	"next.analyze":   "1. Claude Code でセッションを分析:",
+	"next.commit":    "2. AI の要約を受け取ったら、スマートコミットを生成:",
+	"next.summary":   "<AI が生成した要約>",
+	"next.contains":  "レビューファイルにはすべてのスクリーンショットと分析プロンプトが含まれています。",
+	"next.run":       "Claude Code でセッションを分析するには、次を実行してください:",
+	"next.paste":     "またはエディタでファイルを開き、Claude Code に貼り付けてください。",
+	"review.writing": "Claude Code で分析するためのレビューファイルを生成しています...",
+	"review.written": "レビューファイルを生成しました: %s",
+	"review.parts":   "サイズ上限に収めるため、レビューファイルを %d 個に分割して生成しました: %s",
+	"review.next":    "%s から始め、同じ会話で続きのパートを読み込ませてください",
+
+	// Review file
+	"review.title":       "タスク分析レビュー",
+	"review.title_part":  "タスク分析レビュー (パート %d / %d)",
+	"review.task":        "タスク名",
+	"review.session":     "セッション ID",
+	"review.duration":    "作業時間",
+	"review.minutes":     "%.1f 分",
+	"review.wall_clock":  "経過時間",
+	"review.gaps":        "%.1f 分 (%[3]d 件の中断、計 %.1[2]f 分は含まない)",
+	"review.paused":      "一時停止",
+	"review.pauses":      "%[2]d 回の一時停止、計 %.1[1]f 分は含まない",
+	"review.continues":   "継続元",
+	"review.chain":       "%s (このタスクの %[3]d セッションで計 %.1[2]f 分)",
+	"review.total":       "スクリーンショット合計",
+	"review.dropped":     "欠落フレーム",
+	"review.dropped_why": "%d (ディスクの書き込みが追いつきませんでした。タイムラインに抜けがあります)",
+	"review.static":      "変化のないフレーム",
+	"review.static_why":  "%d (直前のフレームと同じため保存していません)",
+	"review.video":       "動画 (モニター %d)",
+	"review.video_of":    "%[1]s、%[3]g fps で %[2]d フレーム。ここのスクリーンショットはそのキーフレームです",
+	"review.sampled":     "抽出したスクリーンショット",
+	"review.tile":        "スクリーンショット %d、タイル %d/%d (%d/%d 行目、%d/%d 列目)",
+	"review.skipped":     "スキップしたフレーム",
+	"review.skipped_why": "%d 件 (空白または変化なし、非表示)",
+	"review.earlier":     "前のパート",
+	"review.earlier_in":  "スクリーンショット 1-%d (%.1f 分まで) は %s にあります",
+	"review.screenshots": "分析対象のスクリーンショット",
+	"review.screenshot":  "スクリーンショット %d (%.1f 分)",
+	"review.monitor":     "モニター",
+	"review.resolution":  "解像度",
+	"review.timestamp":   "時刻",
+	"review.caption":     "キャプション",
+	"review.cursor":      "カーソル位置",
+	"review.marked":      "マーク",
+	"review.important":   "重要としてマーク済み",
+	"review.part_end":    "これはパート %d / %d です。各スクリーンショットの内容を把握してください。分析プロンプトは %s にあります。",
+	"review.markers":     "マーカー",
+	"review.at_shot":     "スクリーンショット %s",
+
+	// Timeline entries between screenshots
+	"timeline.note":            "メモ (%.1f 分)",
+	"timeline.gap":             "中断 (%.1f - %.1f 分)",
+	"timeline.gap_why":         "%.1f 分間キャプチャなし (スリープ、ロック、またはクラッシュ)",
+	"timeline.pause":           "一時停止 (%.1f - %.1f 分)",
+	"timeline.pause_why":       "'task-tracker pause' による。記録なし、%.1f 分は含まない",
+	"timeline.away":            "離席 (%.1f - %.1f 分)",
+	"timeline.away_why":        "ActivityWatch が入力なしを報告",
+	"timeline.paused":          "一時停止 (%.1f - %.1f 分)",
+	"timeline.paused_why":      "%s (プレースホルダー %d 件)",
+	"timeline.quarantined":     "非表示 (%.1f 分)",
+	"timeline.quarantined_why": "機密の可能性があるフレームを隔離したため表示していません",
+	"away.privacy":             "プライバシーモード",
+	"away.blackout":            "ブラックアウト時間帯",
+	"away.disconnected":        "リモートデスクトップ切断",
+	"away.low_disk":            "ディスク容量不足",
+	"away.battery":             "バッテリー残量低下",
+	"away.rule":                "キャプチャルールでスキップ",
+
+	// Analysis prompt
+	"prompt.title":  "分析プロンプト",
+	"prompt.parts":  "これは %d 個のパートの最後です。すべてのパートのスクリーンショットをまとめて考慮してください。",
+	"prompt.intro":  "上のスクリーンショットを分析し、次の内容を日本語で回答してください:",
+	"prompt.done":   "**達成したこと**: 行った作業の明確な要約",
+	"prompt.tasks":  "**主な作業**: 確認できた主なタスクやワークフロー",
+	"prompt.tools":  "**使用した技術/ツール**: 表示されていたアプリケーションやシステム",
+	"prompt.layout": "**作業環境の使い方**: 各モニター/ウィンドウの使い分け (複数モニターの場合)",
+	"prompt.flow":   "**作業の流れ**: 時間の経過とともに作業がどう進んだか",
+	"prompt.jira":   "**Jira 用の要約案**: Jira タスクの更新に適した 2-3 文の簡潔な要約",
+	"prompt.focus":  "スクリーンショットに実際に写っている作業に焦点を当て、具体的に記述してください。",
+	"prompt.tiles":  "大きなスクリーンショットはラベル付きのタイルに分割されています。同じスクリーンショットのタイルは 1 枚の画像として扱い、スクリーンショット番号で参照してください。",
+
+	// Session report
+	"report.session":      "セッション",
+	"report.ticket":       "チケット",
+	"report.tags":         "タグ",
+	"report.when":         "日時",
+	"report.time":         "時間",
+	"report.billable":     "請求対象 %s",
+	"report.task_total":   "タスク合計",
+	"report.across":       "%[2]d セッションで計 %[1]s",
+	"report.estimate":     "見積もり",
+	"report.estimate_of":  "%[3]s の %[2]d セッションで %[1]s。残り %[4]s",
+	"report.screenshots":  "スクリーンショット",
+	"report.components":   "変更したコンポーネント",
+	"report.rate":         "作業ペース",
+	"report.rate_of":      "画面変化 %.0f 回/時、ビルド %d 回、テスト %d 回",
+	"report.summary":      "要約",
+	"report.no_summary":   "要約はまだ保存されていません。`task-tracker summary %s \"...\"` で保存できます。",
+	"report.summary_by":   "要約: %s",
+	"report.notes":        "メモ",
+	"report.smart_commit": "スマートコミット",
+
+	// Session folder SUMMARY.txt
+	"summary.title":    "Task Tracker セッション",
+	"summary.started":  "開始",
+	"summary.ended":    "終了",
+	"summary.monitors": "モニター",
+	"summary.files":    "metadata.json にはこのセッションのすべての詳細が、review.md にはスクリーンショットと Claude Code 用の分析プロンプトが含まれています。",
+	"summary.next":     "次のステップ",
+	"summary.cwd":      "以下のコマンドは %s/ を含むフォルダで実行してください。",
+	"summary.view":     "スクリーンショットを閲覧:",
+	"summary.analyze":  "review.md を再生成:",
+	"summary.report":   "レポートを作成:",
+	"summary.commit":   "チケットにスマートコミットを投稿:",
+	"summary.continue": "新しいセッションでタスクを続ける:",
+	"summary.export":   "セッションを zip ファイルにまとめる:",
+	"summary.written":  "task-tracker %s により %s に作成され、セッションが変わるたびに更新されます。",
+
+	// QA evidence documents
+	"evidence.title":          "テストエビデンス: %s",
+	"evidence.steps":          "ステップ数",
+	"evidence.step":           "ステップ %d",
+	"evidence.at":             "%s に取得 (セッション開始から %s)",
+	"evidence.no_description": "(説明なし)",
+	"evidence.no_frame":       "このステップの画像は取得されませんでした。",
+
+	// Weekly digest
+	"digest.title":      "週間ダイジェスト: %s – %s",
+	"digest.subject":    "週間ダイジェスト",
+	"digest.empty":      "今週記録されたセッションはありません。",
+	"digest.sessions":   "セッション数",
+	"digest.active":     "作業時間",
+	"digest.tickets":    "チケット数",
+	"digest.per_ticket": "チケット別の時間",
+	"digest.top":        "主な作業",
+	"digest.activity":   "作業",
+	"digest.notable":    "注目のセッション",
+	"digest.task":       "タスク",
+	"digest.started":    "開始",
+	"digest.no_ticket":  "(なし)",
+
+	// Totals tables
+	"totals.sessions":  "セッション数",
+	"totals.time":      "時間",
+	"totals.title":     "合計: %s – %s",
+	"totals.wall":      "経過時間",
+	"totals.by_ticket": "チケット別",
+	"totals.by_tag":    "タグ別",
+	"totals.tag":       "タグ",
+
+	// Sprint report
+	"sprint.title":         "スプリントレポート: %s",
+	"sprint.state":         "状態",
+	"sprint.dates":         "期間",
+	"sprint.issues":        "課題",
+	"sprint.worked_on":     "%d 件 (作業済み %d 件)",
+	"sprint.tracked_time":  "記録時間",
+	"sprint.missing":       "作業ログ未記録",
+	"sprint.issue":         "課題",
+	"sprint.status":        "ステータス",
+	"sprint.tracked":       "記録",
+	"sprint.logged":        "Jira の作業ログ",
+	"sprint.none":          "なし",
+	"sprint.missing_title": "未記録の作業ログ",
+	"sprint.missing_why":   "これらの課題では時間が記録されていますが、Jira に作業ログがありません。`task-tracker commit <session> --post` でセッションを記録してください。",
+	"sprint.work":          "課題別の作業",
+
+	// Ticket report and roll-up comment
+	"ticket.title":          "チケット %s",
+	"ticket.link":           "リンク",
+	"ticket.logged":         "チケットに記録済み",
+	"ticket.unlogged":       "未記録",
+	"ticket.unlogged_of":    "%[2]d セッションで %[1]s",
+	"ticket.logged_on":      "記録日",
+	"ticket.combined":       "まとめ",
+	"ticket.work_across":    "%d セッションの作業:",
+	"ticket.estimate_delta": "見積もり %[2]s に対して記録 %[1]s (%[3]s%[4]s、%.0[5]f%%)",
+
+	// Pivot timesheet; the SAP CATS columns are field names and stay as they are
+	"timesheet.project":   "プロジェクト",
+	"timesheet.total":     "合計",
+	"timesheet.no_ticket": "(チケットなし)",
+
+	// Session comparison
+	"compare.title":      "セッション比較: %s と %s",
+	"compare.change":     "変化",
+	"compare.wall":       "経過時間",
+	"compare.gaps":       "中断",
+	"compare.pulse":      "生産性パルス",
+	"compare.min":        "%.1f 分",
+	"compare.min_delta":  "%+.1f 分",
+	"compare.apps":       "アプリ使用状況 (ActivityWatch)",
+	"compare.app":        "アプリ",
+	"compare.categories": "作業分類 (RescueTime)",
+	"compare.category":   "カテゴリ",
+	"compare.frames":     "抽出したフレーム",
+
+	// Rate of work per week
+	"rate.title":   "作業ペース",
+	"rate.week":    "週",
+	"rate.active":  "作業時間",
+	"rate.changes": "画面変化/時",
+	"rate.builds":  "ビルド",
+	"rate.tests":   "テスト",
+}
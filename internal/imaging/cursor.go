@@ -0,0 +1,59 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+)
+
+// cursorShape is a standard arrow pointer: X is outline, . is fill
+var cursorShape = []string{
+	"X",
+	"XX",
+	"X.X",
+	"X..X",
+	"X...X",
+	"X....X",
+	"X.....X",
+	"X......X",
+	"X.......X",
+	"X........X",
+	"X.....XXXXX",
+	"X..X..X",
+	"X.X X..X",
+	"XX  X..X",
+	"X    X..X",
+	"     X..X",
+	"      XX",
+}
+
+// DrawCursor draws an arrow pointer with its tip at p. scale enlarges it
+// for HiDPI frames, where a pointer drawn at 1x would be hard to see.
+func DrawCursor(img *image.RGBA, p image.Point, scale int) {
+	if scale < 1 {
+		scale = 1
+	}
+	outline := color.RGBA{0, 0, 0, 255}
+	fill := color.RGBA{255, 255, 255, 255}
+
+	for y, row := range cursorShape {
+		for x, c := range row {
+			var col color.RGBA
+			switch c {
+			case 'X':
+				col = outline
+			case '.':
+				col = fill
+			default:
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					pt := image.Pt(p.X+x*scale+dx, p.Y+y*scale+dy)
+					if pt.In(img.Rect) {
+						img.SetRGBA(pt.X, pt.Y, col)
+					}
+				}
+			}
+		}
+	}
+}
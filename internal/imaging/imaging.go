@@ -0,0 +1,69 @@
+// Package imaging holds image processing shared by task-tracker and
+// monitor-helper.
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// ToRGBA returns img as *image.RGBA, copying only if needed
+func ToRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba
+}
+
+// redactBlock is the pixelation block size of redacted regions; larger
+// than needed for text since these regions were picked as sensitive
+const redactBlock = 24
+
+// Redact pixelates r beyond recognition. Viewers apply it to frames with
+// redactions, so it must stay deterministic.
+func Redact(img *image.RGBA, r image.Rectangle) {
+	Pixelate(img, r.Add(img.Bounds().Min), redactBlock)
+}
+
+// Pixelate replaces each block x block square of r with its average color.
+// Blocks of 12px or more make ordinary UI text unreadable while keeping
+// window layout recognizable.
+func Pixelate(img *image.RGBA, r image.Rectangle, block int) {
+	r = r.Intersect(img.Bounds())
+	if block < 2 || r.Empty() {
+		return
+	}
+
+	for by := r.Min.Y; by < r.Max.Y; by += block {
+		for bx := r.Min.X; bx < r.Max.X; bx += block {
+			cell := image.Rect(bx, by, bx+block, by+block).Intersect(r)
+
+			var sr, sg, sb, n uint32
+			for y := cell.Min.Y; y < cell.Max.Y; y++ {
+				for x := cell.Min.X; x < cell.Max.X; x++ {
+					c := img.RGBAAt(x, y)
+					sr += uint32(c.R)
+					sg += uint32(c.G)
+					sb += uint32(c.B)
+					n++
+				}
+			}
+
+			avg := color.RGBA{uint8(sr / n), uint8(sg / n), uint8(sb / n), 255}
+			draw.Draw(img, cell, &image.Uniform{avg}, image.Point{}, draw.Src)
+		}
+	}
+}
+
+// maskColor fills masked windows: dark, so a masked terminal looks like an
+// empty one rather than a hole in the screenshot
+var maskColor = color.RGBA{0x2b, 0x2b, 0x2b, 0xff}
+
+// Mask fills r with a flat color. Unlike Redact nothing of the original
+// survives, not even layout.
+func Mask(img *image.RGBA, r image.Rectangle) {
+	draw.Draw(img, r.Intersect(img.Bounds()), &image.Uniform{maskColor}, image.Point{}, draw.Src)
+}
@@ -0,0 +1,130 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Corner is where a watermark is placed
+type Corner string
+
+const (
+	TopLeft     Corner = "top-left"
+	TopRight    Corner = "top-right"
+	BottomLeft  Corner = "bottom-left"
+	BottomRight Corner = "bottom-right"
+)
+
+// ParseCorner validates a corner name; empty means BottomRight
+func ParseCorner(s string) (Corner, error) {
+	switch c := Corner(s); c {
+	case "":
+		return BottomRight, nil
+	case TopLeft, TopRight, BottomLeft, BottomRight:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown corner '%s' (use top-left, top-right, bottom-left or bottom-right)", s)
+}
+
+const (
+	labelPadding    = 10
+	labelLineHeight = 18
+	labelMargin     = 10
+	maxLogoHeight   = 48
+)
+
+// Label draws text on a dark box in the top-left corner of img
+func Label(img *image.RGBA, text string) {
+	box := renderLabel([]string{text}, nil)
+	r := box.Bounds().Add(img.Bounds().Min).Add(image.Pt(labelMargin, labelMargin))
+	draw.Draw(img, r, box, image.Point{}, draw.Over)
+}
+
+// Watermark stamps lines of text and an optional logo onto frames
+type Watermark struct {
+	Lines   []string
+	Logo    image.Image
+	Corner  Corner
+	Opacity float64 // 0-1, 0 means fully opaque
+}
+
+// Draw stamps the watermark onto img
+func (w Watermark) Draw(img *image.RGBA) {
+	box := renderLabel(w.Lines, w.Logo)
+	size := box.Bounds().Size()
+	b := img.Bounds()
+
+	at := image.Pt(b.Min.X+labelMargin, b.Min.Y+labelMargin)
+	switch w.Corner {
+	case TopRight:
+		at.X = b.Max.X - size.X - labelMargin
+	case BottomLeft:
+		at.Y = b.Max.Y - size.Y - labelMargin
+	case BottomRight, "":
+		at = image.Pt(b.Max.X-size.X-labelMargin, b.Max.Y-size.Y-labelMargin)
+	}
+
+	alpha := uint8(255)
+	if w.Opacity > 0 && w.Opacity < 1 {
+		alpha = uint8(w.Opacity * 255)
+	}
+	mask := image.NewUniform(color.Alpha{alpha})
+	draw.DrawMask(img, image.Rectangle{at, at.Add(size)}, box, image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// renderLabel draws white text, and the logo to its left, on a
+// translucent black box sized to fit
+func renderLabel(lines []string, logo image.Image) *image.RGBA {
+	face := basicfont.Face7x13
+
+	textWidth := 0
+	for _, line := range lines {
+		if w := font.MeasureString(face, line).Ceil(); w > textWidth {
+			textWidth = w
+		}
+	}
+	textHeight := len(lines) * labelLineHeight
+
+	logoSize := image.Point{}
+	if logo != nil {
+		logoSize = logo.Bounds().Size()
+		if logoSize.Y > maxLogoHeight {
+			logoSize = image.Pt(logoSize.X*maxLogoHeight/logoSize.Y, maxLogoHeight)
+		}
+	}
+
+	width := textWidth + 2*labelPadding
+	height := max(textHeight, logoSize.Y) + 2*labelPadding
+	if logo != nil {
+		width += logoSize.X + labelPadding
+	}
+
+	box := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(box, box.Bounds(), &image.Uniform{color.RGBA{0, 0, 0, 200}}, image.Point{}, draw.Src)
+
+	textX := labelPadding
+	if logo != nil {
+		r := image.Rect(labelPadding, labelPadding, labelPadding+logoSize.X, labelPadding+logoSize.Y)
+		xdraw.ApproxBiLinear.Scale(box, r, logo, logo.Bounds(), draw.Over, nil)
+		textX += logoSize.X + labelPadding
+	}
+
+	d := &font.Drawer{
+		Dst:  box,
+		Src:  image.NewUniform(color.RGBA{255, 255, 255, 255}),
+		Face: face,
+	}
+	for i, line := range lines {
+		// baseline sits ~4px above the bottom of each line
+		d.Dot = fixed.Point26_6{X: fixed.I(textX), Y: fixed.I(labelPadding + (i+1)*labelLineHeight - 5)}
+		d.DrawString(line)
+	}
+	return box
+}
@@ -0,0 +1,123 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"unicode/utf8"
+)
+
+// OptimizePNG losslessly recompresses a PNG at the best compression level,
+// switching to a palette when the image has at most 256 colors. Text
+// chunks are carried over. The original data is returned if the result
+// isn't smaller.
+func OptimizePNG(data []byte) ([]byte, error) {
+	text, err := ReadPNGText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if p := toPaletted(img); p != nil {
+		img = p
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if err := bestEncoder.Encode(buf, img); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := insertText(&out, buf.Bytes(), text); err != nil {
+		return nil, err
+	}
+	if out.Len() >= len(data) {
+		return data, nil
+	}
+	return out.Bytes(), nil
+}
+
+// toPaletted returns a paletted copy of img, or nil if it has more than
+// 256 distinct colors
+func toPaletted(img image.Image) *image.Paletted {
+	if p, ok := img.(*image.Paletted); ok {
+		return p
+	}
+
+	b := img.Bounds()
+	index := make(map[color.RGBA]uint8)
+	palette := color.Palette{}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			if _, ok := index[c]; ok {
+				continue
+			}
+			if len(palette) == 256 {
+				return nil
+			}
+			index[c] = uint8(len(palette))
+			palette = append(palette, c)
+		}
+	}
+
+	p := image.NewPaletted(b, palette)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			p.SetColorIndex(x, y, index[c])
+		}
+	}
+	return p
+}
+
+// ReadPNGText returns the tEXt and uncompressed iTXt chunks of a PNG
+func ReadPNGText(data []byte) ([]PNGText, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, fmt.Errorf("not a PNG file")
+	}
+
+	var text []PNGText
+	for i := len(pngSignature); i+8 <= len(data); {
+		n := int(binary.BigEndian.Uint32(data[i : i+4]))
+		kind := string(data[i+4 : i+8])
+		if i+12+n > len(data) {
+			return nil, fmt.Errorf("truncated %s chunk", kind)
+		}
+		body := data[i+8 : i+8+n]
+		i += 12 + n
+
+		key, value, ok := bytes.Cut(body, []byte{0})
+		if !ok {
+			continue
+		}
+		switch kind {
+		case "tEXt":
+			text = append(text, PNGText{Key: string(key), Value: string(value)})
+		case "iTXt":
+			// compression flag, method, language tag\0, translated keyword\0
+			if len(value) < 2 || value[0] != 0 {
+				continue
+			}
+			rest := value[2:]
+			if _, rest, ok = bytes.Cut(rest, []byte{0}); !ok {
+				continue
+			}
+			if _, rest, ok = bytes.Cut(rest, []byte{0}); !ok || !utf8.Valid(rest) {
+				continue
+			}
+			text = append(text, PNGText{Key: string(key), Value: string(rest)})
+		case "IEND":
+			return text, nil
+		}
+	}
+	return text, nil
+}
@@ -0,0 +1,101 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"io"
+	"unicode/utf8"
+)
+
+// PNGText is a PNG text chunk. Keywords are 1-79 Latin-1 characters;
+// values that aren't plain ASCII are written as UTF-8 iTXt chunks.
+type PNGText struct {
+	Key   string
+	Value string
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// EncodePNG encodes img as PNG with text chunks placed after the header,
+// so the metadata survives even if the file is copied on its own
+func EncodePNG(w io.Writer, img image.Image, text []PNGText) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if err := defaultEncoder.Encode(buf, img); err != nil {
+		return err
+	}
+	return insertText(w, buf.Bytes(), text)
+}
+
+// insertText writes an encoded PNG with text chunks added after IHDR
+func insertText(w io.Writer, data []byte, text []PNGText) error {
+	if len(text) == 0 {
+		_, err := w.Write(data)
+		return err
+	}
+
+	// signature, then IHDR: length(4) type(4) data(13) crc(4)
+	ihdrEnd := len(pngSignature) + 8 + 13 + 4
+	if len(data) < ihdrEnd || !bytes.Equal(data[:len(pngSignature)], pngSignature) {
+		return fmt.Errorf("unexpected PNG encoder output")
+	}
+
+	if _, err := w.Write(data[:ihdrEnd]); err != nil {
+		return err
+	}
+	for _, t := range text {
+		if len(t.Key) == 0 || len(t.Key) > 79 {
+			return fmt.Errorf("invalid PNG text keyword %q", t.Key)
+		}
+		if err := writeTextChunk(w, t); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(data[ihdrEnd:])
+	return err
+}
+
+func writeTextChunk(w io.Writer, t PNGText) error {
+	var body bytes.Buffer
+	body.WriteString(t.Key)
+	body.WriteByte(0)
+
+	kind := "tEXt"
+	if !isASCII(t.Value) && utf8.ValidString(t.Value) {
+		// uncompressed, no language tag or translated keyword
+		kind = "iTXt"
+		body.Write([]byte{0, 0, 0, 0})
+	}
+	body.WriteString(t.Value)
+
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(body.Len()))
+	copy(header[4:], kind)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(body.Bytes())
+
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+
+	for _, b := range [][]byte{header[:], body.Bytes(), sum[:]} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package imaging
+
+import (
+	"bytes"
+	"image/png"
+	"sync"
+)
+
+// encoderPool lets png.Encoder reuse its zlib writer and row buffers
+// between frames instead of allocating them per Encode
+type encoderPool struct {
+	pool sync.Pool
+}
+
+func (p *encoderPool) Get() *png.EncoderBuffer {
+	b, _ := p.pool.Get().(*png.EncoderBuffer)
+	return b
+}
+
+func (p *encoderPool) Put(b *png.EncoderBuffer) {
+	p.pool.Put(b)
+}
+
+var (
+	defaultEncoder = png.Encoder{BufferPool: &encoderPool{}}
+	bestEncoder    = png.Encoder{CompressionLevel: png.BestCompression, BufferPool: &encoderPool{}}
+
+	bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+)
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	// don't keep unusually large buffers alive
+	if buf.Cap() <= 64<<20 {
+		bufferPool.Put(buf)
+	}
+}
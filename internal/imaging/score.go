@@ -0,0 +1,124 @@
+package imaging
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Relevance estimates how much a frame is worth showing
+type Relevance struct {
+	// Detail is the share of pixels on a sharp luminance edge, 0-1. Text,
+	// code and UI chrome score high; blank desktops, wallpapers and
+	// screensavers score near zero. There's no OCR, so edges stand in for
+	// text density.
+	Detail float64 `json:"detail"`
+
+	// Change is the share of the frame that differs from the previous one,
+	// 0-1. It's 1 without a previous frame.
+	Change float64 `json:"change"`
+}
+
+const (
+	// scoreWidth is the width frames are scored at; text strokes stay
+	// visible at this size while scoring stays cheap
+	scoreWidth = 640
+
+	// edgeThreshold is the luminance step, out of 255, that counts as an edge
+	edgeThreshold = 48
+
+	// changeCell is the size of the grid cells compared between frames,
+	// at scoring size
+	changeCell = 16
+
+	// changeThreshold is the mean luminance difference of a changed cell
+	changeThreshold = 4
+)
+
+// Luma is a downscaled grayscale copy of a frame for scoring
+type Luma struct {
+	w, h int
+	pix  []uint8
+}
+
+// NewLuma samples img down to scoring size
+func NewLuma(img image.Image) *Luma {
+	b := img.Bounds()
+	rgba, ok := img.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(b)
+		draw.Draw(rgba, b, img, b.Min, draw.Src)
+	}
+
+	step := max(1, b.Dx()/scoreWidth)
+	l := &Luma{w: b.Dx() / step, h: b.Dy() / step}
+	l.pix = make([]uint8, l.w*l.h)
+	for y := range l.h {
+		for x := range l.w {
+			i := rgba.PixOffset(b.Min.X+x*step, b.Min.Y+y*step)
+			p := rgba.Pix[i : i+3]
+			l.pix[y*l.w+x] = uint8((299*int(p[0]) + 587*int(p[1]) + 114*int(p[2])) / 1000)
+		}
+	}
+	return l
+}
+
+// Score rates a frame, comparing it with prev (nil for the first frame)
+func Score(cur, prev *Luma) Relevance {
+	return Relevance{Detail: cur.detail(), Change: cur.change(prev)}
+}
+
+// Change is the share of cur that differs from prev, without scoring
+// detail
+func Change(cur, prev *Luma) float64 {
+	return cur.change(prev)
+}
+
+// detail counts pixels with a sharp step to their right or lower neighbour
+func (l *Luma) detail() float64 {
+	if l.w < 2 || l.h < 2 {
+		return 0
+	}
+	edges := 0
+	for y := 0; y < l.h-1; y++ {
+		row := l.pix[y*l.w : (y+1)*l.w]
+		below := l.pix[(y+1)*l.w : (y+2)*l.w]
+		for x := 0; x < l.w-1; x++ {
+			if absDiff(row[x], row[x+1]) >= edgeThreshold || absDiff(row[x], below[x]) >= edgeThreshold {
+				edges++
+			}
+		}
+	}
+	return float64(edges) / float64((l.w-1)*(l.h-1))
+}
+
+// change is the share of grid cells whose mean luminance moved
+func (l *Luma) change(prev *Luma) float64 {
+	if prev == nil || prev.w != l.w || prev.h != l.h {
+		return 1
+	}
+
+	changed, cells := 0, 0
+	for top := 0; top < l.h; top += changeCell {
+		for left := 0; left < l.w; left += changeCell {
+			diff, n := 0, 0
+			for y := top; y < min(top+changeCell, l.h); y++ {
+				for x := left; x < min(left+changeCell, l.w); x++ {
+					diff += int(absDiff(l.pix[y*l.w+x], prev.pix[y*l.w+x]))
+					n++
+				}
+			}
+			cells++
+			if diff >= changeThreshold*n {
+				changed++
+			}
+		}
+	}
+	return float64(changed) / float64(cells)
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,22 @@
+package imaging
+
+import "image"
+
+// Tiles splits bounds into a grid of equal tiles no larger than maxSize on
+// either side, row by row. Bounds that already fit return a single tile.
+func Tiles(bounds image.Rectangle, maxSize int) (tiles []image.Rectangle, rows, cols int) {
+	w, h := bounds.Dx(), bounds.Dy()
+	if maxSize <= 0 || (w <= maxSize && h <= maxSize) {
+		return []image.Rectangle{bounds}, 1, 1
+	}
+
+	cols, rows = (w+maxSize-1)/maxSize, (h+maxSize-1)/maxSize
+	tw, th := (w+cols-1)/cols, (h+rows-1)/rows
+	for r := range rows {
+		for c := range cols {
+			tile := image.Rect(c*tw, r*th, (c+1)*tw, (r+1)*th).Add(bounds.Min)
+			tiles = append(tiles, tile.Intersect(bounds))
+		}
+	}
+	return tiles, rows, cols
+}
@@ -0,0 +1,62 @@
+// Package load measures how busy the machine is and how much of it this
+// process takes.
+package load
+
+import (
+	"errors"
+	"runtime"
+	"time"
+)
+
+// ErrUnsupported is returned where the system's CPU use can't be read
+var ErrUnsupported = errors.New("system load not supported on this platform")
+
+// Usage is CPU and memory use since the previous read
+type Usage struct {
+	System  int // percent of all CPUs busy
+	Self    int // percent of all CPUs used by this process
+	MemFree int // percent of physical memory available; -1 if unknown
+}
+
+// Meter measures CPU use between successive reads. The first read only
+// sets the baseline on platforms that count CPU time.
+type Meter struct {
+	at   time.Time
+	self time.Duration
+	cpu  cpuTimes
+}
+
+// cpuTimes is the system's cumulative busy and total CPU time
+type cpuTimes struct {
+	busy, total uint64
+}
+
+// Read returns usage since the previous read
+func (m *Meter) Read() (Usage, error) {
+	now := time.Now()
+	self, err := processTime()
+	if err != nil {
+		return Usage{}, err
+	}
+	system, cpu, err := systemCPU(m.cpu)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	u := Usage{System: system, MemFree: memFree()}
+	if !m.at.IsZero() {
+		if wall := now.Sub(m.at); wall > 0 {
+			u.Self = int(100 * float64(self-m.self) / float64(wall) / float64(runtime.NumCPU()))
+		}
+	}
+	m.at, m.self, m.cpu = now, self, cpu
+	return u, nil
+}
+
+// busyPercent is the share of CPU time between two readings spent busy
+func busyPercent(prev, cur cpuTimes) int {
+	if prev.total == 0 || cur.total <= prev.total || cur.busy < prev.busy {
+		return 0
+	}
+	return int(100 * (cur.busy - prev.busy) / (cur.total - prev.total))
+}
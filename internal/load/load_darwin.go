@@ -0,0 +1,32 @@
+package load
+
+import (
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// systemCPU approximates CPU use from the one-minute load average, since
+// per-CPU times need cgo on macOS
+func systemCPU(prev cpuTimes) (int, cpuTimes, error) {
+	out, err := exec.Command("sysctl", "-n", "vm.loadavg").Output()
+	if err != nil {
+		return 0, prev, err
+	}
+	// "{ 1.82 1.67 1.61 }"
+	fields := strings.Fields(strings.Trim(strings.TrimSpace(string(out)), "{}"))
+	if len(fields) == 0 {
+		return 0, prev, ErrUnsupported
+	}
+	avg, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, prev, err
+	}
+	return min(100, int(100*avg/float64(runtime.NumCPU()))), prev, nil
+}
+
+// memFree isn't read on macOS, whose memory pressure isn't a free figure
+func memFree() int {
+	return -1
+}
@@ -0,0 +1,68 @@
+package load
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// systemCPU reads the aggregate cpu line of /proc/stat
+func systemCPU(prev cpuTimes) (int, cpuTimes, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, cpuTimes{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return 0, cpuTimes{}, fmt.Errorf("/proc/stat is empty")
+	}
+	fields := strings.Fields(scanner.Text())
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, cpuTimes{}, fmt.Errorf("unexpected /proc/stat line %q", scanner.Text())
+	}
+
+	var cur cpuTimes
+	for i, field := range fields[1:] {
+		// Guest time is already counted in user and nice
+		if i >= 8 {
+			break
+		}
+		n, _ := strconv.ParseUint(field, 10, 64)
+		cur.total += n
+		// idle and iowait
+		if i != 3 && i != 4 {
+			cur.busy += n
+		}
+	}
+	return busyPercent(prev, cur), cur, nil
+}
+
+// memFree reads MemAvailable from /proc/meminfo
+func memFree() int {
+	data, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return -1
+	}
+	var total, available uint64
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		n, _ := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+		switch key {
+		case "MemTotal":
+			total = n
+		case "MemAvailable":
+			available = n
+		}
+	}
+	if total == 0 || available == 0 {
+		return -1
+	}
+	return int(100 * available / total)
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !windows
+
+package load
+
+func systemCPU(prev cpuTimes) (int, cpuTimes, error) {
+	return 0, prev, ErrUnsupported
+}
+
+func memFree() int {
+	return -1
+}
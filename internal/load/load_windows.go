@@ -0,0 +1,59 @@
+package load
+
+import (
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32                 = windows.NewLazySystemDLL("kernel32.dll")
+	procGetSystemTimes       = kernel32.NewProc("GetSystemTimes")
+	procGlobalMemoryStatusEx = kernel32.NewProc("GlobalMemoryStatusEx")
+)
+
+// memoryStatusEx is MEMORYSTATUSEX
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+func filetime(ft windows.Filetime) uint64 {
+	return uint64(ft.HighDateTime)<<32 | uint64(ft.LowDateTime)
+}
+
+// systemCPU reads GetSystemTimes, whose kernel time includes idle time
+func systemCPU(prev cpuTimes) (int, cpuTimes, error) {
+	var idle, kernel, user windows.Filetime
+	if r, _, err := procGetSystemTimes.Call(uintptr(unsafe.Pointer(&idle)), uintptr(unsafe.Pointer(&kernel)), uintptr(unsafe.Pointer(&user))); r == 0 {
+		return 0, prev, err
+	}
+	total := filetime(kernel) + filetime(user)
+	cur := cpuTimes{busy: total - filetime(idle), total: total}
+	return busyPercent(prev, cur), cur, nil
+}
+
+func memFree() int {
+	s := memoryStatusEx{Length: uint32(unsafe.Sizeof(memoryStatusEx{}))}
+	if r, _, _ := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&s))); r == 0 || s.TotalPhys == 0 {
+		return -1
+	}
+	return int(100 * s.AvailPhys / s.TotalPhys)
+}
+
+func processTime() (time.Duration, error) {
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(windows.CurrentProcess(), &creation, &exit, &kernel, &user); err != nil {
+		return 0, err
+	}
+	// Filetimes count 100ns intervals
+	return time.Duration(filetime(kernel)+filetime(user)) * 100, nil
+}
@@ -0,0 +1,33 @@
+// Package paths locates the files task-tracker and monitor-helper share,
+// so both find them whichever directory they're started from.
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config returns the location of the config file.
+// TASK_TRACKER_CONFIG overrides the platform default.
+func Config() (string, error) {
+	if p := os.Getenv("TASK_TRACKER_CONFIG"); p != "" {
+		return p, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "task-tracker", "config.json"), nil
+}
+
+// Presets returns the location of the monitor presets, next to the config
+// file
+func Presets() (string, error) {
+	config, err := Config()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(config), "monitor_presets.json"), nil
+}
@@ -0,0 +1,294 @@
+// Package pdf writes simple flowing documents of headings, wrapped text
+// and images as PDF, without external tools. Text is set in the standard
+// Helvetica fonts, which every reader has but which only cover Latin-1 and
+// a few typographic marks; other characters print as '?'.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"strings"
+)
+
+// A4 page size and margins, in points
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 50.0
+)
+
+// Text styles: font resource name, size and line height
+type style struct {
+	font   string
+	size   float64
+	leader float64
+}
+
+var (
+	headingStyle    = style{"F2", 18, 24}
+	subheadingStyle = style{"F2", 13, 18}
+	textStyle       = style{"F1", 10, 14}
+)
+
+// jpegQuality trades image fidelity for document size
+const jpegQuality = 85
+
+// pdfImage is an embedded JPEG
+type pdfImage struct {
+	data          []byte
+	width, height int
+	colorSpace    string
+}
+
+// Document is a PDF being laid out top to bottom, breaking pages as needed
+type Document struct {
+	title  string
+	pages  []*bytes.Buffer
+	images []pdfImage
+	y      float64 // top of the space left on the current page
+}
+
+// New starts a document; title goes in its properties
+func New(title string) *Document {
+	d := &Document{title: title}
+	d.newPage()
+	return d
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+// page is the current page's content stream
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// reserve starts a new page unless height points fit on this one
+func (d *Document) reserve(height float64) {
+	if d.y-height < margin && d.y < pageHeight-margin {
+		d.newPage()
+	}
+}
+
+// Space adds vertical space
+func (d *Document) Space(points float64) {
+	d.y -= points
+}
+
+// Heading adds a large bold line
+func (d *Document) Heading(text string) {
+	d.Space(4)
+	d.write(headingStyle, text)
+	d.Space(6)
+}
+
+// Subheading adds a bold line
+func (d *Document) Subheading(text string) {
+	d.Space(8)
+	d.write(subheadingStyle, text)
+	d.Space(2)
+}
+
+// Text adds a paragraph, wrapped to the page width. Newlines start new
+// lines.
+func (d *Document) Text(text string) {
+	d.write(textStyle, text)
+}
+
+func (d *Document) write(s style, text string) {
+	for _, para := range strings.Split(text, "\n") {
+		for _, line := range wrap(para, s, pageWidth-2*margin) {
+			d.reserve(s.leader)
+			d.y -= s.leader
+			fmt.Fprintf(d.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", s.font, s.size, margin, d.y+s.leader-s.size, encode(line))
+		}
+	}
+}
+
+// Image adds img scaled to the page width, and to at most maxHeight of
+// the page (0-1, where 0 means 0.6)
+func (d *Document) Image(img image.Image, maxHeight float64) error {
+	if maxHeight <= 0 || maxHeight > 1 {
+		maxHeight = 0.6
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	// The JPEG encoder writes gray images with one component
+	colorSpace := "DeviceRGB"
+	if _, ok := img.(*image.Gray); ok {
+		colorSpace = "DeviceGray"
+	}
+	b := img.Bounds()
+	d.images = append(d.images, pdfImage{data: buf.Bytes(), width: b.Dx(), height: b.Dy(), colorSpace: colorSpace})
+
+	w := pageWidth - 2*margin
+	h := w * float64(b.Dy()) / float64(b.Dx())
+	if limit := (pageHeight - 2*margin) * maxHeight; h > limit {
+		w, h = w*limit/h, limit
+	}
+	d.reserve(h)
+	d.y -= h
+	fmt.Fprintf(d.page(), "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", w, h, margin, d.y, len(d.images))
+	d.Space(6)
+	return nil
+}
+
+// Write writes the document
+func (d *Document) Write(w io.Writer) error {
+	var out bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	stream := func(dict string, data []byte) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n<< %s /Length %d >>\nstream\n", len(offsets), dict, len(data))
+		out.Write(data)
+		out.WriteString("\nendstream\nendobj\n")
+	}
+
+	// Objects 1-6 are fixed; images follow, then each page and its content
+	const (
+		catalogObj = 1
+		pagesObj   = 2
+		resObj     = 5
+		infoObj    = 6
+	)
+	firstImage := infoObj + 1
+	firstPage := firstImage + len(d.images)
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	obj(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj))
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	var xobjects strings.Builder
+	for i := range d.images {
+		fmt.Fprintf(&xobjects, " /Im%d %d 0 R", i+1, firstImage+i)
+	}
+	obj(fmt.Sprintf("<< /Font << /F1 3 0 R /F2 4 0 R >> /XObject <<%s >> >>", xobjects.String()))
+	obj(fmt.Sprintf("<< /Title (%s) /Producer (task-tracker) >>", encode(d.title)))
+
+	for _, img := range d.images {
+		stream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /DCTDecode",
+			img.width, img.height, img.colorSpace), img.data)
+	}
+	for i, content := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources %d 0 R /Contents %d 0 R >>",
+			pagesObj, pageWidth, pageHeight, resObj, firstPage+2*i+1))
+		stream("", content.Bytes())
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, catalogObj, infoObj, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// winAnsi maps the typographic characters WinAnsiEncoding has outside
+// Latin-1
+var winAnsi = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// encode converts text to a WinAnsi string literal body
+func encode(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r == '\t':
+			b.WriteByte(' ')
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		case winAnsi[r] != 0:
+			b.WriteByte(winAnsi[r])
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// helveticaWidths are the glyph widths of ASCII 32-126 in Helvetica, in
+// thousandths of the font size
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth estimates the width of text in points. Bold glyphs are a bit
+// wider, so they're padded rather than measured.
+func textWidth(text string, s style) float64 {
+	units := 0
+	for _, r := range text {
+		if r >= 32 && r <= 126 {
+			units += helveticaWidths[r-32]
+		} else {
+			units += 556
+		}
+	}
+	w := float64(units) * s.size / 1000
+	if s.font == "F2" {
+		w *= 1.1
+	}
+	return w
+}
+
+// wrap breaks text into lines no wider than width, splitting words that
+// don't fit on a line of their own
+func wrap(text string, s style, width float64) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	var lines []string
+	line := ""
+	for _, word := range words {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if textWidth(candidate, s) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		line = ""
+		for _, r := range word {
+			if line != "" && textWidth(line+string(r), s) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			line += string(r)
+		}
+	}
+	return append(lines, line)
+}
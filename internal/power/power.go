@@ -0,0 +1,19 @@
+// Package power reports whether the machine runs on battery and how much
+// charge is left.
+package power
+
+import "errors"
+
+// ErrNoBattery is returned on machines without a system battery
+var ErrNoBattery = errors.New("no battery")
+
+// Status is the power source and charge of the system battery
+type Status struct {
+	OnBattery bool
+	Percent   int // charge, 0-100; -1 if unknown
+}
+
+// Battery returns the current power status
+func Battery() (Status, error) {
+	return battery()
+}
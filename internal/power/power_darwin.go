@@ -0,0 +1,26 @@
+package power
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var pmsetPercent = regexp.MustCompile(`(\d+)%`)
+
+func battery() (Status, error) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return Status{}, err
+	}
+	text := string(out)
+	if !strings.Contains(text, "InternalBattery") {
+		return Status{}, ErrNoBattery
+	}
+	st := Status{Percent: -1, OnBattery: strings.Contains(text, "'Battery Power'")}
+	if m := pmsetPercent.FindStringSubmatch(text); m != nil {
+		st.Percent, _ = strconv.Atoi(m[1])
+	}
+	return st, nil
+}
@@ -0,0 +1,51 @@
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const supplyDir = "/sys/class/power_supply"
+
+func battery() (Status, error) {
+	supplies, err := os.ReadDir(supplyDir)
+	if err != nil {
+		return Status{}, ErrNoBattery
+	}
+
+	read := func(supply, name string) string {
+		data, _ := os.ReadFile(filepath.Join(supplyDir, supply, name))
+		return strings.TrimSpace(string(data))
+	}
+	st := Status{Percent: -1}
+	batteries, total := 0, 0
+	mains, discharging := false, false
+	for _, s := range supplies {
+		name := s.Name()
+		// Mice and headsets report batteries too
+		if read(name, "scope") == "Device" {
+			continue
+		}
+		switch read(name, "type") {
+		case "Mains", "USB":
+			mains = mains || read(name, "online") == "1"
+		case "Battery":
+			if read(name, "present") == "0" {
+				continue
+			}
+			if pct, err := strconv.Atoi(read(name, "capacity")); err == nil {
+				batteries++
+				total += pct
+			}
+			discharging = discharging || read(name, "status") == "Discharging"
+		}
+	}
+	if batteries == 0 {
+		return Status{}, ErrNoBattery
+	}
+	st.Percent = total / batteries
+	st.OnBattery = discharging && !mains
+	return st, nil
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !windows
+
+package power
+
+func battery() (Status, error) {
+	return Status{}, ErrNoBattery
+}
@@ -0,0 +1,39 @@
+package power
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procGetSystemPowerStatus = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetSystemPowerStatus")
+
+// systemPowerStatus is SYSTEM_POWER_STATUS
+type systemPowerStatus struct {
+	ACLineStatus        uint8
+	BatteryFlag         uint8
+	BatteryLifePercent  uint8
+	SystemStatusFlag    uint8
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+const (
+	batteryFlagNone = 128 // no system battery
+	percentUnknown  = 255
+)
+
+func battery() (Status, error) {
+	var s systemPowerStatus
+	if r, _, err := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&s))); r == 0 {
+		return Status{}, err
+	}
+	if s.BatteryFlag&batteryFlagNone != 0 {
+		return Status{}, ErrNoBattery
+	}
+	st := Status{OnBattery: s.ACLineStatus == 0, Percent: -1}
+	if s.BatteryLifePercent != percentUnknown {
+		st.Percent = int(s.BatteryLifePercent)
+	}
+	return st, nil
+}
@@ -0,0 +1,269 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// exporter posts OTLP/HTTP JSON payloads to a collector
+type exporter struct {
+	tracesURL  string
+	metricsURL string
+	headers    map[string]string
+	userAgent  string
+	attrs      map[string]string // resource attributes
+	version    string
+}
+
+// exportClient isn't instrumented, so exports don't trace themselves
+var exportClient = &http.Client{Timeout: 10 * time.Second}
+
+// OTLP JSON types, with 64-bit integers as strings per the protobuf JSON
+// mapping
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+func toString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func encodeAttr(a Attr) keyValue {
+	kv := keyValue{Key: a.Key}
+	switch v := a.Value.(type) {
+	case string:
+		kv.Value.StringValue = &v
+	case bool:
+		kv.Value.BoolValue = &v
+	case int:
+		s := strconv.Itoa(v)
+		kv.Value.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		kv.Value.IntValue = &s
+	case float64:
+		kv.Value.DoubleValue = &v
+	default:
+		s := toString(v)
+		kv.Value.StringValue = &s
+	}
+	return kv
+}
+
+func encodeAttrs(attrs []Attr) []keyValue {
+	out := make([]keyValue, len(attrs))
+	for i, a := range attrs {
+		out[i] = encodeAttr(a)
+	}
+	return out
+}
+
+func (e *exporter) resource() resource {
+	keys := make([]string, 0, len(e.attrs))
+	for k := range e.attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]Attr, len(keys))
+	for i, k := range keys {
+		attrs[i] = A(k, e.attrs[k])
+	}
+	return resource{Attributes: encodeAttrs(attrs)}
+}
+
+func nanos(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+type status struct {
+	Code    int    `json:"code"` // 1 ok, 2 error
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID      string     `json:"traceId"`
+	SpanID       string     `json:"spanId"`
+	ParentSpanID string     `json:"parentSpanId,omitempty"`
+	Name         string     `json:"name"`
+	Kind         int        `json:"kind"`
+	Start        string     `json:"startTimeUnixNano"`
+	End          string     `json:"endTimeUnixNano"`
+	Attributes   []keyValue `json:"attributes,omitempty"`
+	Status       status     `json:"status"`
+}
+
+func (e *exporter) exportSpans(batch []spanData) error {
+	if e.tracesURL == "" || len(batch) == 0 {
+		return nil
+	}
+	out := make([]otlpSpan, len(batch))
+	for i, s := range batch {
+		out[i] = otlpSpan{
+			TraceID:      traceID,
+			SpanID:       s.spanID,
+			ParentSpanID: s.parentID,
+			Name:         s.name,
+			Kind:         s.kind,
+			Start:        nanos(s.start),
+			End:          nanos(s.end),
+			Attributes:   encodeAttrs(s.attrs),
+			Status:       status{Code: 1},
+		}
+		if s.failed {
+			out[i].Status = status{Code: 2, Message: s.errMsg}
+		}
+	}
+
+	payload := map[string]any{
+		"resourceSpans": []any{map[string]any{
+			"resource": e.resource(),
+			"scopeSpans": []any{map[string]any{
+				"scope": scope{Name: "task-tracker", Version: e.version},
+				"spans": out,
+			}},
+		}},
+	}
+	return e.post(e.tracesURL, payload)
+}
+
+// Cumulative aggregation temporality
+const cumulative = 2
+
+type numberPoint struct {
+	Attributes []keyValue `json:"attributes,omitempty"`
+	Start      string     `json:"startTimeUnixNano"`
+	Time       string     `json:"timeUnixNano"`
+	AsInt      string     `json:"asInt"`
+}
+
+type histogramPoint struct {
+	Attributes     []keyValue `json:"attributes,omitempty"`
+	Start          string     `json:"startTimeUnixNano"`
+	Time           string     `json:"timeUnixNano"`
+	Count          string     `json:"count"`
+	Sum            float64    `json:"sum"`
+	BucketCounts   []string   `json:"bucketCounts"`
+	ExplicitBounds []float64  `json:"explicitBounds"`
+}
+
+func (e *exporter) exportMetrics(snap metricSnapshot) error {
+	if e.metricsURL == "" || (len(snap.counters) == 0 && len(snap.histos) == 0) {
+		return nil
+	}
+
+	// Series of the same metric share one entry
+	metrics := []map[string]any{}
+	index := map[string]int{}
+	points := map[string][]any{}
+	add := func(name string, entry map[string]any, point any) {
+		if _, ok := index[name]; !ok {
+			index[name] = len(metrics)
+			metrics = append(metrics, entry)
+		}
+		points[name] = append(points[name], point)
+	}
+
+	for _, c := range snap.counters {
+		add(c.name, map[string]any{"name": c.name, "unit": c.unit}, numberPoint{
+			Attributes: encodeAttrs(c.attrs),
+			Start:      nanos(snap.start),
+			Time:       nanos(snap.at),
+			AsInt:      strconv.FormatInt(c.value, 10),
+		})
+	}
+	for _, h := range snap.histos {
+		buckets := make([]string, len(h.counts))
+		for i, n := range h.counts {
+			buckets[i] = strconv.FormatUint(n, 10)
+		}
+		add(h.name, map[string]any{"name": h.name, "unit": "s"}, histogramPoint{
+			Attributes:     encodeAttrs(h.attrs),
+			Start:          nanos(snap.start),
+			Time:           nanos(snap.at),
+			Count:          strconv.FormatUint(h.count, 10),
+			Sum:            h.sum,
+			BucketCounts:   buckets,
+			ExplicitBounds: durationBounds,
+		})
+	}
+	for _, c := range snap.counters {
+		metrics[index[c.name]]["sum"] = map[string]any{
+			"aggregationTemporality": cumulative,
+			"isMonotonic":            true,
+			"dataPoints":             points[c.name],
+		}
+	}
+	for _, h := range snap.histos {
+		metrics[index[h.name]]["histogram"] = map[string]any{
+			"aggregationTemporality": cumulative,
+			"dataPoints":             points[h.name],
+		}
+	}
+
+	payload := map[string]any{
+		"resourceMetrics": []any{map[string]any{
+			"resource": e.resource(),
+			"scopeMetrics": []any{map[string]any{
+				"scope":   scope{Name: "task-tracker", Version: e.version},
+				"metrics": metrics,
+			}},
+		}},
+	}
+	return e.post(e.metricsURL, payload)
+}
+
+func (e *exporter) post(url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", e.userAgent)
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := exportClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s: %s", url, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
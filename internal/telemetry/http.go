@@ -0,0 +1,43 @@
+package telemetry
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// transport traces requests made through it
+type transport struct {
+	base http.RoundTripper
+}
+
+// Transport wraps base (http.DefaultTransport if nil) so each request is
+// recorded as an upload span and counted by host and status
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return transport{base: base}
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	span := StartClient("upload "+req.URL.Host,
+		A("http.request.method", req.Method),
+		A("server.address", req.URL.Host),
+		A("url.path", req.URL.Path))
+
+	resp, err := t.base.RoundTrip(req)
+	failure, code := err, 0
+	if resp != nil {
+		code = resp.StatusCode
+		span.Set("http.response.status_code", code)
+		if code >= 400 {
+			failure = fmt.Errorf("%s", resp.Status)
+		}
+	}
+	d := span.End(failure)
+
+	attrs := []Attr{A("server.address", req.URL.Host), A("http.response.status_code", code)}
+	Add("task_tracker.uploads", "1", 1, attrs...)
+	Observe("task_tracker.upload.duration", d, A("server.address", req.URL.Host))
+	return resp, err
+}
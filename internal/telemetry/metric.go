@@ -0,0 +1,94 @@
+package telemetry
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// durationBounds are the histogram buckets for durations, in seconds
+var durationBounds = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type counter struct {
+	name  string
+	unit  string
+	attrs []Attr
+	value int64
+}
+
+type histogram struct {
+	name   string
+	attrs  []Attr
+	count  uint64
+	sum    float64
+	counts []uint64
+}
+
+// seriesKey identifies a metric with one set of attribute values
+func seriesKey(name string, attrs []Attr) string {
+	parts := make([]string, len(attrs))
+	for i, a := range attrs {
+		parts[i] = a.Key + "=" + toString(a.Value)
+	}
+	sort.Strings(parts)
+	return name + "{" + strings.Join(parts, ",") + "}"
+}
+
+// Add increases a counter. unit follows UCUM, e.g. "1" or "By".
+func Add(name, unit string, n int64, attrs ...Attr) {
+	mu.Lock()
+	defer mu.Unlock()
+	if exp == nil {
+		return
+	}
+	key := seriesKey(name, attrs)
+	c := counters[key]
+	if c == nil {
+		c = &counter{name: name, unit: unit, attrs: attrs}
+		counters[key] = c
+	}
+	c.value += n
+}
+
+// Observe records a duration in a histogram, in seconds
+func Observe(name string, d time.Duration, attrs ...Attr) {
+	mu.Lock()
+	defer mu.Unlock()
+	if exp == nil {
+		return
+	}
+	key := seriesKey(name, attrs)
+	h := histos[key]
+	if h == nil {
+		h = &histogram{name: name, attrs: attrs, counts: make([]uint64, len(durationBounds)+1)}
+		histos[key] = h
+	}
+	s := d.Seconds()
+	h.count++
+	h.sum += s
+	h.counts[sort.SearchFloat64s(durationBounds, s)]++
+}
+
+// metricSnapshot is every series' cumulative value at one time
+type metricSnapshot struct {
+	at       time.Time
+	start    time.Time
+	counters []counter
+	histos   []histogram
+}
+
+// snapshotMetrics copies the metrics for export. Caller must hold mu.
+func snapshotMetrics(at time.Time) metricSnapshot {
+	snap := metricSnapshot{at: at, start: started}
+	for _, c := range counters {
+		snap.counters = append(snap.counters, *c)
+	}
+	for _, h := range histos {
+		cp := *h
+		cp.counts = append([]uint64(nil), h.counts...)
+		snap.histos = append(snap.histos, cp)
+	}
+	sort.Slice(snap.counters, func(i, j int) bool { return snap.counters[i].name < snap.counters[j].name })
+	sort.Slice(snap.histos, func(i, j int) bool { return snap.histos[i].name < snap.histos[j].name })
+	return snap
+}
@@ -0,0 +1,216 @@
+// Package telemetry exports traces and metrics over OTLP/HTTP with JSON
+// encoding, so fleets can watch task-tracker in an existing OpenTelemetry
+// collector or Grafana stack. It's configured with the standard OTEL_*
+// environment variables and does nothing unless an endpoint is set.
+package telemetry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// flushEvery is how often buffered spans and metrics are exported
+const flushEvery = 10 * time.Second
+
+// maxSpans bounds the spans buffered between exports; more are dropped
+const maxSpans = 2048
+
+// Attr is a span or metric attribute
+type Attr struct {
+	Key   string
+	Value any // string, bool, int, int64 or float64
+}
+
+// A returns an attribute
+func A(key string, value any) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+var (
+	mu       sync.Mutex
+	exp      *exporter // nil when disabled
+	traceID  string    // one trace per process run
+	started  time.Time
+	spans    []spanData
+	counters = map[string]*counter{}
+	histos   = map[string]*histogram{}
+	stop     chan struct{}
+	stopped  chan struct{}
+)
+
+// OnError is called with errors from periodic exports. Each distinct
+// error is reported once in a row.
+var OnError func(error)
+
+// Enabled reports whether telemetry is being exported
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return exp != nil
+}
+
+// Init reads the OTEL_* environment and starts exporting if an OTLP
+// endpoint is configured. version and userAgent describe the caller.
+func Init(version, userAgent string) error {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return nil
+	}
+	base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	tracesURL := signalURL(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"), base, "traces")
+	metricsURL := signalURL(os.Getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT"), base, "metrics")
+	if tracesURL == "" && metricsURL == "" {
+		return nil
+	}
+	if p := os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"); p != "" && p != "http/json" {
+		return fmt.Errorf("OTEL_EXPORTER_OTLP_PROTOCOL %s isn't supported (use http/json)", p)
+	}
+
+	headers, err := parseList(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	if err != nil {
+		return fmt.Errorf("OTEL_EXPORTER_OTLP_HEADERS: %w", err)
+	}
+	resource, err := parseList(os.Getenv("OTEL_RESOURCE_ATTRIBUTES"))
+	if err != nil {
+		return fmt.Errorf("OTEL_RESOURCE_ATTRIBUTES: %w", err)
+	}
+	service := os.Getenv("OTEL_SERVICE_NAME")
+	if service == "" {
+		service = "task-tracker"
+	}
+	resource["service.name"] = service
+	resource["service.version"] = version
+	if host, err := os.Hostname(); err == nil {
+		resource["host.name"] = host
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	exp = &exporter{
+		tracesURL:  tracesURL,
+		metricsURL: metricsURL,
+		headers:    headers,
+		userAgent:  userAgent,
+		attrs:      resource,
+		version:    version,
+	}
+	traceID = randomID(16)
+	started = time.Now()
+	stop, stopped = make(chan struct{}), make(chan struct{})
+	go flushLoop()
+	return nil
+}
+
+// signalURL picks a signal's endpoint: the signal-specific one as given,
+// or the base endpoint with /v1/<signal> appended
+func signalURL(specific, base, signal string) string {
+	if specific != "" {
+		return specific
+	}
+	if base == "" {
+		return ""
+	}
+	return strings.TrimSuffix(base, "/") + "/v1/" + signal
+}
+
+// parseList reads the key1=value1,key2=value2 form used by OTEL_* lists;
+// values are URL-encoded
+func parseList(s string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, item := range strings.Split(s, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("'%s' isn't key=value", item)
+		}
+		value, err := url.QueryUnescape(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("'%s': %w", item, err)
+		}
+		out[strings.TrimSpace(k)] = value
+	}
+	return out, nil
+}
+
+func randomID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// flushLoop exports on a timer until Shutdown
+func flushLoop() {
+	defer close(stopped)
+	lastErr := ""
+	ticker := time.NewTicker(flushEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			err := Flush()
+			if err != nil && OnError != nil && err.Error() != lastErr {
+				OnError(err)
+			}
+			lastErr = ""
+			if err != nil {
+				lastErr = err.Error()
+			}
+		}
+	}
+}
+
+// Flush exports buffered spans and the current metric values. Export
+// errors are returned but the data is dropped either way, so a dead
+// collector can't make memory grow.
+func Flush() error {
+	mu.Lock()
+	e := exp
+	batch := spans
+	spans = nil
+	metrics := snapshotMetrics(time.Now())
+	mu.Unlock()
+
+	if e == nil {
+		return nil
+	}
+	var errs []string
+	if err := e.exportSpans(batch); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := e.exportMetrics(metrics); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("telemetry export failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Shutdown stops the export timer and flushes what's left
+func Shutdown() error {
+	mu.Lock()
+	running := exp != nil && stop != nil
+	if running {
+		close(stop)
+	}
+	mu.Unlock()
+	if !running {
+		return nil
+	}
+	<-stopped
+
+	err := Flush()
+	mu.Lock()
+	exp, stop = nil, nil
+	mu.Unlock()
+	return err
+}
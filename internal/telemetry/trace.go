@@ -0,0 +1,91 @@
+package telemetry
+
+import (
+	"time"
+)
+
+// Span kinds from the OTLP protocol
+const (
+	kindInternal = 1
+	kindClient   = 3
+)
+
+// Span times one operation. Spans from a disabled package are no-ops, so
+// callers don't need to check Enabled.
+type Span struct {
+	data *spanData // nil when disabled
+}
+
+type spanData struct {
+	name     string
+	kind     int
+	spanID   string
+	parentID string
+	start    time.Time
+	end      time.Time
+	attrs    []Attr
+	errMsg   string
+	failed   bool
+}
+
+// Start begins a span
+func Start(name string, attrs ...Attr) *Span {
+	return start(name, kindInternal, "", attrs)
+}
+
+// StartClient begins a span for a request to another service
+func StartClient(name string, attrs ...Attr) *Span {
+	return start(name, kindClient, "", attrs)
+}
+
+func start(name string, kind int, parent string, attrs []Attr) *Span {
+	if !Enabled() {
+		return &Span{}
+	}
+	return &Span{data: &spanData{
+		name:     name,
+		kind:     kind,
+		spanID:   randomID(8),
+		parentID: parent,
+		start:    time.Now(),
+		attrs:    attrs,
+	}}
+}
+
+// Child begins a span nested in s
+func (s *Span) Child(name string, attrs ...Attr) *Span {
+	if s.data == nil {
+		return &Span{}
+	}
+	return start(name, kindInternal, s.data.spanID, attrs)
+}
+
+// Set adds an attribute
+func (s *Span) Set(key string, value any) {
+	if s.data != nil {
+		s.data.attrs = append(s.data.attrs, A(key, value))
+	}
+}
+
+// End finishes the span, marking it failed if err isn't nil, and returns
+// its duration
+func (s *Span) End(err error) time.Duration {
+	if s.data == nil {
+		return 0
+	}
+	s.data.end = time.Now()
+	if err != nil {
+		s.data.failed = true
+		s.data.errMsg = err.Error()
+	}
+
+	mu.Lock()
+	if len(spans) < maxSpans {
+		spans = append(spans, *s.data)
+	}
+	mu.Unlock()
+
+	d := s.data.end.Sub(s.data.start)
+	s.data = nil // ending twice records once
+	return d
+}
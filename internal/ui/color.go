@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ANSI escape sequences
+const (
+	reset  = "\033[0m"
+	bold   = "\033[1m"
+	dim    = "\033[2m"
+	red    = "\033[31m"
+	green  = "\033[32m"
+	yellow = "\033[33m"
+	cyan   = "\033[36m"
+)
+
+var ansiPattern = regexp.MustCompile("\033\\[[0-9;]*m")
+
+func paint(code, s string) string {
+	if !Color() {
+		return s
+	}
+	return code + s + reset
+}
+
+// Bold highlights s
+func Bold(s string) string { return paint(bold, s) }
+
+// Dim de-emphasizes s
+func Dim(s string) string { return paint(dim, s) }
+
+// Green colors s green
+func Green(s string) string { return paint(green, s) }
+
+// Yellow colors s yellow
+func Yellow(s string) string { return paint(yellow, s) }
+
+// Red colors s red
+func Red(s string) string { return paint(red, s) }
+
+// Cyan colors s cyan
+func Cyan(s string) string { return paint(cyan, s) }
+
+// visibleLen is the display width of s ignoring ANSI codes
+func visibleLen(s string) int {
+	n := 0
+	for _, r := range ansiPattern.ReplaceAllString(s, "") {
+		if isEmoji(r) && r != 0xFE0F && r != 0x200D {
+			n += 2
+		} else if r != 0xFE0F && r != 0x200D {
+			n++
+		}
+	}
+	return n
+}
+
+// Status prefixes that get colored automatically
+var statusColors = []struct {
+	prefix string
+	code   string
+}{
+	{"❌", red},
+	{"⚠️", yellow},
+	{"✅", green},
+	{"💡", cyan},
+}
+
+// highlight colors status lines by their leading emoji. Caller must hold mu.
+func highlight(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		for _, sc := range statusColors {
+			if strings.HasPrefix(trimmed, sc.prefix) && !strings.Contains(line, "\033[") {
+				lines[i] = sc.code + line + reset
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Inline image protocols for Preview
+const (
+	ProtocolKitty = "kitty"
+	ProtocolSixel = "sixel"
+	ProtocolASCII = "ascii"
+)
+
+// Protocols lists the accepted preview protocols
+var Protocols = []string{ProtocolKitty, ProtocolSixel, ProtocolASCII}
+
+// DetectProtocol guesses the best inline image protocol the terminal
+// supports from its environment. Terminals don't reliably announce sixel
+// support, so only ones known to have it are trusted; everything else,
+// including plain output, gets ASCII.
+func DetectProtocol() string {
+	if Plain() {
+		return ProtocolASCII
+	}
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty" || program == "ghostty":
+		return ProtocolKitty
+	case program == "WezTerm" || strings.HasPrefix(term, "foot") || term == "mlterm" || strings.Contains(term, "sixel"):
+		return ProtocolSixel
+	}
+	return ProtocolASCII
+}
+
+// Preview renders img for inline display at most cols terminal cells wide
+// and rows cells high, using the given protocol. The result ends with a
+// newline.
+func Preview(img image.Image, cols, rows int, protocol string) (string, error) {
+	switch protocol {
+	case ProtocolKitty:
+		return kitty(img, cols, rows)
+	case ProtocolSixel:
+		return sixel(img, cols, rows), nil
+	case ProtocolASCII:
+		return ascii(img, cols, rows), nil
+	}
+	return "", fmt.Errorf("unknown preview protocol '%s' (use %s)", protocol, strings.Join(Protocols, ", "))
+}
+
+// fit scales img to fit within w x h pixels, keeping its aspect ratio.
+// aspect is the height of a pixel relative to its width, for character
+// cells that aren't square.
+func fit(img image.Image, w, h int, aspect float64) *image.RGBA {
+	b := img.Bounds()
+	scale := min(float64(w)/float64(b.Dx()), float64(h)*aspect/float64(b.Dy()))
+	dw := max(1, int(float64(b.Dx())*scale))
+	dh := max(1, int(float64(b.Dy())*scale/aspect))
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	xdraw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, b, xdraw.Src, nil)
+	return dst
+}
+
+// Cell size in pixels assumed when sizing pixel images to a number of cells
+const (
+	cellWidth  = 10
+	cellHeight = 20
+)
+
+// kitty sends a PNG with the kitty graphics protocol, letting the terminal
+// scale it into the cells
+func kitty(img image.Image, cols, rows int) (string, error) {
+	small := fit(img, cols*cellWidth, rows*cellHeight, 1)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, small); err != nil {
+		return "", err
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	// Payloads are sent in chunks of at most 4096 bytes
+	var b strings.Builder
+	for first := true; len(data) > 0; first = false {
+		chunk := data[:min(4096, len(data))]
+		data = data[len(chunk):]
+		more := 0
+		if len(data) > 0 {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(&b, "\033_Ga=T,f=100,q=2,c=%d,m=%d;%s\033\\", small.Bounds().Dx()/cellWidth, more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\033_Gm=%d;%s\033\\", more, chunk)
+		}
+	}
+	b.WriteString("\n")
+	return b.String(), nil
+}
+
+// sixel encodes img as DEC sixel graphics using a 6x6x6 color cube
+func sixel(img image.Image, cols, rows int) string {
+	small := fit(img, cols*cellWidth, rows*cellHeight, 1)
+	w, h := small.Bounds().Dx(), small.Bounds().Dy()
+
+	level := func(v uint8) int { return (int(v)*5 + 127) / 255 }
+	index := make([]int, w*h)
+	for y := range h {
+		for x := range w {
+			i := small.PixOffset(x, y)
+			p := small.Pix[i : i+3]
+			index[y*w+x] = level(p[0])*36 + level(p[1])*6 + level(p[2])
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("\033Pq")
+	fmt.Fprintf(&b, "\"1;1;%d;%d", w, h)
+	for c := range 216 {
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", c, c/36*20, c/6%6*20, c%6*20)
+	}
+
+	// Each band of six rows is drawn one color at a time, returning to the
+	// start of the band between colors
+	row := make([]byte, w)
+	for top := 0; top < h; top += 6 {
+		used := map[int]bool{}
+		for y := top; y < min(top+6, h); y++ {
+			for x := range w {
+				used[index[y*w+x]] = true
+			}
+		}
+		for c := range 216 {
+			if !used[c] {
+				continue
+			}
+			for x := range w {
+				bits := 0
+				for dy := 0; dy < 6 && top+dy < h; dy++ {
+					if index[(top+dy)*w+x] == c {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = byte(63 + bits)
+			}
+			fmt.Fprintf(&b, "#%d", c)
+			writeRuns(&b, row)
+			b.WriteByte('$')
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\033\\\n")
+	return b.String()
+}
+
+// writeRuns writes sixel data with repeats run-length encoded
+func writeRuns(b *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(b, "!%d%c", n, row[i])
+		} else {
+			b.Write(row[i:j])
+		}
+		i = j
+	}
+}
+
+// asciiRamp runs from dark to light
+const asciiRamp = " .:-=+*#%@"
+
+// ascii draws img with characters of increasing density. Cells are about
+// twice as tall as they are wide.
+func ascii(img image.Image, cols, rows int) string {
+	small := fit(img, cols, rows, 2)
+	var b strings.Builder
+	for y := range small.Bounds().Dy() {
+		for x := range small.Bounds().Dx() {
+			i := small.PixOffset(x, y)
+			p := small.Pix[i : i+3]
+			lum := (299*int(p[0]) + 587*int(p[1]) + 114*int(p[2])) / 1000
+			b.WriteByte(asciiRamp[lum*(len(asciiRamp)-1)/255])
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// progressWidth is the number of cells in the bar
+const progressWidth = 30
+
+// bar is the progress bar on the last line of the terminal, if any. Output
+// written while it's shown goes above it.
+var bar *Progress
+
+// Progress tracks a batch of work items. On a terminal it's drawn as a bar
+// that other output scrolls above; in plain mode nothing is drawn, so piped
+// output stays clean.
+type Progress struct {
+	label  string
+	total  int
+	done   int
+	failed int
+	start  time.Time
+}
+
+// NewProgress starts tracking total items
+func NewProgress(label string, total int) *Progress {
+	mu.Lock()
+	defer mu.Unlock()
+	p := &Progress{label: label, total: total, start: time.Now()}
+	if !plain {
+		bar = p
+		p.draw()
+	}
+	return p
+}
+
+// Step records a finished item, failed if err isn't nil
+func (p *Progress) Step(err error) {
+	mu.Lock()
+	defer mu.Unlock()
+	p.done++
+	if err != nil {
+		p.failed++
+	}
+	if bar == p {
+		p.draw()
+	}
+}
+
+// Finish removes the bar
+func (p *Progress) Finish() {
+	mu.Lock()
+	defer mu.Unlock()
+	if bar == p {
+		io.WriteString(out, "\r\033[K")
+		bar = nil
+	}
+}
+
+// draw redraws the bar in place. Caller must hold mu.
+func (p *Progress) draw() {
+	filled := 0
+	if p.total > 0 {
+		filled = p.done * progressWidth / p.total
+	}
+	line := fmt.Sprintf("%s [%s%s] %d/%d", p.label,
+		strings.Repeat("█", filled), strings.Repeat("░", progressWidth-filled), p.done, p.total)
+	if p.failed > 0 {
+		line += fmt.Sprintf(", %d failed", p.failed)
+	}
+	if p.done > 0 && p.done < p.total {
+		left := time.Since(p.start) / time.Duration(p.done) * time.Duration(p.total-p.done)
+		line += fmt.Sprintf(", ~%s left", left.Round(time.Second))
+	}
+	io.WriteString(out, "\r\033[K"+line)
+}
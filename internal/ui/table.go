@@ -0,0 +1,67 @@
+package ui
+
+import "strings"
+
+// Table renders rows in aligned columns
+type Table struct {
+	headers []string
+	rows    [][]string
+}
+
+// NewTable creates a table with the given column headers
+func NewTable(headers ...string) *Table {
+	return &Table{headers: headers}
+}
+
+// AddRow appends a row; cells may contain color codes
+func (t *Table) AddRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Render writes the table to stdout
+func (t *Table) Render() {
+	Print(t.String())
+}
+
+// String formats the table with columns padded to their widest cell
+func (t *Table) String() string {
+	widths := make([]int, len(t.headers))
+	for i, h := range t.headers {
+		widths[i] = visibleLen(h)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i < len(widths) && visibleLen(cell) > widths[i] {
+				widths[i] = visibleLen(cell)
+			}
+		}
+	}
+
+	var b strings.Builder
+	header := make([]string, len(t.headers))
+	for i, h := range t.headers {
+		header[i] = Bold(h)
+	}
+	writeRow(&b, header, widths)
+
+	total := 0
+	for _, w := range widths {
+		total += w + 2
+	}
+	b.WriteString(Dim(strings.Repeat("-", total-2)) + "\n")
+
+	for _, row := range t.rows {
+		writeRow(&b, row, widths)
+	}
+	return b.String()
+}
+
+func writeRow(b *strings.Builder, cells []string, widths []int) {
+	for i, cell := range cells {
+		b.WriteString(cell)
+		if i < len(cells)-1 && i < len(widths) {
+			b.WriteString(strings.Repeat(" ", widths[i]-visibleLen(cell)+2))
+		}
+	}
+	b.WriteString("\n")
+}
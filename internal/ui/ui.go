@@ -0,0 +1,180 @@
+// Package ui handles terminal output shared by task-tracker and monitor-helper.
+//
+// In plain mode (--plain, or stdout isn't a terminal) emoji and separator
+// lines are stripped so output piped to files or scripts is clean ASCII.
+// Color is used only on terminals and can be disabled with --no-color or
+// the NO_COLOR environment variable.
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	mu    sync.Mutex
+	out   io.Writer = os.Stdout
+	plain           = !IsTerminal(os.Stdout)
+	color           = IsTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+)
+
+// IsTerminal reports whether f is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// SetPlain turns plain output on or off
+func SetPlain(on bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	plain = on
+}
+
+// Plain reports whether plain output is enabled
+func Plain() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return plain
+}
+
+// SetColor turns color output on or off
+func SetColor(on bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	color = on
+}
+
+// Color reports whether color output is enabled
+func Color() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return color && !plain
+}
+
+// Printf formats and writes to stdout
+func Printf(format string, a ...any) {
+	write(fmt.Sprintf(format, a...))
+}
+
+// Println writes its operands to stdout followed by a newline
+func Println(a ...any) {
+	write(fmt.Sprintln(a...))
+}
+
+// Print writes its operands to stdout
+func Print(a ...any) {
+	write(fmt.Sprint(a...))
+}
+
+func write(s string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if plain {
+		s = Strip(s)
+	} else if color {
+		s = highlight(s)
+	}
+	if bar != nil {
+		// Print above the progress bar, then put it back
+		io.WriteString(out, "\r\033[K"+s)
+		if !strings.HasSuffix(s, "\n") {
+			io.WriteString(out, "\n")
+		}
+		bar.draw()
+		return
+	}
+	io.WriteString(out, s)
+}
+
+// Strip removes emoji and separator lines from s, replacing a few symbols
+// with ASCII equivalents
+func Strip(s string) string {
+	lines := strings.Split(s, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if isSeparator(line) {
+			continue
+		}
+		kept = append(kept, stripLine(line))
+	}
+	return strings.Join(kept, "\n")
+}
+
+// isSeparator matches lines like "=====" or "-----"
+func isSeparator(line string) bool {
+	line = strings.TrimSpace(line)
+	if len(line) < 10 {
+		return false
+	}
+	return strings.Trim(line, "=-─━") == ""
+}
+
+func stripLine(line string) string {
+	var b strings.Builder
+	skipSpace := false
+
+	for _, r := range line {
+		switch {
+		case r == '•':
+			b.WriteRune('-')
+			skipSpace = false
+		case isEmoji(r):
+			skipSpace = true
+		case skipSpace && r == ' ':
+			// drop padding that followed an emoji
+		case r > unicode.MaxASCII && !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsPunct(r):
+			skipSpace = false
+		default:
+			b.WriteRune(r)
+			skipSpace = false
+		}
+	}
+	return b.String()
+}
+
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, transport, ...
+		return true
+	case r >= 0x2300 && r <= 0x23FF: // ⏱ ⏸ ...
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // ⚠ ✅ ❌ ...
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // ⭐ ...
+		return true
+	case r == 0xFE0F || r == 0x200D: // variation selector, zero-width joiner
+		return true
+	}
+	return false
+}
+
+// BindFlags adds the output flags to a root command
+func BindFlags(root *cobra.Command) {
+	root.PersistentFlags().Bool("plain", false, "Plain ASCII output without emoji (default when stdout isn't a terminal)")
+	root.PersistentFlags().Bool("no-color", false, "Disable colored output (also honors NO_COLOR)")
+
+	prev := root.PersistentPreRun
+	root.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		if cmd.Flags().Changed("plain") {
+			on, _ := cmd.Flags().GetBool("plain")
+			SetPlain(on)
+		}
+		if off, _ := cmd.Flags().GetBool("no-color"); off {
+			SetColor(false)
+		}
+		if prev != nil {
+			prev(cmd, args)
+		}
+	}
+}
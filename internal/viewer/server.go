@@ -0,0 +1,114 @@
+package viewer
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+//go:embed page.html
+var pageSource string
+
+var page = template.Must(template.New("page").Funcs(template.FuncMap{
+	"minutes": func(seconds float64) string { return fmt.Sprintf("%.1f", seconds/60) },
+	"inc":     func(i int) int { return i + 1 },
+	"monitor": func(m int) string {
+		if m == 0 {
+			return "all (composite)"
+		}
+		return strconv.Itoa(m)
+	},
+}).Parse(pageSource))
+
+// pageData is what the page template is executed with
+type pageData struct {
+	Session
+	Ticket    string
+	TicketURL string
+	Token     string
+}
+
+// Server serves the gallery of one session on a local port
+type Server struct {
+	viewer   *Viewer
+	token    string
+	listener net.Listener
+}
+
+// Listen opens a port on the loopback interface (0 for any free one)
+func (v *Viewer) Listen(port int) (*Server, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	token := make([]byte, 16)
+	rand.Read(token)
+	return &Server{viewer: v, token: hex.EncodeToString(token), listener: listener}, nil
+}
+
+// URL is the gallery's address, including the token that grants access
+func (s *Server) URL() string {
+	return fmt.Sprintf("http://%s/?token=%s", s.listener.Addr(), s.token)
+}
+
+// Serve handles requests until the listener is closed
+func (s *Server) Serve() error {
+	return http.Serve(s.listener, s)
+}
+
+// Close stops serving
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The token keeps other local users and web pages from reading frames
+	if r.URL.Query().Get("token") != s.token {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "read-only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/":
+		data := pageData{Session: s.viewer.Session, Token: s.token}
+		if t := data.Session.Ticket; t != nil {
+			data.Ticket, data.TicketURL = t.Key, t.URL
+			if t.Provider != "" && t.Provider != "jira" {
+				data.Ticket = t.Provider + ":" + t.Key
+			}
+		} else {
+			data.Ticket = data.Session.JiraTicket
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := page.Execute(w, data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+
+	case strings.HasPrefix(r.URL.Path, "/frame/"):
+		n, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/frame/"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		data, contentType, err := s.viewer.Frame(n)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write(data)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
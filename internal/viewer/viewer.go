@@ -0,0 +1,223 @@
+// Package viewer shows saved sessions read-only: a session directory or an
+// exported bundle is served as a local web gallery with its metadata and
+// summary. It has no capture code, so it can ship on its own to people who
+// only review sessions.
+package viewer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	_ "golang.org/x/image/webp"
+
+	"task-tracker/internal/archive"
+	"task-tracker/internal/delta"
+	"task-tracker/internal/imaging"
+)
+
+// BundleExt is the extension of exported session bundles. They're zip
+// archives like those of 'task-tracker export', so .zip opens too.
+const BundleExt = ".ttsession"
+
+// Region is a redacted rectangle of a frame in image pixels
+type Region struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// Frame is a screenshot as recorded in metadata.json
+type Frame struct {
+	Path         string   `json:"path"`
+	Monitor      int      `json:"monitor"`
+	RelativeTime float64  `json:"relative_time"`
+	Resolution   string   `json:"resolution"`
+	Marked       bool     `json:"marked"`
+	Caption      string   `json:"caption"`
+	Redact       []Region `json:"redact"`
+}
+
+// Session is the part of metadata.json the viewer shows
+type Session struct {
+	SessionID       string  `json:"session_id"`
+	Name            string  `json:"name"`
+	TaskName        string  `json:"task_name"`
+	StartTime       string  `json:"start_time"`
+	EndTime         string  `json:"end_time"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Ticket          *struct {
+		Provider string `json:"provider"`
+		Key      string `json:"key"`
+		URL      string `json:"url"`
+	} `json:"ticket"`
+	JiraTicket string   `json:"jira_ticket"`
+	TimeSpent  string   `json:"time_spent"`
+	Tags       []string `json:"tags"`
+	Summary    *struct {
+		Text      string `json:"text"`
+		Provider  string `json:"provider"`
+		Model     string `json:"model"`
+		CreatedAt string `json:"created_at"`
+	} `json:"summary"`
+	Notes []struct {
+		RelativeTime float64 `json:"relative_time"`
+		Text         string  `json:"text"`
+	} `json:"notes"`
+	Markers []struct {
+		RelativeTime float64 `json:"relative_time"`
+		Label        string  `json:"label"`
+		Screenshots  []int   `json:"screenshots"`
+	} `json:"markers"`
+	Screenshots []Frame `json:"screenshots"`
+}
+
+// Viewer reads one session from a directory or bundle
+type Viewer struct {
+	Session Session
+	fsys    fs.FS  // rooted at the directory holding the session
+	root    string // the session's directory within fsys
+	closer  io.Closer
+}
+
+// Open opens a session directory or a bundle file
+func Open(target string) (*Viewer, error) {
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Viewer{}
+	if info.IsDir() {
+		abs, err := filepath.Abs(target)
+		if err != nil {
+			return nil, err
+		}
+		v.fsys = os.DirFS(filepath.Dir(abs))
+		v.root = filepath.Base(abs)
+	} else {
+		zr, err := zip.OpenReader(target)
+		if err != nil {
+			return nil, fmt.Errorf("%s isn't a session bundle: %w", target, err)
+		}
+		archive.Reader(&zr.Reader)
+		v.fsys, v.closer = zr, zr
+		if v.root, err = bundleRoot(zr); err != nil {
+			zr.Close()
+			return nil, fmt.Errorf("%s: %w", target, err)
+		}
+	}
+
+	data, err := fs.ReadFile(v.fsys, path.Join(v.root, "metadata.json"))
+	if err != nil {
+		v.Close()
+		return nil, fmt.Errorf("not a session: %w", err)
+	}
+	if err := json.Unmarshal(data, &v.Session); err != nil {
+		v.Close()
+		return nil, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+	return v, nil
+}
+
+// bundleRoot finds the session directory of a bundle
+func bundleRoot(zr *zip.ReadCloser) (string, error) {
+	for _, f := range zr.File {
+		if path.Base(f.Name) == "metadata.json" && strings.Count(f.Name, "/") <= 1 {
+			return path.Dir(f.Name), nil
+		}
+	}
+	return "", fmt.Errorf("no metadata.json in the bundle")
+}
+
+// Close releases a bundle
+func (v *Viewer) Close() error {
+	if v.closer == nil {
+		return nil
+	}
+	return v.closer.Close()
+}
+
+// framePath turns a path from metadata.json into a name in fsys. Like
+// task-tracker, it accepts paths of sessions saved before paths were
+// stored relative to the session.
+func (v *Viewer) framePath(stored string) string {
+	stored = filepath.ToSlash(stored)
+	segments := strings.Split(stored, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] == v.Session.SessionID {
+			stored = strings.Join(segments[i+1:], "/")
+			break
+		}
+	}
+	return path.Clean(path.Join(v.root, stored))
+}
+
+// Frame returns screenshot n (1-based) as image bytes and their content
+// type. Delta frames are reconstructed and redactions applied.
+func (v *Viewer) Frame(n int) ([]byte, string, error) {
+	if n < 1 || n > len(v.Session.Screenshots) {
+		return nil, "", fmt.Errorf("no screenshot %d", n)
+	}
+	frame := v.Session.Screenshots[n-1]
+	name := v.framePath(frame.Path)
+
+	if path.Ext(name) != delta.Ext && len(frame.Redact) == 0 {
+		data, err := fs.ReadFile(v.fsys, name)
+		contentType := "image/png"
+		switch strings.ToLower(path.Ext(name)) {
+		case ".jpg", ".jpeg":
+			contentType = "image/jpeg"
+		case ".webp":
+			contentType = "image/webp"
+		}
+		return data, contentType, err
+	}
+
+	img, err := v.load(name)
+	if err != nil {
+		return nil, "", err
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	copy(rgba.Pix, imaging.ToRGBA(img).Pix)
+	for _, r := range frame.Redact {
+		imaging.Redact(rgba, image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H))
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, rgba); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// load decodes a frame, reconstructing delta frames from their keyframe
+func (v *Viewer) load(name string) (image.Image, error) {
+	file, err := v.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if path.Ext(name) != delta.Ext {
+		img, _, err := image.Decode(file)
+		return img, err
+	}
+	return delta.Decode(file, func(ref string) (image.Image, error) {
+		if path.Ext(ref) == delta.Ext {
+			return nil, fmt.Errorf("keyframe %s is itself a delta", ref)
+		}
+		return v.load(path.Join(path.Dir(name), path.Base(ref)))
+	})
+}
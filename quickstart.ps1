@@ -56,8 +56,8 @@ go mod tidy
 
 Write-Host "Building binaries..."
 New-Item -ItemType Directory -Force -Path "bin" | Out-Null
-go build -ldflags "-s -w" -o bin/task-tracker.exe main.go
-go build -ldflags "-s -w" -o bin/monitor-helper.exe monitor-helper.go
+go build -ldflags "-s -w" -o bin/task-tracker.exe ./cmd/task-tracker
+go build -ldflags "-s -w" -o bin/monitor-helper.exe ./cmd/monitor-helper
 
 Write-Host "✅ Build complete!" -ForegroundColor Green
 
@@ -96,8 +96,8 @@ go mod tidy
 
 echo "Building binaries..."
 mkdir -p bin
-go build -ldflags "-s -w" -o bin/task-tracker cmd/task-tracker/main.go
-go build -ldflags "-s -w" -o bin/monitor-helper cmd/monitor-helper/main.go
+go build -ldflags "-s -w" -o bin/task-tracker ./cmd/task-tracker
+go build -ldflags "-s -w" -o bin/monitor-helper ./cmd/monitor-helper
 
 chmod +x bin/task-tracker bin/monitor-helper
 